@@ -52,6 +52,10 @@ type Options struct {
 	ReconnectingPTYTimeout time.Duration
 	EnvironmentVariables   map[string]string
 	Logger                 slog.Logger
+	// ReportActivity is called every ActivityReportInterval while the agent
+	// has active sessions, so coderd can postpone stopping the workspace.
+	ReportActivity         ReportActivity
+	ActivityReportInterval time.Duration
 }
 
 type Metadata struct {
@@ -68,8 +72,15 @@ type WireguardPublicKeys struct {
 	Disco  key.DiscoPublic `json:"disco"`
 }
 
+// Activity describes the sessions of an agent: SSH connections, reconnecting
+// PTYs and dialed connections used for port forwarding and apps.
+type Activity struct {
+	Sessions int64 `json:"sessions"`
+}
+
 type Dialer func(ctx context.Context, logger slog.Logger) (Metadata, *peerbroker.Listener, error)
 type UploadWireguardKeys func(ctx context.Context, keys WireguardPublicKeys) error
+type ReportActivity func(ctx context.Context, activity Activity) error
 type ListenWireguardPeers func(ctx context.Context, logger slog.Logger) (<-chan peerwg.Handshake, func(), error)
 
 func New(dialer Dialer, options *Options) io.Closer {
@@ -79,6 +90,9 @@ func New(dialer Dialer, options *Options) io.Closer {
 	if options.ReconnectingPTYTimeout == 0 {
 		options.ReconnectingPTYTimeout = 5 * time.Minute
 	}
+	if options.ActivityReportInterval == 0 {
+		options.ActivityReportInterval = time.Minute
+	}
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	server := &agent{
 		dialer:                 dialer,
@@ -90,6 +104,8 @@ func New(dialer Dialer, options *Options) io.Closer {
 		enableWireguard:        options.EnableWireguard,
 		postKeys:               options.UploadWireguardKeys,
 		listenWireguardPeers:   options.ListenWireguardPeers,
+		reportActivity:         options.ReportActivity,
+		activityReportInterval: options.ActivityReportInterval,
 	}
 	server.init(ctx)
 	return server
@@ -117,6 +133,10 @@ type agent struct {
 	network              *peerwg.Network
 	postKeys             UploadWireguardKeys
 	listenWireguardPeers ListenWireguardPeers
+
+	sessions               atomic.Int64
+	reportActivity         ReportActivity
+	activityReportInterval time.Duration
 }
 
 func (a *agent) run(ctx context.Context) {
@@ -237,7 +257,7 @@ func (a *agent) handlePeerConn(ctx context.Context, conn *peer.Conn) {
 
 		switch channel.Protocol() {
 		case ProtocolSSH:
-			go a.sshServer.HandleConn(channel.NetConn())
+			go a.handleSSHConn(channel.NetConn())
 		case ProtocolReconnectingPTY:
 			go a.handleReconnectingPTY(ctx, channel.Label(), channel.NetConn())
 		case ProtocolDial:
@@ -326,6 +346,41 @@ func (a *agent) init(ctx context.Context) {
 	}
 
 	go a.run(ctx)
+	if a.reportActivity != nil {
+		go a.runActivityReporter(ctx)
+	}
+}
+
+// runActivityReporter reports the activity of the agent periodically while
+// it has active sessions.
+func (a *agent) runActivityReporter(ctx context.Context) {
+	ticker := time.NewTicker(a.activityReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		sessions := a.sessions.Load()
+		if sessions == 0 {
+			continue
+		}
+		err := a.reportActivity(ctx, Activity{
+			Sessions: sessions,
+		})
+		if err != nil && ctx.Err() == nil {
+			a.logger.Warn(ctx, "report activity", slog.F("sessions", sessions), slog.Error(err))
+		}
+	}
+}
+
+// handleSSHConn serves an SSH connection, which counts as a session for
+// the lifetime of the connection.
+func (a *agent) handleSSHConn(conn net.Conn) {
+	a.sessions.Inc()
+	defer a.sessions.Dec()
+	a.sshServer.HandleConn(conn)
 }
 
 // createCommand processes raw command input with OpenSSH-like behavior.
@@ -478,6 +533,8 @@ func (a *agent) handleSSHSession(session ssh.Session) error {
 
 func (a *agent) handleReconnectingPTY(ctx context.Context, rawID string, conn net.Conn) {
 	defer conn.Close()
+	a.sessions.Inc()
+	defer a.sessions.Dec()
 
 	// The ID format is referenced in conn.go.
 	// <uuid>:<height>:<width>
@@ -676,6 +733,8 @@ type dialResponse struct {
 
 func (a *agent) handleDial(ctx context.Context, label string, conn net.Conn) {
 	defer conn.Close()
+	a.sessions.Inc()
+	defer a.sessions.Dec()
 
 	writeError := func(responseError error) error {
 		msg := ""
@@ -61,7 +61,7 @@ func (a *agent) startWireguard(ctx context.Context, addrs []netaddr.IPPrefix) er
 	}()
 
 	a.startWireguardListeners(ctx, wg, []handlerPort{
-		{port: 12212, handler: a.sshServer.HandleConn},
+		{port: 12212, handler: a.handleSSHConn},
 	})
 
 	a.network = wg
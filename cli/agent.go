@@ -180,6 +180,7 @@ func workspaceAgent() *cobra.Command {
 				EnableWireguard:      wireguard,
 				UploadWireguardKeys:  client.UploadWorkspaceAgentKeys,
 				ListenWireguardPeers: client.WireguardPeerListener,
+				ReportActivity:       client.PostWorkspaceAgentActivity,
 			})
 			<-cmd.Context().Done()
 			return closer.Close()
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func auditLogs() *cobra.Command {
+	cmd := &cobra.Command{
+		Short: "View audit logs",
+		Use:   "audit",
+	}
+	cmd.AddCommand(
+		auditLogList(),
+	)
+	return cmd
+}
+
+func auditLogList() *cobra.Command {
+	var (
+		columns      []string
+		outputFormat string
+		limit        int
+
+		user         string
+		resourceType string
+		resourceID   string
+		action       string
+		statusCode   int
+		from         string
+		to           string
+	)
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List audit logs, newest first. Only owners and auditors can view audit logs.",
+		Example: formatExamples(
+			example{
+				Description: "List every workspace deleted by a user",
+				Command:     "coder audit list --user alice --resource-type workspace --action delete",
+			},
+			example{
+				Description: "Output audit logs from a time range as JSON",
+				Command:     "coder audit list --from 2022-08-01 --to 2022-08-31 --output json",
+			},
+		),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outputFormat != "table" && outputFormat != "json" {
+				return xerrors.Errorf("unsupported output format %q, must be \"table\" or \"json\"", outputFormat)
+			}
+
+			var query []string
+			for _, filter := range []struct {
+				key   string
+				value string
+			}{
+				{"user", user},
+				{"resource_type", resourceType},
+				{"resource_id", resourceID},
+				{"action", action},
+				{"date_from", from},
+				{"date_to", to},
+			} {
+				if filter.value == "" {
+					continue
+				}
+				// Timestamps contain ':', so values are always quoted.
+				query = append(query, fmt.Sprintf("%s:%q", filter.key, filter.value))
+			}
+			if statusCode != 0 {
+				query = append(query, "status_code:"+strconv.Itoa(statusCode))
+			}
+
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			logs, err := client.AuditLogs(cmd.Context(), codersdk.AuditLogsRequest{
+				SearchQuery: strings.Join(query, " "),
+				Pagination: codersdk.Pagination{
+					Limit: limit,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			if outputFormat == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(logs)
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), displayAuditLogs(columns, logs...))
+			return err
+		},
+	}
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"time", "user", "action", "resource_type", "resource", "status_code"},
+		"Specify a column to filter in the table.")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format. Available formats are: table, json.")
+	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of audit logs to return. Use 0 to return all audit logs.")
+	cmd.Flags().StringVar(&user, "user", "", "Filter by the username or ID of the user that performed the action. Use 'me' for the current user.")
+	cmd.Flags().StringVar(&resourceType, "resource-type", "", "Filter by resource type, e.g. workspace or template.")
+	cmd.Flags().StringVar(&resourceID, "resource-id", "", "Filter by resource ID.")
+	cmd.Flags().StringVar(&action, "action", "", "Filter by action. Available actions are: create, write, delete.")
+	cmd.Flags().IntVar(&statusCode, "status-code", 0, "Filter by the HTTP status code of the request.")
+	cmd.Flags().StringVar(&from, "from", "", "Only show audit logs at or after the given date (2006-01-02) or RFC3339 timestamp.")
+	cmd.Flags().StringVar(&to, "to", "", "Only show audit logs before the given RFC3339 timestamp, or up to and including the given date (2006-01-02).")
+	return cmd
+}
+
+// displayAuditLogs will return a table displaying all audit logs passed in.
+// filterColumns must be a subset of the audit log fields and will determine
+// which columns to display.
+func displayAuditLogs(filterColumns []string, logs ...codersdk.AuditLog) string {
+	tableWriter := cliui.Table()
+	header := table.Row{"id", "time", "user", "action", "resource type", "resource id", "resource", "status code", "ip"}
+	tableWriter.AppendHeader(header)
+	tableWriter.SetColumnConfigs(cliui.FilterTableColumns(header, filterColumns))
+	for _, alog := range logs {
+		user := alog.UserID.String()
+		if alog.User != nil {
+			user = alog.User.Username
+		}
+		tableWriter.AppendRow(table.Row{
+			alog.ID.String(),
+			alog.Time.Format(time.Stamp),
+			user,
+			alog.Action,
+			alog.ResourceType,
+			alog.ResourceID.String(),
+			alog.ResourceTarget,
+			alog.StatusCode,
+			alog.IP,
+		})
+	}
+	return tableWriter.Render()
+}
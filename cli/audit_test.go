@@ -0,0 +1,63 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/pty/ptytest"
+)
+
+func TestAuditList(t *testing.T) {
+	t.Parallel()
+	t.Run("Table", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		cmd, root := clitest.New(t, "audit", "list", "--resource-type", "user", "--action", "create")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t)
+		cmd.SetIn(pty.Input())
+		cmd.SetOut(pty.Output())
+		errC := make(chan error)
+		go func() {
+			errC <- cmd.Execute()
+		}()
+		require.NoError(t, <-errC)
+		pty.ExpectMatch("testuser")
+	})
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_ = coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		cmd, root := clitest.New(t, "audit", "list", "--output", "json", "--user", "me", "--resource-type", "user")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		require.NoError(t, cmd.Execute())
+
+		var logs []codersdk.AuditLog
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logs))
+		require.NotEmpty(t, logs)
+		for _, alog := range logs {
+			require.Equal(t, codersdk.ResourceTypeUser, alog.ResourceType)
+			require.Equal(t, user.UserID, alog.UserID)
+		}
+	})
+	t.Run("InvalidOutput", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		cmd, root := clitest.New(t, "audit", "list", "--output", "yaml")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "unsupported output format")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
@@ -21,6 +22,7 @@ func create() *cobra.Command {
 		startAt       string
 		stopAfter     time.Duration
 		workspaceName string
+		fromWorkspace string
 	)
 	cmd := &cobra.Command{
 		Annotations: workspaceCommand,
@@ -63,7 +65,20 @@ func create() *cobra.Command {
 			}
 
 			var template codersdk.Template
-			if templateName == "" {
+			var source codersdk.Workspace
+			if fromWorkspace != "" {
+				if templateName != "" {
+					return xerrors.New("A template can't be specified when cloning a workspace!")
+				}
+				source, err = namedWorkspace(cmd, client, fromWorkspace)
+				if err != nil {
+					return xerrors.Errorf("get workspace to clone: %w", err)
+				}
+				template, err = client.Template(cmd.Context(), source.TemplateID)
+				if err != nil {
+					return xerrors.Errorf("get template: %w", err)
+				}
+			} else if templateName == "" {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), cliui.Styles.Wrap.Render("Select a template below to preview the provisioned infrastructure:"))
 
 				templates, err := client.TemplatesByOrganization(cmd.Context(), organization.ID)
@@ -111,6 +126,10 @@ func create() *cobra.Command {
 				}
 			}
 
+			if !template.AllowUserScheduleOverride && (cmd.Flags().Changed("start-at") || cmd.Flags().Changed("stop-after")) {
+				return xerrors.Errorf("The %q template doesn't allow changing the schedule of workspaces.", template.Name)
+			}
+
 			var schedSpec *string
 			if startAt != "" {
 				sched, err := parseCLISchedule(startAt)
@@ -119,15 +138,40 @@ func create() *cobra.Command {
 				}
 				schedSpec = ptr.Ref(sched.String())
 			}
+			ttl := ptr.Ref(stopAfter.Milliseconds())
+			if !cmd.Flags().Changed("stop-after") && (template.DefaultTTLMillis > 0 || !template.AllowUserScheduleOverride) {
+				// Use the default of the template.
+				ttl = nil
+			}
 
-			parameters, err := prepWorkspaceBuild(cmd, client, prepWorkspaceBuildArgs{
-				Template:         template,
-				ExistingParams:   []codersdk.Parameter{},
-				ParameterFile:    parameterFile,
-				NewWorkspaceName: workspaceName,
-			})
-			if err != nil {
-				return err
+			var parameters []codersdk.CreateParameterRequest
+			if source.ID != uuid.Nil {
+				// Clones keep the schedule and parameter values of the
+				// workspace unless they're given.
+				if !cmd.Flags().Changed("start-at") {
+					schedSpec = source.AutostartSchedule
+				}
+				if !cmd.Flags().Changed("stop-after") {
+					ttl = source.TTLMillis
+				}
+				if parameterFile != "" {
+					parameters, err = cloneParameters(cmd, client, source, parameterFile)
+					if err != nil {
+						return err
+					}
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cloning %s, built with the %s template.\n",
+					cliui.Styles.Keyword.Render(source.Name), cliui.Styles.Keyword.Render(template.Name))
+			} else {
+				parameters, err = prepWorkspaceBuild(cmd, client, prepWorkspaceBuildArgs{
+					Template:         template,
+					ExistingParams:   []codersdk.Parameter{},
+					ParameterFile:    parameterFile,
+					NewWorkspaceName: workspaceName,
+				})
+				if err != nil {
+					return err
+				}
 			}
 
 			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
@@ -143,11 +187,12 @@ func create() *cobra.Command {
 				TemplateID:        template.ID,
 				Name:              workspaceName,
 				AutostartSchedule: schedSpec,
-				TTLMillis:         ptr.Ref(stopAfter.Milliseconds()),
+				TTLMillis:         ttl,
 				ParameterValues:   parameters,
+				FromWorkspaceID:   source.ID,
 			})
 			if err != nil {
-				return err
+				return quotaError(err)
 			}
 
 			err = cliui.WorkspaceBuild(cmd.Context(), cmd.OutOrStdout(), client, workspace.LatestBuild.ID, after)
@@ -162,12 +207,40 @@ func create() *cobra.Command {
 
 	cliui.AllowSkipPrompt(cmd)
 	cliflag.StringVarP(cmd.Flags(), &templateName, "template", "t", "CODER_TEMPLATE_NAME", "", "Specify a template name.")
+	cliflag.StringVarP(cmd.Flags(), &fromWorkspace, "from", "", "CODER_WORKSPACE_FROM", "", "Specify a workspace to clone. The new workspace uses its template version and parameter values.")
 	cliflag.StringVarP(cmd.Flags(), &parameterFile, "parameter-file", "", "CODER_PARAMETER_FILE", "", "Specify a file path with parameter values.")
 	cliflag.StringVarP(cmd.Flags(), &startAt, "start-at", "", "CODER_WORKSPACE_START_AT", "", "Specify the workspace autostart schedule. Check `coder schedule start --help` for the syntax.")
 	cliflag.DurationVarP(cmd.Flags(), &stopAfter, "stop-after", "", "CODER_WORKSPACE_STOP_AFTER", 8*time.Hour, "Specify a duration after which the workspace should shut down (e.g. 8h).")
 	return cmd
 }
 
+// cloneParameters returns the values of a parameter file that override those
+// of a workspace being cloned.
+func cloneParameters(cmd *cobra.Command, client *codersdk.Client, source codersdk.Workspace, parameterFile string) ([]codersdk.CreateParameterRequest, error) {
+	parameterMap, err := createParameterMapFromFile(parameterFile)
+	if err != nil {
+		return nil, err
+	}
+	parameterSchemas, err := client.TemplateVersionSchema(cmd.Context(), source.LatestBuild.TemplateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	parameters := make([]codersdk.CreateParameterRequest, 0)
+	for _, parameterSchema := range parameterSchemas {
+		value, ok := parameterMap[parameterSchema.Name]
+		if !ok || !parameterSchema.AllowOverrideSource {
+			continue
+		}
+		parameters = append(parameters, codersdk.CreateParameterRequest{
+			Name:              parameterSchema.Name,
+			SourceValue:       value,
+			SourceScheme:      codersdk.ParameterSourceSchemeData,
+			DestinationScheme: parameterSchema.DefaultDestinationScheme,
+		})
+	}
+	return parameters, nil
+}
+
 type prepWorkspaceBuildArgs struct {
 	Template         codersdk.Template
 	ExistingParams   []codersdk.Parameter
@@ -251,6 +251,28 @@ func TestCreate(t *testing.T) {
 		removeTmpDirUntilSuccess(t, tempDir)
 	})
 
+	t.Run("FromWorkspace", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		cmd, root := clitest.New(t, "create", "my-clone", "--from", workspace.Name, "--yes")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		clone, err := client.WorkspaceByOwnerAndName(context.Background(), codersdk.Me, "my-clone", codersdk.WorkspaceOptions{})
+		require.NoError(t, err)
+		require.Equal(t, template.ID, clone.TemplateID)
+		require.Equal(t, workspace.AutostartSchedule, clone.AutostartSchedule)
+		require.Equal(t, workspace.TTLMillis, clone.TTLMillis)
+	})
+
 	t.Run("FailedDryRun", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func groups() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "groups",
+		Short:   "Manage groups of organization members",
+		Aliases: []string{"group"},
+		Example: formatExamples(
+			example{
+				Description: "Create a group that administers the organization",
+				Command:     "coder groups create admins --roles organization-admin",
+			},
+			example{
+				Description: "Add members to a group",
+				Command:     "coder groups edit admins --add-users alice,bob",
+			},
+		),
+	}
+	cmd.AddCommand(
+		groupCreate(),
+		groupList(),
+		groupEdit(),
+		groupDelete(),
+	)
+	return cmd
+}
+
+func groupCreate() *cobra.Command {
+	var roles []string
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a group",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			organization, err := currentOrganization(cmd, client)
+			if err != nil {
+				return err
+			}
+			group, err := client.CreateGroup(cmd.Context(), organization.ID, codersdk.CreateGroupRequest{
+				Name:  args[0],
+				Roles: organizationRoleNames(organization.ID, roles),
+			})
+			if err != nil {
+				return xerrors.Errorf("create group: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Group %s has been created!\n", cliui.Styles.Keyword.Render(group.Name))
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&roles, "roles", nil, "Specify organization roles granted to members of the group.")
+	return cmd
+}
+
+func groupList() *cobra.Command {
+	var columns []string
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the groups of the organization",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			organization, err := currentOrganization(cmd, client)
+			if err != nil {
+				return err
+			}
+			list, err := client.GroupsByOrganization(cmd.Context(), organization.ID)
+			if err != nil {
+				return xerrors.Errorf("list groups: %w", err)
+			}
+			if len(list) == 0 {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), cliui.Styles.Prompt.String()+"No groups found! Create one:")
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "  "+cliui.Styles.Code.Render("coder groups create <name>"))
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+				return nil
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), displayGroups(columns, list...))
+			return err
+		},
+	}
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"name", "members", "roles"},
+		"Specify a column to filter in the table. Available columns are: id, name, members, roles.")
+	return cmd
+}
+
+func groupEdit() *cobra.Command {
+	var (
+		name        string
+		addUsers    []string
+		removeUsers []string
+		roles       []string
+	)
+	cmd := &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Rename a group, change its members or the roles it grants",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			organization, err := currentOrganization(cmd, client)
+			if err != nil {
+				return err
+			}
+			group, err := client.GroupByOrgAndName(cmd.Context(), organization.ID, args[0])
+			if err != nil {
+				return xerrors.Errorf("get group: %w", err)
+			}
+
+			req := codersdk.PatchGroupRequest{Name: name}
+			for _, username := range addUsers {
+				user, err := client.User(cmd.Context(), username)
+				if err != nil {
+					return xerrors.Errorf("get user %q: %w", username, err)
+				}
+				req.AddUsers = append(req.AddUsers, user.ID.String())
+			}
+			for _, username := range removeUsers {
+				user, err := client.User(cmd.Context(), username)
+				if err != nil {
+					return xerrors.Errorf("get user %q: %w", username, err)
+				}
+				req.RemoveUsers = append(req.RemoveUsers, user.ID.String())
+			}
+			group, err = client.PatchGroup(cmd.Context(), group.ID, req)
+			if err != nil {
+				return xerrors.Errorf("update group: %w", err)
+			}
+
+			if cmd.Flags().Changed("roles") {
+				group, err = client.UpdateGroupRoles(cmd.Context(), group.ID, codersdk.UpdateRoles{
+					Roles: organizationRoleNames(organization.ID, roles),
+				})
+				if err != nil {
+					return xerrors.Errorf("update group roles: %w", err)
+				}
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Group %s has been updated!\n", cliui.Styles.Keyword.Render(group.Name))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Specify a new name for the group.")
+	cmd.Flags().StringSliceVarP(&addUsers, "add-users", "a", nil, "Specify the usernames or IDs of users to add to the group.")
+	cmd.Flags().StringSliceVarP(&removeUsers, "remove-users", "r", nil, "Specify the usernames or IDs of users to remove from the group.")
+	cmd.Flags().StringSliceVar(&roles, "roles", nil, "Replace the organization roles granted to members of the group.")
+	return cmd
+}
+
+func groupDelete() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a group",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			organization, err := currentOrganization(cmd, client)
+			if err != nil {
+				return err
+			}
+			group, err := client.GroupByOrgAndName(cmd.Context(), organization.ID, args[0])
+			if err != nil {
+				return xerrors.Errorf("get group: %w", err)
+			}
+
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete the group %s?", cliui.Styles.Code.Render(group.Name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteGroup(cmd.Context(), group.ID)
+			if err != nil {
+				return xerrors.Errorf("delete group: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Group %s has been deleted!\n", cliui.Styles.Keyword.Render(group.Name))
+			return nil
+		},
+	}
+	cliui.AllowSkipPrompt(cmd)
+	return cmd
+}
+
+// organizationRoleNames appends the organization ID to role names that lack
+// one, so "organization-admin" can be passed instead of the full name.
+func organizationRoleNames(organizationID uuid.UUID, roles []string) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !strings.Contains(role, ":") {
+			role += ":" + organizationID.String()
+		}
+		names = append(names, role)
+	}
+	return names
+}
+
+// displayGroups will return a table displaying all groups passed in.
+// filterColumns must be a subset of the group fields and will determine which
+// columns to display
+func displayGroups(filterColumns []string, groups ...codersdk.Group) string {
+	tableWriter := cliui.Table()
+	header := table.Row{"id", "name", "members", "roles"}
+	tableWriter.AppendHeader(header)
+	tableWriter.SetColumnConfigs(cliui.FilterTableColumns(header, filterColumns))
+	for _, group := range groups {
+		members := make([]string, 0, len(group.Members))
+		for _, member := range group.Members {
+			members = append(members, member.Username)
+		}
+		tableWriter.AppendRow(table.Row{
+			group.ID,
+			group.Name,
+			strings.Join(members, ", "),
+			strings.Join(group.Roles, ", "),
+		})
+	}
+	return tableWriter.Render()
+}
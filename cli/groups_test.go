@@ -0,0 +1,72 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/rbac"
+)
+
+func TestGroups(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	admin := coderdtest.CreateFirstUser(t, client)
+	member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	memberUser, err := member.User(context.Background(), "me")
+	require.NoError(t, err)
+
+	//nolint:paralleltest
+	t.Run("Create", func(t *testing.T) {
+		cmd, root := clitest.New(t, "groups", "create", "developers", "--roles", "organization-admin")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		group, err := client.GroupByOrgAndName(context.Background(), admin.OrganizationID, "developers")
+		require.NoError(t, err)
+		require.Equal(t, []string{rbac.RoleOrgAdmin(admin.OrganizationID)}, group.Roles)
+	})
+
+	//nolint:paralleltest
+	t.Run("Edit", func(t *testing.T) {
+		cmd, root := clitest.New(t, "groups", "edit", "developers", "--name", "engineers", "--add-users", memberUser.Username, "--roles", "")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		group, err := client.GroupByOrgAndName(context.Background(), admin.OrganizationID, "engineers")
+		require.NoError(t, err)
+		require.Len(t, group.Members, 1)
+		require.Equal(t, memberUser.ID, group.Members[0].ID)
+		require.Empty(t, group.Roles)
+	})
+
+	//nolint:paralleltest
+	t.Run("List", func(t *testing.T) {
+		cmd, root := clitest.New(t, "groups", "list")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "engineers")
+		require.Contains(t, buf.String(), memberUser.Username)
+	})
+
+	//nolint:paralleltest
+	t.Run("Delete", func(t *testing.T) {
+		cmd, root := clitest.New(t, "groups", "delete", "engineers", "--yes")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		groups, err := client.GroupsByOrganization(context.Background(), admin.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, groups)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/coderd/autobuild/executor"
 	"github.com/coder/coder/coderd/autobuild/schedule"
 	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
@@ -57,6 +58,7 @@ func list() *cobra.Command {
 			tableWriter.SetColumnConfigs(cliui.FilterTableColumns(header, columns))
 
 			now := time.Now()
+			var warnings []string
 			for _, workspace := range workspaces {
 				status := codersdk.WorkspaceDisplayStatus(workspace.LatestBuild.Job.Status, workspace.LatestBuild.Transition)
 
@@ -79,6 +81,9 @@ func list() *cobra.Command {
 				}
 
 				user := usersByID[workspace.OwnerID]
+				if warning := dormancyWarning(user.Username+"/"+workspace.Name, workspace, now); warning != "" {
+					warnings = append(warnings, warning)
+				}
 				tableWriter.AppendRow(table.Row{
 					user.Username + "/" + workspace.Name,
 					workspace.TemplateName,
@@ -90,10 +95,32 @@ func list() *cobra.Command {
 				})
 			}
 			_, err = fmt.Fprintln(cmd.OutOrStdout(), tableWriter.Render())
-			return err
+			if err != nil {
+				return err
+			}
+			for _, warning := range warnings {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), cliui.Styles.Warn.Render("Warning:")+" "+warning)
+			}
+			return nil
 		},
 	}
 	cmd.Flags().StringArrayVarP(&columns, "column", "c", nil,
 		"Specify a column to filter in the table.")
 	return cmd
 }
+
+// dormancyWarning returns a warning if the workspace will be marked dormant or
+// deleted soon, when the autobuild executor starts warning about it.
+func dormancyWarning(name string, workspace codersdk.Workspace, now time.Time) string {
+	warnBefore := executor.DormancyWarningCountdown[0]
+	switch {
+	case workspace.DeletingAt != nil && workspace.DeletingAt.Sub(now) < warnBefore:
+		return fmt.Sprintf("%s is dormant and will be deleted %s. Start it to keep it.",
+			name, relative(workspace.DeletingAt.Sub(now)))
+	case workspace.DormantAfter != nil && workspace.DormantAfter.Sub(now) < warnBefore:
+		return fmt.Sprintf("%s will be marked dormant %s. Start it, or opt it out with %s.",
+			name, relative(workspace.DormantAfter.Sub(now)), cliui.Styles.Code.Render("coder schedule dormancy "+workspace.Name+" off"))
+	default:
+		return ""
+	}
+}
@@ -166,15 +166,11 @@ func login() *cobra.Command {
 				if err != nil {
 					return xerrors.Errorf("create initial user: %w", err)
 				}
-				resp, err := client.LoginWithPassword(cmd.Context(), codersdk.LoginWithPasswordRequest{
-					Email:    email,
-					Password: password,
-				})
+				sessionToken, err := loginWithPassword(cmd, client, email, password)
 				if err != nil {
-					return xerrors.Errorf("login with password: %w", err)
+					return err
 				}
 
-				sessionToken := resp.SessionToken
 				config := createConfig(cmd)
 				err = config.Session().Write(sessionToken)
 				if err != nil {
@@ -194,6 +190,12 @@ func login() *cobra.Command {
 			}
 
 			sessionToken, _ := cmd.Flags().GetString(varToken)
+			if sessionToken == "" && email != "" && password != "" {
+				sessionToken, err = loginWithPassword(cmd, client, email, password)
+				if err != nil {
+					return err
+				}
+			}
 			if sessionToken == "" {
 				authURL := *serverURL
 				// Don't use filepath.Join, we don't want to use the os separator
@@ -249,6 +251,59 @@ func login() *cobra.Command {
 	return cmd
 }
 
+// loginWithPassword returns a session token for the user, prompting for a
+// TOTP code if the user has a second factor or must enroll in one.
+func loginWithPassword(cmd *cobra.Command, client *codersdk.Client, email, password string) (string, error) {
+	resp, err := client.LoginWithPassword(cmd.Context(), codersdk.LoginWithPasswordRequest{
+		Email:    email,
+		Password: password,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("login with password: %w", err)
+	}
+	if resp.TOTPToken == "" {
+		return resp.SessionToken, nil
+	}
+
+	if resp.TOTPEnrollment != nil {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), cliui.Styles.Paragraph.Render("Your account requires two-factor authentication. Add this secret to your authenticator app:"))
+		_, _ = fmt.Fprintln(cmd.OutOrStdout())
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  "+cliui.Styles.Code.Render(resp.TOTPEnrollment.Secret))
+		_, _ = fmt.Fprintln(cmd.OutOrStdout())
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), cliui.Styles.Paragraph.Render("Or open this URL with it:"))
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  "+resp.TOTPEnrollment.URL)
+		_, _ = fmt.Fprintln(cmd.OutOrStdout())
+	}
+	code, err := cliui.Prompt(cmd, cliui.PromptOptions{
+		Text:     "Enter your " + cliui.Styles.Field.Render("two-factor code") + ":",
+		Validate: cliui.ValidateNotEmpty,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("two-factor code prompt: %w", err)
+	}
+	totpResp, err := client.LoginWithTOTP(cmd.Context(), codersdk.LoginWithTOTPRequest{
+		TOTPToken: resp.TOTPToken,
+		Code:      code,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("login with two-factor code: %w", err)
+	}
+	if len(totpResp.RecoveryCodes) > 0 {
+		displayRecoveryCodes(cmd, totpResp.RecoveryCodes)
+	}
+	return totpResp.SessionToken, nil
+}
+
+// displayRecoveryCodes prints recovery codes, which are only returned once.
+func displayRecoveryCodes(cmd *cobra.Command, codes []string) {
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), cliui.Styles.Paragraph.Render("Two-factor authentication is enabled! Store these recovery codes somewhere safe. Each can be used once if you lose access to your authenticator app:"))
+	_, _ = fmt.Fprintln(cmd.OutOrStdout())
+	for _, code := range codes {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  "+cliui.Styles.Code.Render(code))
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout())
+}
+
 // isWSL determines if coder-cli is running within Windows Subsystem for Linux
 func isWSL() (bool, error) {
 	if runtime.GOOS == goosDarwin || runtime.GOOS == goosWindows {
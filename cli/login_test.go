@@ -2,7 +2,9 @@ package cli_test
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -10,6 +12,8 @@ import (
 	"github.com/coder/coder/cli/clitest"
 	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/totp"
+	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/pty/ptytest"
 )
 
@@ -114,9 +118,9 @@ func TestLogin(t *testing.T) {
 		pty.ExpectMatch("Passwords do not match")
 		pty.ExpectMatch("Enter a " + cliui.Styles.Field.Render("password"))
 
-		pty.WriteLine("pass")
+		pty.WriteLine("password")
 		pty.ExpectMatch("Confirm")
-		pty.WriteLine("pass")
+		pty.WriteLine("password")
 		pty.ExpectMatch("Welcome to Coder")
 		<-doneChan
 	})
@@ -169,6 +173,65 @@ func TestLogin(t *testing.T) {
 		<-doneChan
 	})
 
+	t.Run("ExistingUserPassword", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		root, cfg := clitest.New(t, "login", client.URL.String(),
+			"--email", coderdtest.FirstUserParams.Email, "--password", coderdtest.FirstUserParams.Password)
+		err := root.Execute()
+		require.NoError(t, err)
+		sessionFile, err := cfg.Session().Read()
+		require.NoError(t, err)
+		client.SessionToken = sessionFile
+		_, err = client.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+	})
+
+	t.Run("ExistingUserTOTP", func(t *testing.T) {
+		t.Parallel()
+		// The clock is advanced after enrolling, since codes can only be
+		// used once.
+		var offset int64
+		now := time.Now()
+		clock := func() time.Time {
+			return now.Add(time.Duration(atomic.LoadInt64(&offset)))
+		}
+		client := coderdtest.New(t, &coderdtest.Options{Now: clock})
+		coderdtest.CreateFirstUser(t, client)
+		enrollment, err := client.EnrollTOTP(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		code, err := totp.Code(enrollment.Secret, clock())
+		require.NoError(t, err)
+		_, err = client.VerifyTOTP(context.Background(), codersdk.Me, codersdk.VerifyTOTPRequest{Code: code})
+		require.NoError(t, err)
+		atomic.StoreInt64(&offset, int64(totp.Period))
+
+		doneChan := make(chan struct{})
+		root, cfg := clitest.New(t, "login", "--force-tty", client.URL.String(),
+			"--email", coderdtest.FirstUserParams.Email, "--password", coderdtest.FirstUserParams.Password)
+		pty := ptytest.New(t)
+		root.SetIn(pty.Input())
+		root.SetOut(pty.Output())
+		go func() {
+			defer close(doneChan)
+			err := root.Execute()
+			assert.NoError(t, err)
+		}()
+
+		code, err = totp.Code(enrollment.Secret, clock())
+		require.NoError(t, err)
+		pty.ExpectMatch("two-factor code")
+		pty.WriteLine(code)
+		pty.ExpectMatch("Welcome to Coder")
+		<-doneChan
+		sessionFile, err := cfg.Session().Read()
+		require.NoError(t, err)
+		client.SessionToken = sessionFile
+		_, err = client.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+	})
+
 	t.Run("TokenFlag", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func organizations() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "organizations",
+		Short:   "Manage organizations and their members",
+		Aliases: []string{"organization", "orgs", "org"},
+		Example: formatExamples(
+			example{
+				Description: "Rename an organization",
+				Command:     "coder organizations rename acme acme-corp",
+			},
+			example{
+				Description: "Add a user to an organization",
+				Command:     "coder organizations members add acme alice",
+			},
+		),
+	}
+	cmd.AddCommand(
+		organizationList(),
+		organizationRename(),
+		organizationDelete(),
+		organizationMembers(),
+	)
+	return cmd
+}
+
+func organizationList() *cobra.Command {
+	var columns []string
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the organizations you can access",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			list, err := client.Organizations(cmd.Context())
+			if err != nil {
+				return xerrors.Errorf("list organizations: %w", err)
+			}
+
+			tableWriter := cliui.Table()
+			header := table.Row{"id", "name", "created at"}
+			tableWriter.AppendHeader(header)
+			tableWriter.SetColumnConfigs(cliui.FilterTableColumns(header, columns))
+			for _, organization := range list {
+				tableWriter.AppendRow(table.Row{
+					organization.ID,
+					organization.Name,
+					organization.CreatedAt.Format(time.Stamp),
+				})
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), tableWriter.Render())
+			return err
+		},
+	}
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"name", "created at"},
+		"Specify a column to filter in the table. Available columns are: id, name, created at.")
+	return cmd
+}
+
+func organizationRename() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <name> <new-name>",
+		Short: "Rename an organization",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			organization, err := namedOrganization(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			organization, err = client.UpdateOrganization(cmd.Context(), organization.ID, codersdk.UpdateOrganizationRequest{
+				Name: args[1],
+			})
+			if err != nil {
+				return xerrors.Errorf("rename organization: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Organization %s has been renamed to %s!\n",
+				cliui.Styles.Keyword.Render(args[0]), cliui.Styles.Keyword.Render(organization.Name))
+			return nil
+		},
+	}
+}
+
+func organizationDelete() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete an organization. Its templates and workspaces must be deleted first",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			organization, err := namedOrganization(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete the organization %s? Its groups and members will be removed.", cliui.Styles.Code.Render(organization.Name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteOrganization(cmd.Context(), organization.ID)
+			if err != nil {
+				return xerrors.Errorf("delete organization: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Organization %s has been deleted!\n", cliui.Styles.Keyword.Render(organization.Name))
+			return nil
+		},
+	}
+	cliui.AllowSkipPrompt(cmd)
+	return cmd
+}
+
+func organizationMembers() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "members",
+		Short: "List, add, and remove the members of an organization",
+	}
+	cmd.AddCommand(
+		organizationMemberList(),
+		organizationMemberAdd(),
+		organizationMemberRemove(),
+	)
+	return cmd
+}
+
+func organizationMemberList() *cobra.Command {
+	var columns []string
+	cmd := &cobra.Command{
+		Use:     "list <organization>",
+		Aliases: []string{"ls"},
+		Short:   "List the members of an organization and their roles",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			organization, err := namedOrganization(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			members, err := client.OrganizationMembers(cmd.Context(), organization.ID)
+			if err != nil {
+				return xerrors.Errorf("list organization members: %w", err)
+			}
+
+			tableWriter := cliui.Table()
+			header := table.Row{"id", "username", "email", "roles", "joined at"}
+			tableWriter.AppendHeader(header)
+			tableWriter.SetColumnConfigs(cliui.FilterTableColumns(header, columns))
+			tableWriter.SortBy([]table.SortBy{{
+				Name: "Username",
+			}})
+			for _, member := range members {
+				roles := make([]string, 0, len(member.Roles))
+				for _, role := range member.Roles {
+					roles = append(roles, role.DisplayName)
+				}
+				tableWriter.AppendRow(table.Row{
+					member.User.ID,
+					member.User.Username,
+					member.User.Email,
+					strings.Join(roles, ", "),
+					member.CreatedAt.Format(time.Stamp),
+				})
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), tableWriter.Render())
+			return err
+		},
+	}
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"username", "email", "roles", "joined at"},
+		"Specify a column to filter in the table. Available columns are: id, username, email, roles, joined at.")
+	return cmd
+}
+
+func organizationMemberAdd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <organization> <user>",
+		Short: "Add an existing user to an organization",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			organization, err := namedOrganization(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			_, err = client.AddOrganizationMember(cmd.Context(), organization.ID, args[1])
+			if err != nil {
+				return xerrors.Errorf("add organization member: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "User %s has been added to %s!\n",
+				cliui.Styles.Keyword.Render(args[1]), cliui.Styles.Keyword.Render(organization.Name))
+			return nil
+		},
+	}
+}
+
+func organizationMemberRemove() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove <organization> <user>",
+		Aliases: []string{"rm"},
+		Short:   "Remove a user from an organization. They must not own workspaces in it",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			organization, err := namedOrganization(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Remove %s from %s?", cliui.Styles.Code.Render(args[1]), cliui.Styles.Code.Render(organization.Name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.RemoveOrganizationMember(cmd.Context(), organization.ID, args[1])
+			if err != nil {
+				return xerrors.Errorf("remove organization member: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "User %s has been removed from %s!\n",
+				cliui.Styles.Keyword.Render(args[1]), cliui.Styles.Keyword.Render(organization.Name))
+			return nil
+		},
+	}
+	cliui.AllowSkipPrompt(cmd)
+	return cmd
+}
+
+// namedOrganization finds an organization the user can access by its name.
+func namedOrganization(cmd *cobra.Command, client *codersdk.Client, name string) (codersdk.Organization, error) {
+	organizations, err := client.Organizations(cmd.Context())
+	if err != nil {
+		return codersdk.Organization{}, xerrors.Errorf("list organizations: %w", err)
+	}
+	for _, organization := range organizations {
+		if strings.EqualFold(organization.Name, name) {
+			return organization, nil
+		}
+	}
+	return codersdk.Organization{}, xerrors.Errorf("organization %q not found", name)
+}
@@ -0,0 +1,94 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestOrganizations(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	org, err := client.CreateOrganization(context.Background(), codersdk.CreateOrganizationRequest{
+		Name: "acme",
+	})
+	require.NoError(t, err)
+	_, err = client.CreateUser(context.Background(), codersdk.CreateUserRequest{
+		Email:          "alice@coder.com",
+		Username:       "alice",
+		Password:       "testpass",
+		OrganizationID: org.ID,
+	})
+	require.NoError(t, err)
+
+	//nolint:paralleltest
+	t.Run("Rename", func(t *testing.T) {
+		cmd, root := clitest.New(t, "organizations", "rename", "acme", "acme-corp")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		renamed, err := client.Organization(context.Background(), org.ID)
+		require.NoError(t, err)
+		require.Equal(t, "acme-corp", renamed.Name)
+	})
+
+	//nolint:paralleltest
+	t.Run("List", func(t *testing.T) {
+		cmd, root := clitest.New(t, "organizations", "list")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "acme-corp")
+	})
+
+	//nolint:paralleltest
+	t.Run("MembersRemove", func(t *testing.T) {
+		cmd, root := clitest.New(t, "organizations", "members", "remove", "acme-corp", "alice", "--yes")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		members, err := client.OrganizationMembers(context.Background(), org.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+	})
+
+	//nolint:paralleltest
+	t.Run("MembersAdd", func(t *testing.T) {
+		cmd, root := clitest.New(t, "organizations", "members", "add", "acme-corp", "alice")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		cmd, root = clitest.New(t, "organizations", "members", "list", "acme-corp")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		err = cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "alice")
+		require.Contains(t, buf.String(), "Organization Admin")
+	})
+
+	//nolint:paralleltest
+	t.Run("Delete", func(t *testing.T) {
+		cmd, root := clitest.New(t, "organizations", "delete", "acme-corp", "--yes")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		orgs, err := client.Organizations(context.Background())
+		require.NoError(t, err)
+		require.Len(t, orgs, 1)
+	})
+}
@@ -0,0 +1,31 @@
+package cli_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+)
+
+func TestRename(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+	admin := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, admin.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	cmd, root := clitest.New(t, "rename", workspace.Name, "renamed", "--yes")
+	clitest.SetupConfig(t, client, root)
+	err := cmd.Execute()
+	require.NoError(t, err)
+
+	renamed, err := client.Workspace(context.Background(), workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, "renamed", renamed.Name)
+}
@@ -111,26 +111,34 @@ func Root() *cobra.Command {
 	}
 
 	cmd.AddCommand(
+		auditLogs(),
 		configSSH(),
 		create(),
 		deleteWorkspace(),
 		dotfiles(),
 		gitssh(),
+		groups(),
 		list(),
 		login(),
 		logout(),
+		organizations(),
 		parameters(),
 		portForward(),
 		publickey(),
+		rename(),
 		resetPassword(),
 		schedules(),
 		server(),
+		share(),
 		show(),
 		ssh(),
 		start(),
 		state(),
 		stop(),
 		templates(),
+		tokens(),
+		totp(),
+		transfer(),
 		update(),
 		users(),
 		versionCmd(),
@@ -235,7 +243,10 @@ func createAgentClient(cmd *cobra.Command) (*codersdk.Client, error) {
 func currentOrganization(cmd *cobra.Command, client *codersdk.Client) (codersdk.Organization, error) {
 	orgs, err := client.OrganizationsByUser(cmd.Context(), codersdk.Me)
 	if err != nil {
-		return codersdk.Organization{}, nil
+		return codersdk.Organization{}, xerrors.Errorf("get organizations: %w", err)
+	}
+	if len(orgs) == 0 {
+		return codersdk.Organization{}, xerrors.New("you aren't a member of any organization")
 	}
 	// For now, we won't use the config to set this.
 	// Eventually, we will support changing using "coder switch <org>"
@@ -22,6 +22,7 @@ const (
   * The next scheduled start time
   * The duration after which it will stop
   * The next scheduled stop time
+  * Whether the workspace is dormant, and when it will be deleted
 `
 	scheduleStartDescriptionLong = `Schedules a workspace to regularly start at a specific time.
 Schedule format: <start-time> [day-of-week] [location].
@@ -50,13 +51,18 @@ When enabling scheduled stop, enter a duration in one of the following formats:
   * The new stop time is calculated from *now*.
   * The new stop time must be at least 30 minutes in the future.
   * The workspace template may restrict the maximum workspace runtime.
+`
+	scheduleDormancyDescriptionLong = `Opt a workspace out of dormancy, or back in.
+  * Templates may mark workspaces that stay stopped for too long dormant.
+  * Dormant workspaces must be confirmed to start, and may be deleted after a while.
+  * Opting out also wakes a dormant workspace.
 `
 )
 
 func schedules() *cobra.Command {
 	scheduleCmd := &cobra.Command{
 		Annotations: workspaceCommand,
-		Use:         "schedule { show | start | stop | override } <workspace>",
+		Use:         "schedule { show | start | stop | override | dormancy } <workspace>",
 		Short:       "Modify scheduled stop and start times for your workspace",
 	}
 
@@ -65,6 +71,7 @@ func schedules() *cobra.Command {
 		scheduleStart(),
 		scheduleStop(),
 		scheduleOverride(),
+		scheduleDormancy(),
 	)
 
 	return scheduleCmd
@@ -247,6 +254,54 @@ func scheduleOverride() *cobra.Command {
 	return overrideCmd
 }
 
+func scheduleDormancy() *cobra.Command {
+	return &cobra.Command{
+		Args: cobra.ExactArgs(2),
+		Use:  "dormancy <workspace-name> { on | off }",
+		Example: formatExamples(
+			example{
+				Description: "Keep the workspace from being marked dormant or deleted",
+				Command:     "coder schedule dormancy my-workspace off",
+			},
+		),
+		Short: "Opt workspace out of dormancy",
+		Long:  scheduleDormancyDescriptionLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var optOut bool
+			switch args[1] {
+			case "on":
+			case "off":
+				optOut = true
+			default:
+				return xerrors.Errorf("invalid dormancy %q: must be on or off", args[1])
+			}
+
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			workspace, err := namedWorkspace(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			err = client.UpdateWorkspaceDormancy(cmd.Context(), workspace.ID, codersdk.UpdateWorkspaceDormancyRequest{
+				OptOut: optOut,
+			})
+			if err != nil {
+				return err
+			}
+
+			updated, err := namedWorkspace(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			return displaySchedule(updated, cmd.OutOrStdout())
+		},
+	}
+}
+
 func displaySchedule(workspace codersdk.Workspace, out io.Writer) error {
 	loc, err := tz.TimezoneIANA()
 	if err != nil {
@@ -290,6 +345,18 @@ func displaySchedule(workspace codersdk.Workspace, out io.Writer) error {
 	tw.AppendRow(table.Row{"Starts next", schedNextStart})
 	tw.AppendRow(table.Row{"Stops at", schedStop})
 	tw.AppendRow(table.Row{"Stops next", schedNextStop})
+	if workspace.DormancyOptOut {
+		tw.AppendRow(table.Row{"Dormancy", "opted out"})
+	}
+	if workspace.DormantAt != nil {
+		tw.AppendRow(table.Row{"Dormant", "since " + workspace.DormantAt.In(loc).Format(timeFormat+" on "+dateFormat)})
+	}
+	if workspace.DormantAfter != nil {
+		tw.AppendRow(table.Row{"Dormant after", workspace.DormantAfter.In(loc).Format(timeFormat + " on " + dateFormat)})
+	}
+	if workspace.DeletingAt != nil {
+		tw.AppendRow(table.Row{"Deletes at", workspace.DeletingAt.In(loc).Format(timeFormat + " on " + dateFormat)})
+	}
 
 	_, _ = fmt.Fprintln(out, tw.Render())
 	return nil
@@ -211,6 +211,51 @@ func TestScheduleStop(t *testing.T) {
 	}
 }
 
+func TestScheduleDormancy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		client    = coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user      = coderdtest.CreateFirstUser(t, client)
+		version   = coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_         = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		project   = coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace = coderdtest.CreateWorkspace(t, client, user.OrganizationID, project.ID)
+		_         = coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		stdoutBuf = &bytes.Buffer{}
+	)
+
+	// Opt the workspace out of dormancy
+	cmd, root := clitest.New(t, "schedule", "dormancy", workspace.Name, "off")
+	clitest.SetupConfig(t, client, root)
+	cmd.SetOut(stdoutBuf)
+
+	err := cmd.Execute()
+	require.NoError(t, err, "unexpected error")
+	lines := strings.Split(strings.TrimSpace(stdoutBuf.String()), "\n")
+	if assert.Len(t, lines, 5) {
+		assert.Contains(t, lines[4], "Dormancy     opted out")
+	}
+	updated, err := client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.True(t, updated.DormancyOptOut)
+
+	// Opt the workspace back in
+	cmd, root = clitest.New(t, "schedule", "dormancy", workspace.Name, "on")
+	clitest.SetupConfig(t, client, root)
+	err = cmd.Execute()
+	require.NoError(t, err, "unexpected error")
+	updated, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.False(t, updated.DormancyOptOut)
+
+	cmd, root = clitest.New(t, "schedule", "dormancy", workspace.Name, "maybe")
+	clitest.SetupConfig(t, client, root)
+	err = cmd.Execute()
+	require.ErrorContains(t, err, "must be on or off")
+}
+
 func TestScheduleOverride(t *testing.T) {
 	t.Parallel()
 
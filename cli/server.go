@@ -36,6 +36,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"golang.org/x/exp/slices"
 	"golang.org/x/oauth2"
 	xgithub "golang.org/x/oauth2/github"
 	"golang.org/x/sync/errgroup"
@@ -49,14 +50,21 @@ import (
 	"github.com/coder/coder/cli/cliui"
 	"github.com/coder/coder/cli/config"
 	"github.com/coder/coder/coderd"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/audit/backends"
+	"github.com/coder/coder/coderd/audit/retention"
 	"github.com/coder/coder/coderd/autobuild/executor"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/databasefake"
 	"github.com/coder/coder/coderd/devtunnel"
+	"github.com/coder/coder/coderd/dormantusers"
 	"github.com/coder/coder/coderd/gitsshkey"
+	"github.com/coder/coder/coderd/oidc"
+	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/tracing"
 	"github.com/coder/coder/coderd/turnconn"
+	"github.com/coder/coder/coderd/userpassword"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/provisioner/terraform"
 	"github.com/coder/coder/provisionerd"
@@ -69,6 +77,14 @@ func server() *cobra.Command {
 	var (
 		accessURL             string
 		address               string
+		auditFilter           string
+		auditFilterFile       string
+		auditLogFile          string
+		auditRetention        time.Duration
+		auditRetentionOrgs    []string
+		auditWebhookURL       string
+		auditWebhookSecret    string
+		auditWebhookQueueSize int
 		autobuildPollInterval time.Duration
 		promEnabled           bool
 		promAddress           string
@@ -84,6 +100,17 @@ func server() *cobra.Command {
 		oauth2GithubAllowedOrganizations []string
 		oauth2GithubAllowedTeams         []string
 		oauth2GithubAllowSignups         bool
+		oauth2GithubTeamRoles            []string
+		oidcIssuerURL                    string
+		oidcClientID                     string
+		oidcClientSecret                 string
+		oidcScopes                       []string
+		oidcEmailDomains                 []string
+		oidcUsernameField                string
+		oidcEmailField                   string
+		oidcGroupsField                  string
+		oidcGroupRoles                   []string
+		oidcAllowSignups                 bool
 		telemetryEnable                  bool
 		telemetryURL                     string
 		tlsCertFile                      string
@@ -98,6 +125,17 @@ func server() *cobra.Command {
 		trace                            bool
 		secureAuthCookie                 bool
 		sshKeygenAlgorithmRaw            string
+		totpRequiredForAdmins            bool
+		scimAPIKey                       string
+		suspendDormantUsersAfter         time.Duration
+		dormancyWarningWebhookURL        string
+		passwordMinLength                int
+		passwordMinCharacterClasses      int
+		passwordBreachedList             string
+		passwordHistory                  int
+		loginMaxAttempts                 int
+		loginLockoutDuration             time.Duration
+		maxTokenLifetime                 time.Duration
 		spooky                           bool
 		verbose                          bool
 	)
@@ -262,6 +300,29 @@ func server() *cobra.Command {
 				TURNServer:           turnServer,
 				TracerProvider:       tracerProvider,
 				Telemetry:            telemetry.NewNoop(),
+
+				TOTPRequiredForAdmins: totpRequiredForAdmins,
+				SCIMAPIKey:            []byte(scimAPIKey),
+				PasswordPolicy: userpassword.Policy{
+					MinLength:           passwordMinLength,
+					MinCharacterClasses: passwordMinCharacterClasses,
+					History:             passwordHistory,
+				},
+				LoginMaxAttempts:     loginMaxAttempts,
+				LoginLockoutDuration: loginLockoutDuration,
+				MaxTokenLifetime:     maxTokenLifetime,
+			}
+
+			if passwordBreachedList != "" {
+				breachedFile, err := os.Open(passwordBreachedList)
+				if err != nil {
+					return xerrors.Errorf("open breached password list: %w", err)
+				}
+				options.PasswordPolicy.Breached, err = userpassword.ReadBreached(breachedFile)
+				_ = breachedFile.Close()
+				if err != nil {
+					return xerrors.Errorf("read breached password list: %w", err)
+				}
 			}
 
 			if oauth2GithubClientSecret != "" {
@@ -269,6 +330,26 @@ func server() *cobra.Command {
 				if err != nil {
 					return xerrors.Errorf("configure github oauth2: %w", err)
 				}
+				options.GithubOAuth2Config.TeamRoles, err = parseGroupRoles(oauth2GithubTeamRoles)
+				if err != nil {
+					return xerrors.Errorf("parse github team roles: %w", err)
+				}
+			}
+
+			if oidcIssuerURL != "" {
+				options.OIDCConfig, err = configureOIDC(cmd.Context(), accessURLParsed, oidcIssuerURL, oidcClientID, oidcClientSecret, oidcScopes)
+				if err != nil {
+					return xerrors.Errorf("configure oidc: %w", err)
+				}
+				options.OIDCConfig.AllowSignups = oidcAllowSignups
+				options.OIDCConfig.EmailDomains = oidcEmailDomains
+				options.OIDCConfig.UsernameField = oidcUsernameField
+				options.OIDCConfig.EmailField = oidcEmailField
+				options.OIDCConfig.GroupsField = oidcGroupsField
+				options.OIDCConfig.GroupRoles, err = parseGroupRoles(oidcGroupRoles)
+				if err != nil {
+					return xerrors.Errorf("parse oidc group roles: %w", err)
+				}
 			}
 
 			if inMemoryDatabase {
@@ -326,6 +407,7 @@ func server() *cobra.Command {
 					Logger:          logger.Named("telemetry"),
 					URL:             telemetryURL,
 					GitHubOAuth:     oauth2GithubClientID != "",
+					OIDCAuth:        oidcIssuerURL != "",
 					Prometheus:      promEnabled,
 					STUN:            len(stunServers) != 0,
 					Tunnel:          tunnel,
@@ -336,6 +418,48 @@ func server() *cobra.Command {
 				defer options.Telemetry.Close()
 			}
 
+			filter, err := parseAuditFilter(auditFilter, auditFilterFile)
+			if err != nil {
+				return err
+			}
+			auditRetentionByOrg, err := parseAuditRetention(auditRetentionOrgs)
+			if err != nil {
+				return err
+			}
+			if filter != audit.DefaultFilter || auditWebhookURL != "" || auditLogFile != "" {
+				auditBackends := []audit.Backend{backends.NewPostgres(options.Database, true)}
+				if auditWebhookURL != "" {
+					webhookURL, err := url.Parse(auditWebhookURL)
+					if err != nil {
+						return xerrors.Errorf("parse audit webhook url: %w", err)
+					}
+					webhook, err := backends.NewWebhook(backends.WebhookOptions{
+						URL:          webhookURL,
+						Secret:       []byte(auditWebhookSecret),
+						QueueDir:     filepath.Join(cacheDir, "audit-webhook-queue"),
+						MaxQueueSize: auditWebhookQueueSize,
+						Logger:       logger.Named("audit-webhook"),
+					})
+					if err != nil {
+						return xerrors.Errorf("create audit webhook backend: %w", err)
+					}
+					defer webhook.Close()
+					auditBackends = append(auditBackends, webhook)
+				}
+				if auditLogFile != "" {
+					file, err := backends.NewFile(backends.FileOptions{
+						Path:     auditLogFile,
+						Compress: true,
+					})
+					if err != nil {
+						return xerrors.Errorf("create audit file backend: %w", err)
+					}
+					defer file.Close()
+					auditBackends = append(auditBackends, file)
+				}
+				options.Auditor = audit.NewExporter(filter, auditBackends...)
+			}
+
 			coderAPI := coderd.New(options)
 			client := codersdk.New(localURL)
 			if tlsEnable {
@@ -426,9 +550,47 @@ func server() *cobra.Command {
 
 			autobuildPoller := time.NewTicker(autobuildPollInterval)
 			defer autobuildPoller.Stop()
-			autobuildExecutor := executor.New(cmd.Context(), options.Database, logger, autobuildPoller.C)
+			// Owners also see upcoming dormancy in the API and "coder list".
+			// The webhook lets admins notify owners who don't check them.
+			var deliverDormancyWarning func(executor.DormancyWarning)
+			if dormancyWarningWebhookURL != "" {
+				deliverDormancyWarning = executor.DormancyWarningWebhook(cmd.Context(), options.Database, logger.Named("dormancy-webhook"), &http.Client{
+					Timeout: 30 * time.Second,
+				}, dormancyWarningWebhookURL)
+			}
+			autobuildExecutor := executor.New(cmd.Context(), options.Database, logger, autobuildPoller.C).
+				WithDormancyWarnings(func(warning executor.DormancyWarning) {
+					logger.Warn(cmd.Context(), "workspace will become inactive",
+						slog.F("workspace_id", warning.WorkspaceID),
+						slog.F("owner_id", warning.OwnerID),
+						slog.F("action", warning.Kind),
+						slog.F("at", warning.At),
+					)
+					if deliverDormancyWarning != nil {
+						deliverDormancyWarning(warning)
+					}
+				}, executor.DormancyWarningCountdown...)
 			autobuildExecutor.Run()
 
+			if auditRetention > 0 || len(auditRetentionByOrg) > 0 {
+				auditRetentionPoller := time.NewTicker(time.Hour)
+				defer auditRetentionPoller.Stop()
+				retention.New(cmd.Context(), options.Database, logger.Named("audit-retention"), auditRetentionPoller.C, retention.Options{
+					MaxAge:             auditRetention,
+					OrganizationMaxAge: auditRetentionByOrg,
+					ArchiveDir:         filepath.Join(cacheDir, "audit-archive"),
+				}).Run()
+			}
+
+			if suspendDormantUsersAfter > 0 {
+				dormantUsersPoller := time.NewTicker(time.Hour)
+				defer dormantUsersPoller.Stop()
+				dormantusers.New(cmd.Context(), options.Database, logger.Named("dormant-users"), dormantUsersPoller.C, dormantusers.Options{
+					InactiveFor: suspendDormantUsersAfter,
+					Auditor:     options.Auditor,
+				}).Run()
+			}
+
 			// Because the graceful shutdown includes cleaning up workspaces in dev mode, we're
 			// going to make it harder to accidentally skip the graceful shutdown by hitting ctrl+c
 			// two or more times.  So the stopChan is unlimited in size and we don't call
@@ -512,6 +674,15 @@ func server() *cobra.Command {
 		},
 	})
 
+	cliflag.StringVarP(root.Flags(), &auditFilter, "audit-filter", "", "CODER_AUDIT_FILTER", "", "Specifies audit filter rules as YAML or JSON. Rules decide whether each audit log is dropped, only stored, only exported or both. Conflicts with --audit-filter-file.")
+	cliflag.StringVarP(root.Flags(), &auditFilterFile, "audit-filter-file", "", "CODER_AUDIT_FILTER_FILE", "", "Specifies a YAML or JSON file containing audit filter rules.")
+	cliflag.StringVarP(root.Flags(), &auditLogFile, "audit-log-file", "", "CODER_AUDIT_LOG_FILE", "", "Specifies a file to write audit logs to as JSON lines. The file is rotated once it reaches 100MB.")
+	cliflag.DurationVarP(root.Flags(), &auditRetention, "audit-retention", "", "CODER_AUDIT_RETENTION", 0, "Specifies how long audit logs are kept. Expired audit logs are archived to the cache directory and deleted hourly. Audit logs are kept forever if 0.")
+	cliflag.StringArrayVarP(root.Flags(), &auditRetentionOrgs, "audit-retention-organization", "", "CODER_AUDIT_RETENTION_ORGANIZATIONS", nil,
+		"Overrides --audit-retention for an organization, specified as \"<organization name or ID>=<duration>\". A duration of 0 keeps the organization's audit logs forever.")
+	cliflag.StringVarP(root.Flags(), &auditWebhookURL, "audit-webhook-url", "", "CODER_AUDIT_WEBHOOK_URL", "", "Specifies a URL that audit logs are POSTed to as JSON. Undelivered audit logs are queued in the cache directory and retried, except those rejected with a client error, which are appended to \"rejected.jsonl\" in the queue.")
+	cliflag.StringVarP(root.Flags(), &auditWebhookSecret, "audit-webhook-secret", "", "CODER_AUDIT_WEBHOOK_SECRET", "", "Specifies a secret used to sign audit webhook requests. The HMAC-SHA256 of the body is sent in the \"X-Coder-Audit-Signature\" header.")
+	cliflag.IntVarP(root.Flags(), &auditWebhookQueueSize, "audit-webhook-queue-size", "", "CODER_AUDIT_WEBHOOK_QUEUE_SIZE", 10000, "Specifies the maximum number of undelivered audit logs to queue for the audit webhook.")
 	cliflag.DurationVarP(root.Flags(), &autobuildPollInterval, "autobuild-poll-interval", "", "CODER_AUTOBUILD_POLL_INTERVAL", time.Minute, "Specifies the interval at which to poll for and execute automated workspace build operations.")
 	cliflag.StringVarP(root.Flags(), &accessURL, "access-url", "", "CODER_ACCESS_URL", "", "Specifies the external URL to access Coder.")
 	cliflag.StringVarP(root.Flags(), &address, "address", "a", "CODER_ADDRESS", "127.0.0.1:3000", "The address to serve the API and dashboard.")
@@ -540,6 +711,30 @@ func server() *cobra.Command {
 		"Specifies teams inside organizations the user must be a member of to authenticate with GitHub. Formatted as: <organization-name>/<team-slug>.")
 	cliflag.BoolVarP(root.Flags(), &oauth2GithubAllowSignups, "oauth2-github-allow-signups", "", "CODER_OAUTH2_GITHUB_ALLOW_SIGNUPS", false,
 		"Specifies whether new users can sign up with GitHub.")
+	cliflag.StringArrayVarP(root.Flags(), &oauth2GithubTeamRoles, "oauth2-github-team-roles", "", "CODER_OAUTH2_GITHUB_TEAM_ROLES", nil,
+		"Specifies roles granted to members of GitHub teams, synced on every login. Formatted as: <organization-name>/<team-slug>=<role>. "+
+			"Organization roles are formatted as <role>:<coder-organization>. Roles that are mapped are revoked from users outside the team.")
+	cliflag.StringVarP(root.Flags(), &oidcIssuerURL, "oidc-issuer-url", "", "CODER_OIDC_ISSUER_URL", "",
+		"Specifies the issuer URL of an OpenID Connect provider to authenticate with. The provider is configured from its discovery document.")
+	cliflag.StringVarP(root.Flags(), &oidcClientID, "oidc-client-id", "", "CODER_OIDC_CLIENT_ID", "",
+		"Specifies a client ID to use for OpenID Connect.")
+	cliflag.StringVarP(root.Flags(), &oidcClientSecret, "oidc-client-secret", "", "CODER_OIDC_CLIENT_SECRET", "",
+		"Specifies a client secret to use for OpenID Connect.")
+	cliflag.StringArrayVarP(root.Flags(), &oidcScopes, "oidc-scopes", "", "CODER_OIDC_SCOPES", []string{"openid", "profile", "email"},
+		"Specifies scopes to request from the OpenID Connect provider. The \"openid\" scope is always requested.")
+	cliflag.StringArrayVarP(root.Flags(), &oidcEmailDomains, "oidc-email-domain", "", "CODER_OIDC_EMAIL_DOMAIN", nil,
+		"Specifies email domains users must belong to in order to authenticate with OpenID Connect. All domains are allowed if empty.")
+	cliflag.StringVarP(root.Flags(), &oidcUsernameField, "oidc-username-field", "", "CODER_OIDC_USERNAME_FIELD", "preferred_username",
+		"Specifies the ID token claim used as the username of new users. The local part of the email address is used if the claim is missing.")
+	cliflag.StringVarP(root.Flags(), &oidcEmailField, "oidc-email-field", "", "CODER_OIDC_EMAIL_FIELD", "email",
+		"Specifies the ID token claim containing the user's email address.")
+	cliflag.StringVarP(root.Flags(), &oidcGroupsField, "oidc-groups-field", "", "CODER_OIDC_GROUPS_FIELD", "groups",
+		"Specifies the ID token claim listing the groups the user is a member of.")
+	cliflag.StringArrayVarP(root.Flags(), &oidcGroupRoles, "oidc-group-roles", "", "CODER_OIDC_GROUP_ROLES", nil,
+		"Specifies roles granted to members of OpenID Connect groups, synced on every login. Formatted as: <group>=<role>. "+
+			"Organization roles are formatted as <role>:<coder-organization>. Roles that are mapped are revoked from users outside the group.")
+	cliflag.BoolVarP(root.Flags(), &oidcAllowSignups, "oidc-allow-signups", "", "CODER_OIDC_ALLOW_SIGNUPS", true,
+		"Specifies whether new users can sign up with OpenID Connect.")
 	cliflag.BoolVarP(root.Flags(), &telemetryEnable, "telemetry", "", "CODER_TELEMETRY", true, "Specifies whether telemetry is enabled or not. Coder collects anonymized usage data to help improve our product.")
 	cliflag.StringVarP(root.Flags(), &telemetryURL, "telemetry-url", "", "CODER_TELEMETRY_URL", "https://telemetry.coder.com", "Specifies a URL to send telemetry to.")
 	_ = root.Flags().MarkHidden("telemetry-url")
@@ -568,6 +763,28 @@ func server() *cobra.Command {
 	cliflag.BoolVarP(root.Flags(), &secureAuthCookie, "secure-auth-cookie", "", "CODER_SECURE_AUTH_COOKIE", false, "Specifies if the 'Secure' property is set on browser session cookies")
 	cliflag.StringVarP(root.Flags(), &sshKeygenAlgorithmRaw, "ssh-keygen-algorithm", "", "CODER_SSH_KEYGEN_ALGORITHM", "ed25519", "Specifies the algorithm to use for generating ssh keys. "+
 		`Accepted values are "ed25519", "ecdsa", or "rsa4096"`)
+	cliflag.BoolVarP(root.Flags(), &totpRequiredForAdmins, "totp-required-for-admins", "", "CODER_TOTP_REQUIRED_FOR_ADMINS", false,
+		"Specifies if owners must use two-factor authentication when logging in with a password. Owners that haven't enrolled will be prompted to when they log in.")
+	cliflag.StringVarP(root.Flags(), &scimAPIKey, "scim-api-key", "", "CODER_SCIM_API_KEY", "",
+		"Enables SCIM provisioning at /api/v2/scim/v2. Identity providers must send this key as a bearer token.")
+	cliflag.StringVarP(root.Flags(), &dormancyWarningWebhookURL, "dormancy-warning-webhook-url", "", "CODER_DORMANCY_WARNING_WEBHOOK_URL", "",
+		"Specifies a URL that warnings are POSTed to as JSON before workspaces are marked dormant or deleted, with the username and email of their owner.")
+	cliflag.DurationVarP(root.Flags(), &suspendDormantUsersAfter, "suspend-dormant-users-after", "", "CODER_SUSPEND_DORMANT_USERS_AFTER", 0,
+		"Specifies how long users can go without using Coder before they're suspended and their workspaces are stopped. Checked hourly. Owners are never suspended. Users are never suspended if 0.")
+	cliflag.IntVarP(root.Flags(), &passwordMinLength, "password-min-length", "", "CODER_PASSWORD_MIN_LENGTH", 8,
+		"Specifies the minimum length of passwords.")
+	cliflag.IntVarP(root.Flags(), &passwordMinCharacterClasses, "password-min-character-classes", "", "CODER_PASSWORD_MIN_CHARACTER_CLASSES", 0,
+		"Specifies how many of lowercase letters, uppercase letters, digits, and symbols passwords must contain.")
+	cliflag.StringVarP(root.Flags(), &passwordBreachedList, "password-breached-list", "", "CODER_PASSWORD_BREACHED_LIST", "",
+		"Specifies the path to a file of known-breached passwords, one per line, that can't be used.")
+	cliflag.IntVarP(root.Flags(), &passwordHistory, "password-history", "", "CODER_PASSWORD_HISTORY", 0,
+		"Specifies how many previous passwords, including the current one, can't be reused when changing a password.")
+	cliflag.IntVarP(root.Flags(), &loginMaxAttempts, "login-max-attempts", "", "CODER_LOGIN_MAX_ATTEMPTS", 0,
+		"Specifies how many failed password logins in a row lock a user out. Users are never locked out if 0.")
+	cliflag.DurationVarP(root.Flags(), &loginLockoutDuration, "login-lockout-duration", "", "CODER_LOGIN_LOCKOUT_DURATION", 15*time.Minute,
+		"Specifies how long users are locked out after too many failed password logins. Failed logins older than this are forgotten.")
+	cliflag.DurationVarP(root.Flags(), &maxTokenLifetime, "max-token-lifetime", "", "CODER_MAX_TOKEN_LIFETIME", 365*24*time.Hour,
+		"Specifies the longest lifetime users can give the tokens they create.")
 	cliflag.BoolVarP(root.Flags(), &spooky, "spooky", "", "", false, "Specifies spookiness level")
 	cliflag.BoolVarP(root.Flags(), &verbose, "verbose", "v", "CODER_VERBOSE", false, "Enables verbose logging.")
 	_ = root.Flags().MarkHidden("spooky")
@@ -762,13 +979,43 @@ func configureGithubOAuth2(accessURL *url.URL, clientID, clientSecret string, al
 			return emails, err
 		},
 		ListOrganizationMemberships: func(ctx context.Context, client *http.Client) ([]*github.Membership, error) {
-			memberships, _, err := github.NewClient(client).Organizations.ListOrgMemberships(ctx, &github.ListOrgMembershipsOptions{
+			opts := &github.ListOrgMembershipsOptions{
 				State: "active",
 				ListOptions: github.ListOptions{
 					PerPage: 100,
 				},
-			})
-			return memberships, err
+			}
+			var memberships []*github.Membership
+			for {
+				page, resp, err := github.NewClient(client).Organizations.ListOrgMemberships(ctx, opts)
+				if err != nil {
+					return nil, err
+				}
+				memberships = append(memberships, page...)
+				if resp.NextPage == 0 {
+					return memberships, nil
+				}
+				opts.Page = resp.NextPage
+			}
+		},
+		ListTeams: func(ctx context.Context, client *http.Client) ([]*github.Team, error) {
+			// Users may be on more teams than fit on a page, and any of them
+			// could grant access or roles.
+			opts := &github.ListOptions{
+				PerPage: 100,
+			}
+			var teams []*github.Team
+			for {
+				page, resp, err := github.NewClient(client).Teams.ListUserTeams(ctx, opts)
+				if err != nil {
+					return nil, err
+				}
+				teams = append(teams, page...)
+				if resp.NextPage == 0 {
+					return teams, nil
+				}
+				opts.Page = resp.NextPage
+			}
 		},
 		Team: func(ctx context.Context, client *http.Client, org, teamSlug string) (*github.Team, error) {
 			team, _, err := github.NewClient(client).Teams.GetTeamBySlug(ctx, org, teamSlug)
@@ -777,6 +1024,113 @@ func configureGithubOAuth2(accessURL *url.URL, clientID, clientSecret string, al
 	}, nil
 }
 
+// parseAuditFilter returns the audit filter configured by the --audit-filter
+// or --audit-filter-file flags, or audit.DefaultFilter if neither is set.
+func parseAuditFilter(rawConfig, configFile string) (audit.Filter, error) {
+	if rawConfig != "" && configFile != "" {
+		return nil, xerrors.New("only one of --audit-filter and --audit-filter-file can be set")
+	}
+	data := []byte(rawConfig)
+	if configFile != "" {
+		var err error
+		data, err = os.ReadFile(configFile)
+		if err != nil {
+			return nil, xerrors.Errorf("read audit filter file: %w", err)
+		}
+	}
+	if len(data) == 0 {
+		return audit.DefaultFilter, nil
+	}
+
+	config, err := audit.ParseFilterConfig(data)
+	if err != nil {
+		return nil, xerrors.Errorf("parse audit filter: %w", err)
+	}
+	filter, err := audit.NewRuleFilter(config)
+	if err != nil {
+		return nil, xerrors.Errorf("create audit filter: %w", err)
+	}
+	return filter, nil
+}
+
+// parseAuditRetention parses "<organization>=<duration>" pairs into a map of
+// organization name or ID to maximum audit log age.
+func parseAuditRetention(values []string) (map[string]time.Duration, error) {
+	maxAges := make(map[string]time.Duration, len(values))
+	for _, value := range values {
+		organization, rawDuration, ok := strings.Cut(value, "=")
+		if !ok || organization == "" {
+			return nil, xerrors.Errorf("invalid audit retention %q, must be \"<organization>=<duration>\"", value)
+		}
+		duration, err := time.ParseDuration(rawDuration)
+		if err != nil {
+			return nil, xerrors.Errorf("parse audit retention for organization %q: %w", organization, err)
+		}
+		maxAges[organization] = duration
+	}
+	return maxAges, nil
+}
+
+func configureOIDC(ctx context.Context, accessURL *url.URL, issuerURL, clientID, clientSecret string, scopes []string) (*coderd.OIDCConfig, error) {
+	if clientID == "" {
+		return nil, xerrors.New("oidc client id must be provided")
+	}
+	redirectURL, err := accessURL.Parse("/api/v2/users/oidc/callback")
+	if err != nil {
+		return nil, xerrors.Errorf("parse oidc callback url: %w", err)
+	}
+	provider, err := oidc.Discover(ctx, nil, issuerURL)
+	if err != nil {
+		return nil, xerrors.Errorf("discover oidc provider: %w", err)
+	}
+	if !slices.Contains(scopes, oidc.ScopeOpenID) {
+		scopes = append([]string{oidc.ScopeOpenID}, scopes...)
+	}
+	return &coderd.OIDCConfig{
+		OAuth2Config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  redirectURL.String(),
+			Scopes:       scopes,
+		},
+		Verifier: provider.Verifier(clientID),
+	}, nil
+}
+
+// parseGroupRoles parses "<group>=<role>" mappings of identity provider groups
+// to Coder roles.
+func parseGroupRoles(rawGroupRoles []string) (coderd.GroupRoles, error) {
+	if len(rawGroupRoles) == 0 {
+		return nil, nil
+	}
+	groupRoles := coderd.GroupRoles{}
+	for _, rawGroupRole := range rawGroupRoles {
+		// Groups may contain "=", but role names can't.
+		index := strings.LastIndex(rawGroupRole, "=")
+		if index <= 0 {
+			return nil, xerrors.Errorf("group role is formatted incorrectly. got %s; wanted <group>=<role>", rawGroupRole)
+		}
+		group, role := rawGroupRole[:index], rawGroupRole[index+1:]
+		// Organizations are referenced by name, so organization roles are
+		// validated against a placeholder ID.
+		validate := role
+		organization, isOrgRole := rbac.IsOrgRole(role)
+		if isOrgRole {
+			validate = strings.TrimSuffix(role, organization) + uuid.Nil.String()
+		}
+		rbacRole, err := rbac.RoleByName(validate)
+		if err != nil {
+			return nil, xerrors.Errorf("%q is not a supported role", role)
+		}
+		if isOrgRole && len(rbacRole.Org) == 0 {
+			return nil, xerrors.Errorf("%q is not an organization role", role)
+		}
+		groupRoles[group] = append(groupRoles[group], role)
+	}
+	return groupRoles, nil
+}
+
 func serveHandler(ctx context.Context, logger slog.Logger, handler http.Handler, addr, name string) (closeFunc func()) {
 	logger.Debug(ctx, "http server listening", slog.F("addr", addr), slog.F("name", name))
 
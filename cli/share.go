@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func share() *cobra.Command {
+	var (
+		role   string
+		revoke bool
+	)
+	cmd := &cobra.Command{
+		Annotations: workspaceCommand,
+		Use:         "share <workspace> <username|user_id>",
+		Short:       "Share a workspace with another user, or revoke their access",
+		Args:        cobra.ExactArgs(2),
+		Example: formatExamples(
+			example{
+				Description: "Let a user open the applications of a workspace",
+				Command:     "coder share my-workspace alice",
+			},
+			example{
+				Description: "Let a user connect to a workspace over SSH or a terminal",
+				Command:     "coder share my-workspace alice --role full",
+			},
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			workspace, err := namedWorkspace(cmd, client, args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			if revoke {
+				err = client.DeleteWorkspaceShare(cmd.Context(), workspace.ID, args[1])
+				if err != nil {
+					return xerrors.Errorf("revoke share: %w", err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s can no longer access %s.\n",
+					cliui.Styles.Keyword.Render(args[1]), cliui.Styles.Keyword.Render(workspace.Name))
+				return nil
+			}
+
+			shared, err := client.ShareWorkspace(cmd.Context(), workspace.ID, args[1], codersdk.ShareWorkspaceRequest{
+				Role: codersdk.WorkspaceShareRole(role),
+			})
+			if err != nil {
+				return xerrors.Errorf("share workspace: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s has been shared with %s (%s).\n",
+				cliui.Styles.Keyword.Render(workspace.Name), cliui.Styles.Keyword.Render(shared.User.Username), shared.Role)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&role, "role", string(codersdk.WorkspaceShareRoleRead), "Specify the access to give: read or full.")
+	cmd.Flags().BoolVar(&revoke, "revoke", false, "Revoke the access the user was given instead.")
+	return cmd
+}
@@ -0,0 +1,64 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestShare(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	memberUser, err := member.User(context.Background(), codersdk.Me)
+	require.NoError(t, err)
+
+	//nolint:paralleltest
+	t.Run("Share", func(t *testing.T) {
+		cmd, root := clitest.New(t, "share", workspace.Name, memberUser.Username, "--role", "full")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		shares, err := client.WorkspaceShares(context.Background(), workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, shares, 1)
+		require.Equal(t, codersdk.WorkspaceShareRoleFull, shares[0].Role)
+	})
+
+	//nolint:paralleltest
+	t.Run("Show", func(t *testing.T) {
+		cmd, root := clitest.New(t, "show", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), memberUser.Username)
+		require.Contains(t, buf.String(), "full")
+	})
+
+	//nolint:paralleltest
+	t.Run("Revoke", func(t *testing.T) {
+		cmd, root := clitest.New(t, "share", workspace.Name, memberUser.Username, "--revoke")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		shares, err := client.WorkspaceShares(context.Background(), workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares)
+	})
+}
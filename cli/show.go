@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 	"golang.org/x/xerrors"
 
@@ -26,9 +29,27 @@ func show() *cobra.Command {
 			if err != nil {
 				return xerrors.Errorf("get workspace resources: %w", err)
 			}
-			return cliui.WorkspaceResources(cmd.OutOrStdout(), resources, cliui.WorkspaceResourcesOptions{
+			err = cliui.WorkspaceResources(cmd.OutOrStdout(), resources, cliui.WorkspaceResourcesOptions{
 				WorkspaceName: workspace.Name,
 			})
+			if err != nil {
+				return err
+			}
+
+			shares, err := client.WorkspaceShares(cmd.Context(), workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("get workspace shares: %w", err)
+			}
+			if len(shares) == 0 {
+				return nil
+			}
+			tableWriter := cliui.Table()
+			tableWriter.AppendHeader(table.Row{"Shared with", "Role"})
+			for _, share := range shares {
+				tableWriter.AppendRow(table.Row{share.User.Username, share.Role})
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), tableWriter.Render())
+			return err
 		},
 	}
 }
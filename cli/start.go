@@ -33,12 +33,22 @@ func start() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if workspace.DormantAt != nil {
+				_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+					Text:      fmt.Sprintf("The workspace has been dormant since %s because it was stopped for a long time. Start it anyway?", workspace.DormantAt.Format(time.Stamp)),
+					IsConfirm: true,
+				})
+				if err != nil {
+					return err
+				}
+			}
 			before := time.Now()
 			build, err := client.CreateWorkspaceBuild(cmd.Context(), workspace.ID, codersdk.CreateWorkspaceBuildRequest{
-				Transition: codersdk.WorkspaceTransitionStart,
+				Transition:     codersdk.WorkspaceTransitionStart,
+				ConfirmDormant: workspace.DormantAt != nil,
 			})
 			if err != nil {
-				return err
+				return quotaError(err)
 			}
 
 			err = cliui.WorkspaceBuild(cmd.Context(), cmd.OutOrStdout(), client, build.ID, before)
@@ -27,6 +27,13 @@ func templateCreate() *cobra.Command {
 		parameterFile        string
 		maxTTL               time.Duration
 		minAutostartInterval time.Duration
+		costUnits            int32
+		inactivityTTL        time.Duration
+		dormantDeletionTTL   time.Duration
+		defaultAutostart     string
+		defaultTTL           time.Duration
+		requiredStop         string
+		allowScheduleEdits   bool
 	)
 	cmd := &cobra.Command{
 		Use:   "create [name]",
@@ -43,6 +50,15 @@ func templateCreate() *cobra.Command {
 				return err
 			}
 
+			defaultAutostartSchedule, err := parseTemplateSchedule(defaultAutostart, true)
+			if err != nil {
+				return err
+			}
+			requiredStopSchedule, err := parseTemplateSchedule(requiredStop, false)
+			if err != nil {
+				return err
+			}
+
 			var templateName string
 			if len(args) == 0 {
 				templateName = filepath.Base(directory)
@@ -106,6 +122,13 @@ func templateCreate() *cobra.Command {
 				VersionID:                  job.ID,
 				MaxTTLMillis:               ptr.Ref(maxTTL.Milliseconds()),
 				MinAutostartIntervalMillis: ptr.Ref(minAutostartInterval.Milliseconds()),
+				CostUnits:                  costUnits,
+				InactivityTTLMillis:        ptr.Ref(inactivityTTL.Milliseconds()),
+				DormantDeletionTTLMillis:   ptr.Ref(dormantDeletionTTL.Milliseconds()),
+				DefaultAutostartSchedule:   ptr.Ref(defaultAutostartSchedule),
+				DefaultTTLMillis:           ptr.Ref(defaultTTL.Milliseconds()),
+				RequiredStopSchedule:       ptr.Ref(requiredStopSchedule),
+				AllowUserScheduleOverride:  ptr.Ref(allowScheduleEdits),
 			}
 
 			_, err = client.CreateTemplate(cmd.Context(), organization.ID, createReq)
@@ -129,6 +152,13 @@ func templateCreate() *cobra.Command {
 	cmd.Flags().StringVarP(&parameterFile, "parameter-file", "", "", "Specify a file path with parameter values.")
 	cmd.Flags().DurationVarP(&maxTTL, "max-ttl", "", 24*time.Hour, "Specify a maximum TTL for workspaces created from this template.")
 	cmd.Flags().DurationVarP(&minAutostartInterval, "min-autostart-interval", "", time.Hour, "Specify a minimum autostart interval for workspaces created from this template.")
+	cmd.Flags().Int32VarP(&costUnits, "cost-units", "", 0, "Specify how much running workspaces of this template count toward quotas on cost units.")
+	cmd.Flags().DurationVarP(&inactivityTTL, "inactivity-ttl", "", 0, "Specify how long workspaces created from this template can stay stopped before they're marked dormant. Zero disables dormancy.")
+	cmd.Flags().DurationVarP(&dormantDeletionTTL, "dormant-deletion-ttl", "", 0, "Specify how long workspaces created from this template can stay dormant before they're deleted. Zero disables automatic deletion.")
+	cmd.Flags().StringVarP(&defaultAutostart, "default-autostart", "", "", "Specify the autostart schedule of workspaces created from this template. Check `coder schedule start --help` for the syntax.")
+	cmd.Flags().DurationVarP(&defaultTTL, "default-ttl", "", 0, "Specify a duration after which workspaces created from this template shut down. Zero uses the default of the server.")
+	cmd.Flags().StringVarP(&requiredStop, "required-stop", "", "", "Specify a schedule at which workspaces created from this template are always stopped, in the timezone of their owner (e.g. \"2:00AM\" or \"2:00AM Mon-Fri\").")
+	cmd.Flags().BoolVarP(&allowScheduleEdits, "allow-user-schedule-override", "", true, "Specify whether users can change the autostart schedule and TTL of workspaces created from this template.")
 	// This is for testing!
 	err := cmd.Flags().MarkHidden("test.provisioner")
 	if err != nil {
@@ -334,3 +364,20 @@ func prettyDirectoryPath(dir string) string {
 	}
 	return pretty
 }
+
+// parseTemplateSchedule parses a schedule given to template commands in the
+// syntax of `coder schedule start`. Schedules without a timezone are evaluated
+// in the timezone of each workspace owner.
+func parseTemplateSchedule(spec string, withTimezone bool) (string, error) {
+	if spec == "" {
+		return "", nil
+	}
+	sched, err := parseCLISchedule(spec)
+	if err != nil {
+		return "", err
+	}
+	if withTimezone {
+		return sched.String(), nil
+	}
+	return sched.Cron(), nil
+}
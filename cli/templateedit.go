@@ -8,6 +8,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -16,6 +17,13 @@ func templateEdit() *cobra.Command {
 		description          string
 		maxTTL               time.Duration
 		minAutostartInterval time.Duration
+		costUnits            int32
+		inactivityTTL        time.Duration
+		dormantDeletionTTL   time.Duration
+		defaultAutostart     string
+		defaultTTL           time.Duration
+		requiredStop         string
+		allowScheduleEdits   bool
 	)
 
 	cmd := &cobra.Command{
@@ -42,6 +50,35 @@ func templateEdit() *cobra.Command {
 				MaxTTLMillis:               maxTTL.Milliseconds(),
 				MinAutostartIntervalMillis: minAutostartInterval.Milliseconds(),
 			}
+			if cmd.Flags().Changed("cost_units") {
+				req.CostUnits = &costUnits
+			}
+			if cmd.Flags().Changed("inactivity_ttl") {
+				req.InactivityTTLMillis = ptr.Ref(inactivityTTL.Milliseconds())
+			}
+			if cmd.Flags().Changed("dormant_deletion_ttl") {
+				req.DormantDeletionTTLMillis = ptr.Ref(dormantDeletionTTL.Milliseconds())
+			}
+			if cmd.Flags().Changed("default_autostart") {
+				sched, err := parseTemplateSchedule(defaultAutostart, true)
+				if err != nil {
+					return err
+				}
+				req.DefaultAutostartSchedule = &sched
+			}
+			if cmd.Flags().Changed("default_ttl") {
+				req.DefaultTTLMillis = ptr.Ref(defaultTTL.Milliseconds())
+			}
+			if cmd.Flags().Changed("required_stop") {
+				sched, err := parseTemplateSchedule(requiredStop, false)
+				if err != nil {
+					return err
+				}
+				req.RequiredStopSchedule = &sched
+			}
+			if cmd.Flags().Changed("allow_user_schedule_override") {
+				req.AllowUserScheduleOverride = &allowScheduleEdits
+			}
 
 			_, err = client.UpdateTemplateMeta(cmd.Context(), template.ID, req)
 			if err != nil {
@@ -55,6 +92,13 @@ func templateEdit() *cobra.Command {
 	cmd.Flags().StringVarP(&description, "description", "", "", "Edit the template description")
 	cmd.Flags().DurationVarP(&maxTTL, "max_ttl", "", 0, "Edit the template maximum time before shutdown")
 	cmd.Flags().DurationVarP(&minAutostartInterval, "min_autostart_interval", "", 0, "Edit the template minimum autostart interval")
+	cmd.Flags().Int32VarP(&costUnits, "cost_units", "", 0, "Edit how much running workspaces of the template count toward quotas")
+	cmd.Flags().DurationVarP(&inactivityTTL, "inactivity_ttl", "", 0, "Edit how long workspaces can stay stopped before they're marked dormant. Zero disables dormancy")
+	cmd.Flags().DurationVarP(&dormantDeletionTTL, "dormant_deletion_ttl", "", 0, "Edit how long workspaces can stay dormant before they're deleted. Zero disables automatic deletion")
+	cmd.Flags().StringVarP(&defaultAutostart, "default_autostart", "", "", "Edit the autostart schedule of new workspaces of the template. Empty disables it")
+	cmd.Flags().DurationVarP(&defaultTTL, "default_ttl", "", 0, "Edit the time before shutdown of new workspaces of the template. Zero uses the default of the server")
+	cmd.Flags().StringVarP(&requiredStop, "required_stop", "", "", "Edit the schedule at which workspaces of the template are always stopped, in the timezone of their owner. Empty disables it")
+	cmd.Flags().BoolVarP(&allowScheduleEdits, "allow_user_schedule_override", "", true, "Edit whether users can change the autostart schedule and time before shutdown of their workspaces")
 	cliui.AllowSkipPrompt(cmd)
 
 	return cmd
@@ -56,6 +56,37 @@ func TestTemplateEdit(t *testing.T) {
 		assert.Equal(t, minAutostartInterval.Milliseconds(), updated.MinAutostartIntervalMillis)
 	})
 
+	t.Run("Schedules", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		cmdArgs := []string{
+			"templates",
+			"edit",
+			template.Name,
+			"--default_autostart", "9:30AM Mon-Fri Europe/Dublin",
+			"--default_ttl", "4h",
+			"--required_stop", "2:00AM",
+			"--allow_user_schedule_override=false",
+		}
+		cmd, root := clitest.New(t, cmdArgs...)
+		clitest.SetupConfig(t, client, root)
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		updated, err := client.Template(context.Background(), template.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "CRON_TZ=Europe/Dublin 30 9 * * Mon-Fri", updated.DefaultAutostartSchedule)
+		assert.Equal(t, (4 * time.Hour).Milliseconds(), updated.DefaultTTLMillis)
+		assert.Equal(t, "0 2 * * *", updated.RequiredStopSchedule)
+		assert.False(t, updated.AllowUserScheduleOverride)
+	})
+
 	t.Run("NotModified", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func tokens() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "tokens",
+		Short:   "Manage personal access tokens",
+		Aliases: []string{"token"},
+		Example: formatExamples(
+			example{
+				Description: "Create a token for automation",
+				Command:     "coder tokens create --name ci",
+			},
+			example{
+				Description: "List your tokens",
+				Command:     "coder tokens ls",
+			},
+			example{
+				Description: "Remove a token by name or ID",
+				Command:     "coder tokens rm ci",
+			},
+		),
+	}
+	cmd.AddCommand(
+		createToken(),
+		listTokens(),
+		removeToken(),
+	)
+	return cmd
+}
+
+func createToken() *cobra.Command {
+	var (
+		name     string
+		lifetime time.Duration
+		scopes   []string
+	)
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a token. It's only displayed once, so store it somewhere safe",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			res, err := client.CreateToken(cmd.Context(), codersdk.Me, codersdk.CreateTokenRequest{
+				TokenName:       name,
+				LifetimeSeconds: int64(lifetime.Seconds()),
+				Scopes:          scopes,
+			})
+			if err != nil {
+				return xerrors.Errorf("create token: %w", err)
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), res.Key)
+			return err
+		},
+	}
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Specify a name to identify the token by.")
+	cmd.Flags().DurationVar(&lifetime, "lifetime", 0, "Specify how long the token is valid for. Defaults to 30 days, or the server's maximum token lifetime if that's shorter.")
+	cmd.Flags().StringArrayVar(&scopes, "scope", nil, `Restrict the token's permissions, e.g. "workspace:read" or "template:push". Tokens are unrestricted by default.`)
+	return cmd
+}
+
+func listTokens() *cobra.Command {
+	var (
+		all     bool
+		columns []string
+	)
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List your tokens",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			keys, err := client.APIKeys(cmd.Context(), codersdk.Me)
+			if err != nil {
+				return xerrors.Errorf("list tokens: %w", err)
+			}
+			if !all {
+				tokens := make([]codersdk.APIKey, 0, len(keys))
+				for _, key := range keys {
+					if key.LoginType == codersdk.LoginTypeToken {
+						tokens = append(tokens, key)
+					}
+				}
+				keys = tokens
+			}
+			if len(keys) == 0 {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), cliui.Styles.Prompt.String()+"No tokens found! Create one:")
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "  "+cliui.Styles.Code.Render("coder tokens create"))
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+				return nil
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), displayTokens(columns, keys...))
+			return err
+		},
+	}
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Include the sessions created by logging in.")
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"id", "name", "last used", "ip address", "expires at", "scopes"},
+		"Specify a column to filter in the table. Available columns are: id, name, login type, last used, ip address, expires at, created at, scopes.")
+	return cmd
+}
+
+func removeToken() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove <name|id>",
+		Aliases: []string{"rm", "delete"},
+		Short:   "Remove a token",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			keys, err := client.APIKeys(cmd.Context(), codersdk.Me)
+			if err != nil {
+				return xerrors.Errorf("list tokens: %w", err)
+			}
+			var key *codersdk.APIKey
+			for i := range keys {
+				if keys[i].ID == args[0] || (keys[i].TokenName != "" && keys[i].TokenName == args[0]) {
+					key = &keys[i]
+					break
+				}
+			}
+			if key == nil {
+				return xerrors.Errorf("no token found with the name or ID %q", args[0])
+			}
+
+			err = client.DeleteAPIKey(cmd.Context(), codersdk.Me, key.ID)
+			if err != nil {
+				return xerrors.Errorf("remove token: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Token %s has been removed!\n", cliui.Styles.Keyword.Render(args[0]))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// displayTokens will return a table displaying all API keys passed in.
+// filterColumns must be a subset of the key fields and will determine which
+// columns to display
+func displayTokens(filterColumns []string, keys ...codersdk.APIKey) string {
+	tableWriter := cliui.Table()
+	header := table.Row{"id", "name", "login type", "last used", "ip address", "expires at", "created at", "scopes"}
+	tableWriter.AppendHeader(header)
+	tableWriter.SetColumnConfigs(cliui.FilterTableColumns(header, filterColumns))
+	for _, key := range keys {
+		tableWriter.AppendRow(table.Row{
+			key.ID,
+			key.TokenName,
+			key.LoginType,
+			key.LastUsed.Format(time.Stamp),
+			key.IPAddress,
+			key.ExpiresAt.Format(time.Stamp),
+			key.CreatedAt.Format(time.Stamp),
+			strings.Join(key.Scopes, ", "),
+		})
+	}
+	return tableWriter.Render()
+}
@@ -0,0 +1,88 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestTokens(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	//nolint:paralleltest
+	t.Run("Create", func(t *testing.T) {
+		cmd, root := clitest.New(t, "tokens", "create", "--name", "ci", "--scope", "workspace:read")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		key, err := client.GetAPIKey(context.Background(), codersdk.Me, strings.Split(strings.TrimSpace(buf.String()), "-")[0])
+		require.NoError(t, err)
+		require.Equal(t, "ci", key.TokenName)
+		require.Equal(t, []string{"workspace:read"}, key.Scopes)
+	})
+
+	//nolint:paralleltest
+	t.Run("List", func(t *testing.T) {
+		cmd, root := clitest.New(t, "tokens", "list")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		err := cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "ci")
+		require.Contains(t, buf.String(), "workspace:read")
+	})
+
+	//nolint:paralleltest
+	t.Run("Remove", func(t *testing.T) {
+		cmd, root := clitest.New(t, "tokens", "remove", "ci")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		keys, err := client.APIKeys(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		for _, key := range keys {
+			require.NotEqual(t, "ci", key.TokenName)
+		}
+	})
+
+	//nolint:paralleltest
+	t.Run("RemoveNotFound", func(t *testing.T) {
+		cmd, root := clitest.New(t, "tokens", "remove", "ci")
+		clitest.SetupConfig(t, client, root)
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "no token found")
+	})
+}
+
+func TestUserRevokeSessions(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	admin := coderdtest.CreateFirstUser(t, client)
+	other := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	otherUser, err := other.User(context.Background(), codersdk.Me)
+	require.NoError(t, err)
+
+	cmd, root := clitest.New(t, "users", "revoke-sessions", otherUser.Username)
+	clitest.SetupConfig(t, client, root)
+	// Yes to the prompt
+	cmd.SetIn(bytes.NewReader([]byte("yes\n")))
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	_, err = other.User(context.Background(), codersdk.Me)
+	require.Error(t, err)
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func totp() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "totp",
+		Short: "Manage two-factor authentication for password logins",
+		Example: formatExamples(
+			example{
+				Description: "Enroll in two-factor authentication",
+				Command:     "coder totp enroll",
+			},
+			example{
+				Description: "Check whether two-factor authentication is enabled",
+				Command:     "coder totp status",
+			},
+		),
+	}
+	cmd.AddCommand(
+		totpStatus(),
+		totpEnroll(),
+		totpDisable(),
+	)
+	return cmd
+}
+
+func totpStatus() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether two-factor authentication is enabled",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			status, err := client.TOTP(cmd.Context(), codersdk.Me)
+			if err != nil {
+				return xerrors.Errorf("get totp: %w", err)
+			}
+			if !status.Enabled {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Two-factor authentication is disabled. Enable it with "+cliui.Styles.Code.Render("coder totp enroll"))
+				return nil
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Two-factor authentication is enabled with %d recovery codes remaining.\n", status.RecoveryCodesRemaining)
+			return nil
+		},
+	}
+}
+
+func totpEnroll() *cobra.Command {
+	return &cobra.Command{
+		Use:   "enroll",
+		Short: "Enroll in two-factor authentication with an authenticator app",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			enrollment, err := client.EnrollTOTP(cmd.Context(), codersdk.Me)
+			if err != nil {
+				return xerrors.Errorf("enroll totp: %w", err)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), cliui.Styles.Paragraph.Render("Add this secret to your authenticator app:"))
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  "+cliui.Styles.Code.Render(enrollment.Secret))
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), cliui.Styles.Paragraph.Render("Or open this URL with it:"))
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  "+enrollment.URL)
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+
+			var codes codersdk.TOTPRecoveryCodes
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text: "Enter a " + cliui.Styles.Field.Render("two-factor code") + " from the app:",
+				Validate: func(code string) error {
+					codes, err = client.VerifyTOTP(cmd.Context(), codersdk.Me, codersdk.VerifyTOTPRequest{
+						Code: code,
+					})
+					if err != nil {
+						return xerrors.New("That code is invalid! Make sure your clock is accurate.")
+					}
+					return nil
+				},
+			})
+			if err != nil {
+				return xerrors.Errorf("two-factor code prompt: %w", err)
+			}
+			displayRecoveryCodes(cmd, codes.RecoveryCodes)
+			return nil
+		},
+	}
+}
+
+func totpDisable() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "Disable two-factor authentication and remove your recovery codes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text:      "Are you sure you want to disable two-factor authentication?",
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+			// A code is required, so a stolen session can't be used to
+			// remove the second factor.
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text:   "Enter a " + cliui.Styles.Field.Render("two-factor code") + " or recovery code:",
+				Secret: true,
+				Validate: func(code string) error {
+					err = client.ResetTOTP(cmd.Context(), codersdk.Me, codersdk.ResetTOTPRequest{
+						Code: code,
+					})
+					if err != nil {
+						return xerrors.Errorf("disable totp: %w", err)
+					}
+					return nil
+				},
+			})
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Two-factor authentication has been disabled!")
+			return nil
+		},
+	}
+}
+
+// userResetTOTP removes the second factor of a user that has lost access to
+// their authenticator app and recovery codes.
+func userResetTOTP() *cobra.Command {
+	var columns []string
+	cmd := &cobra.Command{
+		Use:   "reset-totp <username|user_id>",
+		Short: "Reset the two-factor authentication of a user. The user will be able to log in with only their password",
+		Args:  cobra.ExactArgs(1),
+		Example: formatExamples(
+			example{
+				Command: "coder users reset-totp example_user",
+			},
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			user, err := client.User(cmd.Context(), args[0])
+			if err != nil {
+				return xerrors.Errorf("fetch user: %w", err)
+			}
+
+			// Display the user
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), displayUsers(columns, user))
+
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text:      "Are you sure you want to reset two-factor authentication for this user?",
+				IsConfirm: true,
+				Default:   cliui.ConfirmYes,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.ResetTOTP(cmd.Context(), user.ID.String(), codersdk.ResetTOTPRequest{})
+			if err != nil {
+				return xerrors.Errorf("reset totp: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nTwo-factor authentication for %s has been reset!\n", cliui.Styles.Keyword.Render(user.Username))
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"username", "email", "created_at", "status"},
+		"Specify a column to filter in the table.")
+	return cmd
+}
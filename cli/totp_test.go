@@ -0,0 +1,126 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/totp"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/pty/ptytest"
+)
+
+func TestTOTP(t *testing.T) {
+	t.Parallel()
+	t.Run("Enroll", func(t *testing.T) {
+		t.Parallel()
+		now := time.Now()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Now: func() time.Time { return now },
+		})
+		coderdtest.CreateFirstUser(t, client)
+
+		doneChan := make(chan struct{})
+		cmd, root := clitest.New(t, "totp", "enroll")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t)
+		cmd.SetIn(pty.Input())
+		cmd.SetOut(pty.Output())
+		go func() {
+			defer close(doneChan)
+			err := cmd.Execute()
+			assert.NoError(t, err)
+		}()
+
+		output := pty.ExpectMatch("two-factor code")
+		secret := regexp.MustCompile(`[A-Z2-7]{32}`).FindString(output)
+		require.NotEmpty(t, secret)
+		pty.WriteLine("000000")
+		pty.ExpectMatch("That code is invalid!")
+		code, err := totp.Code(secret, now)
+		require.NoError(t, err)
+		pty.WriteLine(code)
+		pty.ExpectMatch("Two-factor authentication is enabled!")
+		<-doneChan
+
+		status, err := client.TOTP(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		t.Parallel()
+		now := time.Now()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Now: func() time.Time { return now },
+		})
+		coderdtest.CreateFirstUser(t, client)
+		enrollment, err := client.EnrollTOTP(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		code, err := totp.Code(enrollment.Secret, now)
+		require.NoError(t, err)
+		codes, err := client.VerifyTOTP(context.Background(), codersdk.Me, codersdk.VerifyTOTPRequest{Code: code})
+		require.NoError(t, err)
+
+		doneChan := make(chan struct{})
+		cmd, root := clitest.New(t, "totp", "disable")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t)
+		cmd.SetIn(pty.Input())
+		cmd.SetOut(pty.Output())
+		go func() {
+			defer close(doneChan)
+			err := cmd.Execute()
+			assert.NoError(t, err)
+		}()
+
+		pty.ExpectMatch("Are you sure")
+		pty.WriteLine("yes")
+		pty.ExpectMatch("recovery code")
+		pty.WriteLine("000000")
+		pty.ExpectMatch("valid code is required")
+		pty.WriteLine(codes.RecoveryCodes[0])
+		pty.ExpectMatch("Two-factor authentication has been disabled!")
+		<-doneChan
+
+		status, err := client.TOTP(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+	})
+
+	t.Run("UserReset", func(t *testing.T) {
+		t.Parallel()
+		now := time.Now()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Now: func() time.Time { return now },
+		})
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		memberUser, err := member.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		enrollment, err := member.EnrollTOTP(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		code, err := totp.Code(enrollment.Secret, now)
+		require.NoError(t, err)
+		_, err = member.VerifyTOTP(context.Background(), codersdk.Me, codersdk.VerifyTOTPRequest{Code: code})
+		require.NoError(t, err)
+
+		cmd, root := clitest.New(t, "users", "reset-totp", memberUser.Username)
+		clitest.SetupConfig(t, client, root)
+		// Yes to the prompt
+		cmd.SetIn(bytes.NewReader([]byte("yes\n")))
+		err = cmd.Execute()
+		require.NoError(t, err)
+
+		status, err := member.TOTP(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+	})
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func transfer() *cobra.Command {
+	cmd := &cobra.Command{
+		Annotations: workspaceCommand,
+		Use:         "transfer <owner/workspace> <username|user_id>",
+		Short:       "Transfer a workspace to another member of its organization",
+		Long: "Transfer a workspace, its build history and parameter values to another member of its organization. " +
+			"The workspace is built again, so it uses the metadata and Git SSH key of its new owner.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			workspace, err := namedWorkspace(cmd, client, args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			owner, err := client.User(cmd.Context(), args[1])
+			if err != nil {
+				return xerrors.Errorf("get user: %w", err)
+			}
+
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text: fmt.Sprintf("Transfer %s to %s? It will be built again.",
+					cliui.Styles.Code.Render(workspace.OwnerName+"/"+workspace.Name), cliui.Styles.Code.Render(owner.Username)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			before := time.Now()
+			build, err := client.TransferWorkspace(cmd.Context(), workspace.ID, codersdk.TransferWorkspaceRequest{
+				OwnerID: owner.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("transfer workspace: %w", err)
+			}
+			err = cliui.WorkspaceBuild(cmd.Context(), cmd.OutOrStdout(), client, build.ID, before)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nThe %s workspace has been transferred to %s!\n",
+				cliui.Styles.Keyword.Render(workspace.Name), cliui.Styles.Keyword.Render(owner.Username))
+			return nil
+		},
+	}
+	cliui.AllowSkipPrompt(cmd)
+	return cmd
+}
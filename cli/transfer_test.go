@@ -0,0 +1,38 @@
+package cli_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestTransfer(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+	admin := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+	previous := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	previousUser, err := previous.User(context.Background(), codersdk.Me)
+	require.NoError(t, err)
+	workspace := coderdtest.CreateWorkspace(t, previous, admin.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, previous, workspace.LatestBuild.ID)
+	owner := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	ownerUser, err := owner.User(context.Background(), codersdk.Me)
+	require.NoError(t, err)
+
+	cmd, root := clitest.New(t, "transfer", previousUser.Username+"/"+workspace.Name, ownerUser.Username, "--yes")
+	clitest.SetupConfig(t, client, root)
+	err = cmd.Execute()
+	require.NoError(t, err)
+
+	transferred, err := client.Workspace(context.Background(), workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, ownerUser.ID, transferred.OwnerID)
+}
@@ -8,8 +8,8 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/coderd/userpassword"
 	"github.com/coder/coder/codersdk"
-	"github.com/coder/coder/cryptorand"
 )
 
 func userCreate() *cobra.Command {
@@ -53,7 +53,7 @@ func userCreate() *cobra.Command {
 				}
 			}
 			if password == "" {
-				password, err = cryptorand.StringCharset(cryptorand.Human, 12)
+				password, err = generatePassword(cmd, client)
 				if err != nil {
 					return err
 				}
@@ -88,3 +88,18 @@ Create a workspace  `+cliui.Styles.Code.Render("coder create")+`!`)
 	cmd.Flags().StringVarP(&password, "password", "p", "", "Specifies a password for the new user.")
 	return cmd
 }
+
+// generatePassword generates a password that meets the password policy of the
+// deployment. It contains all character classes, and is at least 12
+// characters long.
+func generatePassword(cmd *cobra.Command, client *codersdk.Client) (string, error) {
+	policy, err := client.PasswordPolicy(cmd.Context())
+	if err != nil {
+		return "", xerrors.Errorf("get password policy: %w", err)
+	}
+	length := 12
+	if policy.MinLength > length {
+		length = policy.MinLength
+	}
+	return userpassword.Generate(length)
+}
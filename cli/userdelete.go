@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func userDelete() *cobra.Command {
+	var (
+		columns []string
+		opts    codersdk.DeleteUserOptions
+	)
+	cmd := &cobra.Command{
+		Use:   "delete <username|user_id>",
+		Short: "Delete a user, freeing their username and email for reuse",
+		Args:  cobra.ExactArgs(1),
+		Example: formatExamples(
+			example{
+				Description: "Delete a user who doesn't own any workspaces",
+				Command:     "coder users delete example_user",
+			},
+			example{
+				Description: "Destroy the workspaces of a user, then delete them",
+				Command:     "coder users delete example_user --destroy-workspaces",
+			},
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			user, err := client.User(cmd.Context(), args[0])
+			if err != nil {
+				return xerrors.Errorf("fetch user: %w", err)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), displayUsers(columns, user))
+
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text:      "Are you sure you want to delete this user?",
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			before := time.Now()
+			builds, err := client.DeleteUser(cmd.Context(), user.ID.String(), opts)
+			if err != nil {
+				return xerrors.Errorf("delete user: %w", err)
+			}
+			if len(builds) > 0 {
+				for _, build := range builds {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nDestroying the %s workspace...\n", cliui.Styles.Keyword.Render(build.WorkspaceName))
+					err = cliui.WorkspaceBuild(cmd.Context(), cmd.OutOrStdout(), client, build.ID, before)
+					if err != nil {
+						return err
+					}
+				}
+				// The user is deleted once their workspaces are gone.
+				_, err = client.DeleteUser(cmd.Context(), user.ID.String(), codersdk.DeleteUserOptions{})
+				if err != nil {
+					return xerrors.Errorf("delete user: %w", err)
+				}
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nUser %s has been deleted!\n", cliui.Styles.Keyword.Render(user.Username))
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"username", "email", "created_at", "status"},
+		"Specify a column to filter in the table.")
+	cmd.Flags().BoolVar(&opts.DestroyWorkspaces, "destroy-workspaces", false,
+		"Destroy the workspaces of the user and wait for them to be deleted before deleting the user.")
+	cliui.AllowSkipPrompt(cmd)
+	return cmd
+}
@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+// importedUser is a row of a CSV file of users to import.
+type importedUser struct {
+	line     int
+	username string
+	email    string
+	password string
+	roles    []string
+	// organizations are the organizations to join, in the order they're
+	// listed, with the roles to have in each.
+	organizations     []uuid.UUID
+	organizationRoles map[uuid.UUID][]string
+}
+
+func userImport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create users from a CSV file",
+		Long: "The file must start with a header row. The username and email columns are required. " +
+			"The password column is optional, and random passwords are generated for users without one. " +
+			"The roles column lists site roles separated by spaces. The organizations column lists the organizations " +
+			"to join separated by spaces, each optionally followed by a colon and a role in it. " +
+			"Users join the current organization when it's empty.",
+		Args: cobra.ExactArgs(1),
+		Example: formatExamples(
+			example{
+				Description: "Import users from a file with the rows \"username,email,roles,organizations\" and \"kyle,kyle@coder.com,auditor,acme:organization-admin\"",
+				Command:     "coder users import users.csv",
+			},
+			example{
+				Description: "Read users from stdin",
+				Command:     "cat users.csv | coder users import -",
+			},
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			var reader io.Reader = cmd.InOrStdin()
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return xerrors.Errorf("open %q: %w", args[0], err)
+				}
+				defer file.Close()
+				reader = file
+			}
+			organizations, err := client.Organizations(cmd.Context())
+			if err != nil {
+				return xerrors.Errorf("list organizations: %w", err)
+			}
+			defaultOrganization, err := currentOrganization(cmd, client)
+			if err != nil {
+				return err
+			}
+			// Every row is parsed and its roles checked before any user is
+			// created, so mistakes in the file don't leave it half imported.
+			users, err := parseImportedUsers(reader, organizations, defaultOrganization.ID)
+			if err != nil {
+				return err
+			}
+			err = checkImportedRoles(cmd, client, users)
+			if err != nil {
+				return err
+			}
+
+			tableWriter := cliui.Table()
+			tableWriter.AppendHeader(table.Row{"username", "email", "password"})
+			failed := 0
+			created := 0
+			for _, user := range users {
+				password, ok, err := importUser(cmd, client, user)
+				if ok {
+					// Users that were created are listed even if the server
+					// failed to apply the rest of the row, so their password
+					// can still be shared.
+					created++
+					tableWriter.AppendRow(table.Row{user.username, user.email, password})
+				}
+				if err != nil {
+					failed++
+					if ok {
+						_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Line %d: created %s, but failed to finish importing it: %s\n", user.line, cliui.Styles.Keyword.Render(user.username), err)
+					} else {
+						_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Line %d: failed to import %s: %s\n", user.line, cliui.Styles.Keyword.Render(user.username), err)
+					}
+				}
+			}
+			if created > 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), tableWriter.Render())
+			}
+			if failed > 0 {
+				return xerrors.Errorf("%d of %d users failed to import", failed, len(users))
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\n%d users have been imported! Share their passwords with them so they can log in.\n", len(users))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// importUser creates a user with the roles and organization memberships of a
+// row. The password it was created with is returned when it was generated.
+// Whether the user was created is returned even if applying the rest of the
+// row failed.
+func importUser(cmd *cobra.Command, client *codersdk.Client, user importedUser) (string, bool, error) {
+	password := user.password
+	generated := ""
+	if password == "" {
+		var err error
+		password, err = generatePassword(cmd, client)
+		if err != nil {
+			return "", false, err
+		}
+		generated = password
+	}
+	created, err := client.CreateUser(cmd.Context(), codersdk.CreateUserRequest{
+		Email:          user.email,
+		Username:       user.username,
+		Password:       password,
+		OrganizationID: user.organizations[0],
+	})
+	if err != nil {
+		return "", false, xerrors.Errorf("create user: %w", err)
+	}
+	for i, organizationID := range user.organizations {
+		// The user joined the first organization when they were created.
+		if i > 0 {
+			_, err = client.AddOrganizationMember(cmd.Context(), organizationID, created.ID.String())
+			if err != nil {
+				return generated, true, xerrors.Errorf("add organization member: %w", err)
+			}
+		}
+		roles := user.organizationRoles[organizationID]
+		if len(roles) == 0 {
+			continue
+		}
+		_, err = client.UpdateOrganizationMemberRoles(cmd.Context(), organizationID, created.ID.String(), codersdk.UpdateRoles{
+			Roles: organizationRoleNames(organizationID, roles),
+		})
+		if err != nil {
+			return generated, true, xerrors.Errorf("update organization roles: %w", err)
+		}
+	}
+	if len(user.roles) > 0 {
+		_, err = client.UpdateUserRoles(cmd.Context(), created.ID.String(), codersdk.UpdateRoles{
+			Roles: user.roles,
+		})
+		if err != nil {
+			return generated, true, xerrors.Errorf("update roles: %w", err)
+		}
+	}
+	return generated, true, nil
+}
+
+// checkImportedRoles returns an error if a row lists a role that doesn't exist
+// in the site or in an organization it joins.
+func checkImportedRoles(cmd *cobra.Command, client *codersdk.Client, users []importedUser) error {
+	var siteRoles map[string]bool
+	organizationRoles := map[uuid.UUID]map[string]bool{}
+	for _, user := range users {
+		if len(user.roles) > 0 && siteRoles == nil {
+			roles, err := client.ListSiteRoles(cmd.Context())
+			if err != nil {
+				return xerrors.Errorf("list site roles: %w", err)
+			}
+			siteRoles = roleNameSet(roles)
+		}
+		for _, role := range user.roles {
+			if !siteRoles[role] {
+				return xerrors.Errorf("line %d: site role %q not found", user.line, role)
+			}
+		}
+		for organizationID, roles := range user.organizationRoles {
+			if len(roles) == 0 {
+				continue
+			}
+			if _, ok := organizationRoles[organizationID]; !ok {
+				assignable, err := client.ListOrganizationRoles(cmd.Context(), organizationID)
+				if err != nil {
+					return xerrors.Errorf("list organization roles: %w", err)
+				}
+				organizationRoles[organizationID] = roleNameSet(assignable)
+			}
+			for i, name := range organizationRoleNames(organizationID, roles) {
+				if !organizationRoles[organizationID][name] {
+					return xerrors.Errorf("line %d: organization role %q not found", user.line, roles[i])
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func roleNameSet(roles []codersdk.Role) map[string]bool {
+	names := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		names[role.Name] = true
+	}
+	return names
+}
+
+func parseImportedUsers(reader io.Reader, organizations []codersdk.Organization, defaultOrganizationID uuid.UUID) ([]importedUser, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, xerrors.Errorf("read csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, xerrors.New("the file is empty")
+	}
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"username", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, xerrors.Errorf("the header row is missing the %q column", required)
+		}
+	}
+	organizationsByName := map[string]uuid.UUID{}
+	for _, organization := range organizations {
+		organizationsByName[strings.ToLower(organization.Name)] = organization.ID
+	}
+
+	users := make([]importedUser, 0, len(records)-1)
+	for i, record := range records[1:] {
+		field := func(name string) string {
+			index, ok := columns[name]
+			if !ok {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		user := importedUser{
+			// Lines are 1-based, and the header is the first.
+			line:              i + 2,
+			username:          field("username"),
+			email:             field("email"),
+			password:          field("password"),
+			roles:             strings.Fields(field("roles")),
+			organizationRoles: map[uuid.UUID][]string{},
+		}
+		if user.username == "" || user.email == "" {
+			return nil, xerrors.Errorf("line %d: a username and email are required", user.line)
+		}
+		for _, entry := range strings.Fields(field("organizations")) {
+			name, role, _ := strings.Cut(entry, ":")
+			organizationID, ok := organizationsByName[strings.ToLower(name)]
+			if !ok {
+				return nil, xerrors.Errorf("line %d: organization %q not found", user.line, name)
+			}
+			if _, ok := user.organizationRoles[organizationID]; !ok {
+				user.organizations = append(user.organizations, organizationID)
+				user.organizationRoles[organizationID] = []string{}
+			}
+			if role != "" {
+				user.organizationRoles[organizationID] = append(user.organizationRoles[organizationID], role)
+			}
+		}
+		if len(user.organizations) == 0 {
+			user.organizations = []uuid.UUID{defaultOrganizationID}
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
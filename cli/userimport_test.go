@@ -0,0 +1,161 @@
+package cli_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/userpassword"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/pty/ptytest"
+)
+
+func TestUserImport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Import", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		eng, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "eng",
+		})
+		require.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "users.csv")
+		err = os.WriteFile(path, []byte(strings.Join([]string{
+			"username,email,password,roles,organizations",
+			"kyle,kyle@coder.com,,auditor,eng:organization-admin",
+			"colin,colin@coder.com,SomeSecurePassword!,,",
+		}, "\n")), 0o600)
+		require.NoError(t, err)
+
+		cmd, root := clitest.New(t, "users", "import", path)
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t)
+		cmd.SetOut(pty.Output())
+		err = cmd.Execute()
+		require.NoError(t, err)
+		pty.ExpectMatch("2 users have been imported")
+
+		kyle, err := client.User(ctx, "kyle")
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{eng.ID}, kyle.OrganizationIDs)
+		roles, err := client.GetUserRoles(ctx, "kyle")
+		require.NoError(t, err)
+		require.Equal(t, []string{"auditor"}, roles.Roles)
+		require.Equal(t, []string{rbac.RoleOrgAdmin(eng.ID)}, roles.OrganizationRoles[eng.ID])
+
+		colin, err := client.User(ctx, "colin")
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{admin.OrganizationID}, colin.OrganizationIDs)
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    "colin@coder.com",
+			Password: "SomeSecurePassword!",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("PasswordPolicy", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		// Generated passwords meet the policy of the deployment.
+		client := coderdtest.New(t, &coderdtest.Options{
+			PasswordPolicy: userpassword.Policy{MinLength: 20, MinCharacterClasses: 4},
+		})
+		first := coderdtest.FirstUserParams
+		first.Password = "Some-Secure-Password-1"
+		_, err := client.CreateFirstUser(ctx, first)
+		require.NoError(t, err)
+		login, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    first.Email,
+			Password: first.Password,
+		})
+		require.NoError(t, err)
+		client.SessionToken = login.SessionToken
+
+		cmd, root := clitest.New(t, "users", "import", "-")
+		clitest.SetupConfig(t, client, root)
+		cmd.SetIn(strings.NewReader(strings.Join([]string{
+			"username,email",
+			"kyle,kyle@coder.com",
+		}, "\n")))
+		pty := ptytest.New(t)
+		cmd.SetOut(pty.Output())
+		err = cmd.Execute()
+		require.NoError(t, err)
+		pty.ExpectMatch("1 users have been imported")
+		_, err = client.User(ctx, "kyle")
+		require.NoError(t, err)
+	})
+
+	t.Run("UnknownOrganization", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+
+		cmd, root := clitest.New(t, "users", "import", "-")
+		clitest.SetupConfig(t, client, root)
+		cmd.SetIn(strings.NewReader(strings.Join([]string{
+			"username,email,organizations",
+			"kyle,kyle@coder.com,",
+			"colin,colin@coder.com,acme",
+		}, "\n")))
+		err := cmd.Execute()
+		require.ErrorContains(t, err, `line 3: organization "acme" not found`)
+
+		// Nothing is imported when the file has mistakes.
+		_, err = client.User(context.Background(), "kyle")
+		require.Error(t, err)
+	})
+
+	t.Run("UnknownRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+
+		cmd, root := clitest.New(t, "users", "import", "-")
+		clitest.SetupConfig(t, client, root)
+		cmd.SetIn(strings.NewReader(strings.Join([]string{
+			"username,email,roles",
+			"kyle,kyle@coder.com,auditor",
+			"colin,colin@coder.com,wizard",
+		}, "\n")))
+		err := cmd.Execute()
+		require.ErrorContains(t, err, `line 3: site role "wizard" not found`)
+
+		// Roles are checked before any user is created.
+		_, err = client.User(context.Background(), "kyle")
+		require.Error(t, err)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		existing, err := client.User(context.Background(), admin.UserID.String())
+		require.NoError(t, err)
+
+		cmd, root := clitest.New(t, "users", "import", "-")
+		clitest.SetupConfig(t, client, root)
+		cmd.SetIn(strings.NewReader(strings.Join([]string{
+			"username,email",
+			existing.Username + "," + existing.Email,
+			"kyle,kyle@coder.com",
+		}, "\n")))
+		err = cmd.Execute()
+		require.ErrorContains(t, err, "1 of 2 users failed to import")
+
+		_, err = client.User(context.Background(), "kyle")
+		require.NoError(t, err)
+	})
+}
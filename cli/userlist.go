@@ -9,16 +9,27 @@ import (
 )
 
 func userList() *cobra.Command {
-	var columns []string
+	var (
+		columns []string
+		search  string
+	)
 	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
+		Example: formatExamples(
+			example{
+				Description: "List active users that haven't been seen since August",
+				Command:     "coder users list --search \"status:active last_seen_before:2022-08-01\"",
+			},
+		),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := createClient(cmd)
 			if err != nil {
 				return err
 			}
-			users, err := client.Users(cmd.Context(), codersdk.UsersRequest{})
+			users, err := client.Users(cmd.Context(), codersdk.UsersRequest{
+				SearchQuery: search,
+			})
 			if err != nil {
 				return err
 			}
@@ -27,8 +38,10 @@ func userList() *cobra.Command {
 			return err
 		},
 	}
-	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"username", "email", "created_at"},
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"username", "email", "created_at", "last_seen_at"},
 		"Specify a column to filter in the table.")
+	cmd.Flags().StringVarP(&search, "search", "q", "",
+		"Filter users by a search query, such as \"status:active\", \"role:admin\", \"last_seen_before:2022-08-01\" or \"last_seen_after:2022-08-01\".")
 	return cmd
 }
 
@@ -31,6 +31,28 @@ func TestUserList(t *testing.T) {
 		require.NoError(t, <-errC)
 		pty.ExpectMatch("coder.com")
 	})
+	t.Run("Search", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		_, err := client.CreateUser(context.Background(), codersdk.CreateUserRequest{
+			Email:          "alice@coder.com",
+			Username:       "alice",
+			Password:       "password",
+			OrganizationID: admin.OrganizationID,
+		})
+		require.NoError(t, err)
+
+		// Alice never logged in.
+		cmd, root := clitest.New(t, "users", "list", "--search", "last_seen_before:2000-01-01")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t)
+		cmd.SetOut(pty.Output())
+		err = cmd.Execute()
+		require.NoError(t, err)
+		pty.ExpectMatch("alice@coder.com")
+		pty.ExpectMatch("Never")
+	})
 	t.Run("NoURLFileErrorHasHelperText", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+	"github.com/coder/coder/codersdk"
+)
+
+func userQuota() *cobra.Command {
+	var (
+		maxWorkspaces        int32
+		maxRunningWorkspaces int32
+		maxCostUnits         int32
+	)
+	cmd := &cobra.Command{
+		Use:   "quota [username|user_id]",
+		Short: "Show the workspace quota of a user, or update it with flags",
+		Args:  cobra.MaximumNArgs(1),
+		Example: formatExamples(
+			example{
+				Description: "Show your own workspace quota",
+				Command:     "coder users quota",
+			},
+			example{
+				Description: "Limit a user to 2 running workspaces. Zero means unlimited",
+				Command:     "coder users quota example_user --max-running-workspaces 2",
+			},
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+			user := codersdk.Me
+			if len(args) > 0 {
+				user = args[0]
+			}
+
+			quota, err := client.UserQuota(cmd.Context(), user)
+			if err != nil {
+				return xerrors.Errorf("fetch quota: %w", err)
+			}
+
+			flags := cmd.Flags()
+			if flags.Changed("max-workspaces") || flags.Changed("max-running-workspaces") || flags.Changed("max-cost-units") {
+				limits := quota.Limits
+				if flags.Changed("max-workspaces") {
+					limits.MaxWorkspaces = maxWorkspaces
+				}
+				if flags.Changed("max-running-workspaces") {
+					limits.MaxRunningWorkspaces = maxRunningWorkspaces
+				}
+				if flags.Changed("max-cost-units") {
+					limits.MaxCostUnits = maxCostUnits
+				}
+				quota, err = client.UpdateUserQuota(cmd.Context(), user, limits)
+				if err != nil {
+					return xerrors.Errorf("update quota: %w", err)
+				}
+			}
+
+			tableWriter := cliui.Table()
+			tableWriter.AppendHeader(table.Row{"", "used", "limit"})
+			tableWriter.AppendRow(table.Row{"Workspaces", quota.Usage.Workspaces, quotaLimit(quota.Limits.MaxWorkspaces)})
+			tableWriter.AppendRow(table.Row{"Running workspaces", quota.Usage.RunningWorkspaces, quotaLimit(quota.Limits.MaxRunningWorkspaces)})
+			tableWriter.AppendRow(table.Row{"Cost units", quota.Usage.CostUnits, quotaLimit(quota.Limits.MaxCostUnits)})
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), tableWriter.Render())
+			return err
+		},
+	}
+	cmd.Flags().Int32Var(&maxWorkspaces, "max-workspaces", 0, "Set the maximum number of workspaces.")
+	cmd.Flags().Int32Var(&maxRunningWorkspaces, "max-running-workspaces", 0, "Set the maximum number of running workspaces.")
+	cmd.Flags().Int32Var(&maxCostUnits, "max-cost-units", 0, "Set the maximum cost units of running workspaces.")
+	return cmd
+}
+
+func quotaLimit(limit int32) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return strconv.Itoa(int(limit))
+}
+
+// quotaError explains errors returned when a workspace quota would be
+// exceeded, and returns other errors unchanged.
+func quotaError(err error) error {
+	var apiErr *codersdk.Error
+	if !errors.As(err, &apiErr) || apiErr.Message != codersdk.WorkspaceQuotaExceeded {
+		return err
+	}
+	return xerrors.Errorf("%s %s\nStop or delete a workspace, or ask an admin to raise the quota. Run %s to see your usage.",
+		apiErr.Message, apiErr.Detail, cliui.Styles.Code.Render("coder users quota"))
+}
@@ -0,0 +1,61 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestUserQuota(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		cmd, root := clitest.New(t, "users", "quota", memberUser.Username, "--max-running-workspaces", "2")
+		clitest.SetupConfig(t, client, root)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		err = cmd.Execute()
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "unlimited")
+
+		quota, err := member.UserQuota(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, quota.Limits.MaxRunningWorkspaces)
+		require.EqualValues(t, 0, quota.Limits.MaxWorkspaces)
+	})
+
+	t.Run("Exceeded", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		_, err := client.UpdateUserQuota(ctx, codersdk.Me, codersdk.QuotaLimits{MaxWorkspaces: 1})
+		require.NoError(t, err)
+		workspace := coderdtest.CreateWorkspace(t, client, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		cmd, root := clitest.New(t, "create", "another", "--template", template.Name, "-y")
+		clitest.SetupConfig(t, client, root)
+		err = cmd.Execute()
+		require.ErrorContains(t, err, codersdk.WorkspaceQuotaExceeded)
+		require.ErrorContains(t, err, "is limited to 1 workspaces")
+		require.ErrorContains(t, err, "coder users quota")
+	})
+}
@@ -18,10 +18,16 @@ func users() *cobra.Command {
 	}
 	cmd.AddCommand(
 		userCreate(),
+		userImport(),
 		userList(),
 		userSingle(),
 		createUserStatusCommand(codersdk.UserStatusActive),
 		createUserStatusCommand(codersdk.UserStatusSuspended),
+		userDelete(),
+		userRevokeSessions(),
+		userResetTOTP(),
+		userUnlock(),
+		userQuota(),
 	)
 	return cmd
 }
@@ -31,18 +37,23 @@ func users() *cobra.Command {
 // columns to display
 func displayUsers(filterColumns []string, users ...codersdk.User) string {
 	tableWriter := cliui.Table()
-	header := table.Row{"id", "username", "email", "created at", "status"}
+	header := table.Row{"id", "username", "email", "created at", "last seen at", "status"}
 	tableWriter.AppendHeader(header)
 	tableWriter.SetColumnConfigs(cliui.FilterTableColumns(header, filterColumns))
 	tableWriter.SortBy([]table.SortBy{{
 		Name: "Username",
 	}})
 	for _, user := range users {
+		lastSeenAt := "Never"
+		if !user.LastSeenAt.IsZero() {
+			lastSeenAt = user.LastSeenAt.Format(time.Stamp)
+		}
 		tableWriter.AppendRow(table.Row{
 			user.ID.String(),
 			user.Username,
 			user.Email,
 			user.CreatedAt.Format(time.Stamp),
+			lastSeenAt,
 			user.Status,
 		})
 	}
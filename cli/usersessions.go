@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+)
+
+// userRevokeSessions deletes every API key of a user, e.g. to sign a
+// suspended user out everywhere.
+func userRevokeSessions() *cobra.Command {
+	var columns []string
+	cmd := &cobra.Command{
+		Use:   "revoke-sessions <username|user_id>",
+		Short: "Revoke all sessions and tokens of a user. The user will have to log in again",
+		Args:  cobra.ExactArgs(1),
+		Example: formatExamples(
+			example{
+				Command: "coder users revoke-sessions example_user",
+			},
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			user, err := client.User(cmd.Context(), args[0])
+			if err != nil {
+				return xerrors.Errorf("fetch user: %w", err)
+			}
+
+			// Display the user
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), displayUsers(columns, user))
+
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text:      "Are you sure you want to revoke all sessions of this user?",
+				IsConfirm: true,
+				Default:   cliui.ConfirmYes,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteAPIKeys(cmd.Context(), user.ID.String())
+			if err != nil {
+				return xerrors.Errorf("revoke sessions: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nAll sessions of %s have been revoked!\n", cliui.Styles.Keyword.Render(user.Username))
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"username", "email", "created_at", "status"},
+		"Specify a column to filter in the table.")
+	return cmd
+}
@@ -25,7 +25,7 @@ func createUserStatusCommand(sdkStatus codersdk.UserStatus) *cobra.Command {
 	case codersdk.UserStatusSuspended:
 		verb = "suspend"
 		pastVerb = "suspended"
-		aliases = []string{"rm", "delete"}
+		aliases = []string{"rm"}
 		short = "Update a user's status to 'suspended'. A suspended user cannot log into the platform"
 	default:
 		panic(fmt.Sprintf("%s is not supported", sdkStatus))
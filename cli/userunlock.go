@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cli/cliui"
+)
+
+func userUnlock() *cobra.Command {
+	var columns []string
+	cmd := &cobra.Command{
+		Use:   "unlock <username|user_id>",
+		Short: "Unlock a user that's locked out after too many failed login attempts, and clear their failed attempts",
+		Args:  cobra.ExactArgs(1),
+		Example: formatExamples(
+			example{
+				Command: "coder users unlock example_user",
+			},
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := createClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			user, err := client.User(cmd.Context(), args[0])
+			if err != nil {
+				return xerrors.Errorf("fetch user: %w", err)
+			}
+			lockout, err := client.UserLockout(cmd.Context(), user.ID.String())
+			if err != nil {
+				return xerrors.Errorf("fetch lockout: %w", err)
+			}
+
+			// Display the user
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), displayUsers(columns, user))
+
+			if lockout.LockedUntil == nil && lockout.FailedAttempts == 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "User %s isn't locked out\n", cliui.Styles.Keyword.Render(user.Username))
+				return nil
+			}
+			if lockout.LockedUntil != nil {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "User %s is locked out until %s\n", cliui.Styles.Keyword.Render(user.Username), lockout.LockedUntil.Format(time.Stamp))
+			} else {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "User %s has %d failed login attempts\n", cliui.Styles.Keyword.Render(user.Username), lockout.FailedAttempts)
+			}
+
+			_, err = cliui.Prompt(cmd, cliui.PromptOptions{
+				Text:      "Are you sure you want to unlock this user?",
+				IsConfirm: true,
+				Default:   cliui.ConfirmYes,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.UnlockUser(cmd.Context(), user.ID.String())
+			if err != nil {
+				return xerrors.Errorf("unlock user: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nUser %s has been unlocked!\n", cliui.Styles.Keyword.Render(user.Username))
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&columns, "column", "c", []string{"username", "email", "created_at", "status"},
+		"Specify a column to filter in the table.")
+	return cmd
+}
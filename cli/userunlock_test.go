@@ -0,0 +1,46 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/cli/clitest"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestUserUnlock(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := coderdtest.New(t, &coderdtest.Options{LoginMaxAttempts: 1})
+	admin := coderdtest.CreateFirstUser(t, client)
+	member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+	memberUser, err := member.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+		Email:    memberUser.Email,
+		Password: "badpassword",
+	})
+	require.Error(t, err)
+	lockout, err := client.UserLockout(ctx, memberUser.Username)
+	require.NoError(t, err)
+	require.NotNil(t, lockout.LockedUntil)
+
+	cmd, root := clitest.New(t, "users", "unlock", memberUser.Username)
+	clitest.SetupConfig(t, client, root)
+	// Yes to the prompt
+	cmd.SetIn(bytes.NewReader([]byte("yes\n")))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	err = cmd.Execute()
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "has been unlocked")
+
+	lockout, err = client.UserLockout(ctx, memberUser.Username)
+	require.NoError(t, err)
+	require.Nil(t, lockout.LockedUntil)
+}
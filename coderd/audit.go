@@ -0,0 +1,219 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceAuditLog) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	apiKey := httpmw.APIKey(r)
+	filter, userQuery, errs := auditSearchQuery(r.URL.Query().Get("q"))
+	if len(errs) > 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid audit log search query.",
+			Validations: errs,
+		})
+		return
+	}
+
+	paginationParams, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+	filter.AfterID = paginationParams.AfterID
+	filter.OffsetOpt = int32(paginationParams.Offset)
+	filter.LimitOpt = int32(paginationParams.Limit)
+
+	switch userQuery {
+	case "":
+	case codersdk.Me:
+		filter.UserID = apiKey.UserID
+	default:
+		userID, err := uuid.Parse(userQuery)
+		if err == nil {
+			filter.UserID = userID
+			break
+		}
+		user, err := api.Database.GetUserByEmailOrUsername(r.Context(), database.GetUserByEmailOrUsernameParams{
+			Username: userQuery,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// An unknown user can't have performed any actions.
+			httpapi.Write(rw, http.StatusOK, []codersdk.AuditLog{})
+			return
+		}
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching user.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		filter.UserID = user.ID
+	}
+
+	logs, err := api.Database.GetAuditLogs(r.Context(), filter)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusOK, []codersdk.AuditLog{})
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching audit logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(logs))
+	for _, alog := range logs {
+		if alog.UserID == uuid.Nil {
+			continue
+		}
+		userIDs = append(userIDs, alog.UserID)
+	}
+	users, err := api.Database.GetUsersByIDs(r.Context(), userIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching users.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	usersByID := make(map[uuid.UUID]database.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	roleDisplayNames := api.siteRoleDisplayNames(r.Context())
+	apiLogs := make([]codersdk.AuditLog, 0, len(logs))
+	for _, alog := range logs {
+		var user *database.User
+		if u, ok := usersByID[alog.UserID]; ok {
+			user = &u
+		}
+		apiLogs = append(apiLogs, convertAuditLog(alog, user, roleDisplayNames))
+	}
+
+	httpapi.Write(rw, http.StatusOK, apiLogs)
+}
+
+func convertAuditLog(alog database.AuditLog, user *database.User, roleDisplayNames map[string]string) codersdk.AuditLog {
+	var ip string
+	if alog.Ip.Valid {
+		ip = alog.Ip.IPNet.IP.String()
+	}
+
+	converted := codersdk.AuditLog{
+		ID:             alog.ID,
+		Time:           alog.Time,
+		OrganizationID: alog.OrganizationID,
+		IP:             ip,
+		UserAgent:      alog.UserAgent,
+		ResourceType:   codersdk.ResourceType(alog.ResourceType),
+		ResourceID:     alog.ResourceID,
+		ResourceTarget: alog.ResourceTarget,
+		Action:         codersdk.AuditAction(alog.Action),
+		Diff:           alog.Diff,
+		StatusCode:     alog.StatusCode,
+		UserID:         alog.UserID,
+	}
+	if user != nil {
+		// Organization memberships aren't relevant to an audit log.
+		apiUser := convertUser(*user, []uuid.UUID{}, roleDisplayNames)
+		converted.User = &apiUser
+	}
+	return converted
+}
+
+// auditSearchQuery parses the "q" query parameter of the audit log endpoint.
+// The user filter is returned separately since it may be a username that
+// needs to be resolved.
+func auditSearchQuery(query string) (database.GetAuditLogsParams, string, []codersdk.ValidationError) {
+	searchParams := make(url.Values)
+	if query == "" {
+		// No filter
+		return database.GetAuditLogsParams{}, "", nil
+	}
+	// Because we do this in 2 passes, we want to maintain quotes on the first
+	// pass. Further splitting occurs on the second pass and quotes will be
+	// dropped. Timestamps contain ':', so they must be quoted.
+	elements := splitQueryParameterByDelimiter(query, ' ', true)
+	for _, element := range elements {
+		parts := splitQueryParameterByDelimiter(element, ':', false)
+		if len(parts) != 2 {
+			return database.GetAuditLogsParams{}, "", []codersdk.ValidationError{
+				{Field: "q", Detail: fmt.Sprintf("Query element %q must be a key:value pair", element)},
+			}
+		}
+		searchParams.Set(strings.ToLower(parts[0]), parts[1])
+	}
+
+	parser := httpapi.NewQueryParamParser()
+	filter := database.GetAuditLogsParams{
+		ResourceType: string(httpapi.ParseCustom(parser, searchParams, "", "resource_type", parseAuditResourceType)),
+		ResourceID:   parser.UUID(searchParams, uuid.Nil, "resource_id"),
+		Action:       string(httpapi.ParseCustom(parser, searchParams, "", "action", parseAuditAction)),
+		StatusCode:   int32(parser.Int(searchParams, 0, "status_code")),
+		TimeFrom:     httpapi.ParseCustom(parser, searchParams, time.Time{}, "date_from", parseAuditTime(false)),
+		TimeTo:       httpapi.ParseCustom(parser, searchParams, time.Time{}, "date_to", parseAuditTime(true)),
+	}
+	user := strings.ToLower(parser.String(searchParams, "", "user"))
+
+	return filter, user, parser.Errors
+}
+
+func parseAuditResourceType(v string) (database.ResourceType, error) {
+	resourceType := database.ResourceType(strings.ToLower(v))
+	if !audit.ValidResourceType(resourceType) {
+		return "", xerrors.Errorf("%q is not a valid resource type", v)
+	}
+	return resourceType, nil
+}
+
+func parseAuditAction(v string) (database.AuditAction, error) {
+	action := database.AuditAction(strings.ToLower(v))
+	if !audit.ValidAction(action) {
+		return "", xerrors.Errorf("%q is not a valid audit action", v)
+	}
+	return action, nil
+}
+
+// parseAuditTime accepts either an RFC3339 timestamp or a date. When
+// endOfDay is set, a date includes the entire day.
+func parseAuditTime(endOfDay bool) func(v string) (time.Time, error) {
+	return func(v string) (time.Time, error) {
+		t, err := time.Parse(time.RFC3339, strings.ToUpper(v))
+		if err == nil {
+			return t, nil
+		}
+		t, err = time.Parse("2006-01-02", v)
+		if err != nil {
+			return time.Time{}, xerrors.Errorf("%q must be a date (2006-01-02) or an RFC3339 timestamp", v)
+		}
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+// Auditor consumes audit logs produced by coderd. *Exporter is the canonical
+// implementation.
+type Auditor interface {
+	Export(ctx context.Context, alog database.AuditLog) error
+}
+
+// NewNop returns an Auditor that drops every audit log.
+func NewNop() Auditor {
+	return nop{}
+}
+
+type nop struct{}
+
+func (nop) Export(context.Context, database.AuditLog) error {
+	return nil
+}
+
+// NewMock returns an Auditor that keeps every audit log in memory. It is
+// intended for tests.
+func NewMock() *MockAuditor {
+	return &MockAuditor{}
+}
+
+type MockAuditor struct {
+	mutex     sync.Mutex
+	auditLogs []database.AuditLog
+}
+
+func (a *MockAuditor) Export(_ context.Context, alog database.AuditLog) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.auditLogs = append(a.auditLogs, alog)
+	return nil
+}
+
+// AuditLogs returns a copy of the audit logs exported so far.
+func (a *MockAuditor) AuditLogs() []database.AuditLog {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	logs := make([]database.AuditLog, len(a.auditLogs))
+	copy(logs, a.auditLogs)
+	return logs
+}
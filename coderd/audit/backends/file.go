@@ -0,0 +1,89 @@
+package backends
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+)
+
+// FileOptions configures a file backend.
+type FileOptions struct {
+	// Path is the file audit logs are written to. Rotated files are kept in
+	// the same directory.
+	Path string
+	// MaxSizeMB is the size a file may grow to before it's rotated.
+	// Defaults to 100.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files to keep. Zero keeps all of
+	// them.
+	MaxBackups int
+	// MaxAgeDays is the number of days to keep rotated files. Zero keeps them
+	// regardless of age.
+	MaxAgeDays int
+	// Compress gzips rotated files.
+	Compress bool
+}
+
+// File is a backend that writes audit logs to a rotating file as JSON lines.
+type File struct {
+	mutex  sync.Mutex
+	writer *lumberjack.Logger
+}
+
+// NewFile creates a backend that appends audit logs to a file, one JSON
+// object per line.
+func NewFile(opts FileOptions) (*File, error) {
+	if opts.Path == "" {
+		return nil, xerrors.New("path must be provided")
+	}
+	if opts.MaxSizeMB == 0 {
+		opts.MaxSizeMB = 100
+	}
+	return &File{
+		writer: &lumberjack.Logger{
+			Filename:   opts.Path,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+			MaxAge:     opts.MaxAgeDays,
+			Compress:   opts.Compress,
+		},
+	}, nil
+}
+
+func (*File) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (f *File) Export(_ context.Context, alog database.AuditLog) error {
+	data, err := MarshalAuditLog(alog)
+	if err != nil {
+		return xerrors.Errorf("marshal audit log: %w", err)
+	}
+	data = append(data, '\n')
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	_, err = f.writer.Write(data)
+	if err != nil {
+		return xerrors.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// Rotate closes the current file and starts a new one.
+func (f *File) Rotate() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.writer.Rotate()
+}
+
+func (f *File) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.writer.Close()
+}
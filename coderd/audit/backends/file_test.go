@@ -0,0 +1,70 @@
+package backends_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit/backends"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestFileBackend(t *testing.T) {
+	t.Parallel()
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx  = context.Background()
+			path = filepath.Join(t.TempDir(), "audit.log")
+			logs = []codersdk.AuditLog{}
+		)
+		backend, err := backends.NewFile(backends.FileOptions{Path: path})
+		require.NoError(t, err)
+		defer backend.Close()
+
+		alog := randomAuditLog()
+		require.NoError(t, backend.Export(ctx, alog))
+		require.NoError(t, backend.Export(ctx, randomAuditLog()))
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var got codersdk.AuditLog
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &got))
+			logs = append(logs, got)
+		}
+		require.NoError(t, scanner.Err())
+		require.Len(t, logs, 2)
+		require.Equal(t, alog.ID, logs[0].ID)
+		require.Equal(t, "127.0.0.1", logs[0].IP)
+		require.Equal(t, codersdk.AuditActionDelete, logs[0].Action)
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx = context.Background()
+			dir = t.TempDir()
+		)
+		backend, err := backends.NewFile(backends.FileOptions{Path: filepath.Join(dir, "audit.log")})
+		require.NoError(t, err)
+		defer backend.Close()
+
+		require.NoError(t, backend.Export(ctx, randomAuditLog()))
+		require.NoError(t, backend.Rotate())
+		require.NoError(t, backend.Export(ctx, randomAuditLog()))
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+	})
+}
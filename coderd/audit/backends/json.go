@@ -0,0 +1,36 @@
+package backends
+
+import (
+	"encoding/json"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+)
+
+// MarshalAuditLog encodes an audit log in the same format the API uses, so
+// external consumers don't need to understand database types.
+func MarshalAuditLog(alog database.AuditLog) ([]byte, error) {
+	var ip string
+	if alog.Ip.Valid {
+		ip = alog.Ip.IPNet.IP.String()
+	}
+	diff := alog.Diff
+	if len(diff) == 0 {
+		diff = json.RawMessage("{}")
+	}
+
+	return json.Marshal(codersdk.AuditLog{
+		ID:             alog.ID,
+		Time:           alog.Time,
+		OrganizationID: alog.OrganizationID,
+		IP:             ip,
+		UserAgent:      alog.UserAgent,
+		ResourceType:   codersdk.ResourceType(alog.ResourceType),
+		ResourceID:     alog.ResourceID,
+		ResourceTarget: alog.ResourceTarget,
+		Action:         codersdk.AuditAction(alog.Action),
+		Diff:           diff,
+		StatusCode:     alog.StatusCode,
+		UserID:         alog.UserID,
+	})
+}
@@ -0,0 +1,357 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+)
+
+// WebhookSignatureHeader contains the hex encoded HMAC-SHA256 of the request
+// body, prefixed with "sha256=". Receivers should compute the same HMAC with
+// the shared secret and compare the two in constant time.
+const WebhookSignatureHeader = "X-Coder-Audit-Signature"
+
+// WebhookOptions configures a webhook backend.
+type WebhookOptions struct {
+	URL *url.URL
+	// Secret signs each request body. Requests are unsigned if empty.
+	Secret []byte
+	// QueueDir stores audit logs that haven't been delivered yet, so they
+	// survive the receiver being down and coderd restarting.
+	QueueDir string
+	// DeadLetterFile is appended audit logs that the receiver rejected with a
+	// client error, one JSON object per line, so they don't block the audit
+	// logs queued after them. Defaults to "rejected.jsonl" in QueueDir.
+	DeadLetterFile string
+	// MaxQueueSize is the maximum number of undelivered audit logs. Once it
+	// is reached, new audit logs are rejected. Defaults to 10000.
+	MaxQueueSize int
+	// RetryInterval is the initial delay before redelivering after a network
+	// error, a server error, or a 408 or 429 response.
+	// The delay doubles on each failure, up to MaxRetryInterval. Defaults to
+	// 1 second.
+	RetryInterval time.Duration
+	// MaxRetryInterval defaults to 5 minutes.
+	MaxRetryInterval time.Duration
+	// HTTPClient defaults to a client with a 30 second timeout.
+	HTTPClient *http.Client
+	Logger     slog.Logger
+}
+
+// Webhook is a backend that POSTs audit logs as JSON to a URL. Audit logs are
+// written to an on-disk queue first and delivered in order by a background
+// goroutine, so an unavailable receiver never blocks a request.
+type Webhook struct {
+	opts WebhookOptions
+
+	mutex sync.Mutex
+	// queued is the number of entries in the queue directory.
+	queued int
+	notify chan struct{}
+
+	closeCancel context.CancelFunc
+	closed      chan struct{}
+}
+
+// NewWebhook creates a webhook backend and starts delivering any audit logs
+// left in the queue by a previous run.
+func NewWebhook(opts WebhookOptions) (*Webhook, error) {
+	if opts.URL == nil {
+		return nil, xerrors.New("url must be provided")
+	}
+	if opts.QueueDir == "" {
+		return nil, xerrors.New("queue directory must be provided")
+	}
+	if opts.DeadLetterFile == "" {
+		opts.DeadLetterFile = filepath.Join(opts.QueueDir, "rejected.jsonl")
+	}
+	if opts.MaxQueueSize <= 0 {
+		opts.MaxQueueSize = 10000
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = time.Second
+	}
+	if opts.MaxRetryInterval <= 0 {
+		opts.MaxRetryInterval = 5 * time.Minute
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{
+			Timeout: 30 * time.Second,
+		}
+	}
+
+	err := os.MkdirAll(opts.QueueDir, 0700)
+	if err != nil {
+		return nil, xerrors.Errorf("create queue directory: %w", err)
+	}
+	// Entries that were never committed are leftovers from a crash.
+	tmpFiles, err := filepath.Glob(filepath.Join(opts.QueueDir, "*.tmp"))
+	if err != nil {
+		return nil, xerrors.Errorf("find uncommitted queue entries: %w", err)
+	}
+	for _, tmpFile := range tmpFiles {
+		_ = os.Remove(tmpFile)
+	}
+	entries, err := queueEntries(opts.QueueDir)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Webhook{
+		opts:        opts,
+		queued:      len(entries),
+		notify:      make(chan struct{}, 1),
+		closeCancel: cancel,
+		closed:      make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w, nil
+}
+
+func (*Webhook) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+// Export queues an audit log for delivery. An error is only returned if the
+// audit log couldn't be queued.
+func (w *Webhook) Export(_ context.Context, alog database.AuditLog) error {
+	data, err := MarshalAuditLog(alog)
+	if err != nil {
+		return xerrors.Errorf("marshal audit log: %w", err)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.queued >= w.opts.MaxQueueSize {
+		return xerrors.Errorf("webhook queue is full (%d undelivered audit logs)", w.queued)
+	}
+
+	// Entries are named by time so they sort in the order they were queued.
+	// Writing to a temporary file first ensures a partially written entry is
+	// never delivered.
+	name := fmt.Sprintf("%020d-%s", time.Now().UnixNano(), uuid.NewString())
+	tmp := filepath.Join(w.opts.QueueDir, name+".tmp")
+	err = os.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return xerrors.Errorf("write queue entry: %w", err)
+	}
+	err = os.Rename(tmp, filepath.Join(w.opts.QueueDir, name+".json"))
+	if err != nil {
+		_ = os.Remove(tmp)
+		return xerrors.Errorf("commit queue entry: %w", err)
+	}
+	w.queued++
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Queued returns the number of audit logs waiting to be delivered.
+func (w *Webhook) Queued() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.queued
+}
+
+// Close stops delivering audit logs. Undelivered audit logs remain queued on
+// disk and are delivered the next time a webhook backend uses the directory.
+func (w *Webhook) Close() error {
+	w.closeCancel()
+	<-w.closed
+	return nil
+}
+
+func (w *Webhook) run(ctx context.Context) {
+	defer close(w.closed)
+
+	var (
+		backoff = w.opts.RetryInterval
+		notify  = w.notify
+		// Deliver anything left over from a previous run immediately.
+		timer = time.NewTimer(0)
+	)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-notify:
+		case <-timer.C:
+		}
+
+		err := w.flush(ctx)
+		if err == nil {
+			backoff = w.opts.RetryInterval
+			notify = w.notify
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		w.opts.Logger.Warn(ctx, "deliver audit log to webhook",
+			slog.F("url", w.opts.URL.String()),
+			slog.F("retry_in", backoff),
+			slog.Error(err),
+		)
+
+		// Ignore new audit logs until the retry is due, otherwise every
+		// request would hit a receiver that is known to be down.
+		notify = nil
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(backoff)
+		backoff *= 2
+		if backoff > w.opts.MaxRetryInterval {
+			backoff = w.opts.MaxRetryInterval
+		}
+	}
+}
+
+// flush delivers queued audit logs in order until the queue is empty or a
+// delivery fails and must be retried. Audit logs the receiver rejects are
+// moved to the dead-letter file instead.
+func (w *Webhook) flush(ctx context.Context) error {
+	entries, err := queueEntries(w.opts.QueueDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(w.opts.QueueDir, entry)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return xerrors.Errorf("read queue entry: %w", err)
+		}
+		err = w.deliver(ctx, data)
+		var rejected *rejectedError
+		if errors.As(err, &rejected) {
+			// Retrying won't change the outcome, and would hold up every
+			// audit log queued after this one.
+			w.opts.Logger.Error(ctx, "audit log rejected by webhook, moving it to the dead-letter file",
+				slog.F("url", w.opts.URL.String()),
+				slog.F("status_code", rejected.statusCode),
+				slog.F("entry", entry),
+				slog.F("dead_letter_file", w.opts.DeadLetterFile),
+			)
+			err = w.deadLetter(data)
+		}
+		if err != nil {
+			return err
+		}
+		err = os.Remove(path)
+		if err != nil {
+			return xerrors.Errorf("remove queue entry: %w", err)
+		}
+		w.mutex.Lock()
+		w.queued--
+		w.mutex.Unlock()
+	}
+	return nil
+}
+
+func (w *Webhook) deliver(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.URL.String(), bytes.NewReader(data))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.opts.Secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+WebhookSignature(w.opts.Secret, data))
+	}
+
+	res, err := w.opts.HTTPClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("post audit log: %w", err)
+	}
+	defer res.Body.Close()
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode >= 400 && res.StatusCode < 500 &&
+		res.StatusCode != http.StatusRequestTimeout && res.StatusCode != http.StatusTooManyRequests {
+		return &rejectedError{statusCode: res.StatusCode}
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// deadLetter appends an audit log that was rejected to the dead-letter file.
+func (w *Webhook) deadLetter(data []byte) error {
+	file, err := os.OpenFile(w.opts.DeadLetterFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return xerrors.Errorf("open dead-letter file: %w", err)
+	}
+	_, err = file.Write(append(bytes.TrimSpace(data), '\n'))
+	if err != nil {
+		_ = file.Close()
+		return xerrors.Errorf("write dead-letter file: %w", err)
+	}
+	err = file.Close()
+	if err != nil {
+		return xerrors.Errorf("close dead-letter file: %w", err)
+	}
+	return nil
+}
+
+// rejectedError is returned when the receiver responds with a client error
+// that won't succeed if retried.
+type rejectedError struct {
+	statusCode int
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("audit log rejected with status code %d", e.statusCode)
+}
+
+// WebhookSignature returns the hex encoded HMAC-SHA256 of body.
+func WebhookSignature(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// queueEntries returns the committed entries in dir, oldest first.
+func queueEntries(dir string) ([]string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("read queue directory: %w", err)
+	}
+	entries := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		entries = append(entries, file.Name())
+	}
+	sort.Strings(entries)
+	return entries, nil
+}
@@ -0,0 +1,264 @@
+package backends_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/audit/backends"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestWebhookBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Signed", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			secret   = []byte("hunter2")
+			received = make(chan codersdk.AuditLog, 1)
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if r.Header.Get(backends.WebhookSignatureHeader) != "sha256="+backends.WebhookSignature(secret, body) {
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var alog codersdk.AuditLog
+			if !assert.NoError(t, json.Unmarshal(body, &alog)) {
+				return
+			}
+			received <- alog
+		}))
+		defer srv.Close()
+
+		backend := newWebhook(t, backends.WebhookOptions{
+			URL:    mustURL(t, srv.URL),
+			Secret: secret,
+		})
+
+		alog := randomAuditLog()
+		require.NoError(t, backend.Export(ctx, alog))
+		select {
+		case got := <-received:
+			require.Equal(t, alog.ID, got.ID)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for audit log")
+		}
+		require.Eventually(t, func() bool {
+			return backend.Queued() == 0
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			failures int32
+			mutex    sync.Mutex
+			received []uuid.UUID
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			// Fail the first few deliveries to simulate the receiver
+			// being down.
+			if atomic.AddInt32(&failures, 1) <= 3 {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var alog codersdk.AuditLog
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&alog)) {
+				return
+			}
+			mutex.Lock()
+			received = append(received, alog.ID)
+			mutex.Unlock()
+		}))
+		defer srv.Close()
+
+		backend := newWebhook(t, backends.WebhookOptions{
+			URL:           mustURL(t, srv.URL),
+			RetryInterval: 10 * time.Millisecond,
+		})
+
+		expected := make([]uuid.UUID, 0, 3)
+		for i := 0; i < 3; i++ {
+			alog := randomAuditLog()
+			expected = append(expected, alog.ID)
+			require.NoError(t, backend.Export(ctx, alog))
+		}
+		require.Eventually(t, func() bool {
+			return backend.Queued() == 0
+		}, 10*time.Second, 10*time.Millisecond)
+
+		// Audit logs are delivered in the order they were exported.
+		mutex.Lock()
+		defer mutex.Unlock()
+		require.Equal(t, expected, received)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			dir      = t.TempDir()
+			rejected = randomAuditLog()
+			accepted = randomAuditLog()
+			mutex    sync.Mutex
+			received []uuid.UUID
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var alog codersdk.AuditLog
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&alog)) {
+				return
+			}
+			if alog.ID == rejected.ID {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mutex.Lock()
+			received = append(received, alog.ID)
+			mutex.Unlock()
+		}))
+		defer srv.Close()
+
+		// Retries would never be due, so the rejected audit log must not be
+		// retried for the next one to be delivered.
+		backend := newWebhook(t, backends.WebhookOptions{
+			URL:           mustURL(t, srv.URL),
+			QueueDir:      dir,
+			RetryInterval: time.Hour,
+		})
+		require.NoError(t, backend.Export(ctx, rejected))
+		require.NoError(t, backend.Export(ctx, accepted))
+		require.Eventually(t, func() bool {
+			return backend.Queued() == 0
+		}, 10*time.Second, 10*time.Millisecond)
+
+		mutex.Lock()
+		require.Equal(t, []uuid.UUID{accepted.ID}, received)
+		mutex.Unlock()
+
+		// The rejected audit log is kept in the dead-letter file.
+		data, err := os.ReadFile(filepath.Join(dir, "rejected.jsonl"))
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 1)
+		var alog codersdk.AuditLog
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &alog))
+		require.Equal(t, rejected.ID, alog.ID)
+	})
+
+	t.Run("QueueFull", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		backend := newWebhook(t, backends.WebhookOptions{
+			URL:           mustURL(t, srv.URL),
+			MaxQueueSize:  2,
+			RetryInterval: time.Hour,
+		})
+
+		ctx := context.Background()
+		require.NoError(t, backend.Export(ctx, randomAuditLog()))
+		require.NoError(t, backend.Export(ctx, randomAuditLog()))
+		require.ErrorContains(t, backend.Export(ctx, randomAuditLog()), "queue is full")
+		require.Equal(t, 2, backend.Queued())
+	})
+
+	t.Run("Persisted", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			dir      = t.TempDir()
+			received = make(chan uuid.UUID, 1)
+		)
+		// Queue an audit log while the receiver is unreachable.
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+		backend, err := backends.NewWebhook(backends.WebhookOptions{
+			URL:           mustURL(t, down.URL),
+			QueueDir:      dir,
+			RetryInterval: time.Hour,
+			Logger:        slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+		})
+		require.NoError(t, err)
+		alog := randomAuditLog()
+		require.NoError(t, backend.Export(ctx, alog))
+		require.NoError(t, backend.Close())
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		// A new backend using the same queue delivers it.
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var got codersdk.AuditLog
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&got)) {
+				return
+			}
+			received <- got.ID
+		}))
+		defer srv.Close()
+		backend = newWebhook(t, backends.WebhookOptions{
+			URL:      mustURL(t, srv.URL),
+			QueueDir: dir,
+		})
+		select {
+		case id := <-received:
+			require.Equal(t, alog.ID, id)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for audit log")
+		}
+		require.Eventually(t, func() bool {
+			return backend.Queued() == 0
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+}
+
+func newWebhook(t *testing.T, opts backends.WebhookOptions) *backends.Webhook {
+	t.Helper()
+	if opts.QueueDir == "" {
+		opts.QueueDir = t.TempDir()
+	}
+	opts.Logger = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	backend, err := backends.NewWebhook(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = backend.Close()
+	})
+	return backend
+}
+
+func mustURL(t *testing.T, rawURL string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	return u
+}
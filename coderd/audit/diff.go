@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -139,6 +140,22 @@ func convertDiffType(left, right any) (newLeft, newRight any, changed bool) {
 
 		return leftInt64Ptr, rightInt64Ptr, true
 
+	case time.Time:
+		return typed.Format(time.RFC3339), right.(time.Time).Format(time.RFC3339), true
+
+	case sql.NullTime:
+		leftStr := typed.Time.Format(time.RFC3339)
+		if !typed.Valid {
+			leftStr = "null"
+		}
+
+		rightStr := right.(sql.NullTime).Time.Format(time.RFC3339)
+		if !right.(sql.NullTime).Valid {
+			rightStr = "null"
+		}
+
+		return leftStr, rightStr, true
+
 	default:
 		return left, right, false
 	}
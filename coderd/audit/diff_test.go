@@ -2,6 +2,7 @@ package audit_test
 
 import (
 	"database/sql"
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -16,6 +17,83 @@ import (
 func TestDiff(t *testing.T) {
 	t.Parallel()
 
+	runDiffTests(t, []diffTest[database.APIKey]{
+		{
+			name: "Create",
+			left: audit.Empty[database.APIKey](),
+			right: database.APIKey{
+				ID:               "keyid",
+				HashedSecret:     []byte("a very secret hash"),
+				UserID:           uuid.UUID{1},
+				LastUsed:         time.Now(),
+				ExpiresAt:        time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+				CreatedAt:        time.Now(),
+				UpdatedAt:        time.Now(),
+				LoginType:        database.LoginTypeToken,
+				LifetimeSeconds:  3600,
+				OAuthAccessToken: "a very secret token",
+				TokenName:        "ci",
+			},
+			exp: audit.Map{
+				"id":                 "keyid",
+				"hashed_secret":      []byte(nil),
+				"user_id":            uuid.UUID{1}.String(),
+				"expires_at":         "2022-08-01T00:00:00Z",
+				"login_type":         database.LoginTypeToken,
+				"lifetime_seconds":   int64(3600),
+				"oauth_access_token": "",
+				"token_name":         "ci",
+			},
+		},
+	})
+
+	runDiffTests(t, []diffTest[database.CustomRole]{
+		{
+			name: "Create",
+			left: audit.Empty[database.CustomRole](),
+			right: database.CustomRole{
+				ID:              uuid.UUID{1},
+				Name:            "log-reader",
+				DisplayName:     "Log Reader",
+				SitePermissions: json.RawMessage(`[{"resource_type":"audit_log","action":"read"}]`),
+				OrgPermissions:  json.RawMessage(`[]`),
+				UserPermissions: json.RawMessage(`[]`),
+				CreatedAt:       time.Now(),
+				UpdatedAt:       time.Now(),
+			},
+			exp: audit.Map{
+				"id":               uuid.UUID{1}.String(),
+				"name":             "log-reader",
+				"display_name":     "Log Reader",
+				"site_permissions": json.RawMessage(`[{"resource_type":"audit_log","action":"read"}]`),
+				"org_permissions":  json.RawMessage(`[]`),
+				"user_permissions": json.RawMessage(`[]`),
+			},
+		},
+	})
+
+	runDiffTests(t, []diffTest[database.Group]{
+		{
+			name: "Rename",
+			left: database.Group{
+				ID:             uuid.UUID{1},
+				Name:           "developers",
+				OrganizationID: uuid.UUID{2},
+				Roles:          []string{},
+			},
+			right: database.Group{
+				ID:             uuid.UUID{1},
+				Name:           "engineers",
+				OrganizationID: uuid.UUID{2},
+				Roles:          []string{},
+				UpdatedAt:      time.Now(),
+			},
+			exp: audit.Map{
+				"name": "engineers",
+			},
+		},
+	})
+
 	runDiffTests(t, []diffTest[database.GitSSHKey]{
 		{
 			name: "Create",
@@ -209,6 +287,24 @@ func TestDiff(t *testing.T) {
 				"name":        "rust workspace",
 			},
 		},
+		{
+			name: "Dormant",
+			left: audit.Empty[database.Workspace](),
+			right: database.Workspace{
+				ID:         uuid.UUID{1},
+				OwnerID:    uuid.UUID{2},
+				TemplateID: uuid.UUID{3},
+				Name:       "rust workspace",
+				DormantAt:  sql.NullTime{Time: time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+			},
+			exp: audit.Map{
+				"id":          uuid.UUID{1}.String(),
+				"owner_id":    uuid.UUID{2}.String(),
+				"template_id": uuid.UUID{3}.String(),
+				"name":        "rust workspace",
+				"dormant_at":  "2022-08-01T00:00:00Z",
+			},
+		},
 	})
 }
 
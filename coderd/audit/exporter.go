@@ -2,6 +2,8 @@ package audit
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"golang.org/x/xerrors"
 
@@ -33,12 +35,17 @@ func NewExporter(filter Filter, backends ...Backend) *Exporter {
 // Export exports and audit log. Before exporting to a backend, it uses the
 // filter to determine if the backend tolerates the audit log. If not, it is
 // dropped.
+//
+// A failing backend does not prevent the audit log from being sent to the
+// remaining backends. If any backend fails, an *ExportError describing each
+// failure is returned.
 func (e *Exporter) Export(ctx context.Context, alog database.AuditLog) error {
 	decision, err := e.filter.Check(ctx, alog)
 	if err != nil {
 		return xerrors.Errorf("filter check: %w", err)
 	}
 
+	var exportErr *ExportError
 	for _, backend := range e.backends {
 		if decision&backend.Decision() != backend.Decision() {
 			continue
@@ -46,10 +53,37 @@ func (e *Exporter) Export(ctx context.Context, alog database.AuditLog) error {
 
 		err = backend.Export(ctx, alog)
 		if err != nil {
-			// naively return the first error. should probably make this smarter
-			// by returning multiple errors.
-			return xerrors.Errorf("export audit log to backend: %w", err)
+			if exportErr == nil {
+				exportErr = &ExportError{}
+			}
+			exportErr.Failures = append(exportErr.Failures, BackendFailure{
+				Backend: backend,
+				Err:     err,
+			})
 		}
 	}
+	if exportErr != nil {
+		return exportErr
+	}
 	return nil
 }
+
+// BackendFailure is a single backend that failed to export an audit log.
+type BackendFailure struct {
+	Backend Backend
+	Err     error
+}
+
+// ExportError is returned by Exporter.Export when one or more backends fail to
+// export an audit log.
+type ExportError struct {
+	Failures []BackendFailure
+}
+
+func (e *ExportError) Error() string {
+	msgs := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		msgs = append(msgs, fmt.Sprintf("%T: %s", failure.Backend, failure.Err))
+	}
+	return fmt.Sprintf("export audit log to %d backend(s): %s", len(e.Failures), strings.Join(msgs, "; "))
+}
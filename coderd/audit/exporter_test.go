@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/tabbed/pqtype"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
@@ -94,6 +95,25 @@ func TestExporter(t *testing.T) {
 	}
 }
 
+func TestExporterBackendFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		failing  = &testBackend{decision: audit.FilterDecisionExport, err: xerrors.New("receiver is down")}
+		working  = &testBackend{decision: audit.FilterDecisionExport}
+		exporter = audit.NewExporter(audit.DefaultFilter, failing, working)
+	)
+
+	err := exporter.Export(context.Background(), randomAuditLog())
+	var exportErr *audit.ExportError
+	require.ErrorAs(t, err, &exportErr)
+	require.Len(t, exportErr.Failures, 1)
+	require.Equal(t, failing, exportErr.Failures[0].Backend)
+	require.ErrorContains(t, err, "receiver is down")
+	// Backends after the failing one still receive the audit log.
+	require.Len(t, working.alogs, 1)
+}
+
 func randomAuditLog() database.AuditLog {
 	_, inet, _ := net.ParseCIDR("127.0.0.1/32")
 	return database.AuditLog{
@@ -117,6 +137,7 @@ func randomAuditLog() database.AuditLog {
 
 type testBackend struct {
 	decision audit.FilterDecision
+	err      error
 
 	alogs []database.AuditLog
 }
@@ -126,6 +147,9 @@ func (t *testBackend) Decision() audit.FilterDecision {
 }
 
 func (t *testBackend) Export(_ context.Context, alog database.AuditLog) error {
+	if t.err != nil {
+		return t.err
+	}
 	t.alogs = append(t.alogs, alog)
 	return nil
 }
@@ -1,7 +1,16 @@
 package audit
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
 
 	"github.com/coder/coder/coderd/database"
 )
@@ -40,3 +49,177 @@ type FilterFunc func(ctx context.Context, alog database.AuditLog) (FilterDecisio
 func (f FilterFunc) Check(ctx context.Context, alog database.AuditLog) (FilterDecision, error) {
 	return f(ctx, alog)
 }
+
+// FilterRuleDecision is the human readable form of a FilterDecision used in
+// filter configuration files.
+type FilterRuleDecision string
+
+const (
+	FilterRuleDecisionDrop           FilterRuleDecision = "drop"
+	FilterRuleDecisionStoreOnly      FilterRuleDecision = "store_only"
+	FilterRuleDecisionExportOnly     FilterRuleDecision = "export_only"
+	FilterRuleDecisionStoreAndExport FilterRuleDecision = "store_and_export"
+)
+
+func (d FilterRuleDecision) decision() (FilterDecision, error) {
+	switch d {
+	case FilterRuleDecisionDrop:
+		return FilterDecisionDrop, nil
+	case FilterRuleDecisionStoreOnly:
+		return FilterDecisionStore, nil
+	case FilterRuleDecisionExportOnly:
+		return FilterDecisionExport, nil
+	case FilterRuleDecisionStoreAndExport:
+		return FilterDecisionStore | FilterDecisionExport, nil
+	default:
+		return FilterDecisionDrop, xerrors.Errorf("unknown decision %q", d)
+	}
+}
+
+// FilterConfig declares which audit logs are stored and exported. Rules are
+// evaluated in order, and the first rule that matches an audit log decides
+// what happens to it. Audit logs that match no rule use the default decision.
+//
+// An example configuration that only exports workspace deletions, and drops
+// every change to a user's git SSH key:
+//
+//	default: store_and_export
+//	rules:
+//	  - decision: drop
+//	    resource_types: [git_ssh_key]
+//	  - decision: store_and_export
+//	    resource_types: [workspace]
+//	    actions: [delete]
+//	  - decision: store_only
+//	    resource_types: [workspace]
+type FilterConfig struct {
+	// Default is the decision for audit logs that match no rule. Defaults to
+	// store_and_export.
+	Default FilterRuleDecision `yaml:"default" json:"default"`
+	Rules   []FilterRule       `yaml:"rules" json:"rules"`
+}
+
+// FilterRule matches audit logs by their fields. Each list matches if it's
+// empty or contains the audit log's value, and a rule matches if all of its
+// lists match.
+type FilterRule struct {
+	Decision        FilterRuleDecision      `yaml:"decision" json:"decision"`
+	ResourceTypes   []database.ResourceType `yaml:"resource_types" json:"resource_types"`
+	Actions         []database.AuditAction  `yaml:"actions" json:"actions"`
+	UserIDs         []uuid.UUID             `yaml:"user_ids" json:"user_ids"`
+	OrganizationIDs []uuid.UUID             `yaml:"organization_ids" json:"organization_ids"`
+	// DiffFields matches audit logs that changed any of the given fields.
+	DiffFields []string `yaml:"diff_fields" json:"diff_fields"`
+}
+
+// ParseFilterConfig parses a filter configuration. JSON is a subset of YAML,
+// so either format is accepted.
+func ParseFilterConfig(data []byte) (FilterConfig, error) {
+	var config FilterConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&config)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return FilterConfig{}, xerrors.Errorf("decode filter config: %w", err)
+	}
+	return config, nil
+}
+
+type compiledFilterRule struct {
+	FilterRule
+	decision FilterDecision
+}
+
+type ruleFilter struct {
+	defaultDecision FilterDecision
+	rules           []compiledFilterRule
+}
+
+// NewRuleFilter creates a Filter from a filter configuration.
+func NewRuleFilter(config FilterConfig) (Filter, error) {
+	if config.Default == "" {
+		config.Default = FilterRuleDecisionStoreAndExport
+	}
+	defaultDecision, err := config.Default.decision()
+	if err != nil {
+		return nil, xerrors.Errorf("default: %w", err)
+	}
+
+	rules := make([]compiledFilterRule, 0, len(config.Rules))
+	for i, rule := range config.Rules {
+		if rule.Decision == "" {
+			return nil, xerrors.Errorf("rule %d: decision must be provided", i)
+		}
+		decision, err := rule.Decision.decision()
+		if err != nil {
+			return nil, xerrors.Errorf("rule %d: %w", i, err)
+		}
+		for _, resourceType := range rule.ResourceTypes {
+			if !ValidResourceType(resourceType) {
+				return nil, xerrors.Errorf("rule %d: unknown resource type %q", i, resourceType)
+			}
+		}
+		for _, action := range rule.Actions {
+			if !ValidAction(action) {
+				return nil, xerrors.Errorf("rule %d: unknown action %q", i, action)
+			}
+		}
+		rules = append(rules, compiledFilterRule{
+			FilterRule: rule,
+			decision:   decision,
+		})
+	}
+
+	return &ruleFilter{
+		defaultDecision: defaultDecision,
+		rules:           rules,
+	}, nil
+}
+
+func (f *ruleFilter) Check(_ context.Context, alog database.AuditLog) (FilterDecision, error) {
+	var diff map[string]json.RawMessage
+	for _, rule := range f.rules {
+		if len(rule.DiffFields) > 0 && diff == nil {
+			// Only decode the diff once, and only if a rule needs it.
+			diff = map[string]json.RawMessage{}
+			if len(alog.Diff) > 0 {
+				err := json.Unmarshal(alog.Diff, &diff)
+				if err != nil {
+					return FilterDecisionDrop, xerrors.Errorf("decode diff: %w", err)
+				}
+			}
+		}
+		if rule.matches(alog, diff) {
+			return rule.decision, nil
+		}
+	}
+	return f.defaultDecision, nil
+}
+
+func (r compiledFilterRule) matches(alog database.AuditLog, diff map[string]json.RawMessage) bool {
+	if len(r.ResourceTypes) > 0 && !slices.Contains(r.ResourceTypes, alog.ResourceType) {
+		return false
+	}
+	if len(r.Actions) > 0 && !slices.Contains(r.Actions, alog.Action) {
+		return false
+	}
+	if len(r.UserIDs) > 0 && !slices.Contains(r.UserIDs, alog.UserID) {
+		return false
+	}
+	if len(r.OrganizationIDs) > 0 && !slices.Contains(r.OrganizationIDs, alog.OrganizationID) {
+		return false
+	}
+	if len(r.DiffFields) > 0 {
+		changed := false
+		for _, field := range r.DiffFields {
+			if _, ok := diff[field]; ok {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return false
+		}
+	}
+	return true
+}
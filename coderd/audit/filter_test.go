@@ -0,0 +1,221 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+)
+
+func TestRuleFilter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID = uuid.New()
+		orgID  = uuid.New()
+	)
+	workspaceLog := func(action database.AuditAction, diff audit.Map) database.AuditLog {
+		alog := randomAuditLog()
+		alog.ResourceType = database.ResourceTypeWorkspace
+		alog.Action = action
+		alog.UserID = userID
+		alog.OrganizationID = orgID
+		alog.Diff, _ = json.Marshal(diff)
+		return alog
+	}
+
+	tests := []struct {
+		name     string
+		config   audit.FilterConfig
+		alog     database.AuditLog
+		decision audit.FilterDecision
+	}{
+		{
+			name:     "EmptyConfig",
+			config:   audit.FilterConfig{},
+			alog:     workspaceLog(database.AuditActionCreate, nil),
+			decision: audit.FilterDecisionStore | audit.FilterDecisionExport,
+		},
+		{
+			name: "Default",
+			config: audit.FilterConfig{
+				Default: audit.FilterRuleDecisionStoreOnly,
+				Rules: []audit.FilterRule{{
+					Decision:      audit.FilterRuleDecisionDrop,
+					ResourceTypes: []database.ResourceType{database.ResourceTypeTemplate},
+				}},
+			},
+			alog:     workspaceLog(database.AuditActionCreate, nil),
+			decision: audit.FilterDecisionStore,
+		},
+		{
+			name: "FirstMatchWins",
+			config: audit.FilterConfig{
+				Rules: []audit.FilterRule{{
+					Decision:      audit.FilterRuleDecisionExportOnly,
+					ResourceTypes: []database.ResourceType{database.ResourceTypeWorkspace},
+					Actions:       []database.AuditAction{database.AuditActionDelete},
+				}, {
+					Decision:      audit.FilterRuleDecisionDrop,
+					ResourceTypes: []database.ResourceType{database.ResourceTypeWorkspace},
+				}},
+			},
+			alog:     workspaceLog(database.AuditActionDelete, nil),
+			decision: audit.FilterDecisionExport,
+		},
+		{
+			name: "FallsThroughToLaterRule",
+			config: audit.FilterConfig{
+				Rules: []audit.FilterRule{{
+					Decision:      audit.FilterRuleDecisionExportOnly,
+					ResourceTypes: []database.ResourceType{database.ResourceTypeWorkspace},
+					Actions:       []database.AuditAction{database.AuditActionDelete},
+				}, {
+					Decision:      audit.FilterRuleDecisionDrop,
+					ResourceTypes: []database.ResourceType{database.ResourceTypeWorkspace},
+				}},
+			},
+			alog:     workspaceLog(database.AuditActionWrite, nil),
+			decision: audit.FilterDecisionDrop,
+		},
+		{
+			name: "BroadRuleShadowsNarrowRule",
+			config: audit.FilterConfig{
+				Rules: []audit.FilterRule{{
+					Decision: audit.FilterRuleDecisionStoreOnly,
+					UserIDs:  []uuid.UUID{userID},
+				}, {
+					Decision:        audit.FilterRuleDecisionDrop,
+					UserIDs:         []uuid.UUID{userID},
+					OrganizationIDs: []uuid.UUID{orgID},
+				}},
+			},
+			alog:     workspaceLog(database.AuditActionWrite, nil),
+			decision: audit.FilterDecisionStore,
+		},
+		{
+			name: "AllConditionsMustMatch",
+			config: audit.FilterConfig{
+				Rules: []audit.FilterRule{{
+					Decision:        audit.FilterRuleDecisionDrop,
+					UserIDs:         []uuid.UUID{userID},
+					OrganizationIDs: []uuid.UUID{uuid.New()},
+				}},
+			},
+			alog:     workspaceLog(database.AuditActionWrite, nil),
+			decision: audit.FilterDecisionStore | audit.FilterDecisionExport,
+		},
+		{
+			name: "DiffField",
+			config: audit.FilterConfig{
+				Rules: []audit.FilterRule{{
+					Decision:   audit.FilterRuleDecisionDrop,
+					DiffFields: []string{"autostart_schedule", "ttl"},
+				}},
+			},
+			alog:     workspaceLog(database.AuditActionWrite, audit.Map{"ttl": 3600}),
+			decision: audit.FilterDecisionDrop,
+		},
+		{
+			name: "DiffFieldNotChanged",
+			config: audit.FilterConfig{
+				Rules: []audit.FilterRule{{
+					Decision:   audit.FilterRuleDecisionDrop,
+					DiffFields: []string{"autostart_schedule"},
+				}},
+			},
+			alog:     workspaceLog(database.AuditActionWrite, audit.Map{"name": "new"}),
+			decision: audit.FilterDecisionStore | audit.FilterDecisionExport,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := audit.NewRuleFilter(test.config)
+			require.NoError(t, err)
+			decision, err := filter.Check(context.Background(), test.alog)
+			require.NoError(t, err)
+			require.Equal(t, test.decision, decision)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, config := range []audit.FilterConfig{
+			{Default: "keep"},
+			{Rules: []audit.FilterRule{{}}},
+			{Rules: []audit.FilterRule{{Decision: "maybe"}}},
+			{Rules: []audit.FilterRule{{Decision: audit.FilterRuleDecisionDrop, ResourceTypes: []database.ResourceType{"nope"}}}},
+			{Rules: []audit.FilterRule{{Decision: audit.FilterRuleDecisionDrop, Actions: []database.AuditAction{"read"}}}},
+		} {
+			_, err := audit.NewRuleFilter(config)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestParseFilterConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("YAML", func(t *testing.T) {
+		t.Parallel()
+
+		userID := uuid.New()
+		config, err := audit.ParseFilterConfig([]byte(`
+default: store_only
+rules:
+  - decision: drop
+    resource_types: [git_ssh_key]
+    user_ids: [` + userID.String() + `]
+  - decision: export_only
+    actions: [delete]
+    diff_fields: [name]
+`))
+		require.NoError(t, err)
+		require.Equal(t, audit.FilterConfig{
+			Default: audit.FilterRuleDecisionStoreOnly,
+			Rules: []audit.FilterRule{{
+				Decision:      audit.FilterRuleDecisionDrop,
+				ResourceTypes: []database.ResourceType{database.ResourceTypeGitSSHKey},
+				UserIDs:       []uuid.UUID{userID},
+			}, {
+				Decision:   audit.FilterRuleDecisionExportOnly,
+				Actions:    []database.AuditAction{database.AuditActionDelete},
+				DiffFields: []string{"name"},
+			}},
+		}, config)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := audit.ParseFilterConfig([]byte(`{"rules": [{"decision": "drop", "resource_types": ["user"]}]}`))
+		require.NoError(t, err)
+		require.Len(t, config.Rules, 1)
+		require.Equal(t, []database.ResourceType{database.ResourceTypeUser}, config.Rules[0].ResourceTypes)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := audit.ParseFilterConfig(nil)
+		require.NoError(t, err)
+		require.Equal(t, audit.FilterConfig{}, config)
+	})
+
+	t.Run("UnknownField", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := audit.ParseFilterConfig([]byte(`rules: [{decision: drop, resource_type: user}]`))
+		require.Error(t, err)
+	})
+}
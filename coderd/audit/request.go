@@ -0,0 +1,383 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/tabbed/pqtype"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpmw"
+)
+
+// RequestParams describes the HTTP request an audit log is produced for.
+type RequestParams struct {
+	Auditor Auditor
+	Log     slog.Logger
+
+	Request *http.Request
+	Action  database.AuditAction
+}
+
+// Request holds the state of a resource before and after a mutating request.
+// Handlers set Old and New as the values become known, and the audit log is
+// committed when the function returned by InitRequest is called.
+type Request[T Auditable] struct {
+	params *RequestParams
+
+	Old T
+	New T
+}
+
+// InitRequest initializes an audit log for a request. It returns a function
+// that must be deferred, causing the audit log to be committed when the
+// handler returns.
+func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request[T], func()) {
+	sw, ok := w.(chimw.WrapResponseWriter)
+	if !ok {
+		panic("dev error: http.ResponseWriter is not chimw.WrapResponseWriter")
+	}
+
+	req := &Request[T]{
+		params: p,
+	}
+
+	return req, func() {
+		// Audit logs are committed after the response is written, so the
+		// request context may already be canceled.
+		ctx := context.Background()
+		logCtx := p.Request.Context()
+
+		// Prefer the new value of the resource, since it's what the request
+		// resulted in. Deletions only ever have an old value.
+		resource := req.New
+		if isEmpty(resource) {
+			resource = req.Old
+		}
+		// Handlers that fail before they know the resource, e.g. because the
+		// user isn't authorized, leave nothing worth auditing.
+		if isEmpty(resource) {
+			return
+		}
+
+		diff := Diff(req.Old, req.New)
+		diffRaw, err := json.Marshal(diff)
+		if err != nil {
+			p.Log.Warn(logCtx, "marshal diff", slog.Error(err))
+			diffRaw = []byte("{}")
+		}
+
+		var userID uuid.UUID
+		key, ok := httpmw.APIKeyOptional(p.Request)
+		if ok {
+			userID = key.UserID
+		}
+
+		statusCode := sw.Status()
+		if statusCode == 0 {
+			// Handlers that never call WriteHeader implicitly respond with
+			// a 200.
+			statusCode = http.StatusOK
+		}
+
+		err = p.Auditor.Export(ctx, database.AuditLog{
+			ID:             uuid.New(),
+			Time:           database.Now(),
+			UserID:         userID,
+			OrganizationID: ResourceOrganizationID(resource),
+			Ip:             parseIP(p.Request.RemoteAddr),
+			UserAgent:      truncate(p.Request.UserAgent(), 256),
+			ResourceType:   ResourceType(resource),
+			ResourceID:     ResourceID(resource),
+			ResourceTarget: ResourceTarget(resource),
+			Action:         p.Action,
+			Diff:           diffRaw,
+			StatusCode:     int32(statusCode),
+		})
+		if err != nil {
+			p.Log.Error(logCtx, "export audit log", slog.Error(err))
+			return
+		}
+	}
+}
+
+// BackgroundAuditParams describes an audit log for a change that isn't made by
+// an HTTP request, e.g. by a background job. A nil UserID means the change was
+// made by Coder itself. RemoteAddr and UserAgent are optional, and describe the
+// client that caused the change.
+type BackgroundAuditParams[T Auditable] struct {
+	Auditor Auditor
+	Log     slog.Logger
+
+	UserID     uuid.UUID
+	RemoteAddr string
+	UserAgent  string
+	Action     database.AuditAction
+	StatusCode int
+	Old        T
+	New        T
+}
+
+// BackgroundAudit exports an audit log for a change that isn't made by an
+// HTTP request.
+func BackgroundAudit[T Auditable](ctx context.Context, p *BackgroundAuditParams[T]) {
+	resource := p.New
+	if isEmpty(resource) {
+		resource = p.Old
+	}
+
+	diff := Diff(p.Old, p.New)
+	diffRaw, err := json.Marshal(diff)
+	if err != nil {
+		p.Log.Warn(ctx, "marshal diff", slog.Error(err))
+		diffRaw = []byte("{}")
+	}
+
+	statusCode := p.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	err = p.Auditor.Export(ctx, database.AuditLog{
+		ID:             uuid.New(),
+		Time:           database.Now(),
+		UserID:         p.UserID,
+		OrganizationID: ResourceOrganizationID(resource),
+		Ip:             parseIP(p.RemoteAddr),
+		UserAgent:      truncate(p.UserAgent, 256),
+		ResourceType:   ResourceType(resource),
+		ResourceID:     ResourceID(resource),
+		ResourceTarget: ResourceTarget(resource),
+		Action:         p.Action,
+		Diff:           diffRaw,
+		StatusCode:     int32(statusCode),
+	})
+	if err != nil {
+		p.Log.Error(ctx, "export audit log", slog.Error(err))
+	}
+}
+
+// ResourceTarget returns a human readable name for an auditable resource.
+func ResourceTarget[T Auditable](tgt T) string {
+	switch typed := any(tgt).(type) {
+	case database.APIKey:
+		// The ID isn't secret, it's shown to users to identify their keys.
+		if typed.TokenName != "" {
+			return typed.TokenName
+		}
+		return typed.ID
+	case database.CustomRole:
+		return typed.Name
+	case database.GitSSHKey:
+		return typed.PublicKey
+	case database.Group:
+		return typed.Name
+	case database.OrganizationMember:
+		return typed.UserID.String()
+	case database.Organization:
+		return typed.Name
+	case database.OrganizationQuota:
+		return typed.OrganizationID.String()
+	case database.Template:
+		return typed.Name
+	case database.TemplateVersion:
+		return typed.Name
+	case database.User:
+		return typed.Username
+	case database.UserLockout:
+		return typed.UserID.String()
+	case database.UserQuota:
+		return typed.UserID.String()
+	case database.UserTOTP:
+		return typed.UserID.String()
+	case database.Workspace:
+		return typed.Name
+	default:
+		panic(fmt.Sprintf("unknown resource %T", tgt))
+	}
+}
+
+// ResourceID returns the ID of an auditable resource.
+func ResourceID[T Auditable](tgt T) uuid.UUID {
+	switch typed := any(tgt).(type) {
+	case database.APIKey:
+		// API key IDs aren't UUIDs, so keys are identified by the user they
+		// belong to.
+		return typed.UserID
+	case database.CustomRole:
+		return typed.ID
+	case database.GitSSHKey:
+		// Git SSH keys are identified by the user they belong to.
+		return typed.UserID
+	case database.Group:
+		return typed.ID
+	case database.OrganizationMember:
+		return typed.UserID
+	case database.Organization:
+		return typed.ID
+	case database.OrganizationQuota:
+		// Organizations have a single quota, so it's identified by the
+		// organization.
+		return typed.OrganizationID
+	case database.Template:
+		return typed.ID
+	case database.TemplateVersion:
+		return typed.ID
+	case database.User:
+		return typed.ID
+	case database.UserLockout:
+		// Users have a single lockout, so it's identified by the user.
+		return typed.UserID
+	case database.UserQuota:
+		// Users have a single quota, so it's identified by the user.
+		return typed.UserID
+	case database.UserTOTP:
+		// Users have a single second factor, so it's identified by the user.
+		return typed.UserID
+	case database.Workspace:
+		return typed.ID
+	default:
+		panic(fmt.Sprintf("unknown resource %T", tgt))
+	}
+}
+
+// ResourceType returns the database resource type of an auditable resource.
+func ResourceType[T Auditable](tgt T) database.ResourceType {
+	switch any(tgt).(type) {
+	case database.APIKey:
+		return database.ResourceTypeAPIKey
+	case database.CustomRole:
+		return database.ResourceTypeCustomRole
+	case database.GitSSHKey:
+		return database.ResourceTypeGitSSHKey
+	case database.Group:
+		return database.ResourceTypeGroup
+	case database.OrganizationMember:
+		return database.ResourceTypeOrganizationMember
+	case database.Organization:
+		return database.ResourceTypeOrganization
+	case database.OrganizationQuota:
+		return database.ResourceTypeOrganizationQuota
+	case database.Template:
+		return database.ResourceTypeTemplate
+	case database.TemplateVersion:
+		return database.ResourceTypeTemplateVersion
+	case database.User:
+		return database.ResourceTypeUser
+	case database.UserLockout:
+		return database.ResourceTypeUserLockout
+	case database.UserQuota:
+		return database.ResourceTypeUserQuota
+	case database.UserTOTP:
+		return database.ResourceTypeUserTOTP
+	case database.Workspace:
+		return database.ResourceTypeWorkspace
+	default:
+		panic(fmt.Sprintf("unknown resource %T", tgt))
+	}
+}
+
+// ValidResourceType returns whether audit logs can be about resources of the
+// given type.
+func ValidResourceType(resourceType database.ResourceType) bool {
+	switch resourceType {
+	case database.ResourceTypeOrganization,
+		database.ResourceTypeTemplate,
+		database.ResourceTypeTemplateVersion,
+		database.ResourceTypeUser,
+		database.ResourceTypeWorkspace,
+		database.ResourceTypeOrganizationMember,
+		database.ResourceTypeGitSSHKey,
+		database.ResourceTypeAPIKey,
+		database.ResourceTypeUserTOTP,
+		database.ResourceTypeCustomRole,
+		database.ResourceTypeGroup,
+		database.ResourceTypeUserLockout,
+		database.ResourceTypeUserQuota,
+		database.ResourceTypeOrganizationQuota:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidAction returns whether audit logs can record the given action.
+func ValidAction(action database.AuditAction) bool {
+	switch action {
+	case database.AuditActionCreate, database.AuditActionWrite, database.AuditActionDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// ResourceOrganizationID returns the organization an auditable resource
+// belongs to. Site-wide resources return uuid.Nil.
+func ResourceOrganizationID[T Auditable](tgt T) uuid.UUID {
+	switch typed := any(tgt).(type) {
+	case database.APIKey:
+		return uuid.Nil
+	case database.CustomRole:
+		// Site roles don't belong to an organization, so this is uuid.Nil.
+		return typed.OrganizationID.UUID
+	case database.GitSSHKey:
+		return uuid.Nil
+	case database.Group:
+		return typed.OrganizationID
+	case database.OrganizationMember:
+		return typed.OrganizationID
+	case database.Organization:
+		return typed.ID
+	case database.OrganizationQuota:
+		return typed.OrganizationID
+	case database.Template:
+		return typed.OrganizationID
+	case database.TemplateVersion:
+		return typed.OrganizationID
+	case database.User:
+		return uuid.Nil
+	case database.UserLockout:
+		return uuid.Nil
+	case database.UserQuota:
+		return uuid.Nil
+	case database.UserTOTP:
+		return uuid.Nil
+	case database.Workspace:
+		return typed.OrganizationID
+	default:
+		panic(fmt.Sprintf("unknown resource %T", tgt))
+	}
+}
+
+func isEmpty[T Auditable](tgt T) bool {
+	return ResourceID(tgt) == uuid.Nil
+}
+
+func parseIP(addr string) pqtype.Inet {
+	host, _, _ := net.SplitHostPort(addr)
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ip = net.IPv4(0, 0, 0, 0)
+	}
+	bitlen := len(ip) * 8
+	return pqtype.Inet{
+		IPNet: net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(bitlen, bitlen),
+		},
+		Valid: true,
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,182 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+)
+
+func TestInitRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Write", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			auditor = audit.NewMock()
+			rw      = chimw.NewWrapResponseWriter(httptest.NewRecorder(), 1)
+			r       = httptest.NewRequest(http.MethodPatch, "/", nil)
+			old     = database.Template{
+				ID:             uuid.New(),
+				OrganizationID: uuid.New(),
+				Name:           "my-template",
+				Description:    "old",
+			}
+		)
+		r.RemoteAddr = "10.0.0.1:1234"
+		r.Header.Set("User-Agent", "audit-test")
+
+		aReq, commit := audit.InitRequest[database.Template](rw, &audit.RequestParams{
+			Auditor: auditor,
+			Log:     slogtest.Make(t, nil),
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+		aReq.Old = old
+		aReq.New = old
+		aReq.New.Description = "new"
+		rw.WriteHeader(http.StatusOK)
+		commit()
+
+		logs := auditor.AuditLogs()
+		require.Len(t, logs, 1)
+		alog := logs[0]
+		require.Equal(t, database.ResourceTypeTemplate, alog.ResourceType)
+		require.Equal(t, old.ID, alog.ResourceID)
+		require.Equal(t, old.OrganizationID, alog.OrganizationID)
+		require.Equal(t, "my-template", alog.ResourceTarget)
+		require.Equal(t, database.AuditActionWrite, alog.Action)
+		require.Equal(t, int32(http.StatusOK), alog.StatusCode)
+		require.Equal(t, "10.0.0.1", alog.Ip.IPNet.IP.String())
+		require.Equal(t, "audit-test", alog.UserAgent)
+
+		var diff audit.Map
+		require.NoError(t, json.Unmarshal(alog.Diff, &diff))
+		require.Equal(t, audit.Map{"description": "new"}, diff)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			auditor = audit.NewMock()
+			rw      = chimw.NewWrapResponseWriter(httptest.NewRecorder(), 1)
+			r       = httptest.NewRequest(http.MethodDelete, "/", nil)
+			old     = database.Workspace{
+				ID:   uuid.New(),
+				Name: "my-workspace",
+			}
+		)
+
+		aReq, commit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Auditor: auditor,
+			Log:     slogtest.Make(t, nil),
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+		aReq.Old = old
+		rw.WriteHeader(http.StatusNoContent)
+		commit()
+
+		logs := auditor.AuditLogs()
+		require.Len(t, logs, 1)
+		require.Equal(t, old.ID, logs[0].ResourceID)
+		require.Equal(t, "my-workspace", logs[0].ResourceTarget)
+		require.Equal(t, int32(http.StatusNoContent), logs[0].StatusCode)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			auditor = audit.NewMock()
+			rw      = chimw.NewWrapResponseWriter(httptest.NewRecorder(), 1)
+			r       = httptest.NewRequest(http.MethodPost, "/", nil)
+		)
+
+		_, commit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Auditor: auditor,
+			Log:     slogtest.Make(t, nil),
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+		rw.WriteHeader(http.StatusForbidden)
+		commit()
+
+		// Failed requests aren't recorded when the resource is unknown.
+		require.Empty(t, auditor.AuditLogs())
+	})
+
+	t.Run("FailedWithResource", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			auditor = audit.NewMock()
+			rw      = chimw.NewWrapResponseWriter(httptest.NewRecorder(), 1)
+			r       = httptest.NewRequest(http.MethodPatch, "/", nil)
+			user    = database.User{ID: uuid.New(), Username: "colin"}
+		)
+
+		req, commit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Auditor: auditor,
+			Log:     slogtest.Make(t, nil),
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+		req.Old = user
+		rw.WriteHeader(http.StatusForbidden)
+		commit()
+
+		// Failed requests on a known resource are still recorded.
+		logs := auditor.AuditLogs()
+		require.Len(t, logs, 1)
+		require.Equal(t, user.ID, logs[0].ResourceID)
+		require.Equal(t, int32(http.StatusForbidden), logs[0].StatusCode)
+	})
+}
+
+func TestBackgroundAudit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		auditor = audit.NewMock()
+		old     = database.User{
+			ID:       uuid.New(),
+			Username: "dormant",
+			Status:   database.UserStatusActive,
+		}
+		updated = old
+	)
+	updated.Status = database.UserStatusSuspended
+
+	audit.BackgroundAudit(context.Background(), &audit.BackgroundAuditParams[database.User]{
+		Auditor: auditor,
+		Log:     slogtest.Make(t, nil),
+		Action:  database.AuditActionWrite,
+		Old:     old,
+		New:     updated,
+	})
+
+	logs := auditor.AuditLogs()
+	require.Len(t, logs, 1)
+	alog := logs[0]
+	require.Equal(t, uuid.Nil, alog.UserID)
+	require.Equal(t, database.ResourceTypeUser, alog.ResourceType)
+	require.Equal(t, old.ID, alog.ResourceID)
+	require.Equal(t, "dormant", alog.ResourceTarget)
+	require.Equal(t, int32(http.StatusOK), alog.StatusCode)
+
+	var diff audit.Map
+	require.NoError(t, json.Unmarshal(alog.Diff, &diff))
+	require.Equal(t, audit.Map{"status": "suspended"}, diff)
+}
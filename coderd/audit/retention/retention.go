@@ -0,0 +1,267 @@
+package retention
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/audit/backends"
+	"github.com/coder/coder/coderd/database"
+)
+
+// batchSize is the number of audit logs archived and deleted per transaction.
+const batchSize = 1000
+
+var purgedTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "coderd",
+	Subsystem: "audit",
+	Name:      "logs_purged_total",
+	Help:      "The total number of audit logs deleted by the retention job",
+})
+
+// Options configures how long audit logs are kept.
+type Options struct {
+	// MaxAge is how long audit logs are kept before being purged. Audit logs
+	// are kept forever if zero.
+	MaxAge time.Duration
+	// OrganizationMaxAge overrides MaxAge for individual organizations, keyed
+	// by organization name or ID. A zero duration keeps the organization's
+	// audit logs forever.
+	OrganizationMaxAge map[string]time.Duration
+	// ArchiveDir is where purged audit logs are written before being deleted.
+	// Each batch of purged audit logs is written to a gzip compressed JSON
+	// lines file named after the oldest audit log of the batch.
+	ArchiveDir string
+}
+
+// Purger archives and deletes audit logs older than their organization's
+// maximum age.
+type Purger struct {
+	ctx     context.Context
+	db      database.Store
+	log     slog.Logger
+	tick    <-chan time.Time
+	opts    Options
+	statsCh chan<- Stats
+}
+
+// Stats contains information about one run of Purger.
+type Stats struct {
+	// Purged is the number of audit logs deleted in each organization.
+	Purged map[uuid.UUID]int64
+	// Archives are the files purged audit logs were written to.
+	Archives []string
+	Elapsed  time.Duration
+	Error    error
+}
+
+// New returns a new audit log retention purger.
+func New(ctx context.Context, db database.Store, log slog.Logger, tick <-chan time.Time, opts Options) *Purger {
+	return &Purger{
+		ctx:  ctx,
+		db:   db,
+		log:  log,
+		tick: tick,
+		opts: opts,
+	}
+}
+
+// WithStatsChannel will cause Purger to push a Stats to ch after every tick.
+func (p *Purger) WithStatsChannel(ch chan<- Stats) *Purger {
+	p.statsCh = ch
+	return p
+}
+
+// Run will cause the purger to purge expired audit logs on every tick from
+// its channel. It will stop when its channel is closed.
+func (p *Purger) Run() {
+	go func() {
+		for t := range p.tick {
+			stats := p.runOnce(t)
+			if stats.Error != nil {
+				p.log.Error(p.ctx, "error purging audit logs", slog.Error(stats.Error))
+			}
+			if p.statsCh != nil {
+				p.statsCh <- stats
+			}
+			p.log.Debug(p.ctx, "run stats", slog.F("elapsed", stats.Elapsed), slog.F("purged", stats.Purged))
+		}
+	}()
+}
+
+func (p *Purger) runOnce(t time.Time) (stats Stats) {
+	var err error
+	stats = Stats{
+		Purged: make(map[uuid.UUID]int64),
+	}
+	// stats is a named result so the deferred function sets the returned
+	// error.
+	defer func() {
+		stats.Elapsed = time.Since(t)
+		stats.Error = err
+	}()
+
+	maxAges, err := p.maxAges()
+	if err != nil {
+		return stats
+	}
+
+	for organizationID, maxAge := range maxAges {
+		if maxAge <= 0 {
+			continue
+		}
+		for {
+			var (
+				purged  int
+				archive string
+			)
+			err = p.db.InTx(func(db database.Store) error {
+				logs, err := db.GetAuditLogsOlderThan(p.ctx, database.GetAuditLogsOlderThanParams{
+					OrganizationID: organizationID,
+					TimeBefore:     t.Add(-maxAge),
+					RowLimit:       batchSize,
+				})
+				if err != nil {
+					return xerrors.Errorf("get expired audit logs: %w", err)
+				}
+				if len(logs) == 0 {
+					return nil
+				}
+
+				// The archive is written before the audit logs are deleted, so
+				// they're never lost. If the delete is rolled back, the next
+				// run selects the same oldest audit log again and replaces the
+				// archive instead of duplicating it.
+				archive, err = writeArchive(p.opts.ArchiveDir, organizationID, logs)
+				if err != nil {
+					return err
+				}
+				ids := make([]uuid.UUID, 0, len(logs))
+				for _, alog := range logs {
+					ids = append(ids, alog.ID)
+				}
+				err = db.DeleteAuditLogsByIDs(p.ctx, ids)
+				if err != nil {
+					return xerrors.Errorf("delete audit logs: %w", err)
+				}
+				purged = len(logs)
+				return nil
+			})
+			if err != nil {
+				return stats
+			}
+			if purged == 0 {
+				break
+			}
+			stats.Purged[organizationID] += int64(purged)
+			stats.Archives = append(stats.Archives, archive)
+			purgedTotal.Add(float64(purged))
+			if purged < batchSize {
+				break
+			}
+		}
+	}
+	return stats
+}
+
+// maxAges returns the maximum audit log age for every organization that has
+// audit logs.
+func (p *Purger) maxAges() (map[uuid.UUID]time.Duration, error) {
+	organizationIDs, err := p.db.GetAuditLogOrganizationIDs(p.ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get audit log organizations: %w", err)
+	}
+	maxAges := make(map[uuid.UUID]time.Duration, len(organizationIDs))
+	for _, organizationID := range organizationIDs {
+		maxAges[organizationID] = p.opts.MaxAge
+	}
+	if len(p.opts.OrganizationMaxAge) == 0 {
+		return maxAges, nil
+	}
+
+	organizations, err := p.db.GetOrganizations(p.ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get organizations: %w", err)
+	}
+	organizationsByName := make(map[string]uuid.UUID, len(organizations))
+	for _, organization := range organizations {
+		organizationsByName[organization.Name] = organization.ID
+	}
+	for organization, maxAge := range p.opts.OrganizationMaxAge {
+		organizationID, err := uuid.Parse(organization)
+		if err != nil {
+			var ok bool
+			organizationID, ok = organizationsByName[organization]
+			if !ok {
+				p.log.Warn(p.ctx, "audit log retention configured for unknown organization", slog.F("organization", organization))
+				continue
+			}
+		}
+		if _, ok := maxAges[organizationID]; ok {
+			maxAges[organizationID] = maxAge
+		}
+	}
+	return maxAges, nil
+}
+
+// writeArchive writes a batch of audit logs to a gzip compressed JSON lines
+// file named after the oldest audit log of the batch, replacing any earlier
+// archive of the same batch. It returns the path of the archive.
+func writeArchive(dir string, organizationID uuid.UUID, logs []database.AuditLog) (string, error) {
+	if dir == "" {
+		return "", xerrors.New("archive directory must be provided")
+	}
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", xerrors.Errorf("create archive directory: %w", err)
+	}
+	oldest := logs[0]
+	path := filepath.Join(dir, fmt.Sprintf("audit-logs-%s-%s-%s.jsonl.gz",
+		organizationID, oldest.Time.UTC().Format("20060102T150405Z"), oldest.ID))
+
+	// The archive is written to a temporary file first, so a partially
+	// written archive never replaces a complete one.
+	file, err := os.CreateTemp(dir, ".audit-logs-*.tmp")
+	if err != nil {
+		return "", xerrors.Errorf("create archive: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+	writer := gzip.NewWriter(file)
+	for _, alog := range logs {
+		data, err := backends.MarshalAuditLog(alog)
+		if err != nil {
+			return "", xerrors.Errorf("marshal audit log: %w", err)
+		}
+		_, err = writer.Write(append(data, '\n'))
+		if err != nil {
+			return "", xerrors.Errorf("write archive: %w", err)
+		}
+	}
+	err = writer.Close()
+	if err != nil {
+		return "", xerrors.Errorf("close archive: %w", err)
+	}
+	err = file.Sync()
+	if err != nil {
+		return "", xerrors.Errorf("sync archive: %w", err)
+	}
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return "", xerrors.Errorf("rename archive: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,236 @@
+package retention_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/audit/retention"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/databasefake"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestPurger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Purge", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx        = context.Background()
+			db         = databasefake.New()
+			now        = time.Now()
+			archiveDir = t.TempDir()
+			tickCh     = make(chan time.Time)
+			statsCh    = make(chan retention.Stats)
+		)
+		short := insertOrganization(t, db, "short")
+		forever := insertOrganization(t, db, "forever")
+		def := insertOrganization(t, db, "default")
+
+		shortOld := insertAuditLog(t, db, short.ID, now.Add(-2*time.Hour))
+		shortNew := insertAuditLog(t, db, short.ID, now.Add(-time.Minute))
+		foreverOld := insertAuditLog(t, db, forever.ID, now.Add(-365*24*time.Hour))
+		defOld := insertAuditLog(t, db, def.ID, now.Add(-48*time.Hour))
+		defNew := insertAuditLog(t, db, def.ID, now.Add(-2*time.Hour))
+		// Audit logs for users aren't in an organization.
+		noOrgOld := insertAuditLog(t, db, uuid.Nil, now.Add(-48*time.Hour))
+
+		retention.New(ctx, db, slogtest.Make(t, nil), tickCh, retention.Options{
+			MaxAge: 24 * time.Hour,
+			OrganizationMaxAge: map[string]time.Duration{
+				short.ID.String(): time.Hour,
+				forever.Name:      0,
+			},
+			ArchiveDir: archiveDir,
+		}).WithStatsChannel(statsCh).Run()
+		t.Cleanup(func() {
+			close(tickCh)
+		})
+
+		tickCh <- now
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Equal(t, map[uuid.UUID]int64{
+			short.ID: 1,
+			def.ID:   1,
+			uuid.Nil: 1,
+		}, stats.Purged)
+
+		logs, err := db.GetAuditLogs(ctx, database.GetAuditLogsParams{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{shortNew.ID, foreverOld.ID, defNew.ID}, auditLogIDs(logs))
+
+		require.Len(t, stats.Archives, 3)
+		archived := readArchives(t, stats.Archives...)
+		require.ElementsMatch(t, []uuid.UUID{shortOld.ID, defOld.ID, noOrgOld.ID}, archived)
+
+		// Nothing is left to purge, so no archive is written.
+		tickCh <- now
+		stats = <-statsCh
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.Archives)
+		require.Empty(t, stats.Purged)
+	})
+
+	t.Run("KeepForever", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			db      = databasefake.New()
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan retention.Stats)
+		)
+		org := insertOrganization(t, db, "org")
+		insertAuditLog(t, db, org.ID, time.Now().Add(-365*24*time.Hour))
+
+		retention.New(context.Background(), db, slogtest.Make(t, nil), tickCh, retention.Options{
+			ArchiveDir: t.TempDir(),
+		}).WithStatsChannel(statsCh).Run()
+		t.Cleanup(func() {
+			close(tickCh)
+		})
+
+		tickCh <- time.Now()
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Empty(t, stats.Purged)
+		require.Empty(t, stats.Archives)
+	})
+
+	t.Run("RolledBack", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			db         = &failDeleteStore{Store: databasefake.New(), fail: true}
+			now        = time.Now()
+			archiveDir = t.TempDir()
+			tickCh     = make(chan time.Time)
+			statsCh    = make(chan retention.Stats)
+		)
+		org := insertOrganization(t, db, "org")
+		old := insertAuditLog(t, db, org.ID, now.Add(-48*time.Hour))
+
+		retention.New(context.Background(), db, slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), tickCh, retention.Options{
+			MaxAge:     24 * time.Hour,
+			ArchiveDir: archiveDir,
+		}).WithStatsChannel(statsCh).Run()
+		t.Cleanup(func() {
+			close(tickCh)
+		})
+
+		// The first delete fails after the archive is written.
+		tickCh <- now
+		stats := <-statsCh
+		require.Error(t, stats.Error)
+
+		db.fail = false
+		tickCh <- now.Add(time.Minute)
+		stats = <-statsCh
+		require.NoError(t, stats.Error)
+		require.Equal(t, map[uuid.UUID]int64{org.ID: 1}, stats.Purged)
+
+		// The audit log was archived once.
+		entries, err := os.ReadDir(archiveDir)
+		require.NoError(t, err)
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			paths = append(paths, filepath.Join(archiveDir, entry.Name()))
+		}
+		require.Equal(t, []uuid.UUID{old.ID}, readArchives(t, paths...))
+	})
+}
+
+// failDeleteStore fails to delete audit logs while fail is set.
+type failDeleteStore struct {
+	database.Store
+	fail bool
+}
+
+func (s *failDeleteStore) InTx(fn func(database.Store) error) error {
+	return s.Store.InTx(func(db database.Store) error {
+		return fn(&failDeleteStore{Store: db, fail: s.fail})
+	})
+}
+
+func (s *failDeleteStore) DeleteAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	if s.fail {
+		return xerrors.New("delete failed")
+	}
+	return s.Store.DeleteAuditLogsByIDs(ctx, ids)
+}
+
+func insertOrganization(t *testing.T, db database.Store, name string) database.Organization {
+	t.Helper()
+	org, err := db.InsertOrganization(context.Background(), database.InsertOrganizationParams{
+		ID:        uuid.New(),
+		Name:      name,
+		CreatedAt: database.Now(),
+		UpdatedAt: database.Now(),
+	})
+	require.NoError(t, err)
+	return org
+}
+
+func insertAuditLog(t *testing.T, db database.Store, organizationID uuid.UUID, at time.Time) database.AuditLog {
+	t.Helper()
+	alog, err := db.InsertAuditLog(context.Background(), database.InsertAuditLogParams{
+		ID:             uuid.New(),
+		Time:           at,
+		UserID:         uuid.New(),
+		OrganizationID: organizationID,
+		ResourceType:   database.ResourceTypeWorkspace,
+		ResourceID:     uuid.New(),
+		ResourceTarget: "workspace",
+		Action:         database.AuditActionCreate,
+		Diff:           []byte("{}"),
+		StatusCode:     201,
+	})
+	require.NoError(t, err)
+	return alog
+}
+
+func auditLogIDs(logs []database.AuditLog) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(logs))
+	for _, alog := range logs {
+		ids = append(ids, alog.ID)
+	}
+	return ids
+}
+
+func readArchives(t *testing.T, paths ...string) []uuid.UUID {
+	t.Helper()
+	var ids []uuid.UUID
+	for _, path := range paths {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		reader, err := gzip.NewReader(file)
+		require.NoError(t, err)
+
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			var alog codersdk.AuditLog
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &alog))
+			ids = append(ids, alog.ID)
+		}
+		require.NoError(t, scanner.Err())
+	}
+	return ids
+}
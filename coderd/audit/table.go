@@ -10,12 +10,19 @@ import (
 // auditable types. If you want to audit a new type, first define it in
 // AuditableResources, then add it to this interface.
 type Auditable interface {
-	database.GitSSHKey |
+	database.APIKey |
+		database.CustomRole |
+		database.GitSSHKey |
+		database.Group |
 		database.OrganizationMember |
 		database.Organization |
+		database.OrganizationQuota |
 		database.Template |
 		database.TemplateVersion |
 		database.User |
+		database.UserLockout |
+		database.UserQuota |
+		database.UserTOTP |
 		database.Workspace
 }
 
@@ -39,6 +46,35 @@ type Table map[string]map[string]Action
 // AuditableResources contains a definitive list of all auditable resources and
 // which fields are auditable.
 var AuditableResources = auditMap(map[any]map[string]Action{
+	&database.APIKey{}: {
+		"id":                  ActionTrack,
+		"hashed_secret":       ActionSecret, // We don't want to expose the hash of the secret in diffs.
+		"user_id":             ActionTrack,
+		"last_used":           ActionIgnore, // Changes as the key is used, not helpful in a diff.
+		"expires_at":          ActionTrack,
+		"created_at":          ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":          ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"login_type":          ActionTrack,
+		"oauth_access_token":  ActionSecret, // We don't want to expose OAuth tokens in diffs.
+		"oauth_refresh_token": ActionSecret,
+		"oauth_id_token":      ActionSecret,
+		"oauth_expiry":        ActionIgnore, // Changes as OAuth tokens are refreshed.
+		"lifetime_seconds":    ActionTrack,
+		"ip_address":          ActionIgnore, // Changes as the key is used, not helpful in a diff.
+		"scopes":              ActionTrack,
+		"token_name":          ActionTrack,
+	},
+	&database.CustomRole{}: {
+		"id":               ActionTrack,
+		"name":             ActionTrack,
+		"display_name":     ActionTrack,
+		"organization_id":  ActionTrack,
+		"site_permissions": ActionTrack,
+		"org_permissions":  ActionTrack,
+		"user_permissions": ActionTrack,
+		"created_at":       ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":       ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
 	&database.GitSSHKey{}: {
 		"user_id":     ActionTrack,
 		"created_at":  ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
@@ -46,6 +82,14 @@ var AuditableResources = auditMap(map[any]map[string]Action{
 		"private_key": ActionSecret, // We don't want to expose private keys in diffs.
 		"public_key":  ActionTrack,  // Public keys are ok to expose in a diff.
 	},
+	&database.Group{}: {
+		"id":              ActionTrack,
+		"name":            ActionTrack,
+		"organization_id": ActionTrack,
+		"roles":           ActionTrack,
+		"created_at":      ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":      ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
 	&database.OrganizationMember{}: {
 		"user_id":         ActionTrack,
 		"organization_id": ActionTrack,
@@ -60,19 +104,35 @@ var AuditableResources = auditMap(map[any]map[string]Action{
 		"created_at":  ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
 		"updated_at":  ActionIgnore, // Changes, but is implicit and not helpful in a diff.
 	},
-	&database.Template{}: {
-		"id":                     ActionTrack,
-		"created_at":             ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
-		"updated_at":             ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	&database.OrganizationQuota{}: {
 		"organization_id":        ActionTrack,
-		"deleted":                ActionIgnore, // Changes, but is implicit when a delete event is fired.
-		"name":                   ActionTrack,
-		"provisioner":            ActionTrack,
-		"active_version_id":      ActionTrack,
-		"description":            ActionTrack,
-		"max_ttl":                ActionTrack,
-		"min_autostart_interval": ActionTrack,
-		"created_by":             ActionTrack,
+		"max_workspaces":         ActionTrack,
+		"max_running_workspaces": ActionTrack,
+		"max_cost_units":         ActionTrack,
+		"updated_at":             ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.Template{}: {
+		"id":                           ActionTrack,
+		"created_at":                   ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":                   ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"organization_id":              ActionTrack,
+		"deleted":                      ActionIgnore, // Changes, but is implicit when a delete event is fired.
+		"name":                         ActionTrack,
+		"provisioner":                  ActionTrack,
+		"active_version_id":            ActionTrack,
+		"description":                  ActionTrack,
+		"max_ttl":                      ActionTrack,
+		"min_autostart_interval":       ActionTrack,
+		"created_by":                   ActionTrack,
+		"group_acl":                    ActionTrack,
+		"user_acl":                     ActionTrack,
+		"cost_units":                   ActionTrack,
+		"inactivity_ttl":               ActionTrack,
+		"dormant_deletion_ttl":         ActionTrack,
+		"default_autostart_schedule":   ActionTrack,
+		"default_ttl":                  ActionTrack,
+		"required_stop_schedule":       ActionTrack,
+		"allow_user_schedule_override": ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":              ActionTrack,
@@ -93,6 +153,31 @@ var AuditableResources = auditMap(map[any]map[string]Action{
 		"updated_at":      ActionIgnore, // Changes, but is implicit and not helpful in a diff.
 		"status":          ActionTrack,
 		"rbac_roles":      ActionTrack,
+		"last_seen_at":    ActionIgnore, // Changes as the user uses Coder, not helpful in a diff.
+	},
+	&database.UserLockout{}: {
+		"user_id":         ActionTrack,
+		"failed_attempts": ActionTrack,
+		"locked_until":    ActionTrack,
+		"updated_at":      ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.UserQuota{}: {
+		"user_id":                ActionTrack,
+		"max_workspaces":         ActionTrack,
+		"max_running_workspaces": ActionTrack,
+		"max_cost_units":         ActionTrack,
+		"updated_at":             ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.UserTOTP{}: {
+		"user_id":                    ActionTrack,
+		"secret":                     ActionSecret, // We don't want to expose second factor secrets in diffs.
+		"enabled":                    ActionTrack,
+		"last_used_step":             ActionIgnore, // Changes on every login, not helpful in a diff.
+		"login_challenge":            ActionIgnore, // Changes on every login, not helpful in a diff.
+		"login_challenge_expires_at": ActionIgnore,
+		"login_challenge_attempts":   ActionIgnore,
+		"created_at":                 ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":                 ActionIgnore, // Changes, but is implicit and not helpful in a diff.
 	},
 	&database.Workspace{}: {
 		"id":                 ActionTrack,
@@ -105,6 +190,9 @@ var AuditableResources = auditMap(map[any]map[string]Action{
 		"name":               ActionTrack,
 		"autostart_schedule": ActionTrack,
 		"ttl":                ActionTrack,
+		"user_acl":           ActionTrack,
+		"dormant_at":         ActionTrack,
+		"dormancy_opt_out":   ActionTrack,
 	},
 })
 
@@ -0,0 +1,131 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Filter", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		logs, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{})
+		require.NoError(t, err)
+		require.NotEmpty(t, logs)
+		// Newest first.
+		require.Equal(t, codersdk.ResourceTypeTemplate, logs[0].ResourceType)
+		require.Equal(t, template.ID, logs[0].ResourceID)
+		require.NotNil(t, logs[0].User)
+		require.Equal(t, user.UserID, logs[0].User.ID)
+
+		logs, err = client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			SearchQuery: "resource_type:template_version action:create user:me",
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, version.ID, logs[0].ResourceID)
+
+		logs, err = client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			SearchQuery: "resource_id:" + template.ID.String(),
+		})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, codersdk.AuditActionCreate, logs[0].Action)
+		require.Equal(t, int32(http.StatusCreated), logs[0].StatusCode)
+
+		logs, err = client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			SearchQuery: "action:delete",
+		})
+		require.NoError(t, err)
+		require.Empty(t, logs)
+
+		logs, err = client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			SearchQuery: "user:doesnotexist",
+		})
+		require.NoError(t, err)
+		require.Empty(t, logs)
+
+		logs, err = client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			SearchQuery: `date_from:"2000-01-01T00:00:00Z" date_to:2000-01-01`,
+		})
+		require.NoError(t, err)
+		require.Empty(t, logs)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		for i := 0; i < 3; i++ {
+			coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		}
+
+		all, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(all), 4)
+
+		page, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			Pagination: codersdk.Pagination{Limit: 2},
+		})
+		require.NoError(t, err)
+		require.Equal(t, all[:2], page)
+
+		page, err = client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			Pagination: codersdk.Pagination{AfterID: all[1].ID, Limit: 2},
+		})
+		require.NoError(t, err)
+		require.Equal(t, all[2:4], page)
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.AuditLogs(context.Background(), codersdk.AuditLogsRequest{
+			SearchQuery: "action:explode status_code:abc",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 2)
+	})
+
+	t.Run("Auditor", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		auditor := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, "auditor")
+
+		logs, err := auditor.AuditLogs(context.Background(), codersdk.AuditLogsRequest{})
+		require.NoError(t, err)
+		require.NotEmpty(t, logs)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		_, err := member.AuditLogs(context.Background(), codersdk.AuditLogsRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
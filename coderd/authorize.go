@@ -12,7 +12,8 @@ import (
 
 func AuthorizeFilter[O rbac.Objecter](api *API, r *http.Request, action rbac.Action, objects []O) []O {
 	roles := httpmw.AuthorizationUserRoles(r)
-	return rbac.Filter(r.Context(), api.Authorizer, roles.ID.String(), roles.Roles, action, objects)
+	scopes := httpmw.APIKey(r).Scopes
+	return rbac.Filter(r.Context(), api.Authorizer, roles.ID.String(), roles.Roles, scopes, roles.Groups, action, objects)
 }
 
 // Authorize will return false if the user is not authorized to do the action.
@@ -25,7 +26,8 @@ func AuthorizeFilter[O rbac.Objecter](api *API, r *http.Request, action rbac.Act
 //	}
 func (api *API) Authorize(r *http.Request, action rbac.Action, object rbac.Objecter) bool {
 	roles := httpmw.AuthorizationUserRoles(r)
-	err := api.Authorizer.ByRoleName(r.Context(), roles.ID.String(), roles.Roles, action, object.RBACObject())
+	scopes := httpmw.APIKey(r).Scopes
+	err := api.Authorizer.ByRoleName(r.Context(), roles.ID.String(), roles.Roles, scopes, roles.Groups, action, object.RBACObject())
 	if err != nil {
 		// Log the errors for debugging
 		internalError := new(rbac.UnauthorizedError)
@@ -37,6 +39,7 @@ func (api *API) Authorize(r *http.Request, action rbac.Action, object rbac.Objec
 		// in the early days
 		logger.Warn(r.Context(), "unauthorized",
 			slog.F("roles", roles.Roles),
+			slog.F("scopes", scopes),
 			slog.F("user_id", roles.ID),
 			slog.F("username", roles.Username),
 			slog.F("route", r.URL.Path),
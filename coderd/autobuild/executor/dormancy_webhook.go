@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+// DormancyWarningWebhookPayload is the JSON body POSTed by
+// DormancyWarningWebhook. It contains the contact details of the owner so the
+// receiver can notify them, e.g. by email.
+type DormancyWarningWebhookPayload struct {
+	WorkspaceID   uuid.UUID           `json:"workspace_id"`
+	WorkspaceName string              `json:"workspace_name"`
+	OwnerID       uuid.UUID           `json:"owner_id"`
+	OwnerUsername string              `json:"owner_username"`
+	OwnerEmail    string              `json:"owner_email"`
+	Kind          DormancyWarningKind `json:"kind"`
+	At            time.Time           `json:"at"`
+}
+
+// DormancyWarningWebhook returns a function for WithDormancyWarnings that
+// POSTs each warning to url. Warnings are delivered in the background so a
+// slow receiver doesn't hold up the executor, and failures are logged.
+func DormancyWarningWebhook(ctx context.Context, db database.Store, log slog.Logger, client *http.Client, url string) func(DormancyWarning) {
+	return func(warning DormancyWarning) {
+		go func() {
+			err := postDormancyWarning(ctx, db, client, url, warning)
+			if err != nil {
+				log.Warn(ctx, "deliver dormancy warning",
+					slog.F("workspace_id", warning.WorkspaceID),
+					slog.Error(err),
+				)
+			}
+		}()
+	}
+}
+
+func postDormancyWarning(ctx context.Context, db database.Store, client *http.Client, url string, warning DormancyWarning) error {
+	workspace, err := db.GetWorkspaceByID(ctx, warning.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	owner, err := db.GetUserByID(ctx, warning.OwnerID)
+	if err != nil {
+		return xerrors.Errorf("get owner: %w", err)
+	}
+	body, err := json.Marshal(DormancyWarningWebhookPayload{
+		WorkspaceID:   workspace.ID,
+		WorkspaceName: workspace.Name,
+		OwnerID:       owner.ID,
+		OwnerUsername: owner.Username,
+		OwnerEmail:    owner.Email,
+		Kind:          warning.Kind,
+		At:            warning.At,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal warning: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("post warning: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
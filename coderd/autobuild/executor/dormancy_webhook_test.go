@@ -0,0 +1,76 @@
+package executor_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/coderd/autobuild/executor"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/databasefake"
+)
+
+func TestDormancyWarningWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := databasefake.New()
+	owner, err := db.InsertUser(ctx, database.InsertUserParams{
+		ID:        uuid.New(),
+		Email:     "alice@coder.com",
+		Username:  "alice",
+		CreatedAt: database.Now(),
+		UpdatedAt: database.Now(),
+	})
+	require.NoError(t, err)
+	workspace, err := db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
+		ID:             uuid.New(),
+		CreatedAt:      database.Now(),
+		UpdatedAt:      database.Now(),
+		OwnerID:        owner.ID,
+		OrganizationID: uuid.New(),
+		TemplateID:     uuid.New(),
+		Name:           "dev",
+	})
+	require.NoError(t, err)
+
+	payloads := make(chan executor.DormancyWarningWebhookPayload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var payload executor.DormancyWarningWebhookPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		rw.WriteHeader(http.StatusNoContent)
+		payloads <- payload
+	}))
+	defer srv.Close()
+
+	// Given: a warning that the workspace will be marked dormant
+	at := database.Now().Add(time.Hour)
+	warn := executor.DormancyWarningWebhook(ctx, db, slogtest.Make(t, nil), srv.Client(), srv.URL)
+
+	// When: the executor warns about it
+	warn(executor.DormancyWarning{
+		WorkspaceID: workspace.ID,
+		OwnerID:     owner.ID,
+		Kind:        executor.DormancyWarningDormant,
+		At:          at,
+	})
+
+	// Then: the webhook receives the contact details of the owner
+	payload := <-payloads
+	require.Equal(t, workspace.ID, payload.WorkspaceID)
+	require.Equal(t, "dev", payload.WorkspaceName)
+	require.Equal(t, owner.ID, payload.OwnerID)
+	require.Equal(t, "alice", payload.OwnerUsername)
+	require.Equal(t, "alice@coder.com", payload.OwnerEmail)
+	require.Equal(t, executor.DormancyWarningDormant, payload.Kind)
+	require.True(t, at.Equal(payload.At))
+}
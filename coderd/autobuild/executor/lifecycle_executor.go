@@ -2,33 +2,75 @@ package executor
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"time"
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/coderd/autobuild/notify"
 	"github.com/coder/coder/coderd/autobuild/schedule"
 	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/quota"
 
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
 	"golang.org/x/xerrors"
 )
 
-// Executor automatically starts or stops workspaces.
+// Executor automatically starts or stops workspaces, and marks workspaces
+// that stay stopped for too long dormant and eventually deletes them.
 type Executor struct {
 	ctx     context.Context
 	db      database.Store
 	log     slog.Logger
 	tick    <-chan time.Time
 	statsCh chan<- Stats
+
+	warn      func(DormancyWarning)
+	countdown []time.Duration
+	// notifiers are only accessed from the goroutine started by Run.
+	notifiers map[dormancyNotifierKey]*notify.Notifier
 }
 
 // Stats contains information about one run of Executor.
 type Stats struct {
 	Transitions map[uuid.UUID]database.WorkspaceTransition
-	Elapsed     time.Duration
-	Error       error
+	// Dormant contains the workspaces that were marked dormant.
+	Dormant []uuid.UUID
+	Elapsed time.Duration
+	Error   error
+}
+
+// DormancyWarningCountdown is how long before a workspace is marked dormant
+// or deleted its owner is warned by default.
+var DormancyWarningCountdown = []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour}
+
+// DormancyWarningKind is what will happen to the workspace a DormancyWarning
+// is about.
+type DormancyWarningKind string
+
+const (
+	// DormancyWarningDormant warns that a stopped workspace will be marked
+	// dormant.
+	DormancyWarningDormant DormancyWarningKind = "dormant"
+	// DormancyWarningDelete warns that a dormant workspace will be deleted.
+	DormancyWarningDelete DormancyWarningKind = "delete"
+)
+
+// DormancyWarning is sent ahead of a workspace being marked dormant or
+// deleted.
+type DormancyWarning struct {
+	WorkspaceID uuid.UUID
+	OwnerID     uuid.UUID
+	Kind        DormancyWarningKind
+	At          time.Time
+}
+
+type dormancyNotifierKey struct {
+	workspaceID uuid.UUID
+	kind        DormancyWarningKind
+	at          time.Time
 }
 
 // New returns a new autobuild executor.
@@ -49,6 +91,15 @@ func (e *Executor) WithStatsChannel(ch chan<- Stats) *Executor {
 	return e
 }
 
+// WithDormancyWarnings will cause Executor to call fn once for every
+// duration in countdown before a workspace is marked dormant or deleted.
+func (e *Executor) WithDormancyWarnings(fn func(DormancyWarning), countdown ...time.Duration) *Executor {
+	e.warn = fn
+	e.countdown = countdown
+	e.notifiers = make(map[dormancyNotifierKey]*notify.Notifier)
+	return e
+}
+
 // Run will cause executor to start or stop workspaces on every
 // tick from its channel. It will stop when its context is Done, or when
 // its channel is closed.
@@ -62,7 +113,7 @@ func (e *Executor) Run() {
 			if e.statsCh != nil {
 				e.statsCh <- stats
 			}
-			e.log.Debug(e.ctx, "run stats", slog.F("elapsed", stats.Elapsed), slog.F("transitions", stats.Transitions))
+			e.log.Debug(e.ctx, "run stats", slog.F("elapsed", stats.Elapsed), slog.F("transitions", stats.Transitions), slog.F("dormant", stats.Dormant))
 		}
 	}()
 }
@@ -121,23 +172,42 @@ func (e *Executor) runOnce(t time.Time) Stats {
 				continue
 			}
 
+			template, err := db.GetTemplateByID(e.ctx, ws.TemplateID)
+			if err != nil {
+				e.log.Warn(e.ctx, "get workspace template",
+					slog.F("workspace_id", ws.ID),
+					slog.Error(err),
+				)
+				continue
+			}
+
 			var validTransition database.WorkspaceTransition
 			var nextTransition time.Time
 			switch priorHistory.Transition {
 			case database.WorkspaceTransitionStart:
 				validTransition = database.WorkspaceTransitionStop
-				if priorHistory.Deadline.IsZero() {
+				// The template may require the workspace to be stopped
+				// earlier, whatever its deadline.
+				//
+				// For stopping, do not truncate. This is inconsistent with autostart, but
+				// it ensures we will not stop too early.
+				nextTransition = Deadline(template, ws, priorHistory.Deadline, priorJob.CompletedAt.Time)
+				if nextTransition.IsZero() {
 					e.log.Debug(e.ctx, "latest workspace build has zero deadline, skipping",
 						slog.F("workspace_id", ws.ID),
 						slog.F("workspace_build_id", priorHistory.ID),
 					)
 					continue
 				}
-				// For stopping, do not truncate. This is inconsistent with autostart, but
-				// it ensures we will not stop too early.
-				nextTransition = priorHistory.Deadline
 			case database.WorkspaceTransitionStop:
 				validTransition = database.WorkspaceTransitionStart
+				if ws.DormantAt.Valid {
+					e.log.Debug(e.ctx, "workspace is dormant, skipping autostart",
+						slog.F("workspace_id", ws.ID),
+						slog.F("dormant_at", ws.DormantAt.Time),
+					)
+					continue
+				}
 				sched, err := schedule.Weekly(ws.AutostartSchedule.String)
 				if err != nil {
 					e.log.Debug(e.ctx, "workspace has invalid autostart schedule, skipping",
@@ -167,13 +237,40 @@ func (e *Executor) runOnce(t time.Time) Stats {
 				continue
 			}
 
+			if validTransition == database.WorkspaceTransitionStart {
+				// Other transactions may wait for quotas this one holds, so
+				// it doesn't wait for theirs. The workspace is started on a
+				// later tick instead.
+				err = quota.TryCheck(e.ctx, db, quota.Workspace{
+					OwnerID:        ws.OwnerID,
+					OrganizationID: ws.OrganizationID,
+					Template:       template,
+					Starting:       true,
+				})
+				var exceeded *quota.ExceededError
+				if xerrors.As(err, &exceeded) {
+					e.log.Info(e.ctx, "workspace quota exceeded, skipping autostart",
+						slog.F("workspace_id", ws.ID),
+						slog.F("detail", exceeded.Detail),
+					)
+					continue
+				}
+				if err != nil {
+					e.log.Warn(e.ctx, "check workspace quota, skipping autostart",
+						slog.F("workspace_id", ws.ID),
+						slog.Error(err),
+					)
+					continue
+				}
+			}
+
 			e.log.Info(e.ctx, "scheduling workspace transition",
 				slog.F("workspace_id", ws.ID),
 				slog.F("transition", validTransition),
 			)
 
 			stats.Transitions[ws.ID] = validTransition
-			if err := build(e.ctx, db, ws, validTransition, priorHistory, priorJob); err != nil {
+			if err := Build(e.ctx, db, ws, validTransition, priorHistory, priorJob); err != nil {
 				e.log.Error(e.ctx, "unable to transition workspace",
 					slog.F("workspace_id", ws.ID),
 					slog.F("transition", validTransition),
@@ -181,14 +278,179 @@ func (e *Executor) runOnce(t time.Time) Stats {
 				)
 			}
 		}
-		return nil
+
+		return e.runDormancy(db, currentTick, &stats)
 	})
 	return stats
 }
 
+// runDormancy marks workspaces that have been stopped for longer than the
+// inactivity TTL of their template dormant, and deletes workspaces that have
+// been dormant for longer than the dormant deletion TTL.
+func (e *Executor) runDormancy(db database.Store, currentTick time.Time, stats *Stats) error {
+	eligibleWorkspaces, err := db.GetWorkspacesEligibleForDormancy(e.ctx)
+	if err != nil {
+		return xerrors.Errorf("get eligible workspaces for dormancy: %w", err)
+	}
+
+	warned := make(map[dormancyNotifierKey]bool)
+	for _, ws := range eligibleWorkspaces {
+		template, err := db.GetTemplateByID(e.ctx, ws.TemplateID)
+		if err != nil {
+			e.log.Warn(e.ctx, "get workspace template",
+				slog.F("workspace_id", ws.ID),
+				slog.Error(err),
+			)
+			continue
+		}
+
+		priorHistory, err := db.GetLatestWorkspaceBuildByWorkspaceID(e.ctx, ws.ID)
+		if err != nil {
+			e.log.Warn(e.ctx, "get latest workspace build",
+				slog.F("workspace_id", ws.ID),
+				slog.Error(err),
+			)
+			continue
+		}
+
+		priorJob, err := db.GetProvisionerJobByID(e.ctx, priorHistory.JobID)
+		if err != nil {
+			e.log.Warn(e.ctx, "get last provisioner job for workspace",
+				slog.F("workspace_id", ws.ID),
+				slog.Error(err),
+			)
+			continue
+		}
+
+		// Only workspaces that were stopped successfully are inactive.
+		if priorHistory.Transition != database.WorkspaceTransitionStop || !priorJob.CompletedAt.Valid || priorJob.Error.String != "" {
+			continue
+		}
+
+		var (
+			kind DormancyWarningKind
+			at   time.Time
+		)
+		if ws.DormantAt.Valid {
+			if template.DormantDeletionTtl <= 0 {
+				continue
+			}
+			kind = DormancyWarningDelete
+			at = ws.DormantAt.Time.Add(time.Duration(template.DormantDeletionTtl))
+		} else {
+			if template.InactivityTtl <= 0 {
+				continue
+			}
+			kind = DormancyWarningDormant
+			at = priorJob.CompletedAt.Time.Add(time.Duration(template.InactivityTtl))
+		}
+
+		if currentTick.Before(at) {
+			if e.warn != nil {
+				key := dormancyNotifierKey{workspaceID: ws.ID, kind: kind, at: at}
+				warned[key] = true
+				e.notifyDormancy(key, ws.OwnerID, currentTick)
+			}
+			continue
+		}
+
+		switch kind {
+		case DormancyWarningDormant:
+			e.log.Info(e.ctx, "marking workspace dormant",
+				slog.F("workspace_id", ws.ID),
+			)
+			err = db.UpdateWorkspaceDormantAt(e.ctx, database.UpdateWorkspaceDormantAtParams{
+				ID: ws.ID,
+				DormantAt: sql.NullTime{
+					Time:  currentTick,
+					Valid: true,
+				},
+			})
+			if err != nil {
+				e.log.Error(e.ctx, "unable to mark workspace dormant",
+					slog.F("workspace_id", ws.ID),
+					slog.Error(err),
+				)
+				continue
+			}
+			stats.Dormant = append(stats.Dormant, ws.ID)
+		case DormancyWarningDelete:
+			e.log.Info(e.ctx, "scheduling deletion of dormant workspace",
+				slog.F("workspace_id", ws.ID),
+			)
+			stats.Transitions[ws.ID] = database.WorkspaceTransitionDelete
+			if err := Build(e.ctx, db, ws, database.WorkspaceTransitionDelete, priorHistory, priorJob); err != nil {
+				e.log.Error(e.ctx, "unable to delete dormant workspace",
+					slog.F("workspace_id", ws.ID),
+					slog.Error(err),
+				)
+			}
+		}
+	}
+
+	// Forget about warnings that no longer apply, e.g. because the workspace
+	// was started.
+	for key := range e.notifiers {
+		if !warned[key] {
+			delete(e.notifiers, key)
+		}
+	}
+	return nil
+}
+
+// notifyDormancy warns about a workspace being marked dormant or deleted if
+// the time left crossed a value of the countdown since the last warning.
+func (e *Executor) notifyDormancy(key dormancyNotifierKey, ownerID uuid.UUID, currentTick time.Time) {
+	notifier, ok := e.notifiers[key]
+	if !ok {
+		warning := DormancyWarning{
+			WorkspaceID: key.workspaceID,
+			OwnerID:     ownerID,
+			Kind:        key.kind,
+			At:          key.at,
+		}
+		notifier = notify.New(func(time.Time) (time.Time, func()) {
+			return warning.At, func() {
+				e.warn(warning)
+			}
+		}, e.countdown...)
+		e.notifiers[key] = notifier
+	}
+	notifier.PollOnce(currentTick)
+}
+
+// Deadline returns deadline brought forward to the first time after t at
+// which the template of a workspace requires it to be stopped. A zero deadline
+// is manual shutdown. The required stop schedule is evaluated in the timezone
+// of the owner's autostart schedule for the workspace, and in UTC if there's
+// none.
+func Deadline(template database.Template, workspace database.Workspace, deadline, t time.Time) time.Time {
+	if template.RequiredStopSchedule == "" {
+		return deadline
+	}
+	loc := time.UTC
+	if workspace.AutostartSchedule.Valid {
+		if autostart, err := schedule.Weekly(workspace.AutostartSchedule.String); err == nil {
+			loc = autostart.Location()
+		}
+	}
+	sched, err := schedule.Weekly("CRON_TZ=" + loc.String() + " " + template.RequiredStopSchedule)
+	if err != nil {
+		return deadline
+	}
+	requiredStop := sched.Next(t)
+	if deadline.IsZero() || requiredStop.Before(deadline) {
+		return requiredStop
+	}
+	return deadline
+}
+
+// Build queues an automatic start, stop or deletion of a workspace on behalf
+// of its owner, reusing the template version and state of its prior build.
+//
 // TODO(cian): this function duplicates most of api.postWorkspaceBuilds. Refactor.
 // See: https://github.com/coder/coder/issues/1401
-func build(ctx context.Context, store database.Store, workspace database.Workspace, trans database.WorkspaceTransition, priorHistory database.WorkspaceBuild, priorJob database.ProvisionerJob) error {
+func Build(ctx context.Context, store database.Store, workspace database.Workspace, trans database.WorkspaceTransition, priorHistory database.WorkspaceBuild, priorJob database.ProvisionerJob) error {
 	template, err := store.GetTemplateByID(ctx, workspace.TemplateID)
 	if err != nil {
 		return xerrors.Errorf("get workspace template: %w", err)
@@ -216,6 +478,8 @@ func build(ctx context.Context, store database.Store, workspace database.Workspa
 		buildReason = database.BuildReasonAutostart
 	case database.WorkspaceTransitionStop:
 		buildReason = database.BuildReasonAutostop
+	case database.WorkspaceTransitionDelete:
+		buildReason = database.BuildReasonAutodelete
 	default:
 		return xerrors.Errorf("Unsupported transition: %q", trans)
 	}
@@ -2,10 +2,13 @@ package executor_test
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/goleak"
 
 	"github.com/coder/coder/coderd/autobuild/executor"
@@ -173,6 +176,90 @@ func TestExecutorAutostartNotEnabled(t *testing.T) {
 	require.Len(t, stats.Transitions, 0)
 }
 
+func TestExecutorAutostartQuotaExceeded(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan executor.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:     tickCh,
+			IncludeProvisionerD: true,
+			AutobuildStats:      statsCh,
+		})
+		// Given: we have a user with a workspace that has autostart enabled
+		workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = ptr.Ref(sched.String())
+		})
+	)
+	// Given: workspace is stopped
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+	// Given: the user already runs as many workspaces as their quota allows
+	_, err := client.UpdateUserQuota(ctx, codersdk.Me, codersdk.QuotaLimits{MaxRunningWorkspaces: 1})
+	require.NoError(t, err)
+	user, err := client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	other := coderdtest.CreateWorkspace(t, client, user.OrganizationIDs[0], workspace.TemplateID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, other.LatestBuild.ID)
+
+	// When: the autobuild executor ticks after the scheduled time
+	go func() {
+		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
+		close(tickCh)
+	}()
+
+	// Then: the workspace should not be started
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Transitions, 0)
+}
+
+func TestExecutorAutostartSuspendedOwner(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan executor.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:     tickCh,
+			IncludeProvisionerD: true,
+			AutobuildStats:      statsCh,
+		})
+		admin    = coderdtest.CreateFirstUser(t, client)
+		version  = coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		template = coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		_        = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		member   = coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		// Given: we have a member with a workspace that has autostart enabled
+		workspace = coderdtest.CreateWorkspace(t, member, admin.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = ptr.Ref(sched.String())
+		})
+	)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	// Given: workspace is stopped
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+	// Given: the member is suspended
+	_, err := client.UpdateUserStatus(ctx, workspace.OwnerID.String(), codersdk.UserStatusSuspended)
+	require.NoError(t, err)
+
+	// When: the autobuild executor ticks after the scheduled time
+	go func() {
+		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
+		close(tickCh)
+	}()
+
+	// Then: the workspace should not be started
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Transitions, 0)
+}
+
 func TestExecutorAutostopOK(t *testing.T) {
 	t.Parallel()
 
@@ -540,6 +627,204 @@ func TestExecutorAutostartMultipleOK(t *testing.T) {
 	assert.Len(t, stats2.Transitions, 0)
 }
 
+func TestExecutorDormancy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		tickCh   = make(chan time.Time)
+		statsCh  = make(chan executor.Stats)
+		warnings = make(chan executor.DormancyWarning, 10)
+		client   = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:     tickCh,
+			IncludeProvisionerD: true,
+			AutobuildStats:      statsCh,
+			AutobuildDormancyWarnings: func(warning executor.DormancyWarning) {
+				warnings <- warning
+			},
+		})
+		// Given: we have a user with a workspace that does not have autostart enabled
+		workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = nil
+		})
+	)
+	// Given: the template marks workspaces dormant after an hour, and deletes them after a day
+	_, err := client.UpdateTemplateMeta(ctx, workspace.TemplateID, codersdk.UpdateTemplateMeta{
+		InactivityTTLMillis:      ptr.Ref(time.Hour.Milliseconds()),
+		DormantDeletionTTLMillis: ptr.Ref((24 * time.Hour).Milliseconds()),
+	})
+	require.NoError(t, err)
+
+	// Given: workspace is stopped
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+	require.NotNil(t, workspace.LatestBuild.Job.CompletedAt)
+	stoppedAt := *workspace.LatestBuild.Job.CompletedAt
+	// Then: the owner can see when the workspace will be marked dormant
+	require.NotNil(t, workspace.DormantAfter)
+	require.WithinDuration(t, stoppedAt.Add(time.Hour), *workspace.DormantAfter, time.Second)
+
+	// When: the autobuild executor ticks less than an hour after the workspace stopped
+	tickCh <- stoppedAt.Add(30 * time.Minute)
+
+	// Then: the workspace should not be dormant, but its owner should be warned
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Dormant, 0)
+	require.Len(t, stats.Transitions, 0)
+	warning := <-warnings
+	require.Equal(t, workspace.ID, warning.WorkspaceID)
+	require.Equal(t, executor.DormancyWarningDormant, warning.Kind)
+	require.WithinDuration(t, stoppedAt.Add(time.Hour), warning.At, time.Second)
+
+	// When: the autobuild executor ticks after the inactivity TTL
+	tickCh <- stoppedAt.Add(time.Hour + time.Minute)
+
+	// Then: the workspace should be dormant
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{workspace.ID}, stats.Dormant)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.NotNil(t, workspace.DormantAt)
+	require.NotNil(t, workspace.DeletingAt)
+	require.Equal(t, workspace.DormantAt.Add(24*time.Hour), *workspace.DeletingAt)
+
+	// Then: starting the workspace should require confirmation
+	_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStart,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusPreconditionFailed, apiErr.StatusCode())
+
+	// When: the autobuild executor ticks after the dormant deletion TTL
+	tickCh <- workspace.DeletingAt.Add(time.Minute)
+	close(tickCh)
+
+	// Then: the workspace should be deleted
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Transitions, 1)
+	require.Equal(t, database.WorkspaceTransitionDelete, stats.Transitions[workspace.ID])
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.Equal(t, codersdk.BuildReasonAutodelete, workspace.LatestBuild.Reason)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+}
+
+func TestExecutorDormancyWake(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan executor.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:     tickCh,
+			IncludeProvisionerD: true,
+			AutobuildStats:      statsCh,
+		})
+		// Given: we have a user with a workspace
+		workspace = mustProvisionWorkspace(t, client)
+	)
+	// Given: the template marks workspaces dormant after an hour
+	_, err := client.UpdateTemplateMeta(ctx, workspace.TemplateID, codersdk.UpdateTemplateMeta{
+		InactivityTTLMillis: ptr.Ref(time.Hour.Milliseconds()),
+	})
+	require.NoError(t, err)
+
+	// Given: workspace is dormant
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+	tickCh <- workspace.LatestBuild.Job.CompletedAt.Add(time.Hour + time.Minute)
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Dormant, 1)
+
+	// When: the owner confirms starting the workspace
+	build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition:     codersdk.WorkspaceTransitionStart,
+		ConfirmDormant: true,
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+
+	// Then: the workspace should no longer be dormant
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.Nil(t, workspace.DormantAt)
+	require.Nil(t, workspace.DeletingAt)
+
+	// When: the workspace opts out of dormancy and is stopped again
+	err = client.UpdateWorkspaceDormancy(ctx, workspace.ID, codersdk.UpdateWorkspaceDormancyRequest{
+		OptOut: true,
+	})
+	require.NoError(t, err)
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+	tickCh <- workspace.LatestBuild.Job.CompletedAt.Add(24 * time.Hour)
+	close(tickCh)
+
+	// Then: the workspace should not become dormant
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Dormant, 0)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.True(t, workspace.DormancyOptOut)
+	require.Nil(t, workspace.DormantAt)
+}
+
+func TestExecutorRequiredStop(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan executor.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:     tickCh,
+			IncludeProvisionerD: true,
+			AutobuildStats:      statsCh,
+		})
+		// Given: we have a user in India with a running workspace that stops
+		// after 8 hours
+		workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = ptr.Ref("CRON_TZ=Asia/Kolkata 30 9 * * 1-5")
+		})
+	)
+	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	require.NotNil(t, workspace.LatestBuild.Job.CompletedAt)
+
+	// Given: the template requires workspaces to be stopped an hour after the
+	// workspace started, in the timezone of the owner
+	loc := mustSchedule(t, *workspace.AutostartSchedule).Location()
+	require.Equal(t, "Asia/Kolkata", loc.String())
+	requiredStop := workspace.LatestBuild.Job.CompletedAt.Add(time.Hour).In(loc).Truncate(time.Minute)
+	require.True(t, requiredStop.Before(workspace.LatestBuild.Deadline))
+	_, err := client.UpdateTemplateMeta(ctx, workspace.TemplateID, codersdk.UpdateTemplateMeta{
+		RequiredStopSchedule: ptr.Ref(fmt.Sprintf("%d %d * * *", requiredStop.Minute(), requiredStop.Hour())),
+	})
+	require.NoError(t, err)
+
+	// When: the autobuild executor ticks before the required stop
+	tickCh <- requiredStop.Add(-time.Minute)
+
+	// Then: the workspace should not be stopped
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Transitions, 0)
+
+	// When: the autobuild executor ticks after the required stop, but before the deadline
+	go func() {
+		tickCh <- requiredStop.Add(time.Minute)
+		close(tickCh)
+	}()
+
+	// Then: the workspace should be stopped
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Transitions, 1)
+	require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[workspace.ID])
+
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.Equal(t, codersdk.BuildReasonAutostop, workspace.LatestBuild.Reason)
+}
+
 func mustProvisionWorkspace(t *testing.T, client *codersdk.Client, mut ...func(*codersdk.CreateWorkspaceRequest)) codersdk.Workspace {
 	t.Helper()
 	user := coderdtest.CreateFirstUser(t, client)
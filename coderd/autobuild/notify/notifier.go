@@ -64,6 +64,12 @@ func (n *Notifier) Poll(ticker <-chan time.Time) {
 	}
 }
 
+// PollOnce polls once with the given time. It's useful for callers that
+// already poll on their own schedule.
+func (n *Notifier) PollOnce(tick time.Time) {
+	n.pollOnce(tick)
+}
+
 func (n *Notifier) pollOnce(tick time.Time) {
 	n.lock.Lock()
 	defer n.lock.Unlock()
@@ -22,6 +22,8 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/buildinfo"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/audit/backends"
 	"github.com/coder/coder/coderd/awsidentity"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/gitsshkey"
@@ -31,6 +33,7 @@ import (
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/tracing"
 	"github.com/coder/coder/coderd/turnconn"
+	"github.com/coder/coder/coderd/userpassword"
 	"github.com/coder/coder/coderd/wsconncache"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/site"
@@ -53,16 +56,38 @@ type Options struct {
 	// app. Specific routes may have their own limiters.
 	APIRateLimit         int
 	AWSCertificates      awsidentity.Certificates
+	Auditor              audit.Auditor
 	Authorizer           rbac.Authorizer
 	AzureCertificates    x509.VerifyOptions
 	GoogleTokenValidator *idtoken.Validator
 	GithubOAuth2Config   *GithubOAuth2Config
+	OIDCConfig           *OIDCConfig
 	ICEServers           []webrtc.ICEServer
 	SecureAuthCookie     bool
 	SSHKeygenAlgorithm   gitsshkey.Algorithm
 	Telemetry            telemetry.Reporter
 	TURNServer           *turnconn.Server
 	TracerProvider       *sdktrace.TracerProvider
+	// TOTPRequiredForAdmins requires owners that log in with a password to
+	// enroll in TOTP.
+	TOTPRequiredForAdmins bool
+	// SCIMAPIKey authenticates identity providers provisioning users with
+	// SCIM. Provisioning is disabled when it's empty.
+	SCIMAPIKey []byte
+	// PasswordPolicy is the requirements passwords must meet when they're
+	// set.
+	PasswordPolicy userpassword.Policy
+	// LoginMaxAttempts is the number of failed password logins after which
+	// users are locked out for LoginLockoutDuration. Failed logins are
+	// forgotten after LoginLockoutDuration too. Lockouts are disabled when
+	// it's zero.
+	LoginMaxAttempts     int
+	LoginLockoutDuration time.Duration
+	// MaxTokenLifetime is the longest lifetime users can give the tokens
+	// they create.
+	MaxTokenLifetime time.Duration
+	// Now returns the current time. It's overridden in tests.
+	Now func() time.Time
 }
 
 // New constructs a Coder API handler.
@@ -77,14 +102,29 @@ func New(options *Options) *API {
 	if options.APIRateLimit == 0 {
 		options.APIRateLimit = 512
 	}
+	if options.Now == nil {
+		options.Now = database.Now
+	}
+	if options.LoginLockoutDuration == 0 {
+		options.LoginLockoutDuration = 15 * time.Minute
+	}
+	if options.MaxTokenLifetime == 0 {
+		options.MaxTokenLifetime = 365 * 24 * time.Hour
+	}
 	if options.Authorizer == nil {
-		var err error
-		options.Authorizer, err = rbac.NewAuthorizer()
+		authorizer, err := rbac.NewAuthorizer()
 		if err != nil {
 			// This should never happen, as the unit tests would fail if the
 			// default built in authorizer failed.
 			panic(xerrors.Errorf("rego authorize panic: %w", err))
 		}
+		options.Authorizer = authorizer.WithRoleLookup(customRoleLookup(options.Database))
+	}
+
+	if options.Auditor == nil {
+		options.Auditor = audit.NewExporter(audit.DefaultFilter,
+			backends.NewPostgres(options.Database, true),
+		)
 	}
 
 	siteCacheDir := options.CacheDir
@@ -105,6 +145,8 @@ func New(options *Options) *API {
 	api.workspaceAgentCache = wsconncache.New(api.dialWorkspaceAgent, 0)
 	oauthConfigs := &httpmw.OAuth2Configs{
 		Github: options.GithubOAuth2Config,
+		OIDC:   options.OIDCConfig,
+		Resync: api.resyncGroupRoles,
 	}
 	apiKeyMiddleware := httpmw.ExtractAPIKey(options.Database, oauthConfigs, false)
 
@@ -116,6 +158,7 @@ func New(options *Options) *API {
 		},
 		httpmw.Prometheus,
 		tracing.HTTPMW(api.TracerProvider, "coderd.http"),
+		withCustomRoleCache,
 	)
 
 	apps := func(r chi.Router) {
@@ -152,6 +195,12 @@ func New(options *Options) *API {
 		// All CSP errors will be logged
 		r.Post("/csp/reports", api.logReportCSPViolations)
 
+		r.Route("/audit", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+			)
+			r.Get("/", api.auditLogs)
+		})
 		r.Route("/buildinfo", func(r chi.Router) {
 			r.Get("/", func(rw http.ResponseWriter, r *http.Request) {
 				httpapi.Write(rw, http.StatusOK, codersdk.BuildInfoResponse{
@@ -180,12 +229,17 @@ func New(options *Options) *API {
 			r.Use(
 				apiKeyMiddleware,
 			)
+			r.Get("/", api.organizations)
 			r.Post("/", api.postOrganizations)
 			r.Route("/{organization}", func(r chi.Router) {
 				r.Use(
 					httpmw.ExtractOrganizationParam(options.Database),
 				)
 				r.Get("/", api.organization)
+				r.Patch("/", api.patchOrganization)
+				r.Delete("/", api.deleteOrganization)
+				r.Get("/quota", api.organizationQuota)
+				r.Put("/quota", api.putOrganizationQuota)
 				r.Post("/templateversions", api.postTemplateVersionsByOrganization)
 				r.Route("/templates", func(r chi.Router) {
 					r.Post("/", api.postTemplateByOrganization)
@@ -193,18 +247,54 @@ func New(options *Options) *API {
 					r.Get("/{templatename}", api.templateByOrganizationAndName)
 				})
 				r.Post("/workspaces", api.postWorkspacesByOrganization)
+				r.Route("/groups", func(r chi.Router) {
+					r.Post("/", api.postGroupByOrganization)
+					r.Get("/", api.groupsByOrganization)
+					r.Get("/{groupname}", api.groupByOrganizationAndName)
+				})
+				r.Route("/roles", func(r chi.Router) {
+					r.Get("/", api.customRoles(true))
+					r.Post("/", api.postCustomRole(true))
+					r.Put("/{role}", api.putCustomRole(true))
+					r.Delete("/{role}", api.deleteCustomRole(true))
+				})
 				r.Route("/members", func(r chi.Router) {
+					r.Get("/", api.organizationMembers)
 					r.Get("/roles", api.assignableOrgRoles)
 					r.Route("/{user}", func(r chi.Router) {
 						r.Use(
 							httpmw.ExtractUserParam(options.Database),
-							httpmw.ExtractOrganizationMemberParam(options.Database),
 						)
-						r.Put("/roles", api.putMemberRoles)
+						r.Post("/", api.postOrganizationMember)
+						r.Group(func(r chi.Router) {
+							r.Use(
+								httpmw.ExtractOrganizationMemberParam(options.Database),
+							)
+							r.Delete("/", api.deleteOrganizationMember)
+							r.Put("/roles", api.putMemberRoles)
+						})
 					})
 				})
 			})
 		})
+		// These routes manage custom site roles.
+		r.Route("/roles", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.customRoles(false))
+			r.Post("/", api.postCustomRole(false))
+			r.Put("/{role}", api.putCustomRole(false))
+			r.Delete("/{role}", api.deleteCustomRole(false))
+		})
+		r.Route("/groups/{group}", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				httpmw.ExtractGroupParam(options.Database),
+			)
+			r.Get("/", api.group)
+			r.Patch("/", api.patchGroup)
+			r.Delete("/", api.deleteGroup)
+			r.Put("/roles", api.putGroupRoles)
+		})
 		r.Route("/parameters/{scope}/{id}", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Post("/", api.postParameter)
@@ -213,6 +303,15 @@ func New(options *Options) *API {
 				r.Delete("/", api.deleteParameter)
 			})
 		})
+		r.Route("/scim/v2", func(r chi.Router) {
+			r.Use(api.scimAuthorize)
+			r.Route("/Users", func(r chi.Router) {
+				r.Get("/", api.scimGetUsers)
+				r.Post("/", api.scimPostUser)
+				r.Get("/{id}", api.scimGetUser)
+				r.Patch("/{id}", api.scimPatchUser)
+			})
+		})
 		r.Route("/templates/{template}", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -222,6 +321,8 @@ func New(options *Options) *API {
 			r.Get("/", api.template)
 			r.Delete("/", api.deleteTemplate)
 			r.Patch("/", api.patchTemplateMeta)
+			r.Get("/acl", api.templateACL)
+			r.Patch("/acl", api.patchTemplateACL)
 			r.Route("/versions", func(r chi.Router) {
 				r.Get("/", api.templateVersionsByTemplate)
 				r.Patch("/", api.patchActiveTemplateVersion)
@@ -252,13 +353,19 @@ func New(options *Options) *API {
 			r.Get("/first", api.firstUser)
 			r.Post("/first", api.postFirstUser)
 			r.Post("/login", api.postLogin)
+			r.Post("/login/totp", api.postLoginTOTP)
 			r.Get("/authmethods", api.userAuthMethods)
+			r.Get("/password-policy", api.passwordPolicy)
 			r.Route("/oauth2", func(r chi.Router) {
 				r.Route("/github", func(r chi.Router) {
 					r.Use(httpmw.ExtractOAuth2(options.GithubOAuth2Config))
 					r.Get("/callback", api.userOAuth2Github)
 				})
 			})
+			r.Route("/oidc", func(r chi.Router) {
+				r.Use(httpmw.ExtractOAuth2(options.OIDCConfig))
+				r.Get("/callback", api.userOIDC)
+			})
 			r.Group(func(r chi.Router) {
 				r.Use(
 					apiKeyMiddleware,
@@ -273,6 +380,7 @@ func New(options *Options) *API {
 				r.Route("/{user}", func(r chi.Router) {
 					r.Use(httpmw.ExtractUserParam(options.Database))
 					r.Get("/", api.userByName)
+					r.Delete("/", api.deleteUser)
 					r.Put("/profile", api.putUserProfile)
 					r.Route("/status", func(r chi.Router) {
 						r.Put("/suspend", api.putUserStatus(database.UserStatusSuspended))
@@ -289,7 +397,11 @@ func New(options *Options) *API {
 
 					r.Route("/keys", func(r chi.Router) {
 						r.Post("/", api.postAPIKey)
+						r.Get("/", api.apiKeys)
+						r.Delete("/", api.deleteAPIKeys)
+						r.Post("/tokens", api.postToken)
 						r.Get("/{keyid}", api.apiKey)
+						r.Delete("/{keyid}", api.deleteAPIKey)
 					})
 
 					r.Route("/organizations", func(r chi.Router) {
@@ -302,6 +414,18 @@ func New(options *Options) *API {
 					})
 					r.Get("/gitsshkey", api.gitSSHKey)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
+					r.Route("/totp", func(r chi.Router) {
+						r.Get("/", api.userTOTP)
+						r.Post("/", api.postUserTOTP)
+						r.Delete("/", api.deleteUserTOTP)
+						r.Post("/verify", api.postUserTOTPVerify)
+					})
+					r.Route("/lockout", func(r chi.Router) {
+						r.Get("/", api.userLockout)
+						r.Delete("/", api.deleteUserLockout)
+					})
+					r.Get("/quota", api.userQuota)
+					r.Put("/quota", api.putUserQuota)
 				})
 			})
 		})
@@ -318,6 +442,7 @@ func New(options *Options) *API {
 				r.Get("/iceservers", api.workspaceAgentICEServers)
 				r.Get("/wireguardlisten", api.workspaceAgentWireguardListener)
 				r.Post("/keys", api.postWorkspaceAgentKeys)
+				r.Post("/activity", api.postWorkspaceAgentActivity)
 				r.Get("/derp", api.derpMap)
 			})
 			r.Route("/{workspaceagent}", func(r chi.Router) {
@@ -366,6 +491,17 @@ func New(options *Options) *API {
 				})
 				r.Get("/watch", api.watchWorkspace)
 				r.Put("/extend", api.putExtendWorkspace)
+				r.Put("/owner", api.putWorkspaceOwner)
+				r.Put("/name", api.putWorkspaceName)
+				r.Put("/dormancy", api.putWorkspaceDormancy)
+				r.Route("/shares", func(r chi.Router) {
+					r.Get("/", api.workspaceShares)
+					r.Route("/{user}", func(r chi.Router) {
+						r.Use(httpmw.ExtractUserParam(options.Database))
+						r.Put("/", api.putWorkspaceShare)
+						r.Delete("/", api.deleteWorkspaceShare)
+					})
+				})
 			})
 		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
@@ -208,6 +208,11 @@ func TestAuthorizeAllEndpoints(t *testing.T) {
 	})
 	require.NoError(t, err, "create template param")
 
+	group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{
+		Name: "developers",
+	})
+	require.NoError(t, err, "create group")
+
 	// Always fail auth from this point forward
 	authorizer.AlwaysReturn = rbac.ForbiddenWithInternal(xerrors.New("fake implementation"), nil, nil)
 
@@ -227,13 +232,15 @@ func TestAuthorizeAllEndpoints(t *testing.T) {
 	}
 	assertRoute := map[string]routeCheck{
 		// These endpoints do not require auth
-		"GET:/api/v2":                   {NoAuthorize: true},
-		"GET:/api/v2/buildinfo":         {NoAuthorize: true},
-		"GET:/api/v2/users/first":       {NoAuthorize: true},
-		"POST:/api/v2/users/first":      {NoAuthorize: true},
-		"POST:/api/v2/users/login":      {NoAuthorize: true},
-		"GET:/api/v2/users/authmethods": {NoAuthorize: true},
-		"POST:/api/v2/csp/reports":      {NoAuthorize: true},
+		"GET:/api/v2":                       {NoAuthorize: true},
+		"GET:/api/v2/buildinfo":             {NoAuthorize: true},
+		"GET:/api/v2/users/first":           {NoAuthorize: true},
+		"POST:/api/v2/users/first":          {NoAuthorize: true},
+		"POST:/api/v2/users/login":          {NoAuthorize: true},
+		"POST:/api/v2/users/login/totp":     {NoAuthorize: true},
+		"GET:/api/v2/users/authmethods":     {NoAuthorize: true},
+		"GET:/api/v2/users/password-policy": {NoAuthorize: true},
+		"POST:/api/v2/csp/reports":          {NoAuthorize: true},
 
 		"GET:/%40{user}/{workspacename}/apps/{application}/*": {
 			AssertAction: rbac.ActionRead,
@@ -246,6 +253,7 @@ func TestAuthorizeAllEndpoints(t *testing.T) {
 
 		// Has it's own auth
 		"GET:/api/v2/users/oauth2/github/callback": {NoAuthorize: true},
+		"GET:/api/v2/users/oidc/callback":          {NoAuthorize: true},
 
 		// All workspaceagents endpoints do not use rbac
 		"POST:/api/v2/workspaceagents/aws-instance-identity":      {NoAuthorize: true},
@@ -259,13 +267,50 @@ func TestAuthorizeAllEndpoints(t *testing.T) {
 		"GET:/api/v2/workspaceagents/me/derp":                     {NoAuthorize: true},
 		"GET:/api/v2/workspaceagents/me/wireguardlisten":          {NoAuthorize: true},
 		"POST:/api/v2/workspaceagents/me/keys":                    {NoAuthorize: true},
+		"POST:/api/v2/workspaceagents/me/activity":                {NoAuthorize: true},
 		"GET:/api/v2/workspaceagents/{workspaceagent}/iceservers": {NoAuthorize: true},
 		"GET:/api/v2/workspaceagents/{workspaceagent}/turn":       {NoAuthorize: true},
 		"GET:/api/v2/workspaceagents/{workspaceagent}/derp":       {NoAuthorize: true},
 
+		// SCIM endpoints are authenticated by their own API key.
+		"GET:/api/v2/scim/v2/Users":        {NoAuthorize: true},
+		"POST:/api/v2/scim/v2/Users":       {NoAuthorize: true},
+		"GET:/api/v2/scim/v2/Users/{id}":   {NoAuthorize: true},
+		"PATCH:/api/v2/scim/v2/Users/{id}": {NoAuthorize: true},
+
 		// These endpoints have more assertions. This is good, add more endpoints to assert if you can!
+		"GET:/api/v2/audit": {
+			AssertAction: rbac.ActionRead,
+			AssertObject: rbac.ResourceAuditLog,
+		},
 		"GET:/api/v2/organizations/{organization}": {AssertObject: rbac.ResourceOrganization.InOrg(admin.OrganizationID)},
 		"GET:/api/v2/users/{user}/organizations":   {StatusCode: http.StatusOK, AssertObject: rbac.ResourceOrganization},
+		"GET:/api/v2/organizations":                {StatusCode: http.StatusOK, AssertObject: rbac.ResourceOrganization},
+		"DELETE:/api/v2/users/{user}": {
+			AssertAction: rbac.ActionDelete,
+			AssertObject: rbac.ResourceUser,
+		},
+		"PATCH:/api/v2/organizations/{organization}": {
+			AssertAction: rbac.ActionUpdate,
+			AssertObject: rbac.ResourceOrganization.InOrg(admin.OrganizationID),
+		},
+		"DELETE:/api/v2/organizations/{organization}": {
+			AssertAction: rbac.ActionRead,
+			AssertObject: rbac.ResourceOrganization.InOrg(admin.OrganizationID),
+		},
+		"GET:/api/v2/organizations/{organization}/members": {
+			StatusCode:   http.StatusOK,
+			AssertAction: rbac.ActionRead,
+			AssertObject: rbac.ResourceOrganizationMember.InOrg(admin.OrganizationID),
+		},
+		"POST:/api/v2/organizations/{organization}/members/{user}": {
+			AssertAction: rbac.ActionCreate,
+			AssertObject: rbac.ResourceOrganizationMember.InOrg(admin.OrganizationID),
+		},
+		"DELETE:/api/v2/organizations/{organization}/members/{user}": {
+			AssertAction: rbac.ActionDelete,
+			AssertObject: rbac.ResourceOrganizationMember.InOrg(admin.OrganizationID),
+		},
 		"GET:/api/v2/users/{user}/workspace/{workspacename}": {
 			AssertObject: rbac.ResourceWorkspace,
 			AssertAction: rbac.ActionRead,
@@ -295,19 +340,35 @@ func TestAuthorizeAllEndpoints(t *testing.T) {
 			AssertObject: workspaceRBACObj,
 		},
 		"PUT:/api/v2/workspaces/{workspace}/autostart": {
-			AssertAction: rbac.ActionUpdate,
+			AssertAction: rbac.ActionRead,
 			AssertObject: workspaceRBACObj,
 		},
 		"PUT:/api/v2/workspaces/{workspace}/autostop": {
 			AssertAction: rbac.ActionUpdate,
 			AssertObject: workspaceRBACObj,
 		},
+		"PUT:/api/v2/workspaces/{workspace}/owner": {
+			AssertAction: rbac.ActionRead,
+			AssertObject: workspaceRBACObj,
+		},
+		"GET:/api/v2/workspaces/{workspace}/shares": {
+			AssertAction: rbac.ActionRead,
+			AssertObject: workspaceRBACObj,
+		},
+		"PUT:/api/v2/workspaces/{workspace}/shares/{user}": {
+			AssertAction: rbac.ActionUpdate,
+			AssertObject: workspaceRBACObj,
+		},
+		"DELETE:/api/v2/workspaces/{workspace}/shares/{user}": {
+			AssertAction: rbac.ActionUpdate,
+			AssertObject: workspaceRBACObj,
+		},
 		"GET:/api/v2/workspaceresources/{workspaceresource}": {
 			AssertAction: rbac.ActionRead,
 			AssertObject: workspaceRBACObj,
 		},
 		"PATCH:/api/v2/workspacebuilds/{workspacebuild}/cancel": {
-			AssertAction: rbac.ActionUpdate,
+			AssertAction: rbac.ActionRead,
 			AssertObject: workspaceRBACObj,
 		},
 		"GET:/api/v2/workspacebuilds/{workspacebuild}/resources": {
@@ -449,6 +510,75 @@ func TestAuthorizeAllEndpoints(t *testing.T) {
 			AssertObject: rbac.ResourceOrganization,
 		},
 		"GET:/api/v2/users": {StatusCode: http.StatusOK, AssertObject: rbac.ResourceUser},
+		"GET:/api/v2/roles": {
+			AssertAction: rbac.ActionRead,
+			AssertObject: rbac.ResourceRole,
+		},
+		"POST:/api/v2/roles": {
+			AssertAction: rbac.ActionCreate,
+			AssertObject: rbac.ResourceRole,
+		},
+		"PUT:/api/v2/roles/{role}": {
+			AssertAction: rbac.ActionUpdate,
+			AssertObject: rbac.ResourceRole,
+		},
+		"DELETE:/api/v2/roles/{role}": {
+			AssertAction: rbac.ActionDelete,
+			AssertObject: rbac.ResourceRole,
+		},
+		"GET:/api/v2/organizations/{organization}/roles": {
+			AssertAction: rbac.ActionRead,
+			AssertObject: rbac.ResourceRole.InOrg(organization.ID),
+		},
+		"POST:/api/v2/organizations/{organization}/roles": {
+			AssertAction: rbac.ActionCreate,
+			AssertObject: rbac.ResourceRole.InOrg(organization.ID),
+		},
+		"PUT:/api/v2/organizations/{organization}/roles/{role}": {
+			AssertAction: rbac.ActionUpdate,
+			AssertObject: rbac.ResourceRole.InOrg(organization.ID),
+		},
+		"DELETE:/api/v2/organizations/{organization}/roles/{role}": {
+			AssertAction: rbac.ActionDelete,
+			AssertObject: rbac.ResourceRole.InOrg(organization.ID),
+		},
+		"GET:/api/v2/organizations/{organization}/groups": {
+			StatusCode:   http.StatusOK,
+			AssertAction: rbac.ActionRead,
+			AssertObject: rbac.ResourceGroup.InOrg(organization.ID).WithID(group.ID.String()),
+		},
+		"POST:/api/v2/organizations/{organization}/groups": {
+			AssertAction: rbac.ActionCreate,
+			AssertObject: rbac.ResourceGroup.InOrg(organization.ID),
+		},
+		"GET:/api/v2/organizations/{organization}/groups/{groupname}": {
+			AssertAction: rbac.ActionRead,
+			AssertObject: rbac.ResourceGroup.InOrg(organization.ID).WithID(group.ID.String()),
+		},
+		"GET:/api/v2/groups/{group}": {
+			AssertAction: rbac.ActionRead,
+			AssertObject: rbac.ResourceGroup.InOrg(organization.ID).WithID(group.ID.String()),
+		},
+		"PATCH:/api/v2/groups/{group}": {
+			AssertAction: rbac.ActionUpdate,
+			AssertObject: rbac.ResourceGroup.InOrg(organization.ID).WithID(group.ID.String()),
+		},
+		"DELETE:/api/v2/groups/{group}": {
+			AssertAction: rbac.ActionDelete,
+			AssertObject: rbac.ResourceGroup.InOrg(organization.ID).WithID(group.ID.String()),
+		},
+		"PUT:/api/v2/groups/{group}/roles": {
+			AssertAction: rbac.ActionUpdate,
+			AssertObject: rbac.ResourceGroup.InOrg(organization.ID).WithID(group.ID.String()),
+		},
+		"GET:/api/v2/templates/{template}/acl": {
+			AssertAction: rbac.ActionRead,
+			AssertObject: rbac.ResourceTemplate.InOrg(template.OrganizationID).WithID(template.ID.String()),
+		},
+		"PATCH:/api/v2/templates/{template}/acl": {
+			AssertAction: rbac.ActionUpdate,
+			AssertObject: rbac.ResourceTemplate.InOrg(template.OrganizationID).WithID(template.ID.String()),
+		},
 
 		// These endpoints need payloads to get to the auth part. Payloads will be required
 		"PUT:/api/v2/users/{user}/roles":                                {StatusCode: http.StatusBadRequest, NoAuthorize: true},
@@ -505,6 +635,8 @@ func TestAuthorizeAllEndpoints(t *testing.T) {
 			route = strings.ReplaceAll(route, "{templateversion}", version.ID.String())
 			route = strings.ReplaceAll(route, "{templateversiondryrun}", templateVersionDryRun.ID.String())
 			route = strings.ReplaceAll(route, "{templatename}", template.Name)
+			route = strings.ReplaceAll(route, "{group}", group.ID.String())
+			route = strings.ReplaceAll(route, "{groupname}", group.Name)
 			// Only checking template scoped params here
 			route = strings.ReplaceAll(route, "{scope}", string(templateParam.Scope))
 			route = strings.ReplaceAll(route, "{id}", templateParam.ScopeID.String())
@@ -563,7 +695,7 @@ type fakeAuthorizer struct {
 	AlwaysReturn error
 }
 
-func (f *fakeAuthorizer) ByRoleName(_ context.Context, subjectID string, roleNames []string, action rbac.Action, object rbac.Object) error {
+func (f *fakeAuthorizer) ByRoleName(_ context.Context, subjectID string, roleNames []string, _ []string, _ []string, action rbac.Action, object rbac.Object) error {
 	f.Called = &authCall{
 		SubjectID: subjectID,
 		Roles:     roleNames,
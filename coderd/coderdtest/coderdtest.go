@@ -40,6 +40,7 @@ import (
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/coderd"
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/autobuild/executor"
 	"github.com/coder/coder/coderd/awsidentity"
 	"github.com/coder/coder/coderd/database"
@@ -49,6 +50,7 @@ import (
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/turnconn"
+	"github.com/coder/coder/coderd/userpassword"
 	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/cryptorand"
@@ -60,14 +62,27 @@ import (
 
 type Options struct {
 	AWSCertificates      awsidentity.Certificates
+	Auditor              audit.Auditor
 	Authorizer           rbac.Authorizer
 	AzureCertificates    x509.VerifyOptions
 	GithubOAuth2Config   *coderd.GithubOAuth2Config
+	OIDCConfig           *coderd.OIDCConfig
 	GoogleTokenValidator *idtoken.Validator
 	SSHKeygenAlgorithm   gitsshkey.Algorithm
 	APIRateLimit         int
 	AutobuildTicker      <-chan time.Time
 	AutobuildStats       chan<- executor.Stats
+	// AutobuildDormancyWarnings receives the warnings of the autobuild
+	// executor before workspaces are marked dormant or deleted.
+	AutobuildDormancyWarnings func(executor.DormancyWarning)
+	// Now overrides the clock used for validating TOTP codes.
+	Now                   func() time.Time
+	TOTPRequiredForAdmins bool
+	SCIMAPIKey            []byte
+	PasswordPolicy        userpassword.Policy
+	LoginMaxAttempts      int
+	LoginLockoutDuration  time.Duration
+	MaxTokenLifetime      time.Duration
 
 	// IncludeProvisionerD when true means to start an in-memory provisionerD
 	IncludeProvisionerD bool
@@ -149,6 +164,9 @@ func newWithCloser(t *testing.T, options *Options) (*codersdk.Client, io.Closer)
 		slogtest.Make(t, nil).Named("autobuild.executor").Leveled(slog.LevelDebug),
 		options.AutobuildTicker,
 	).WithStatsChannel(options.AutobuildStats)
+	if options.AutobuildDormancyWarnings != nil {
+		lifecycleExecutor.WithDormancyWarnings(options.AutobuildDormancyWarnings, executor.DormancyWarningCountdown...)
+	}
 	lifecycleExecutor.Run()
 
 	srv := httptest.NewUnstartedServer(nil)
@@ -179,14 +197,24 @@ func newWithCloser(t *testing.T, options *Options) (*codersdk.Client, io.Closer)
 		Pubsub:                         pubsub,
 
 		AWSCertificates:      options.AWSCertificates,
+		Auditor:              options.Auditor,
 		AzureCertificates:    options.AzureCertificates,
 		GithubOAuth2Config:   options.GithubOAuth2Config,
+		OIDCConfig:           options.OIDCConfig,
 		GoogleTokenValidator: options.GoogleTokenValidator,
 		SSHKeygenAlgorithm:   options.SSHKeygenAlgorithm,
 		TURNServer:           turnServer,
 		APIRateLimit:         options.APIRateLimit,
 		Authorizer:           options.Authorizer,
 		Telemetry:            telemetry.NewNoop(),
+		Now:                  options.Now,
+
+		TOTPRequiredForAdmins: options.TOTPRequiredForAdmins,
+		SCIMAPIKey:            options.SCIMAPIKey,
+		PasswordPolicy:        options.PasswordPolicy,
+		LoginMaxAttempts:      options.LoginMaxAttempts,
+		LoginLockoutDuration:  options.LoginLockoutDuration,
+		MaxTokenLifetime:      options.MaxTokenLifetime,
 	})
 	srv.Config.Handler = coderAPI.Handler
 
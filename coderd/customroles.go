@@ -0,0 +1,517 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+type customRoleCacheKey struct{}
+
+// customRoleCache holds the custom roles resolved during a request.
+// Authorizing a list of objects resolves the roles of the subject for each
+// object, so they're only fetched from the database once.
+type customRoleCache struct {
+	mutex sync.Mutex
+	roles map[string]cachedCustomRole
+	// displayNames are the display names of site custom roles by name. They're
+	// nil until they're first fetched.
+	displayNames map[string]string
+}
+
+type cachedCustomRole struct {
+	role rbac.Role
+	err  error
+}
+
+// withCustomRoleCache is a middleware that caches custom roles for the
+// duration of the request.
+func withCustomRoleCache(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), customRoleCacheKey{}, &customRoleCache{
+			roles: map[string]cachedCustomRole{},
+		})
+		next.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
+
+// invalidateCustomRoles clears the custom roles cached for the request. It's
+// called when a custom role is changed, so it isn't authorized with its old
+// permissions later in the request.
+func invalidateCustomRoles(ctx context.Context) {
+	cache, ok := ctx.Value(customRoleCacheKey{}).(*customRoleCache)
+	if !ok {
+		return
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.roles = map[string]cachedCustomRole{}
+	cache.displayNames = nil
+}
+
+// customRoleLookup resolves custom roles stored in the database for the
+// authorizer. Roles are cached for the request if withCustomRoleCache is
+// used.
+func customRoleLookup(db database.Store) rbac.RoleLookup {
+	lookup := func(ctx context.Context, roleName string) (rbac.Role, error) {
+		role, err := getCustomRole(ctx, db, roleName)
+		if err != nil {
+			return rbac.Role{}, err
+		}
+		return convertCustomRoleToRBAC(role)
+	}
+	return func(ctx context.Context, roleName string) (rbac.Role, error) {
+		cache, ok := ctx.Value(customRoleCacheKey{}).(*customRoleCache)
+		if !ok {
+			return lookup(ctx, roleName)
+		}
+		cache.mutex.Lock()
+		defer cache.mutex.Unlock()
+		cached, ok := cache.roles[roleName]
+		if !ok {
+			cached.role, cached.err = lookup(ctx, roleName)
+			cache.roles[roleName] = cached
+		}
+		return cached.role, cached.err
+	}
+}
+
+// siteRoleDisplayNames returns the display names of site custom roles by
+// name, so users are shown with them instead of the names of their roles.
+// Failing to fetch them isn't fatal, since they're only used for display.
+func (api *API) siteRoleDisplayNames(ctx context.Context) map[string]string {
+	cache, cached := ctx.Value(customRoleCacheKey{}).(*customRoleCache)
+	if cached {
+		cache.mutex.Lock()
+		defer cache.mutex.Unlock()
+		if cache.displayNames != nil {
+			return cache.displayNames
+		}
+	}
+	roles, err := api.Database.GetCustomRoles(ctx, uuid.NullUUID{})
+	if err != nil {
+		api.Logger.Warn(ctx, "get custom roles", slog.Error(err))
+		return map[string]string{}
+	}
+	displayNames := make(map[string]string, len(roles))
+	for _, role := range roles {
+		displayNames[role.Name] = role.DisplayName
+	}
+	if cached {
+		cache.displayNames = displayNames
+	}
+	return displayNames
+}
+
+// roleByName returns a built-in role, or the custom role with the name.
+func roleByName(ctx context.Context, db database.Store, roleName string) (rbac.Role, error) {
+	if rbac.IsBuiltInRole(roleName) {
+		return rbac.RoleByName(roleName)
+	}
+	return customRoleLookup(db)(ctx, roleName)
+}
+
+func getCustomRole(ctx context.Context, db database.Store, roleName string) (database.CustomRole, error) {
+	name, organization, err := rbac.SplitRoleName(roleName)
+	if err != nil {
+		return database.CustomRole{}, xerrors.Errorf("split role name: %w", err)
+	}
+	var organizationID uuid.NullUUID
+	if organization != "" {
+		organizationID.UUID, err = uuid.Parse(organization)
+		if err != nil {
+			return database.CustomRole{}, xerrors.Errorf("parse organization id %q: %w", organization, err)
+		}
+		organizationID.Valid = true
+	}
+	role, err := db.GetCustomRoleByName(ctx, database.GetCustomRoleByNameParams{
+		Name:           name,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return database.CustomRole{}, xerrors.Errorf("get custom role %q: %w", roleName, err)
+	}
+	return role, nil
+}
+
+// customRoles lists the custom roles of the site, or of the organization in
+// the URL if organizationScoped is set.
+func (api *API) customRoles(organizationScoped bool) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		organizationID, object := customRoleScope(r, organizationScoped)
+		if !api.Authorize(r, rbac.ActionRead, object) {
+			httpapi.Forbidden(rw)
+			return
+		}
+
+		roles, err := api.Database.GetCustomRoles(r.Context(), organizationID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching custom roles.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		converted := make([]codersdk.CustomRole, 0, len(roles))
+		for _, role := range roles {
+			c, err := convertCustomRole(role)
+			if err != nil {
+				httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error converting custom role.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			converted = append(converted, c)
+		}
+		httpapi.Write(rw, http.StatusOK, converted)
+	}
+}
+
+func (api *API) postCustomRole(organizationScoped bool) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		aReq, commitAudit := audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Auditor: api.Auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+		defer commitAudit()
+		organizationID, object := customRoleScope(r, organizationScoped)
+		if !api.Authorize(r, rbac.ActionCreate, object) {
+			httpapi.Forbidden(rw)
+			return
+		}
+
+		var req codersdk.CreateRoleRequest
+		if !httpapi.Read(rw, r, &req) {
+			return
+		}
+		if rbac.IsBuiltInRole(req.Name) {
+			httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("%q is the name of a built-in role.", req.Name),
+			})
+			return
+		}
+		site, org, user, ok := validateCustomRolePermissions(rw, organizationScoped,
+			req.SitePermissions, req.OrganizationPermissions, req.UserPermissions)
+		if !ok {
+			return
+		}
+
+		_, err := api.Database.GetCustomRoleByName(r.Context(), database.GetCustomRoleByNameParams{
+			Name:           req.Name,
+			OrganizationID: organizationID,
+		})
+		if err == nil {
+			httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("Role %q already exists.", req.Name),
+			})
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching custom role.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		now := database.Now()
+		role, err := api.Database.InsertCustomRole(r.Context(), database.InsertCustomRoleParams{
+			ID:              uuid.New(),
+			Name:            req.Name,
+			DisplayName:     req.DisplayName,
+			OrganizationID:  organizationID,
+			SitePermissions: site,
+			OrgPermissions:  org,
+			UserPermissions: user,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		})
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error inserting custom role.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		aReq.New = role
+		invalidateCustomRoles(r.Context())
+		converted, err := convertCustomRole(role)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error converting custom role.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		httpapi.Write(rw, http.StatusCreated, converted)
+	}
+}
+
+func (api *API) putCustomRole(organizationScoped bool) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		aReq, commitAudit := audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Auditor: api.Auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+		defer commitAudit()
+		organizationID, object := customRoleScope(r, organizationScoped)
+		if !api.Authorize(r, rbac.ActionUpdate, object) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		role, ok := customRoleParam(rw, r, api.Database, organizationID)
+		if !ok {
+			return
+		}
+		aReq.Old = role
+
+		var req codersdk.UpdateRoleRequest
+		if !httpapi.Read(rw, r, &req) {
+			return
+		}
+		site, org, user, ok := validateCustomRolePermissions(rw, organizationScoped,
+			req.SitePermissions, req.OrganizationPermissions, req.UserPermissions)
+		if !ok {
+			return
+		}
+
+		role, err := api.Database.UpdateCustomRole(r.Context(), database.UpdateCustomRoleParams{
+			ID:              role.ID,
+			DisplayName:     req.DisplayName,
+			SitePermissions: site,
+			OrgPermissions:  org,
+			UserPermissions: user,
+			UpdatedAt:       database.Now(),
+		})
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error updating custom role.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		aReq.New = role
+		invalidateCustomRoles(r.Context())
+		converted, err := convertCustomRole(role)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error converting custom role.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		httpapi.Write(rw, http.StatusOK, converted)
+	}
+}
+
+// deleteCustomRole deletes a custom role and removes it from the users,
+// members or groups it's assigned to.
+func (api *API) deleteCustomRole(organizationScoped bool) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		aReq, commitAudit := audit.InitRequest[database.CustomRole](rw, &audit.RequestParams{
+			Auditor: api.Auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+		defer commitAudit()
+		organizationID, object := customRoleScope(r, organizationScoped)
+		if !api.Authorize(r, rbac.ActionDelete, object) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		role, ok := customRoleParam(rw, r, api.Database, organizationID)
+		if !ok {
+			return
+		}
+		aReq.Old = role
+
+		err := api.Database.InTx(func(store database.Store) error {
+			err := store.DeleteCustomRole(r.Context(), role.ID)
+			if err != nil {
+				return xerrors.Errorf("delete custom role: %w", err)
+			}
+			if organizationID.Valid {
+				roleName := role.Name + ":" + organizationID.UUID.String()
+				err = store.DeleteRoleFromOrganizationMembers(r.Context(), database.DeleteRoleFromOrganizationMembersParams{
+					RoleName:       roleName,
+					OrganizationID: organizationID.UUID,
+				})
+				if err != nil {
+					return xerrors.Errorf("remove role from members: %w", err)
+				}
+				err = store.DeleteRoleFromGroups(r.Context(), database.DeleteRoleFromGroupsParams{
+					RoleName:       roleName,
+					OrganizationID: organizationID.UUID,
+				})
+			} else {
+				err = store.DeleteRoleFromUsers(r.Context(), role.Name)
+			}
+			if err != nil {
+				return xerrors.Errorf("remove role from assignees: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error deleting custom role.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		invalidateCustomRoles(r.Context())
+		httpapi.Write(rw, http.StatusOK, codersdk.Response{
+			Message: "Role has been deleted!",
+		})
+	}
+}
+
+// customRoleScope returns the organization custom roles are scoped to, and
+// the object to authorize against.
+func customRoleScope(r *http.Request, organizationScoped bool) (uuid.NullUUID, rbac.Object) {
+	if !organizationScoped {
+		return uuid.NullUUID{}, rbac.ResourceRole
+	}
+	organization := httpmw.OrganizationParam(r)
+	return uuid.NullUUID{UUID: organization.ID, Valid: true}, rbac.ResourceRole.InOrg(organization.ID)
+}
+
+func customRoleParam(rw http.ResponseWriter, r *http.Request, db database.Store, organizationID uuid.NullUUID) (database.CustomRole, bool) {
+	role, err := db.GetCustomRoleByName(r.Context(), database.GetCustomRoleByNameParams{
+		Name:           chi.URLParam(r, "role"),
+		OrganizationID: organizationID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return database.CustomRole{}, false
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom role.",
+			Detail:  err.Error(),
+		})
+		return database.CustomRole{}, false
+	}
+	return role, true
+}
+
+// validateCustomRolePermissions ensures the permissions are valid for the
+// scope of the role, and marshals them for the database.
+func validateCustomRolePermissions(rw http.ResponseWriter, organizationScoped bool, site, org, user []codersdk.Permission) (siteJSON, orgJSON, userJSON json.RawMessage, ok bool) {
+	if organizationScoped && (len(site) > 0 || len(user) > 0) {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Organization roles can only have organization permissions.",
+		})
+		return nil, nil, nil, false
+	}
+	if !organizationScoped && len(org) > 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Site roles can't have organization permissions.",
+		})
+		return nil, nil, nil, false
+	}
+
+	marshaled := make([]json.RawMessage, 0, 3)
+	for _, perms := range [][]codersdk.Permission{site, org, user} {
+		err := rbac.ValidatePermissions(convertPermissionsToRBAC(perms))
+		if err != nil {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid permissions.",
+				Detail:  err.Error(),
+			})
+			return nil, nil, nil, false
+		}
+		if perms == nil {
+			perms = []codersdk.Permission{}
+		}
+		data, err := json.Marshal(perms)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error marshaling permissions.",
+				Detail:  err.Error(),
+			})
+			return nil, nil, nil, false
+		}
+		marshaled = append(marshaled, data)
+	}
+	return marshaled[0], marshaled[1], marshaled[2], true
+}
+
+func convertPermissionsToRBAC(perms []codersdk.Permission) []rbac.Permission {
+	converted := make([]rbac.Permission, 0, len(perms))
+	for _, perm := range perms {
+		converted = append(converted, rbac.Permission{
+			Negate:       perm.Negate,
+			ResourceType: perm.ResourceType,
+			// Permissions of custom roles apply to all resources of a type.
+			ResourceID: rbac.WildcardSymbol,
+			Action:     rbac.Action(perm.Action),
+		})
+	}
+	return converted
+}
+
+func convertCustomRoleToRBAC(role database.CustomRole) (rbac.Role, error) {
+	converted, err := convertCustomRole(role)
+	if err != nil {
+		return rbac.Role{}, err
+	}
+	var organizationID string
+	if role.OrganizationID.Valid {
+		organizationID = role.OrganizationID.UUID.String()
+	}
+	return rbac.CustomRole(role.Name, role.DisplayName, organizationID,
+		convertPermissionsToRBAC(converted.SitePermissions),
+		convertPermissionsToRBAC(converted.OrganizationPermissions),
+		convertPermissionsToRBAC(converted.UserPermissions),
+	), nil
+}
+
+func convertCustomRole(role database.CustomRole) (codersdk.CustomRole, error) {
+	converted := codersdk.CustomRole{
+		ID:          role.ID,
+		Name:        role.Name,
+		DisplayName: role.DisplayName,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+	if role.OrganizationID.Valid {
+		converted.OrganizationID = &role.OrganizationID.UUID
+	}
+	for _, perms := range []struct {
+		data json.RawMessage
+		dst  *[]codersdk.Permission
+	}{
+		{role.SitePermissions, &converted.SitePermissions},
+		{role.OrgPermissions, &converted.OrganizationPermissions},
+		{role.UserPermissions, &converted.UserPermissions},
+	} {
+		err := json.Unmarshal(perms.data, perms.dst)
+		if err != nil {
+			return codersdk.CustomRole{}, xerrors.Errorf("unmarshal permissions of role %q: %w", role.Name, err)
+		}
+	}
+	return converted, nil
+}
@@ -0,0 +1,323 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestCustomSiteRoles(t *testing.T) {
+	t.Parallel()
+
+	logReader := codersdk.CreateRoleRequest{
+		Name:        "log-reader",
+		DisplayName: "Log Reader",
+		SitePermissions: []codersdk.Permission{{
+			ResourceType: rbac.ResourceAuditLog.Type,
+			Action:       string(rbac.ActionRead),
+		}},
+	}
+	checkReadAuditLogs := codersdk.UserAuthorizationRequest{
+		Checks: map[string]codersdk.UserAuthorization{
+			"read-audit-logs": {
+				Object: codersdk.UserAuthorizationObject{ResourceType: rbac.ResourceAuditLog.Type},
+				Action: string(rbac.ActionRead),
+			},
+		},
+	}
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		role, err := client.CreateCustomSiteRole(ctx, logReader)
+		require.NoError(t, err)
+		require.Equal(t, logReader.Name, role.Name)
+		require.Nil(t, role.OrganizationID)
+		require.Len(t, role.SitePermissions, 1)
+
+		roles, err := client.CustomSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+
+		assignable, err := client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Contains(t, assignable, codersdk.Role{Name: "log-reader", DisplayName: "Log Reader"})
+
+		role, err = client.UpdateCustomSiteRole(ctx, role.Name, codersdk.UpdateRoleRequest{
+			DisplayName: "Readers",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Readers", role.DisplayName)
+		require.Empty(t, role.SitePermissions)
+
+		err = client.DeleteCustomSiteRole(ctx, role.Name)
+		require.NoError(t, err)
+		roles, err = client.CustomSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+	})
+
+	t.Run("AuditLog", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		role, err := client.CreateCustomSiteRole(ctx, logReader)
+		require.NoError(t, err)
+		_, err = client.UpdateCustomSiteRole(ctx, role.Name, codersdk.UpdateRoleRequest{
+			DisplayName: "Readers",
+		})
+		require.NoError(t, err)
+		err = client.DeleteCustomSiteRole(ctx, role.Name)
+		require.NoError(t, err)
+
+		actions := []database.AuditAction{}
+		for _, alog := range auditor.AuditLogs() {
+			if alog.ResourceType != database.ResourceTypeCustomRole {
+				continue
+			}
+			require.Equal(t, role.ID, alog.ResourceID)
+			require.Equal(t, role.Name, alog.ResourceTarget)
+			actions = append(actions, alog.Action)
+		}
+		require.Equal(t, []database.AuditAction{
+			database.AuditActionCreate,
+			database.AuditActionWrite,
+			database.AuditActionDelete,
+		}, actions)
+	})
+
+	t.Run("BuiltInName", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.CreateCustomSiteRole(ctx, codersdk.CreateRoleRequest{
+			Name:        rbac.RoleAdmin(),
+			DisplayName: "Admin",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.CreateCustomSiteRole(ctx, logReader)
+		require.NoError(t, err)
+		_, err = client.CreateCustomSiteRole(ctx, logReader)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidPermission", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.CreateCustomSiteRole(ctx, codersdk.CreateRoleRequest{
+			Name:        "bad",
+			DisplayName: "Bad",
+			SitePermissions: []codersdk.Permission{{
+				ResourceType: "spaceship",
+				Action:       string(rbac.ActionRead),
+			}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.CreateCustomSiteRole(ctx, codersdk.CreateRoleRequest{
+			Name:        "bad",
+			DisplayName: "Bad",
+			OrganizationPermissions: []codersdk.Permission{{
+				ResourceType: rbac.ResourceTemplate.Type,
+				Action:       string(rbac.ActionRead),
+			}},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+		_, err := member.CreateCustomSiteRole(ctx, logReader)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Assign", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		allowed, err := member.CheckPermissions(ctx, checkReadAuditLogs)
+		require.NoError(t, err)
+		require.False(t, allowed["read-audit-logs"])
+
+		_, err = client.UpdateUserRoles(ctx, memberUser.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{"log-reader"},
+		})
+		require.Error(t, err, "role doesn't exist yet")
+
+		_, err = client.CreateCustomSiteRole(ctx, logReader)
+		require.NoError(t, err)
+		user, err := client.UpdateUserRoles(ctx, memberUser.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{"log-reader"},
+		})
+		require.NoError(t, err)
+		require.Contains(t, user.Roles, codersdk.Role{Name: "log-reader", DisplayName: "Log Reader"})
+
+		allowed, err = member.CheckPermissions(ctx, checkReadAuditLogs)
+		require.NoError(t, err)
+		require.True(t, allowed["read-audit-logs"])
+
+		// Deleting the role removes it from the user.
+		err = client.DeleteCustomSiteRole(ctx, "log-reader")
+		require.NoError(t, err)
+		roles, err := client.GetUserRoles(ctx, memberUser.ID.String())
+		require.NoError(t, err)
+		require.NotContains(t, roles.Roles, "log-reader")
+		allowed, err = member.CheckPermissions(ctx, checkReadAuditLogs)
+		require.NoError(t, err)
+		require.False(t, allowed["read-audit-logs"])
+	})
+}
+
+func TestCustomOrganizationRoles(t *testing.T) {
+	t.Parallel()
+
+	readWorkspaces := codersdk.CreateRoleRequest{
+		Name:        "workspace-reader",
+		DisplayName: "Workspace Reader",
+		OrganizationPermissions: []codersdk.Permission{{
+			ResourceType: rbac.ResourceWorkspace.Type,
+			Action:       string(rbac.ActionRead),
+		}},
+	}
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+
+		role, err := client.CreateCustomOrganizationRole(ctx, admin.OrganizationID, readWorkspaces)
+		require.NoError(t, err)
+		require.NotNil(t, role.OrganizationID)
+		require.Equal(t, admin.OrganizationID, *role.OrganizationID)
+
+		roles, err := client.CustomOrganizationRoles(ctx, admin.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		roles, err = client.CustomSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+
+		assignable, err := client.ListOrganizationRoles(ctx, admin.OrganizationID)
+		require.NoError(t, err)
+		require.Contains(t, assignable, codersdk.Role{
+			Name:        "workspace-reader:" + admin.OrganizationID.String(),
+			DisplayName: "Workspace Reader",
+		})
+
+		_, err = client.UpdateCustomOrganizationRole(ctx, admin.OrganizationID, role.Name, codersdk.UpdateRoleRequest{
+			DisplayName: "Readers",
+			SitePermissions: []codersdk.Permission{{
+				ResourceType: rbac.ResourceWorkspace.Type,
+				Action:       string(rbac.ActionRead),
+			}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		err = client.DeleteCustomOrganizationRole(ctx, admin.OrganizationID, role.Name)
+		require.NoError(t, err)
+		err = client.DeleteCustomOrganizationRole(ctx, admin.OrganizationID, role.Name)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Assign", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		check := codersdk.UserAuthorizationRequest{
+			Checks: map[string]codersdk.UserAuthorization{
+				"read-workspaces": {
+					Object: codersdk.UserAuthorizationObject{
+						ResourceType:   rbac.ResourceWorkspace.Type,
+						OrganizationID: admin.OrganizationID.String(),
+					},
+					Action: string(rbac.ActionRead),
+				},
+			},
+		}
+		allowed, err := member.CheckPermissions(ctx, check)
+		require.NoError(t, err)
+		require.False(t, allowed["read-workspaces"])
+
+		role, err := client.CreateCustomOrganizationRole(ctx, admin.OrganizationID, readWorkspaces)
+		require.NoError(t, err)
+		roleName := role.Name + ":" + admin.OrganizationID.String()
+		_, err = client.UpdateOrganizationMemberRoles(ctx, admin.OrganizationID, memberUser.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{roleName},
+		})
+		require.NoError(t, err)
+
+		allowed, err = member.CheckPermissions(ctx, check)
+		require.NoError(t, err)
+		require.True(t, allowed["read-workspaces"])
+
+		err = client.DeleteCustomOrganizationRole(ctx, admin.OrganizationID, role.Name)
+		require.NoError(t, err)
+		allowed, err = member.CheckPermissions(ctx, check)
+		require.NoError(t, err)
+		require.False(t, allowed["read-workspaces"])
+	})
+}
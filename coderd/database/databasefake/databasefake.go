@@ -1,6 +1,7 @@
 package databasefake
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"sort"
@@ -27,8 +28,12 @@ func New() database.Store {
 			users:               make([]database.User, 0),
 
 			auditLogs:               make([]database.AuditLog, 0),
+			customRoles:             make([]database.CustomRole, 0),
 			files:                   make([]database.File, 0),
 			gitSSHKey:               make([]database.GitSSHKey, 0),
+			groupMembers:            make([]database.GroupMember, 0),
+			groups:                  make([]database.Group, 0),
+			organizationQuotas:      make([]database.OrganizationQuota, 0),
 			parameterSchemas:        make([]database.ParameterSchema, 0),
 			parameterValues:         make([]database.ParameterValue, 0),
 			provisionerDaemons:      make([]database.ProvisionerDaemon, 0),
@@ -38,6 +43,11 @@ func New() database.Store {
 			provisionerJobs:         make([]database.ProvisionerJob, 0),
 			templateVersions:        make([]database.TemplateVersion, 0),
 			templates:               make([]database.Template, 0),
+			userLockouts:            make([]database.UserLockout, 0),
+			userPasswordHistory:     make([]database.UserPasswordHistory, 0),
+			userQuotas:              make([]database.UserQuota, 0),
+			userTOTPs:               make([]database.UserTOTP, 0),
+			userTOTPRecoveryCodes:   make([]database.UserTOTPRecoveryCode, 0),
 			workspaceBuilds:         make([]database.WorkspaceBuild, 0),
 			workspaceApps:           make([]database.WorkspaceApp, 0),
 			workspaces:              make([]database.Workspace, 0),
@@ -75,8 +85,12 @@ type data struct {
 
 	// New tables
 	auditLogs               []database.AuditLog
+	customRoles             []database.CustomRole
 	files                   []database.File
 	gitSSHKey               []database.GitSSHKey
+	groupMembers            []database.GroupMember
+	groups                  []database.Group
+	organizationQuotas      []database.OrganizationQuota
 	parameterSchemas        []database.ParameterSchema
 	parameterValues         []database.ParameterValue
 	provisionerDaemons      []database.ProvisionerDaemon
@@ -86,6 +100,11 @@ type data struct {
 	provisionerJobs         []database.ProvisionerJob
 	templateVersions        []database.TemplateVersion
 	templates               []database.Template
+	userLockouts            []database.UserLockout
+	userPasswordHistory     []database.UserPasswordHistory
+	userQuotas              []database.UserQuota
+	userTOTPs               []database.UserTOTP
+	userTOTPRecoveryCodes   []database.UserTOTPRecoveryCode
 	workspaceBuilds         []database.WorkspaceBuild
 	workspaceApps           []database.WorkspaceApp
 	workspaces              []database.Workspace
@@ -181,6 +200,22 @@ func (q *fakeQuerier) GetAPIKeysLastUsedAfter(_ context.Context, after time.Time
 	return apiKeys, nil
 }
 
+func (q *fakeQuerier) GetAPIKeysByUserID(_ context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	apiKeys := make([]database.APIKey, 0)
+	for _, key := range q.apiKeys {
+		if key.UserID == userID {
+			apiKeys = append(apiKeys, key)
+		}
+	}
+	sort.Slice(apiKeys, func(i, j int) bool {
+		return apiKeys[i].CreatedAt.Before(apiKeys[j].CreatedAt)
+	})
+	return apiKeys, nil
+}
+
 func (q *fakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -196,6 +231,20 @@ func (q *fakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) DeleteAPIKeysByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	apiKeys := make([]database.APIKey, 0, len(q.apiKeys))
+	for _, key := range q.apiKeys {
+		if key.UserID != userID {
+			apiKeys = append(apiKeys, key)
+		}
+	}
+	q.apiKeys = apiKeys
+	return nil
+}
+
 func (q *fakeQuerier) GetFileByHash(_ context.Context, hash string) (database.File, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -308,6 +357,20 @@ func (q *fakeQuerier) GetUsers(_ context.Context, params database.GetUsersParams
 		users = usersFilteredByRole
 	}
 
+	if !params.LastSeenBefore.IsZero() || !params.LastSeenAfter.IsZero() {
+		usersFilteredByLastSeen := make([]database.User, 0, len(users))
+		for i, user := range users {
+			if !params.LastSeenBefore.IsZero() && user.LastSeenAt.After(params.LastSeenBefore) {
+				continue
+			}
+			if !params.LastSeenAfter.IsZero() && user.LastSeenAt.Before(params.LastSeenAfter) {
+				continue
+			}
+			usersFilteredByLastSeen = append(usersFilteredByLastSeen, users[i])
+		}
+		users = usersFilteredByLastSeen
+	}
+
 	if params.OffsetOpt > 0 {
 		if int(params.OffsetOpt) > len(users)-1 {
 			return nil, sql.ErrNoRows
@@ -357,10 +420,24 @@ func (q *fakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 		}
 	}
 
+	groups := make([]string, 0)
 	for _, mem := range q.organizationMembers {
 		if mem.UserID == userID {
 			roles = append(roles, mem.Roles...)
 			roles = append(roles, "organization-member:"+mem.OrganizationID.String())
+			groups = append(groups, mem.OrganizationID.String())
+		}
+	}
+
+	for _, member := range q.groupMembers {
+		if member.UserID != userID {
+			continue
+		}
+		groups = append(groups, member.GroupID.String())
+		for _, group := range q.groups {
+			if group.ID == member.GroupID {
+				roles = append(roles, group.Roles...)
+			}
 		}
 	}
 
@@ -373,6 +450,7 @@ func (q *fakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 		Username: user.Username,
 		Status:   user.Status,
 		Roles:    roles,
+		Groups:   groups,
 	}, nil
 }
 
@@ -385,6 +463,9 @@ func (q *fakeQuerier) GetWorkspaces(_ context.Context, arg database.GetWorkspace
 		if arg.OwnerID != uuid.Nil && workspace.OwnerID != arg.OwnerID {
 			continue
 		}
+		if arg.OrganizationID != uuid.Nil && workspace.OrganizationID != arg.OrganizationID {
+			continue
+		}
 		if arg.OwnerUsername != "" {
 			owner, err := q.GetUserByID(context.Background(), workspace.OwnerID)
 			if err == nil && !strings.EqualFold(arg.OwnerUsername, owner.Username) {
@@ -510,12 +591,49 @@ func (q *fakeQuerier) GetWorkspaceAppsByAgentIDs(_ context.Context, ids []uuid.U
 func (q *fakeQuerier) GetWorkspacesAutostart(_ context.Context) ([]database.Workspace, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
+	activeUsers := make(map[uuid.UUID]bool)
+	for _, user := range q.users {
+		activeUsers[user.ID] = user.Status == database.UserStatusActive
+	}
 	workspaces := make([]database.Workspace, 0)
 	for _, ws := range q.workspaces {
+		if ws.Deleted || !activeUsers[ws.OwnerID] {
+			continue
+		}
 		if ws.AutostartSchedule.String != "" {
 			workspaces = append(workspaces, ws)
 		} else if ws.Ttl.Valid {
 			workspaces = append(workspaces, ws)
+		} else {
+			for _, template := range q.templates {
+				if template.ID == ws.TemplateID && template.RequiredStopSchedule != "" {
+					workspaces = append(workspaces, ws)
+					break
+				}
+			}
+		}
+	}
+	return workspaces, nil
+}
+
+func (q *fakeQuerier) GetWorkspacesEligibleForDormancy(_ context.Context) ([]database.Workspace, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspaces := make([]database.Workspace, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.DormancyOptOut {
+			continue
+		}
+		if workspace.DormantAt.Valid {
+			workspaces = append(workspaces, workspace)
+			continue
+		}
+		for _, template := range q.templates {
+			if template.ID == workspace.TemplateID && template.InactivityTtl > 0 {
+				workspaces = append(workspaces, workspace)
+				break
+			}
 		}
 	}
 	return workspaces, nil
@@ -781,6 +899,97 @@ func (q *fakeQuerier) GetOrganizationsByUserID(_ context.Context, userID uuid.UU
 	return organizations, nil
 }
 
+func (q *fakeQuerier) UpdateOrganizationByID(_ context.Context, arg database.UpdateOrganizationByIDParams) (database.Organization, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, organization := range q.organizations {
+		if organization.ID != arg.ID {
+			continue
+		}
+		organization.Name = arg.Name
+		organization.UpdatedAt = arg.UpdatedAt
+		q.organizations[index] = organization
+		return organization, nil
+	}
+	return database.Organization{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteOrganizationByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, organization := range q.organizations {
+		if organization.ID != id {
+			continue
+		}
+		q.organizations = append(q.organizations[:index], q.organizations[index+1:]...)
+
+		// Mirror the cascading foreign keys of the organization.
+		members := make([]database.OrganizationMember, 0, len(q.organizationMembers))
+		for _, member := range q.organizationMembers {
+			if member.OrganizationID != id {
+				members = append(members, member)
+			}
+		}
+		q.organizationMembers = members
+		groups := make([]database.Group, 0, len(q.groups))
+		groupIDs := make(map[uuid.UUID]struct{})
+		for _, group := range q.groups {
+			if group.OrganizationID == id {
+				groupIDs[group.ID] = struct{}{}
+				continue
+			}
+			groups = append(groups, group)
+		}
+		q.groups = groups
+		groupMembers := make([]database.GroupMember, 0, len(q.groupMembers))
+		for _, member := range q.groupMembers {
+			if _, ok := groupIDs[member.GroupID]; !ok {
+				groupMembers = append(groupMembers, member)
+			}
+		}
+		q.groupMembers = groupMembers
+		customRoles := make([]database.CustomRole, 0, len(q.customRoles))
+		for _, role := range q.customRoles {
+			if !role.OrganizationID.Valid || role.OrganizationID.UUID != id {
+				customRoles = append(customRoles, role)
+			}
+		}
+		q.customRoles = customRoles
+		quotas := make([]database.OrganizationQuota, 0, len(q.organizationQuotas))
+		for _, quota := range q.organizationQuotas {
+			if quota.OrganizationID != id {
+				quotas = append(quotas, quota)
+			}
+		}
+		q.organizationQuotas = quotas
+		templates := make([]database.Template, 0, len(q.templates))
+		for _, template := range q.templates {
+			if template.OrganizationID != id {
+				templates = append(templates, template)
+			}
+		}
+		q.templates = templates
+		templateVersions := make([]database.TemplateVersion, 0, len(q.templateVersions))
+		for _, version := range q.templateVersions {
+			if version.OrganizationID != id {
+				templateVersions = append(templateVersions, version)
+			}
+		}
+		q.templateVersions = templateVersions
+		jobs := make([]database.ProvisionerJob, 0, len(q.provisionerJobs))
+		for _, job := range q.provisionerJobs {
+			if job.OrganizationID != id {
+				jobs = append(jobs, job)
+			}
+		}
+		q.provisionerJobs = jobs
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) ParameterValues(_ context.Context, arg database.ParameterValuesParams) ([]database.ParameterValue, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -854,6 +1063,13 @@ func (q *fakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.Description = arg.Description
 		tpl.MaxTtl = arg.MaxTtl
 		tpl.MinAutostartInterval = arg.MinAutostartInterval
+		tpl.CostUnits = arg.CostUnits
+		tpl.InactivityTtl = arg.InactivityTtl
+		tpl.DormantDeletionTtl = arg.DormantDeletionTtl
+		tpl.DefaultAutostartSchedule = arg.DefaultAutostartSchedule
+		tpl.DefaultTtl = arg.DefaultTtl
+		tpl.RequiredStopSchedule = arg.RequiredStopSchedule
+		tpl.AllowUserScheduleOverride = arg.AllowUserScheduleOverride
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -861,6 +1077,18 @@ func (q *fakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateTemplatesCreatedBy(_ context.Context, arg database.UpdateTemplatesCreatedByParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, template := range q.templates {
+		if template.CreatedBy == arg.OldCreatedBy {
+			q.templates[index].CreatedBy = arg.NewCreatedBy
+		}
+	}
+	return nil
+}
+
 func (q *fakeQuerier) GetTemplatesWithFilter(_ context.Context, arg database.GetTemplatesWithFilterParams) ([]database.Template, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1123,6 +1351,22 @@ func (q *fakeQuerier) GetOrganizationMembershipsByUserID(_ context.Context, user
 	return memberships, nil
 }
 
+func (q *fakeQuerier) GetOrganizationMembersByOrganizationID(_ context.Context, organizationID uuid.UUID) ([]database.OrganizationMember, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	members := make([]database.OrganizationMember, 0)
+	for _, member := range q.organizationMembers {
+		if member.OrganizationID == organizationID {
+			members = append(members, member)
+		}
+	}
+	slices.SortFunc(members, func(a, b database.OrganizationMember) bool {
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return members, nil
+}
+
 func (q *fakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	for i, mem := range q.organizationMembers {
 		if mem.UserID == arg.UserID && mem.OrganizationID == arg.OrgID {
@@ -1145,6 +1389,19 @@ func (q *fakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMe
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) DeleteOrganizationMember(_ context.Context, arg database.DeleteOrganizationMemberParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, member := range q.organizationMembers {
+		if member.OrganizationID == arg.OrganizationID && member.UserID == arg.UserID {
+			q.organizationMembers = append(q.organizationMembers[:index], q.organizationMembers[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *fakeQuerier) GetProvisionerDaemons(_ context.Context) ([]database.ProvisionerDaemon, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1376,6 +1633,9 @@ func (q *fakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 	if arg.LifetimeSeconds == 0 {
 		arg.LifetimeSeconds = 86400
 	}
+	if arg.Scopes == nil {
+		arg.Scopes = []string{"all"}
+	}
 
 	//nolint:gosimple
 	key := database.APIKey{
@@ -1393,6 +1653,8 @@ func (q *fakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 		OAuthRefreshToken: arg.OAuthRefreshToken,
 		OAuthIDToken:      arg.OAuthIDToken,
 		OAuthExpiry:       arg.OAuthExpiry,
+		Scopes:            arg.Scopes,
+		TokenName:         arg.TokenName,
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -1475,20 +1737,35 @@ func (q *fakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 	if arg.MinAutostartInterval == 0 {
 		arg.MinAutostartInterval = int64(time.Hour)
 	}
+	if arg.GroupACL == nil {
+		arg.GroupACL = database.TemplateACL{}
+	}
+	if arg.UserACL == nil {
+		arg.UserACL = database.TemplateACL{}
+	}
 
 	//nolint:gosimple
 	template := database.Template{
-		ID:                   arg.ID,
-		CreatedAt:            arg.CreatedAt,
-		UpdatedAt:            arg.UpdatedAt,
-		OrganizationID:       arg.OrganizationID,
-		Name:                 arg.Name,
-		Provisioner:          arg.Provisioner,
-		ActiveVersionID:      arg.ActiveVersionID,
-		Description:          arg.Description,
-		MaxTtl:               arg.MaxTtl,
-		MinAutostartInterval: arg.MinAutostartInterval,
-		CreatedBy:            arg.CreatedBy,
+		ID:                        arg.ID,
+		CreatedAt:                 arg.CreatedAt,
+		UpdatedAt:                 arg.UpdatedAt,
+		OrganizationID:            arg.OrganizationID,
+		Name:                      arg.Name,
+		Provisioner:               arg.Provisioner,
+		ActiveVersionID:           arg.ActiveVersionID,
+		Description:               arg.Description,
+		MaxTtl:                    arg.MaxTtl,
+		MinAutostartInterval:      arg.MinAutostartInterval,
+		CreatedBy:                 arg.CreatedBy,
+		GroupACL:                  arg.GroupACL,
+		UserACL:                   arg.UserACL,
+		CostUnits:                 arg.CostUnits,
+		InactivityTtl:             arg.InactivityTtl,
+		DormantDeletionTtl:        arg.DormantDeletionTtl,
+		DefaultAutostartSchedule:  arg.DefaultAutostartSchedule,
+		DefaultTtl:                arg.DefaultTtl,
+		RequiredStopSchedule:      arg.RequiredStopSchedule,
+		AllowUserScheduleOverride: arg.AllowUserScheduleOverride,
 	}
 	q.templates = append(q.templates, template)
 	return template, nil
@@ -1687,6 +1964,21 @@ func (q *fakeQuerier) UpdateUserRoles(_ context.Context, arg database.UpdateUser
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateUserLastSeenAt(_ context.Context, arg database.UpdateUserLastSeenAtParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, user := range q.users {
+		if user.ID != arg.ID {
+			continue
+		}
+		user.LastSeenAt = arg.LastSeenAt
+		q.users[index] = user
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateUserProfile(_ context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1719,6 +2011,78 @@ func (q *fakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUse
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *fakeQuerier) DeleteUserByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, user := range q.users {
+		if user.ID != id {
+			continue
+		}
+		q.users = append(q.users[:index], q.users[index+1:]...)
+
+		// Mirror the cascading foreign keys of the user.
+		apiKeys := make([]database.APIKey, 0, len(q.apiKeys))
+		for _, key := range q.apiKeys {
+			if key.UserID != id {
+				apiKeys = append(apiKeys, key)
+			}
+		}
+		q.apiKeys = apiKeys
+		members := make([]database.OrganizationMember, 0, len(q.organizationMembers))
+		for _, member := range q.organizationMembers {
+			if member.UserID != id {
+				members = append(members, member)
+			}
+		}
+		q.organizationMembers = members
+		groupMembers := make([]database.GroupMember, 0, len(q.groupMembers))
+		for _, member := range q.groupMembers {
+			if member.UserID != id {
+				groupMembers = append(groupMembers, member)
+			}
+		}
+		q.groupMembers = groupMembers
+		totps := make([]database.UserTOTP, 0, len(q.userTOTPs))
+		for _, totp := range q.userTOTPs {
+			if totp.UserID != id {
+				totps = append(totps, totp)
+			}
+		}
+		q.userTOTPs = totps
+		codes := make([]database.UserTOTPRecoveryCode, 0, len(q.userTOTPRecoveryCodes))
+		for _, code := range q.userTOTPRecoveryCodes {
+			if code.UserID != id {
+				codes = append(codes, code)
+			}
+		}
+		q.userTOTPRecoveryCodes = codes
+		lockouts := make([]database.UserLockout, 0, len(q.userLockouts))
+		for _, lockout := range q.userLockouts {
+			if lockout.UserID != id {
+				lockouts = append(lockouts, lockout)
+			}
+		}
+		q.userLockouts = lockouts
+		history := make([]database.UserPasswordHistory, 0, len(q.userPasswordHistory))
+		for _, password := range q.userPasswordHistory {
+			if password.UserID != id {
+				history = append(history, password)
+			}
+		}
+		q.userPasswordHistory = history
+		quotas := make([]database.UserQuota, 0, len(q.userQuotas))
+		for _, quota := range q.userQuotas {
+			if quota.UserID != id {
+				quotas = append(quotas, quota)
+			}
+		}
+		q.userQuotas = quotas
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateUserHashedPassword(_ context.Context, arg database.UpdateUserHashedPasswordParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1749,6 +2113,7 @@ func (q *fakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		Name:              arg.Name,
 		AutostartSchedule: arg.AutostartSchedule,
 		Ttl:               arg.Ttl,
+		UserACL:           database.WorkspaceACL{},
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -1848,6 +2213,42 @@ func (q *fakeQuerier) UpdateTemplateDeletedByID(_ context.Context, arg database.
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) DeleteUserFromTemplateACLs(_ context.Context, userID string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, template := range q.templates {
+		if _, ok := template.UserACL[userID]; !ok {
+			continue
+		}
+		acl := make(database.TemplateACL, len(template.UserACL))
+		for id, actions := range template.UserACL {
+			if id != userID {
+				acl[id] = actions
+			}
+		}
+		template.UserACL = acl
+		q.templates[index] = template
+	}
+	return nil
+}
+
+func (q *fakeQuerier) UpdateTemplateACLByID(_ context.Context, arg database.UpdateTemplateACLByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, template := range q.templates {
+		if template.ID != arg.ID {
+			continue
+		}
+		template.GroupACL = arg.GroupACL
+		template.UserACL = arg.UserACL
+		q.templates[index] = template
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateTemplateVersionByID(_ context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1896,6 +2297,22 @@ func (q *fakeQuerier) UpdateProvisionerDaemonByID(_ context.Context, arg databas
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) UpdateWorkspaceAgentAuthTokenByID(_ context.Context, arg database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, agent := range q.provisionerJobAgents {
+		if agent.ID != arg.ID {
+			continue
+		}
+		agent.AuthToken = arg.AuthToken
+		agent.UpdatedAt = arg.UpdatedAt
+		q.provisionerJobAgents[index] = agent
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *fakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2011,24 +2428,24 @@ func (q *fakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateW
 	return sql.ErrNoRows
 }
 
-func (q *fakeQuerier) UpdateWorkspaceBuildByID(_ context.Context, arg database.UpdateWorkspaceBuildByIDParams) error {
+func (q *fakeQuerier) UpdateWorkspaceDormancyOptOut(_ context.Context, arg database.UpdateWorkspaceDormancyOptOutParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for index, workspaceBuild := range q.workspaceBuilds {
-		if workspaceBuild.ID != arg.ID {
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
 			continue
 		}
-		workspaceBuild.UpdatedAt = arg.UpdatedAt
-		workspaceBuild.ProvisionerState = arg.ProvisionerState
-		workspaceBuild.Deadline = arg.Deadline
-		q.workspaceBuilds[index] = workspaceBuild
+		workspace.DormancyOptOut = arg.DormancyOptOut
+		workspace.DormantAt = sql.NullTime{}
+		q.workspaces[index] = workspace
 		return nil
 	}
+
 	return sql.ErrNoRows
 }
 
-func (q *fakeQuerier) UpdateWorkspaceDeletedByID(_ context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
+func (q *fakeQuerier) UpdateWorkspaceDormantAt(_ context.Context, arg database.UpdateWorkspaceDormantAtParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
@@ -2036,46 +2453,179 @@ func (q *fakeQuerier) UpdateWorkspaceDeletedByID(_ context.Context, arg database
 		if workspace.ID != arg.ID {
 			continue
 		}
-		workspace.Deleted = arg.Deleted
+		workspace.DormantAt = arg.DormantAt
 		q.workspaces[index] = workspace
 		return nil
 	}
+
 	return sql.ErrNoRows
 }
 
-func (q *fakeQuerier) InsertGitSSHKey(_ context.Context, arg database.InsertGitSSHKeyParams) (database.GitSSHKey, error) {
+func (q *fakeQuerier) UpdateWorkspaceName(_ context.Context, arg database.UpdateWorkspaceNameParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	//nolint:gosimple
-	gitSSHKey := database.GitSSHKey{
-		UserID:     arg.UserID,
-		CreatedAt:  arg.CreatedAt,
-		UpdatedAt:  arg.UpdatedAt,
-		PrivateKey: arg.PrivateKey,
-		PublicKey:  arg.PublicKey,
-	}
-	q.gitSSHKey = append(q.gitSSHKey, gitSSHKey)
-	return gitSSHKey, nil
-}
-
-func (q *fakeQuerier) GetGitSSHKey(_ context.Context, userID uuid.UUID) (database.GitSSHKey, error) {
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
-
-	for _, key := range q.gitSSHKey {
-		if key.UserID == userID {
-			return key, nil
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
 		}
+		workspace.Name = arg.Name
+		workspace.UpdatedAt = arg.UpdatedAt
+		q.workspaces[index] = workspace
+		return nil
 	}
-	return database.GitSSHKey{}, sql.ErrNoRows
+
+	return sql.ErrNoRows
 }
 
-func (q *fakeQuerier) UpdateGitSSHKey(_ context.Context, arg database.UpdateGitSSHKeyParams) error {
+func (q *fakeQuerier) UpdateWorkspaceOwner(_ context.Context, arg database.UpdateWorkspaceOwnerParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for index, key := range q.gitSSHKey {
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.OwnerID = arg.OwnerID
+		workspace.UpdatedAt = arg.UpdatedAt
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateWorkspaceUserACL(_ context.Context, arg database.UpdateWorkspaceUserACLParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.UserACL = arg.UserACL
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateWorkspaceBuildByID(_ context.Context, arg database.UpdateWorkspaceBuildByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspaceBuild := range q.workspaceBuilds {
+		if workspaceBuild.ID != arg.ID {
+			continue
+		}
+		workspaceBuild.UpdatedAt = arg.UpdatedAt
+		workspaceBuild.ProvisionerState = arg.ProvisionerState
+		workspaceBuild.Deadline = arg.Deadline
+		q.workspaceBuilds[index] = workspaceBuild
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateWorkspaceDeletedByID(_ context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.Deleted = arg.Deleted
+		q.workspaces[index] = workspace
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteDeletedWorkspacesByOrganizationID(_ context.Context, organizationID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	workspaces := make([]database.Workspace, 0, len(q.workspaces))
+	for _, workspace := range q.workspaces {
+		if workspace.OrganizationID == organizationID && workspace.Deleted {
+			continue
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	q.workspaces = workspaces
+	return nil
+}
+
+func (q *fakeQuerier) DeleteDeletedWorkspacesByOwnerID(_ context.Context, ownerID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	workspaces := make([]database.Workspace, 0, len(q.workspaces))
+	for _, workspace := range q.workspaces {
+		if workspace.OwnerID == ownerID && workspace.Deleted {
+			continue
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	q.workspaces = workspaces
+	return nil
+}
+
+func (q *fakeQuerier) DeleteUserFromWorkspaceACLs(_ context.Context, userID string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if _, ok := workspace.UserACL[userID]; !ok {
+			continue
+		}
+		acl := make(database.WorkspaceACL, len(workspace.UserACL))
+		for id, actions := range workspace.UserACL {
+			if id != userID {
+				acl[id] = actions
+			}
+		}
+		workspace.UserACL = acl
+		q.workspaces[index] = workspace
+	}
+	return nil
+}
+
+func (q *fakeQuerier) InsertGitSSHKey(_ context.Context, arg database.InsertGitSSHKeyParams) (database.GitSSHKey, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	gitSSHKey := database.GitSSHKey{
+		UserID:     arg.UserID,
+		CreatedAt:  arg.CreatedAt,
+		UpdatedAt:  arg.UpdatedAt,
+		PrivateKey: arg.PrivateKey,
+		PublicKey:  arg.PublicKey,
+	}
+	q.gitSSHKey = append(q.gitSSHKey, gitSSHKey)
+	return gitSSHKey, nil
+}
+
+func (q *fakeQuerier) GetGitSSHKey(_ context.Context, userID uuid.UUID) (database.GitSSHKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.gitSSHKey {
+		if key.UserID == userID {
+			return key, nil
+		}
+	}
+	return database.GitSSHKey{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateGitSSHKey(_ context.Context, arg database.UpdateGitSSHKeyParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, key := range q.gitSSHKey {
 		if key.UserID.String() != arg.UserID.String() {
 			continue
 		}
@@ -2103,6 +2653,83 @@ func (q *fakeQuerier) DeleteGitSSHKey(_ context.Context, userID uuid.UUID) error
 	return sql.ErrNoRows
 }
 
+func (q *fakeQuerier) GetAuditLogs(_ context.Context, arg database.GetAuditLogsParams) ([]database.AuditLog, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	// Avoid side-effect of sorting.
+	logs := make([]database.AuditLog, len(q.auditLogs))
+	copy(logs, q.auditLogs)
+
+	// Database orders by time DESC, using the ID as a tie breaker.
+	slices.SortFunc(logs, func(a, b database.AuditLog) bool {
+		if a.Time.Equal(b.Time) {
+			return a.ID.String() > b.ID.String()
+		}
+		return a.Time.After(b.Time)
+	})
+
+	if arg.AfterID != uuid.Nil {
+		found := false
+		for i, alog := range logs {
+			if alog.ID == arg.AfterID {
+				// We want to return all logs after index i.
+				logs = logs[i+1:]
+				found = true
+				break
+			}
+		}
+
+		// If the cursor doesn't exist, we return an empty list.
+		if !found {
+			return nil, sql.ErrNoRows
+		}
+	}
+
+	filtered := make([]database.AuditLog, 0, len(logs))
+	for _, alog := range logs {
+		if arg.UserID != uuid.Nil && alog.UserID != arg.UserID {
+			continue
+		}
+		if arg.ResourceType != "" && string(alog.ResourceType) != arg.ResourceType {
+			continue
+		}
+		if arg.ResourceID != uuid.Nil && alog.ResourceID != arg.ResourceID {
+			continue
+		}
+		if arg.Action != "" && string(alog.Action) != arg.Action {
+			continue
+		}
+		if arg.StatusCode != 0 && alog.StatusCode != arg.StatusCode {
+			continue
+		}
+		if !arg.TimeFrom.IsZero() && alog.Time.Before(arg.TimeFrom) {
+			continue
+		}
+		if !arg.TimeTo.IsZero() && !alog.Time.Before(arg.TimeTo) {
+			continue
+		}
+		filtered = append(filtered, alog)
+	}
+	logs = filtered
+
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(logs)-1 {
+			return nil, sql.ErrNoRows
+		}
+		logs = logs[arg.OffsetOpt:]
+	}
+
+	if arg.LimitOpt > 0 {
+		if int(arg.LimitOpt) > len(logs) {
+			arg.LimitOpt = int32(len(logs))
+		}
+		logs = logs[:arg.LimitOpt]
+	}
+
+	return logs, nil
+}
+
 func (q *fakeQuerier) GetAuditLogsBefore(_ context.Context, arg database.GetAuditLogsBeforeParams) ([]database.AuditLog, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2140,6 +2767,60 @@ func (q *fakeQuerier) GetAuditLogsBefore(_ context.Context, arg database.GetAudi
 	return logs, nil
 }
 
+func (q *fakeQuerier) GetAuditLogsOlderThan(_ context.Context, arg database.GetAuditLogsOlderThanParams) ([]database.AuditLog, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.AuditLog, 0)
+	for _, alog := range q.auditLogs {
+		if alog.OrganizationID != arg.OrganizationID || !alog.Time.Before(arg.TimeBefore) {
+			continue
+		}
+		logs = append(logs, alog)
+	}
+
+	// Database orders by time ASC, using the ID as a tie breaker.
+	slices.SortFunc(logs, func(a, b database.AuditLog) bool {
+		if a.Time.Equal(b.Time) {
+			return a.ID.String() < b.ID.String()
+		}
+		return a.Time.Before(b.Time)
+	})
+	if len(logs) > int(arg.RowLimit) {
+		logs = logs[:arg.RowLimit]
+	}
+	return logs, nil
+}
+
+func (q *fakeQuerier) GetAuditLogOrganizationIDs(_ context.Context) ([]uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	organizationIDs := make([]uuid.UUID, 0)
+	for _, alog := range q.auditLogs {
+		if slices.Contains(organizationIDs, alog.OrganizationID) {
+			continue
+		}
+		organizationIDs = append(organizationIDs, alog.OrganizationID)
+	}
+	return organizationIDs, nil
+}
+
+func (q *fakeQuerier) DeleteAuditLogsByIDs(_ context.Context, ids []uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logs := make([]database.AuditLog, 0, len(q.auditLogs))
+	for _, alog := range q.auditLogs {
+		if slices.Contains(ids, alog.ID) {
+			continue
+		}
+		logs = append(logs, alog)
+	}
+	q.auditLogs = logs
+	return nil
+}
+
 func (q *fakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAuditLogParams) (database.AuditLog, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2168,3 +2849,719 @@ func (q *fakeQuerier) GetDeploymentID(_ context.Context) (string, error) {
 
 	return q.deploymentID, nil
 }
+
+func (q *fakeQuerier) GetUserTOTPByUserID(_ context.Context, userID uuid.UUID) (database.UserTOTP, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, userTOTP := range q.userTOTPs {
+		if userTOTP.UserID == userID {
+			return userTOTP, nil
+		}
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetUserTOTPByLoginChallenge(_ context.Context, loginChallenge []byte) (database.UserTOTP, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, userTOTP := range q.userTOTPs {
+		if userTOTP.LoginChallenge != nil && bytes.Equal(userTOTP.LoginChallenge, loginChallenge) {
+			return userTOTP, nil
+		}
+	}
+	return database.UserTOTP{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpsertUserTOTP(_ context.Context, arg database.UpsertUserTOTPParams) (database.UserTOTP, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, userTOTP := range q.userTOTPs {
+		if userTOTP.UserID != arg.UserID {
+			continue
+		}
+		userTOTP.Secret = arg.Secret
+		userTOTP.Enabled = false
+		userTOTP.LastUsedStep = 0
+		userTOTP.UpdatedAt = arg.UpdatedAt
+		q.userTOTPs[index] = userTOTP
+		return userTOTP, nil
+	}
+	userTOTP := database.UserTOTP{
+		UserID:    arg.UserID,
+		Secret:    arg.Secret,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+	}
+	q.userTOTPs = append(q.userTOTPs, userTOTP)
+	return userTOTP, nil
+}
+
+func (q *fakeQuerier) UpdateUserTOTP(_ context.Context, arg database.UpdateUserTOTPParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, userTOTP := range q.userTOTPs {
+		if userTOTP.UserID != arg.UserID {
+			continue
+		}
+		userTOTP.Enabled = arg.Enabled
+		userTOTP.LastUsedStep = arg.LastUsedStep
+		userTOTP.UpdatedAt = arg.UpdatedAt
+		q.userTOTPs[index] = userTOTP
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateUserTOTPLoginChallenge(_ context.Context, arg database.UpdateUserTOTPLoginChallengeParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, userTOTP := range q.userTOTPs {
+		if userTOTP.UserID != arg.UserID {
+			continue
+		}
+		userTOTP.LoginChallenge = arg.LoginChallenge
+		userTOTP.LoginChallengeExpiresAt = arg.LoginChallengeExpiresAt
+		userTOTP.LoginChallengeAttempts = arg.LoginChallengeAttempts
+		q.userTOTPs[index] = userTOTP
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteUserTOTPByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, userTOTP := range q.userTOTPs {
+		if userTOTP.UserID != userID {
+			continue
+		}
+		q.userTOTPs[index] = q.userTOTPs[len(q.userTOTPs)-1]
+		q.userTOTPs = q.userTOTPs[:len(q.userTOTPs)-1]
+		return nil
+	}
+	return nil
+}
+
+func (q *fakeQuerier) InsertUserTOTPRecoveryCode(_ context.Context, arg database.InsertUserTOTPRecoveryCodeParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userTOTPRecoveryCodes = append(q.userTOTPRecoveryCodes, database.UserTOTPRecoveryCode{
+		UserID:     arg.UserID,
+		HashedCode: arg.HashedCode,
+	})
+	return nil
+}
+
+func (q *fakeQuerier) GetUserTOTPRecoveryCodeCount(_ context.Context, userID uuid.UUID) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var count int64
+	for _, code := range q.userTOTPRecoveryCodes {
+		if code.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *fakeQuerier) DeleteUserTOTPRecoveryCode(_ context.Context, arg database.DeleteUserTOTPRecoveryCodeParams) (database.UserTOTPRecoveryCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.userTOTPRecoveryCodes {
+		if code.UserID != arg.UserID || !bytes.Equal(code.HashedCode, arg.HashedCode) {
+			continue
+		}
+		q.userTOTPRecoveryCodes[index] = q.userTOTPRecoveryCodes[len(q.userTOTPRecoveryCodes)-1]
+		q.userTOTPRecoveryCodes = q.userTOTPRecoveryCodes[:len(q.userTOTPRecoveryCodes)-1]
+		return code, nil
+	}
+	return database.UserTOTPRecoveryCode{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteUserTOTPRecoveryCodesByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	codes := make([]database.UserTOTPRecoveryCode, 0, len(q.userTOTPRecoveryCodes))
+	for _, code := range q.userTOTPRecoveryCodes {
+		if code.UserID != userID {
+			codes = append(codes, code)
+		}
+	}
+	q.userTOTPRecoveryCodes = codes
+	return nil
+}
+
+func (q *fakeQuerier) GetUserLockoutByUserID(_ context.Context, userID uuid.UUID) (database.UserLockout, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, lockout := range q.userLockouts {
+		if lockout.UserID == userID {
+			return lockout, nil
+		}
+	}
+	return database.UserLockout{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) IncrementUserLockoutFailedAttempts(_ context.Context, arg database.IncrementUserLockoutFailedAttemptsParams) (database.UserLockout, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, lockout := range q.userLockouts {
+		if lockout.UserID != arg.UserID {
+			continue
+		}
+		if lockout.UpdatedAt.Before(arg.ResetBefore) {
+			lockout.FailedAttempts = 0
+		}
+		lockout.FailedAttempts++
+		lockout.UpdatedAt = arg.UpdatedAt
+		q.userLockouts[index] = lockout
+		return lockout, nil
+	}
+	lockout := database.UserLockout{
+		UserID:         arg.UserID,
+		FailedAttempts: 1,
+		UpdatedAt:      arg.UpdatedAt,
+	}
+	q.userLockouts = append(q.userLockouts, lockout)
+	return lockout, nil
+}
+
+func (q *fakeQuerier) UpdateUserLockout(_ context.Context, arg database.UpdateUserLockoutParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, lockout := range q.userLockouts {
+		if lockout.UserID != arg.UserID {
+			continue
+		}
+		lockout.FailedAttempts = arg.FailedAttempts
+		lockout.LockedUntil = arg.LockedUntil
+		lockout.UpdatedAt = arg.UpdatedAt
+		q.userLockouts[index] = lockout
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteUserLockoutByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, lockout := range q.userLockouts {
+		if lockout.UserID != userID {
+			continue
+		}
+		q.userLockouts[index] = q.userLockouts[len(q.userLockouts)-1]
+		q.userLockouts = q.userLockouts[:len(q.userLockouts)-1]
+		return nil
+	}
+	return nil
+}
+
+func (q *fakeQuerier) GetUserPasswordHistory(_ context.Context, arg database.GetUserPasswordHistoryParams) ([]database.UserPasswordHistory, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	history := make([]database.UserPasswordHistory, 0)
+	for _, password := range q.userPasswordHistory {
+		if password.UserID == arg.UserID {
+			history = append(history, password)
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreatedAt.After(history[j].CreatedAt)
+	})
+	if len(history) > int(arg.Limit) {
+		history = history[:arg.Limit]
+	}
+	return history, nil
+}
+
+func (q *fakeQuerier) InsertUserPasswordHistory(_ context.Context, arg database.InsertUserPasswordHistoryParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	q.userPasswordHistory = append(q.userPasswordHistory, database.UserPasswordHistory{
+		UserID:         arg.UserID,
+		HashedPassword: arg.HashedPassword,
+		CreatedAt:      arg.CreatedAt,
+	})
+	return nil
+}
+
+func (q *fakeQuerier) GetOrganizationQuota(_ context.Context, organizationID uuid.UUID) (database.OrganizationQuota, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, quota := range q.organizationQuotas {
+		if quota.OrganizationID == organizationID {
+			return quota, nil
+		}
+	}
+	return database.OrganizationQuota{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpsertOrganizationQuota(_ context.Context, arg database.UpsertOrganizationQuotaParams) (database.OrganizationQuota, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	quota := database.OrganizationQuota{
+		OrganizationID:       arg.OrganizationID,
+		MaxWorkspaces:        arg.MaxWorkspaces,
+		MaxRunningWorkspaces: arg.MaxRunningWorkspaces,
+		MaxCostUnits:         arg.MaxCostUnits,
+		UpdatedAt:            arg.UpdatedAt,
+	}
+	for index, existing := range q.organizationQuotas {
+		if existing.OrganizationID == arg.OrganizationID {
+			q.organizationQuotas[index] = quota
+			return quota, nil
+		}
+	}
+	q.organizationQuotas = append(q.organizationQuotas, quota)
+	return quota, nil
+}
+
+func (q *fakeQuerier) GetUserQuota(_ context.Context, userID uuid.UUID) (database.UserQuota, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, quota := range q.userQuotas {
+		if quota.UserID == userID {
+			return quota, nil
+		}
+	}
+	return database.UserQuota{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpsertUserQuota(_ context.Context, arg database.UpsertUserQuotaParams) (database.UserQuota, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	quota := database.UserQuota{
+		UserID:               arg.UserID,
+		MaxWorkspaces:        arg.MaxWorkspaces,
+		MaxRunningWorkspaces: arg.MaxRunningWorkspaces,
+		MaxCostUnits:         arg.MaxCostUnits,
+		UpdatedAt:            arg.UpdatedAt,
+	}
+	for index, existing := range q.userQuotas {
+		if existing.UserID == arg.UserID {
+			q.userQuotas[index] = quota
+			return quota, nil
+		}
+	}
+	q.userQuotas = append(q.userQuotas, quota)
+	return quota, nil
+}
+
+func (q *fakeQuerier) GetQuotaUsage(_ context.Context, arg database.GetQuotaUsageParams) (database.GetQuotaUsageRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var usage database.GetQuotaUsageRow
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		if arg.OwnerID != uuid.Nil && workspace.OwnerID != arg.OwnerID {
+			continue
+		}
+		if arg.OrganizationID != uuid.Nil && workspace.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		var template database.Template
+		found := false
+		for _, t := range q.templates {
+			if t.ID == workspace.TemplateID {
+				template = t
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		usage.Workspaces++
+
+		var latest *database.WorkspaceBuild
+		for index, build := range q.workspaceBuilds {
+			if build.WorkspaceID != workspace.ID {
+				continue
+			}
+			if latest == nil || build.BuildNumber > latest.BuildNumber {
+				latest = &q.workspaceBuilds[index]
+			}
+		}
+		if latest == nil || latest.Transition != database.WorkspaceTransitionStart {
+			continue
+		}
+		running := false
+		for _, job := range q.provisionerJobs {
+			if job.ID == latest.JobID {
+				running = !job.Error.Valid && !job.CanceledAt.Valid
+				break
+			}
+		}
+		if !running {
+			continue
+		}
+		usage.RunningWorkspaces++
+		usage.CostUnits += int64(template.CostUnits)
+	}
+	return usage, nil
+}
+
+func (*fakeQuerier) AcquireQuotaLock(_ context.Context, _ uuid.UUID) error {
+	// Transactions hold the mutex of the fake database until they end, so
+	// they're already counted toward quotas one at a time.
+	return nil
+}
+
+func (*fakeQuerier) TryAcquireQuotaLock(_ context.Context, _ uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func (q *fakeQuerier) GetCustomRoles(_ context.Context, organizationID uuid.NullUUID) ([]database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	roles := make([]database.CustomRole, 0)
+	for _, role := range q.customRoles {
+		if role.OrganizationID == organizationID {
+			roles = append(roles, role)
+		}
+	}
+	slices.SortFunc(roles, func(a, b database.CustomRole) bool {
+		return a.Name < b.Name
+	})
+	return roles, nil
+}
+
+func (q *fakeQuerier) GetCustomRoleByName(_ context.Context, arg database.GetCustomRoleByNameParams) (database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, role := range q.customRoles {
+		if role.Name == arg.Name && role.OrganizationID == arg.OrganizationID {
+			return role, nil
+		}
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) InsertCustomRole(_ context.Context, arg database.InsertCustomRoleParams) (database.CustomRole, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	role := database.CustomRole{
+		ID:              arg.ID,
+		Name:            arg.Name,
+		DisplayName:     arg.DisplayName,
+		OrganizationID:  arg.OrganizationID,
+		SitePermissions: arg.SitePermissions,
+		OrgPermissions:  arg.OrgPermissions,
+		UserPermissions: arg.UserPermissions,
+		CreatedAt:       arg.CreatedAt,
+		UpdatedAt:       arg.UpdatedAt,
+	}
+	q.customRoles = append(q.customRoles, role)
+	return role, nil
+}
+
+func (q *fakeQuerier) UpdateCustomRole(_ context.Context, arg database.UpdateCustomRoleParams) (database.CustomRole, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, role := range q.customRoles {
+		if role.ID != arg.ID {
+			continue
+		}
+		role.DisplayName = arg.DisplayName
+		role.SitePermissions = arg.SitePermissions
+		role.OrgPermissions = arg.OrgPermissions
+		role.UserPermissions = arg.UserPermissions
+		role.UpdatedAt = arg.UpdatedAt
+		q.customRoles[index] = role
+		return role, nil
+	}
+	return database.CustomRole{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteCustomRole(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, role := range q.customRoles {
+		if role.ID != id {
+			continue
+		}
+		q.customRoles[index] = q.customRoles[len(q.customRoles)-1]
+		q.customRoles = q.customRoles[:len(q.customRoles)-1]
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteRoleFromUsers(_ context.Context, roleName string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, user := range q.users {
+		roles := make([]string, 0, len(user.RBACRoles))
+		for _, role := range user.RBACRoles {
+			if role != roleName {
+				roles = append(roles, role)
+			}
+		}
+		q.users[index].RBACRoles = roles
+	}
+	return nil
+}
+
+func (q *fakeQuerier) DeleteRoleFromOrganizationMembers(_ context.Context, arg database.DeleteRoleFromOrganizationMembersParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, member := range q.organizationMembers {
+		if member.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		roles := make([]string, 0, len(member.Roles))
+		for _, role := range member.Roles {
+			if role != arg.RoleName {
+				roles = append(roles, role)
+			}
+		}
+		q.organizationMembers[index].Roles = roles
+	}
+	return nil
+}
+
+func (q *fakeQuerier) GetGroupByID(_ context.Context, id uuid.UUID) (database.Group, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, group := range q.groups {
+		if group.ID == id {
+			return group, nil
+		}
+	}
+	return database.Group{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetGroupByOrganizationAndName(_ context.Context, arg database.GetGroupByOrganizationAndNameParams) (database.Group, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, group := range q.groups {
+		if group.OrganizationID == arg.OrganizationID && group.Name == arg.Name {
+			return group, nil
+		}
+	}
+	return database.Group{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) GetGroupsByOrganizationID(_ context.Context, organizationID uuid.UUID) ([]database.Group, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	groups := make([]database.Group, 0)
+	for _, group := range q.groups {
+		if group.OrganizationID == organizationID {
+			groups = append(groups, group)
+		}
+	}
+	slices.SortFunc(groups, func(a, b database.Group) bool {
+		return a.Name < b.Name
+	})
+	return groups, nil
+}
+
+func (q *fakeQuerier) InsertGroup(_ context.Context, arg database.InsertGroupParams) (database.Group, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	group := database.Group{
+		ID:             arg.ID,
+		Name:           arg.Name,
+		OrganizationID: arg.OrganizationID,
+		Roles:          arg.Roles,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+	}
+	q.groups = append(q.groups, group)
+	return group, nil
+}
+
+func (q *fakeQuerier) UpdateGroupByID(_ context.Context, arg database.UpdateGroupByIDParams) (database.Group, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, group := range q.groups {
+		if group.ID != arg.ID {
+			continue
+		}
+		group.Name = arg.Name
+		group.UpdatedAt = arg.UpdatedAt
+		q.groups[index] = group
+		return group, nil
+	}
+	return database.Group{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) UpdateGroupRoles(_ context.Context, arg database.UpdateGroupRolesParams) (database.Group, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, group := range q.groups {
+		if group.ID != arg.ID {
+			continue
+		}
+		uniqueRoles := make([]string, 0, len(arg.GrantedRoles))
+		exist := make(map[string]struct{})
+		for _, r := range arg.GrantedRoles {
+			if _, ok := exist[r]; ok {
+				continue
+			}
+			exist[r] = struct{}{}
+			uniqueRoles = append(uniqueRoles, r)
+		}
+		sort.Strings(uniqueRoles)
+
+		group.Roles = uniqueRoles
+		group.UpdatedAt = arg.UpdatedAt
+		q.groups[index] = group
+		return group, nil
+	}
+	return database.Group{}, sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteGroupByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, group := range q.groups {
+		if group.ID != id {
+			continue
+		}
+		q.groups = append(q.groups[:index], q.groups[index+1:]...)
+
+		members := make([]database.GroupMember, 0, len(q.groupMembers))
+		for _, member := range q.groupMembers {
+			if member.GroupID != id {
+				members = append(members, member)
+			}
+		}
+		q.groupMembers = members
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *fakeQuerier) DeleteRoleFromGroups(_ context.Context, arg database.DeleteRoleFromGroupsParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, group := range q.groups {
+		if group.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		roles := make([]string, 0, len(group.Roles))
+		for _, role := range group.Roles {
+			if role != arg.RoleName {
+				roles = append(roles, role)
+			}
+		}
+		q.groups[index].Roles = roles
+	}
+	return nil
+}
+
+func (q *fakeQuerier) GetGroupMembers(_ context.Context, groupID uuid.UUID) ([]database.User, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	users := make([]database.User, 0)
+	for _, member := range q.groupMembers {
+		if member.GroupID != groupID {
+			continue
+		}
+		for _, user := range q.users {
+			if user.ID == member.UserID {
+				users = append(users, user)
+			}
+		}
+	}
+	slices.SortFunc(users, func(a, b database.User) bool {
+		return a.Username < b.Username
+	})
+	return users, nil
+}
+
+func (q *fakeQuerier) InsertGroupMember(_ context.Context, arg database.InsertGroupMemberParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, member := range q.groupMembers {
+		if member.GroupID == arg.GroupID && member.UserID == arg.UserID {
+			return nil
+		}
+	}
+	//nolint:gosimple
+	q.groupMembers = append(q.groupMembers, database.GroupMember{
+		GroupID:   arg.GroupID,
+		UserID:    arg.UserID,
+		CreatedAt: arg.CreatedAt,
+	})
+	return nil
+}
+
+func (q *fakeQuerier) DeleteGroupMember(_ context.Context, arg database.DeleteGroupMemberParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, member := range q.groupMembers {
+		if member.GroupID == arg.GroupID && member.UserID == arg.UserID {
+			q.groupMembers = append(q.groupMembers[:index], q.groupMembers[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *fakeQuerier) DeleteGroupMembersByOrganizationAndUser(_ context.Context, arg database.DeleteGroupMembersByOrganizationAndUserParams) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	groupIDs := make(map[uuid.UUID]struct{})
+	for _, group := range q.groups {
+		if group.OrganizationID == arg.OrganizationID {
+			groupIDs[group.ID] = struct{}{}
+		}
+	}
+	members := make([]database.GroupMember, 0, len(q.groupMembers))
+	for _, member := range q.groupMembers {
+		if _, ok := groupIDs[member.GroupID]; ok && member.UserID == arg.UserID {
+			continue
+		}
+		members = append(members, member)
+	}
+	q.groupMembers = members
+	return nil
+}
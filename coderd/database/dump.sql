@@ -9,7 +9,8 @@ CREATE TYPE audit_action AS ENUM (
 CREATE TYPE build_reason AS ENUM (
     'initiator',
     'autostart',
-    'autostop'
+    'autostop',
+    'autodelete'
 );
 
 CREATE TYPE log_level AS ENUM (
@@ -27,7 +28,9 @@ CREATE TYPE log_source AS ENUM (
 
 CREATE TYPE login_type AS ENUM (
     'password',
-    'github'
+    'github',
+    'oidc',
+    'token'
 );
 
 CREATE TYPE parameter_destination_scheme AS ENUM (
@@ -72,7 +75,16 @@ CREATE TYPE resource_type AS ENUM (
     'template',
     'template_version',
     'user',
-    'workspace'
+    'workspace',
+    'organization_member',
+    'git_ssh_key',
+    'api_key',
+    'user_totp',
+    'custom_role',
+    'group',
+    'user_lockout',
+    'user_quota',
+    'organization_quota'
 );
 
 CREATE TYPE user_status AS ENUM (
@@ -100,7 +112,9 @@ CREATE TABLE api_keys (
     oauth_id_token text DEFAULT ''::text NOT NULL,
     oauth_expiry timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
-    ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL
+    ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
+    scopes text[] DEFAULT '{all}'::text[] NOT NULL,
+    token_name text DEFAULT ''::text NOT NULL
 );
 
 CREATE TABLE audit_logs (
@@ -118,6 +132,18 @@ CREATE TABLE audit_logs (
     status_code integer NOT NULL
 );
 
+CREATE TABLE custom_roles (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    display_name text NOT NULL,
+    organization_id uuid,
+    site_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    org_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    user_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
 CREATE TABLE files (
     hash character varying(64) NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -134,6 +160,21 @@ CREATE TABLE gitsshkeys (
     public_key text NOT NULL
 );
 
+CREATE TABLE group_members (
+    group_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE groups (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    organization_id uuid NOT NULL,
+    roles text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
 CREATE TABLE licenses (
     id integer NOT NULL,
     license jsonb NOT NULL,
@@ -158,6 +199,14 @@ CREATE TABLE organization_members (
     roles text[] DEFAULT '{organization-member}'::text[] NOT NULL
 );
 
+CREATE TABLE organization_quotas (
+    organization_id uuid NOT NULL,
+    max_workspaces integer DEFAULT 0 NOT NULL,
+    max_running_workspaces integer DEFAULT 0 NOT NULL,
+    max_cost_units integer DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
 CREATE TABLE organizations (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -261,7 +310,54 @@ CREATE TABLE templates (
     description character varying(128) DEFAULT ''::character varying NOT NULL,
     max_ttl bigint DEFAULT '604800000000000'::bigint NOT NULL,
     min_autostart_interval bigint DEFAULT '3600000000000'::bigint NOT NULL,
-    created_by uuid NOT NULL
+    created_by uuid NOT NULL,
+    group_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    user_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    cost_units integer DEFAULT 0 NOT NULL,
+    inactivity_ttl bigint DEFAULT 0 NOT NULL,
+    dormant_deletion_ttl bigint DEFAULT 0 NOT NULL,
+    default_autostart_schedule text DEFAULT ''::text NOT NULL,
+    default_ttl bigint DEFAULT 0 NOT NULL,
+    required_stop_schedule text DEFAULT ''::text NOT NULL,
+    allow_user_schedule_override boolean DEFAULT true NOT NULL
+);
+
+CREATE TABLE user_lockouts (
+    user_id uuid NOT NULL,
+    failed_attempts integer DEFAULT 0 NOT NULL,
+    locked_until timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE user_password_history (
+    user_id uuid NOT NULL,
+    hashed_password bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE user_quotas (
+    user_id uuid NOT NULL,
+    max_workspaces integer DEFAULT 0 NOT NULL,
+    max_running_workspaces integer DEFAULT 0 NOT NULL,
+    max_cost_units integer DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE user_totp (
+    user_id uuid NOT NULL,
+    secret text NOT NULL,
+    enabled boolean DEFAULT false NOT NULL,
+    last_used_step bigint DEFAULT 0 NOT NULL,
+    login_challenge bytea,
+    login_challenge_expires_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
+    login_challenge_attempts integer DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE user_totp_recovery_codes (
+    user_id uuid NOT NULL,
+    hashed_code bytea NOT NULL
 );
 
 CREATE TABLE users (
@@ -272,7 +368,8 @@ CREATE TABLE users (
     created_at timestamp with time zone NOT NULL,
     updated_at timestamp with time zone NOT NULL,
     status user_status DEFAULT 'active'::public.user_status NOT NULL,
-    rbac_roles text[] DEFAULT '{}'::text[] NOT NULL
+    rbac_roles text[] DEFAULT '{}'::text[] NOT NULL,
+    last_seen_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
 CREATE TABLE workspace_agents (
@@ -344,7 +441,10 @@ CREATE TABLE workspaces (
     deleted boolean DEFAULT false NOT NULL,
     name character varying(64) NOT NULL,
     autostart_schedule text,
-    ttl bigint
+    ttl bigint,
+    user_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    dormant_at timestamp with time zone,
+    dormancy_opt_out boolean DEFAULT false NOT NULL
 );
 
 ALTER TABLE ONLY licenses ALTER COLUMN id SET DEFAULT nextval('public.licenses_id_seq'::regclass);
@@ -355,18 +455,33 @@ ALTER TABLE ONLY api_keys
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY files
     ADD CONSTRAINT files_pkey PRIMARY KEY (hash);
 
 ALTER TABLE ONLY gitsshkeys
     ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id);
 
+ALTER TABLE ONLY group_members
+    ADD CONSTRAINT group_members_pkey PRIMARY KEY (group_id, user_id);
+
+ALTER TABLE ONLY groups
+    ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
+
+ALTER TABLE ONLY groups
+    ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
+ALTER TABLE ONLY organization_quotas
+    ADD CONSTRAINT organization_quotas_pkey PRIMARY KEY (organization_id);
+
 ALTER TABLE ONLY organizations
     ADD CONSTRAINT organizations_pkey PRIMARY KEY (id);
 
@@ -406,6 +521,18 @@ ALTER TABLE ONLY template_versions
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY user_lockouts
+    ADD CONSTRAINT user_lockouts_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_quotas
+    ADD CONSTRAINT user_quotas_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_totp_recovery_codes
+    ADD CONSTRAINT user_totp_recovery_codes_pkey PRIMARY KEY (user_id, hashed_code);
+
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
@@ -438,6 +565,8 @@ ALTER TABLE ONLY workspaces
 
 CREATE INDEX idx_api_keys_user ON api_keys USING btree (user_id);
 
+CREATE UNIQUE INDEX idx_api_keys_user_id_token_name ON api_keys USING btree (user_id, token_name) WHERE (token_name <> ''::text);
+
 CREATE INDEX idx_audit_log_organization_id ON audit_logs USING btree (organization_id);
 
 CREATE INDEX idx_audit_log_resource_id ON audit_logs USING btree (resource_id);
@@ -446,6 +575,10 @@ CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
 
 CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
 
+CREATE UNIQUE INDEX idx_custom_roles_name_organization_id ON custom_roles USING btree (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+CREATE INDEX idx_group_members_user_id ON group_members USING btree (user_id);
+
 CREATE INDEX idx_organization_member_organization_id_uuid ON organization_members USING btree (organization_id);
 
 CREATE INDEX idx_organization_member_user_id_uuid ON organization_members USING btree (user_id);
@@ -454,6 +587,10 @@ CREATE UNIQUE INDEX idx_organization_name ON organizations USING btree (name);
 
 CREATE UNIQUE INDEX idx_organization_name_lower ON organizations USING btree (lower(name));
 
+CREATE INDEX idx_user_password_history_user_id ON user_password_history USING btree (user_id, created_at);
+
+CREATE INDEX idx_user_totp_login_challenge ON user_totp USING btree (login_challenge);
+
 CREATE UNIQUE INDEX idx_users_email ON users USING btree (email);
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username);
@@ -467,15 +604,30 @@ CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (own
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY gitsshkeys
     ADD CONSTRAINT gitsshkeys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
+ALTER TABLE ONLY group_members
+    ADD CONSTRAINT group_members_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY group_members
+    ADD CONSTRAINT group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY groups
+    ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY organization_quotas
+    ADD CONSTRAINT organization_quotas_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY parameter_schemas
     ADD CONSTRAINT parameter_schemas_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
@@ -497,6 +649,21 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_lockouts
+    ADD CONSTRAINT user_lockouts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_password_history
+    ADD CONSTRAINT user_password_history_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_quotas
+    ADD CONSTRAINT user_quotas_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_totp
+    ADD CONSTRAINT user_totp_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_totp_recovery_codes
+    ADD CONSTRAINT user_totp_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agents
     ADD CONSTRAINT workspace_agents_resource_id_fkey FOREIGN KEY (resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
@@ -0,0 +1,9 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+
+-- Delete all audit logs that use the new enum values.
+DELETE FROM
+    audit_logs
+WHERE
+    resource_type IN ('organization_member', 'git_ssh_key')
+;
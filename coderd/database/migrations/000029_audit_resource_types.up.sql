@@ -0,0 +1,5 @@
+ALTER TYPE resource_type
+ADD VALUE IF NOT EXISTS 'organization_member';
+
+ALTER TYPE resource_type
+ADD VALUE IF NOT EXISTS 'git_ssh_key';
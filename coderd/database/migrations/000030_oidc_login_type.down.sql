@@ -0,0 +1,10 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+
+-- Delete all API keys created by OIDC logins, otherwise they would remain
+-- valid without a way to refresh them.
+DELETE FROM
+    api_keys
+WHERE
+    login_type = 'oidc'
+;
@@ -0,0 +1,2 @@
+ALTER TYPE login_type
+ADD VALUE IF NOT EXISTS 'oidc';
@@ -0,0 +1,11 @@
+-- Delete all tokens, since they'd otherwise gain the full permissions of
+-- their user.
+DELETE FROM
+    api_keys
+WHERE
+    login_type = 'token'
+    OR scopes != '{all}'
+;
+
+ALTER TABLE api_keys
+DROP COLUMN scopes;
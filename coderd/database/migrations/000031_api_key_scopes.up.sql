@@ -0,0 +1,8 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+ALTER TYPE login_type
+ADD VALUE IF NOT EXISTS 'token';
+
+-- Scopes restrict an API key to a subset of its user's permissions.
+ALTER TABLE api_keys
+ADD COLUMN scopes text[] NOT NULL DEFAULT '{all}';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_api_keys_user_id_token_name;
+
+ALTER TABLE api_keys
+DROP COLUMN token_name;
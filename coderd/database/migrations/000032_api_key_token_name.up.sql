@@ -0,0 +1,5 @@
+-- Tokens are named so they can be told apart when listing or removing them.
+ALTER TABLE api_keys
+ADD COLUMN token_name text NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_api_keys_user_id_token_name ON api_keys USING btree (user_id, token_name) WHERE (token_name != '');
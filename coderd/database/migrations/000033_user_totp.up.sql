@@ -0,0 +1,27 @@
+-- TOTP second factors for users logging in with a password.
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    secret text NOT NULL,
+    -- Enrollment is pending until the user verifies a code.
+    enabled boolean NOT NULL DEFAULT false,
+    -- The last time step a code was accepted for. Codes for it or earlier
+    -- steps are rejected, so they can't be replayed.
+    last_used_step bigint NOT NULL DEFAULT 0,
+    -- Logging in with a password issues a challenge that's exchanged for a
+    -- session with a code. Only the hash of the challenge is stored.
+    login_challenge bytea,
+    login_challenge_expires_at timestamp with time zone NOT NULL DEFAULT '0001-01-01 00:00:00+00:00',
+    login_challenge_attempts integer NOT NULL DEFAULT 0,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_totp_login_challenge ON user_totp USING btree (login_challenge);
+
+-- Recovery codes are used once in place of a TOTP code.
+CREATE TABLE IF NOT EXISTS user_totp_recovery_codes (
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    hashed_code bytea NOT NULL,
+    PRIMARY KEY (user_id, hashed_code)
+);
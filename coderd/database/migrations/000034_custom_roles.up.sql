@@ -0,0 +1,20 @@
+-- Roles created by admins in addition to the built-in roles. Site roles have
+-- no organization.
+CREATE TABLE IF NOT EXISTS custom_roles (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    display_name text NOT NULL,
+    organization_id uuid REFERENCES organizations (id) ON DELETE CASCADE,
+    -- Permissions are JSON arrays of rbac.Permission. Site roles have site and
+    -- user permissions, organization roles only have org permissions.
+    site_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+    org_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+    user_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (id)
+);
+
+-- Site role names are unique, organization role names are unique in the
+-- organization.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_roles_name_organization_id ON custom_roles USING btree (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
@@ -0,0 +1,3 @@
+ALTER TABLE templates DROP COLUMN IF EXISTS group_acl;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,26 @@
+-- Groups of organization members. Roles and template access can be granted
+-- to a group instead of each member.
+CREATE TABLE IF NOT EXISTS groups (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    -- Organization roles granted to every member of the group.
+    roles text[] NOT NULL DEFAULT '{}',
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (name, organization_id)
+);
+
+CREATE TABLE IF NOT EXISTS group_members (
+    group_id uuid NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_members_user_id ON group_members USING btree (user_id);
+
+-- Maps the IDs of groups to the actions their members can perform on the
+-- template.
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS group_acl jsonb NOT NULL DEFAULT '{}'::jsonb;
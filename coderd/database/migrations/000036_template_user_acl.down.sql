@@ -0,0 +1,3 @@
+UPDATE templates SET group_acl = group_acl - organization_id::text;
+
+ALTER TABLE templates DROP COLUMN IF EXISTS user_acl;
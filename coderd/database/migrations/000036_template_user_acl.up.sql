@@ -0,0 +1,7 @@
+-- Maps the IDs of users to the actions they can perform on the template.
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS user_acl jsonb NOT NULL DEFAULT '{}'::jsonb;
+
+-- Templates used to be readable by every member of their organization. The
+-- organization ID is used as the ID of the group of all its members, so
+-- existing templates stay usable by everyone.
+UPDATE templates SET group_acl = group_acl || jsonb_build_object(organization_id::text, '["read"]'::jsonb);
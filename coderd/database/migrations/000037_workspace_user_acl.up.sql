@@ -0,0 +1,3 @@
+-- Maps the IDs of users the workspace is shared with to the actions they can
+-- perform on it.
+ALTER TABLE workspaces ADD COLUMN IF NOT EXISTS user_acl jsonb NOT NULL DEFAULT '{}'::jsonb;
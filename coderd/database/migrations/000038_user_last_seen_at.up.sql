@@ -0,0 +1,11 @@
+-- Users that were never seen have a zero last_seen_at.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_seen_at timestamp with time zone NOT NULL DEFAULT '0001-01-01 00:00:00+00:00'::timestamp with time zone;
+
+-- Users were last seen when they last used an API key.
+UPDATE users SET last_seen_at = api_keys.last_used FROM (
+	SELECT user_id, max(last_used) AS last_used FROM api_keys GROUP BY user_id
+) AS api_keys WHERE users.id = api_keys.user_id;
+
+-- Users without API keys are considered seen now, so existing users aren't
+-- suspended as dormant as soon as Coder is upgraded.
+UPDATE users SET last_seen_at = now() WHERE last_seen_at = '0001-01-01 00:00:00+00:00'::timestamp with time zone;
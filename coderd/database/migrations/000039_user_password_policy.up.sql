@@ -0,0 +1,19 @@
+-- Failed password logins, used to temporarily lock accounts that are
+-- targeted by password guessing.
+CREATE TABLE IF NOT EXISTS user_lockouts (
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- Failed logins since the last successful login or lockout.
+    failed_attempts integer NOT NULL DEFAULT 0,
+    locked_until timestamp with time zone NOT NULL DEFAULT '0001-01-01 00:00:00+00:00',
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id)
+);
+
+-- Previous password hashes, so passwords can't be reused.
+CREATE TABLE IF NOT EXISTS user_password_history (
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    hashed_password bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_password_history_user_id ON user_password_history USING btree (user_id, created_at);
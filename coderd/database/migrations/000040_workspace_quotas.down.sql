@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_quotas;
+DROP TABLE IF EXISTS organization_quotas;
+ALTER TABLE templates DROP COLUMN IF EXISTS cost_units;
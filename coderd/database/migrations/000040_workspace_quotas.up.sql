@@ -0,0 +1,23 @@
+-- The cost of running a workspace from the template, counted against the
+-- cost unit budgets of quotas.
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS cost_units integer NOT NULL DEFAULT 0;
+
+-- Quotas limit the workspaces of all users in an organization, or of a user
+-- across organizations. Limits of zero are unlimited.
+CREATE TABLE IF NOT EXISTS organization_quotas (
+    organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    max_workspaces integer NOT NULL DEFAULT 0,
+    max_running_workspaces integer NOT NULL DEFAULT 0,
+    max_cost_units integer NOT NULL DEFAULT 0,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (organization_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_quotas (
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    max_workspaces integer NOT NULL DEFAULT 0,
+    max_running_workspaces integer NOT NULL DEFAULT 0,
+    max_cost_units integer NOT NULL DEFAULT 0,
+    updated_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE workspaces DROP COLUMN IF EXISTS dormancy_opt_out;
+ALTER TABLE workspaces DROP COLUMN IF EXISTS dormant_at;
+ALTER TABLE templates DROP COLUMN IF EXISTS dormant_deletion_ttl;
+ALTER TABLE templates DROP COLUMN IF EXISTS inactivity_ttl;
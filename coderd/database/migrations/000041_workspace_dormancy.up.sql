@@ -0,0 +1,13 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+ALTER TYPE build_reason
+ADD VALUE IF NOT EXISTS 'autodelete';
+
+-- Stopped workspaces become dormant after the inactivity TTL of their template,
+-- and are deleted after being dormant for the deletion TTL. Zero disables
+-- either.
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS inactivity_ttl bigint NOT NULL DEFAULT 0;
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS dormant_deletion_ttl bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE workspaces ADD COLUMN IF NOT EXISTS dormant_at timestamp with time zone;
+ALTER TABLE workspaces ADD COLUMN IF NOT EXISTS dormancy_opt_out boolean NOT NULL DEFAULT false;
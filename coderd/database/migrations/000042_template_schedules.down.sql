@@ -0,0 +1,4 @@
+ALTER TABLE templates DROP COLUMN IF EXISTS allow_user_schedule_override;
+ALTER TABLE templates DROP COLUMN IF EXISTS required_stop_schedule;
+ALTER TABLE templates DROP COLUMN IF EXISTS default_ttl;
+ALTER TABLE templates DROP COLUMN IF EXISTS default_autostart_schedule;
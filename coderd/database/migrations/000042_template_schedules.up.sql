@@ -0,0 +1,8 @@
+-- Templates can set a default autostart schedule and TTL that's applied to new
+-- workspaces, and a weekly schedule at which running workspaces are always
+-- stopped. The required stop schedule has no timezone, it's evaluated in the
+-- timezone of the workspace owner.
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS default_autostart_schedule text NOT NULL DEFAULT '';
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS default_ttl bigint NOT NULL DEFAULT 0;
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS required_stop_schedule text NOT NULL DEFAULT '';
+ALTER TABLE templates ADD COLUMN IF NOT EXISTS allow_user_schedule_override boolean NOT NULL DEFAULT true;
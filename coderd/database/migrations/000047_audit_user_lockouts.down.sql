@@ -0,0 +1,9 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+
+-- Delete all audit logs that use the new enum value.
+DELETE FROM
+    audit_logs
+WHERE
+    resource_type = 'user_lockout'
+;
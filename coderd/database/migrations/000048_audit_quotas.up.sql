@@ -0,0 +1,7 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+ALTER TYPE resource_type
+ADD VALUE IF NOT EXISTS 'user_quota';
+
+ALTER TYPE resource_type
+ADD VALUE IF NOT EXISTS 'organization_quota';
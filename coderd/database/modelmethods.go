@@ -3,22 +3,33 @@ package database
 import "github.com/coder/coder/coderd/rbac"
 
 func (t Template) RBACObject() rbac.Object {
-	return rbac.ResourceTemplate.InOrg(t.OrganizationID).WithID(t.ID.String())
+	return rbac.ResourceTemplate.InOrg(t.OrganizationID).WithID(t.ID.String()).
+		WithGroupACL(t.GroupACL).
+		WithUserACL(t.UserACL)
 }
 
-func (t TemplateVersion) RBACObject() rbac.Object {
+// RBACObject returns the RBAC object of the template the version belongs to,
+// so versions share the access lists of their template. Versions without a
+// template must pass an empty template.
+func (t TemplateVersion) RBACObject(template Template) rbac.Object {
 	// Just use the parent template resource for controlling versions
-	return rbac.ResourceTemplate.InOrg(t.OrganizationID).WithID(t.TemplateID.UUID.String())
+	return rbac.ResourceTemplate.InOrg(t.OrganizationID).WithID(t.TemplateID.UUID.String()).
+		WithGroupACL(template.GroupACL).
+		WithUserACL(template.UserACL)
 }
 
 func (w Workspace) RBACObject() rbac.Object {
-	return rbac.ResourceWorkspace.InOrg(w.OrganizationID).WithID(w.ID.String()).WithOwner(w.OwnerID.String())
+	return rbac.ResourceWorkspace.InOrg(w.OrganizationID).WithID(w.ID.String()).WithOwner(w.OwnerID.String()).WithUserACL(w.UserACL)
 }
 
 func (m OrganizationMember) RBACObject() rbac.Object {
 	return rbac.ResourceOrganizationMember.InOrg(m.OrganizationID).WithID(m.UserID.String())
 }
 
+func (g Group) RBACObject() rbac.Object {
+	return rbac.ResourceGroup.InOrg(g.OrganizationID).WithID(g.ID.String())
+}
+
 func (o Organization) RBACObject() rbac.Object {
 	return rbac.ResourceOrganization.InOrg(o.ID).WithID(o.ID.String())
 }
@@ -38,9 +38,10 @@ func (e *AuditAction) Scan(src interface{}) error {
 type BuildReason string
 
 const (
-	BuildReasonInitiator BuildReason = "initiator"
-	BuildReasonAutostart BuildReason = "autostart"
-	BuildReasonAutostop  BuildReason = "autostop"
+	BuildReasonInitiator  BuildReason = "initiator"
+	BuildReasonAutostart  BuildReason = "autostart"
+	BuildReasonAutostop   BuildReason = "autostop"
+	BuildReasonAutodelete BuildReason = "autodelete"
 )
 
 func (e *BuildReason) Scan(src interface{}) error {
@@ -101,6 +102,8 @@ type LoginType string
 const (
 	LoginTypePassword LoginType = "password"
 	LoginTypeGithub   LoginType = "github"
+	LoginTypeOIDC     LoginType = "oidc"
+	LoginTypeToken    LoginType = "token"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -253,11 +256,20 @@ func (e *ProvisionerType) Scan(src interface{}) error {
 type ResourceType string
 
 const (
-	ResourceTypeOrganization    ResourceType = "organization"
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
+	ResourceTypeOrganization       ResourceType = "organization"
+	ResourceTypeTemplate           ResourceType = "template"
+	ResourceTypeTemplateVersion    ResourceType = "template_version"
+	ResourceTypeUser               ResourceType = "user"
+	ResourceTypeWorkspace          ResourceType = "workspace"
+	ResourceTypeOrganizationMember ResourceType = "organization_member"
+	ResourceTypeGitSSHKey          ResourceType = "git_ssh_key"
+	ResourceTypeAPIKey             ResourceType = "api_key"
+	ResourceTypeUserTOTP           ResourceType = "user_totp"
+	ResourceTypeCustomRole         ResourceType = "custom_role"
+	ResourceTypeGroup              ResourceType = "group"
+	ResourceTypeUserLockout        ResourceType = "user_lockout"
+	ResourceTypeUserQuota          ResourceType = "user_quota"
+	ResourceTypeOrganizationQuota  ResourceType = "organization_quota"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -326,6 +338,8 @@ type APIKey struct {
 	OAuthExpiry       time.Time   `db:"oauth_expiry" json:"oauth_expiry"`
 	LifetimeSeconds   int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
 	IPAddress         pqtype.Inet `db:"ip_address" json:"ip_address"`
+	Scopes            []string    `db:"scopes" json:"scopes"`
+	TokenName         string      `db:"token_name" json:"token_name"`
 }
 
 type AuditLog struct {
@@ -343,6 +357,18 @@ type AuditLog struct {
 	StatusCode     int32           `db:"status_code" json:"status_code"`
 }
 
+type CustomRole struct {
+	ID              uuid.UUID       `db:"id" json:"id"`
+	Name            string          `db:"name" json:"name"`
+	DisplayName     string          `db:"display_name" json:"display_name"`
+	OrganizationID  uuid.NullUUID   `db:"organization_id" json:"organization_id"`
+	SitePermissions json.RawMessage `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  json.RawMessage `db:"org_permissions" json:"org_permissions"`
+	UserPermissions json.RawMessage `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+}
+
 type File struct {
 	Hash      string    `db:"hash" json:"hash"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
@@ -359,6 +385,21 @@ type GitSSHKey struct {
 	PublicKey  string    `db:"public_key" json:"public_key"`
 }
 
+type Group struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Roles          []string  `db:"roles" json:"roles"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type GroupMember struct {
+	GroupID   uuid.UUID `db:"group_id" json:"group_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
 type License struct {
 	ID        int32           `db:"id" json:"id"`
 	License   json.RawMessage `db:"license" json:"license"`
@@ -381,6 +422,14 @@ type OrganizationMember struct {
 	Roles          []string  `db:"roles" json:"roles"`
 }
 
+type OrganizationQuota struct {
+	OrganizationID       uuid.UUID `db:"organization_id" json:"organization_id"`
+	MaxWorkspaces        int32     `db:"max_workspaces" json:"max_workspaces"`
+	MaxRunningWorkspaces int32     `db:"max_running_workspaces" json:"max_running_workspaces"`
+	MaxCostUnits         int32     `db:"max_cost_units" json:"max_cost_units"`
+	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type ParameterSchema struct {
 	ID                       uuid.UUID                  `db:"id" json:"id"`
 	CreatedAt                time.Time                  `db:"created_at" json:"created_at"`
@@ -454,18 +503,27 @@ type SiteConfig struct {
 }
 
 type Template struct {
-	ID                   uuid.UUID       `db:"id" json:"id"`
-	CreatedAt            time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt            time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID       uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Deleted              bool            `db:"deleted" json:"deleted"`
-	Name                 string          `db:"name" json:"name"`
-	Provisioner          ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID      uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description          string          `db:"description" json:"description"`
-	MaxTtl               int64           `db:"max_ttl" json:"max_ttl"`
-	MinAutostartInterval int64           `db:"min_autostart_interval" json:"min_autostart_interval"`
-	CreatedBy            uuid.UUID       `db:"created_by" json:"created_by"`
+	ID                        uuid.UUID       `db:"id" json:"id"`
+	CreatedAt                 time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt                 time.Time       `db:"updated_at" json:"updated_at"`
+	OrganizationID            uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Deleted                   bool            `db:"deleted" json:"deleted"`
+	Name                      string          `db:"name" json:"name"`
+	Provisioner               ProvisionerType `db:"provisioner" json:"provisioner"`
+	ActiveVersionID           uuid.UUID       `db:"active_version_id" json:"active_version_id"`
+	Description               string          `db:"description" json:"description"`
+	MaxTtl                    int64           `db:"max_ttl" json:"max_ttl"`
+	MinAutostartInterval      int64           `db:"min_autostart_interval" json:"min_autostart_interval"`
+	CreatedBy                 uuid.UUID       `db:"created_by" json:"created_by"`
+	GroupACL                  TemplateACL     `db:"group_acl" json:"group_acl"`
+	UserACL                   TemplateACL     `db:"user_acl" json:"user_acl"`
+	CostUnits                 int32           `db:"cost_units" json:"cost_units"`
+	InactivityTtl             int64           `db:"inactivity_ttl" json:"inactivity_ttl"`
+	DormantDeletionTtl        int64           `db:"dormant_deletion_ttl" json:"dormant_deletion_ttl"`
+	DefaultAutostartSchedule  string          `db:"default_autostart_schedule" json:"default_autostart_schedule"`
+	DefaultTtl                int64           `db:"default_ttl" json:"default_ttl"`
+	RequiredStopSchedule      string          `db:"required_stop_schedule" json:"required_stop_schedule"`
+	AllowUserScheduleOverride bool            `db:"allow_user_schedule_override" json:"allow_user_schedule_override"`
 }
 
 type TemplateVersion struct {
@@ -488,6 +546,45 @@ type User struct {
 	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
 	Status         UserStatus `db:"status" json:"status"`
 	RBACRoles      []string   `db:"rbac_roles" json:"rbac_roles"`
+	LastSeenAt     time.Time  `db:"last_seen_at" json:"last_seen_at"`
+}
+
+type UserLockout struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	FailedAttempts int32     `db:"failed_attempts" json:"failed_attempts"`
+	LockedUntil    time.Time `db:"locked_until" json:"locked_until"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type UserPasswordHistory struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+type UserQuota struct {
+	UserID               uuid.UUID `db:"user_id" json:"user_id"`
+	MaxWorkspaces        int32     `db:"max_workspaces" json:"max_workspaces"`
+	MaxRunningWorkspaces int32     `db:"max_running_workspaces" json:"max_running_workspaces"`
+	MaxCostUnits         int32     `db:"max_cost_units" json:"max_cost_units"`
+	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type UserTOTP struct {
+	UserID                  uuid.UUID `db:"user_id" json:"user_id"`
+	Secret                  string    `db:"secret" json:"secret"`
+	Enabled                 bool      `db:"enabled" json:"enabled"`
+	LastUsedStep            int64     `db:"last_used_step" json:"last_used_step"`
+	LoginChallenge          []byte    `db:"login_challenge" json:"login_challenge"`
+	LoginChallengeExpiresAt time.Time `db:"login_challenge_expires_at" json:"login_challenge_expires_at"`
+	LoginChallengeAttempts  int32     `db:"login_challenge_attempts" json:"login_challenge_attempts"`
+	CreatedAt               time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type UserTOTPRecoveryCode struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	HashedCode []byte    `db:"hashed_code" json:"hashed_code"`
 }
 
 type Workspace struct {
@@ -501,6 +598,9 @@ type Workspace struct {
 	Name              string         `db:"name" json:"name"`
 	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
 	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
+	UserACL           WorkspaceACL   `db:"user_acl" json:"user_acl"`
+	DormantAt         sql.NullTime   `db:"dormant_at" json:"dormant_at"`
+	DormancyOptOut    bool           `db:"dormancy_opt_out" json:"dormancy_opt_out"`
 }
 
 type WorkspaceAgent struct {
@@ -19,27 +19,78 @@ type querier interface {
 	// multiple provisioners from acquiring the same jobs. See:
 	// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
 	AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error)
+	// Blocks until the quota of a user or organization is locked for the rest of
+	// the transaction, so concurrent builds are counted toward it one at a time.
+	AcquireQuotaLock(ctx context.Context, id uuid.UUID) error
 	DeleteAPIKeyByID(ctx context.Context, id string) error
+	DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) error
+	DeleteCustomRole(ctx context.Context, id uuid.UUID) error
+	// Removes the workspaces of an organization that were already deleted, so the
+	// organization itself can be deleted.
+	DeleteDeletedWorkspacesByOrganizationID(ctx context.Context, organizationID uuid.UUID) error
+	// Removes the workspaces of a user that were already deleted, so the user
+	// itself can be deleted.
+	DeleteDeletedWorkspacesByOwnerID(ctx context.Context, ownerID uuid.UUID) error
 	DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error
+	DeleteGroupByID(ctx context.Context, id uuid.UUID) error
+	DeleteGroupMember(ctx context.Context, arg DeleteGroupMemberParams) error
+	// Removes a user from every group of an organization they are leaving.
+	DeleteGroupMembersByOrganizationAndUser(ctx context.Context, arg DeleteGroupMembersByOrganizationAndUserParams) error
+	DeleteOrganizationByID(ctx context.Context, id uuid.UUID) error
+	DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error
 	DeleteParameterValueByID(ctx context.Context, id uuid.UUID) error
+	// Removes a deleted custom role from the groups it was granted to.
+	DeleteRoleFromGroups(ctx context.Context, arg DeleteRoleFromGroupsParams) error
+	// Removes a deleted custom role from the members it was assigned to.
+	DeleteRoleFromOrganizationMembers(ctx context.Context, arg DeleteRoleFromOrganizationMembersParams) error
+	// Removes a deleted custom role from the users it was assigned to.
+	DeleteRoleFromUsers(ctx context.Context, roleName string) error
+	DeleteUserByID(ctx context.Context, id uuid.UUID) error
+	// Removes a deleted user from the access lists of templates.
+	DeleteUserFromTemplateACLs(ctx context.Context, userID string) error
+	// Removes a deleted user from the users workspaces are shared with.
+	DeleteUserFromWorkspaceACLs(ctx context.Context, userID string) error
+	DeleteUserLockoutByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteUserTOTPByUserID(ctx context.Context, userID uuid.UUID) error
+	// Recovery codes are deleted when they're used, so they can only be used once.
+	DeleteUserTOTPRecoveryCode(ctx context.Context, arg DeleteUserTOTPRecoveryCodeParams) (UserTOTPRecoveryCode, error)
+	DeleteUserTOTPRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error
 	GetAPIKeyByID(ctx context.Context, id string) (APIKey, error)
+	GetAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error)
 	GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error)
+	// GetAuditLogOrganizationIDs returns every organization ID referenced by an
+	// audit log, including organizations that no longer exist.
+	GetAuditLogOrganizationIDs(ctx context.Context) ([]uuid.UUID, error)
+	GetAuditLogs(ctx context.Context, arg GetAuditLogsParams) ([]AuditLog, error)
 	// GetAuditLogsBefore retrieves `limit` number of audit logs before the provided
 	// ID.
 	GetAuditLogsBefore(ctx context.Context, arg GetAuditLogsBeforeParams) ([]AuditLog, error)
+	// GetAuditLogsOlderThan returns the oldest audit logs in an organization
+	// created before the provided time, oldest first.
+	GetAuditLogsOlderThan(ctx context.Context, arg GetAuditLogsOlderThanParams) ([]AuditLog, error)
 	// This function returns roles for authorization purposes. Implied member roles
 	// are included.
 	GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error)
+	GetCustomRoleByName(ctx context.Context, arg GetCustomRoleByNameParams) (CustomRole, error)
+	// Site roles are returned if organization_id is NULL.
+	GetCustomRoles(ctx context.Context, organizationID uuid.NullUUID) ([]CustomRole, error)
 	GetDeploymentID(ctx context.Context) (string, error)
 	GetFileByHash(ctx context.Context, hash string) (File, error)
 	GetGitSSHKey(ctx context.Context, userID uuid.UUID) (GitSSHKey, error)
+	GetGroupByID(ctx context.Context, id uuid.UUID) (Group, error)
+	GetGroupByOrganizationAndName(ctx context.Context, arg GetGroupByOrganizationAndNameParams) (Group, error)
+	GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error)
+	GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error)
 	GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error)
 	GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error)
 	GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	GetOrganizationByName(ctx context.Context, name string) (Organization, error)
 	GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error)
 	GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error)
+	GetOrganizationMembersByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]OrganizationMember, error)
 	GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]OrganizationMember, error)
+	GetOrganizationQuota(ctx context.Context, organizationID uuid.UUID) (OrganizationQuota, error)
 	GetOrganizations(ctx context.Context) ([]Organization, error)
 	GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error)
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
@@ -51,6 +102,10 @@ type querier interface {
 	GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error)
 	GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error)
 	GetProvisionerLogsByIDBetween(ctx context.Context, arg GetProvisionerLogsByIDBetweenParams) ([]ProvisionerJobLog, error)
+	// Counts the workspaces of an owner or organization, and the workspaces and
+	// template cost units of those that are running. Workspaces are running when
+	// their latest build starts them and hasn't failed or been canceled.
+	GetQuotaUsage(ctx context.Context, arg GetQuotaUsageParams) (GetQuotaUsageRow, error)
 	GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error)
 	GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error)
 	GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error)
@@ -63,6 +118,13 @@ type querier interface {
 	GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserCount(ctx context.Context) (int64, error)
+	GetUserLockoutByUserID(ctx context.Context, userID uuid.UUID) (UserLockout, error)
+	// Returns the most recent previous passwords first.
+	GetUserPasswordHistory(ctx context.Context, arg GetUserPasswordHistoryParams) ([]UserPasswordHistory, error)
+	GetUserQuota(ctx context.Context, userID uuid.UUID) (UserQuota, error)
+	GetUserTOTPByLoginChallenge(ctx context.Context, loginChallenge []byte) (UserTOTP, error)
+	GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (UserTOTP, error)
+	GetUserTOTPRecoveryCodeCount(ctx context.Context, userID uuid.UUID) (int64, error)
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error)
 	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error)
 	GetWorkspaceAgentByAuthToken(ctx context.Context, authToken uuid.UUID) (WorkspaceAgent, error)
@@ -88,11 +150,19 @@ type querier interface {
 	GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error)
 	GetWorkspaces(ctx context.Context, arg GetWorkspacesParams) ([]Workspace, error)
 	GetWorkspacesAutostart(ctx context.Context) ([]Workspace, error)
+	// Returns workspaces that may become dormant or be deleted because their
+	// template has an inactivity TTL, or because they already are dormant.
+	GetWorkspacesEligibleForDormancy(ctx context.Context) ([]Workspace, error)
+	// Attempts start over when the last one failed before reset_before.
+	IncrementUserLockoutFailedAttempts(ctx context.Context, arg IncrementUserLockoutFailedAttemptsParams) (UserLockout, error)
 	InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error)
 	InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error)
+	InsertCustomRole(ctx context.Context, arg InsertCustomRoleParams) (CustomRole, error)
 	InsertDeploymentID(ctx context.Context, value string) error
 	InsertFile(ctx context.Context, arg InsertFileParams) (File, error)
 	InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error)
+	InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error)
+	InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error
 	InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error)
 	InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error)
 	InsertParameterSchema(ctx context.Context, arg InsertParameterSchemaParams) (ParameterSchema, error)
@@ -103,6 +173,8 @@ type querier interface {
 	InsertTemplate(ctx context.Context, arg InsertTemplateParams) (Template, error)
 	InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) (TemplateVersion, error)
 	InsertUser(ctx context.Context, arg InsertUserParams) (User, error)
+	InsertUserPasswordHistory(ctx context.Context, arg InsertUserPasswordHistoryParams) error
+	InsertUserTOTPRecoveryCode(ctx context.Context, arg InsertUserTOTPRecoveryCodeParams) error
 	InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error)
 	InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error)
 	InsertWorkspaceApp(ctx context.Context, arg InsertWorkspaceAppParams) (WorkspaceApp, error)
@@ -110,28 +182,53 @@ type querier interface {
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
 	ParameterValue(ctx context.Context, id uuid.UUID) (ParameterValue, error)
 	ParameterValues(ctx context.Context, arg ParameterValuesParams) ([]ParameterValue, error)
+	// Locks the quota of a user or organization for the rest of the transaction
+	// like AcquireQuotaLock, but returns false instead of blocking if another
+	// transaction holds the lock.
+	TryAcquireQuotaLock(ctx context.Context, id uuid.UUID) (bool, error)
 	UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDParams) error
+	UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleParams) (CustomRole, error)
 	UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) error
+	UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error)
+	UpdateGroupRoles(ctx context.Context, arg UpdateGroupRolesParams) (Group, error)
 	UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error)
+	UpdateOrganizationByID(ctx context.Context, arg UpdateOrganizationByIDParams) (Organization, error)
 	UpdateProvisionerDaemonByID(ctx context.Context, arg UpdateProvisionerDaemonByIDParams) error
 	UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error
 	UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error
 	UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error
+	UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error
 	UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error
 	UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error
 	UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error
 	UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error
 	UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error
+	// Attributes the templates of a user to another, so the user can be deleted.
+	UpdateTemplatesCreatedBy(ctx context.Context, arg UpdateTemplatesCreatedByParams) error
 	UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error
+	UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) error
+	UpdateUserLockout(ctx context.Context, arg UpdateUserLockoutParams) error
 	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
 	UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error)
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
+	UpdateUserTOTP(ctx context.Context, arg UpdateUserTOTPParams) error
+	UpdateUserTOTPLoginChallenge(ctx context.Context, arg UpdateUserTOTPLoginChallengeParams) error
+	UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg UpdateWorkspaceAgentAuthTokenByIDParams) error
 	UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error
 	UpdateWorkspaceAgentKeysByID(ctx context.Context, arg UpdateWorkspaceAgentKeysByIDParams) error
 	UpdateWorkspaceAutostart(ctx context.Context, arg UpdateWorkspaceAutostartParams) error
 	UpdateWorkspaceBuildByID(ctx context.Context, arg UpdateWorkspaceBuildByIDParams) error
 	UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateWorkspaceDeletedByIDParams) error
+	UpdateWorkspaceDormancyOptOut(ctx context.Context, arg UpdateWorkspaceDormancyOptOutParams) error
+	UpdateWorkspaceDormantAt(ctx context.Context, arg UpdateWorkspaceDormantAtParams) error
+	UpdateWorkspaceName(ctx context.Context, arg UpdateWorkspaceNameParams) error
+	UpdateWorkspaceOwner(ctx context.Context, arg UpdateWorkspaceOwnerParams) error
 	UpdateWorkspaceTTL(ctx context.Context, arg UpdateWorkspaceTTLParams) error
+	UpdateWorkspaceUserACL(ctx context.Context, arg UpdateWorkspaceUserACLParams) error
+	UpsertOrganizationQuota(ctx context.Context, arg UpsertOrganizationQuotaParams) (OrganizationQuota, error)
+	UpsertUserQuota(ctx context.Context, arg UpsertUserQuotaParams) (UserQuota, error)
+	// Enrolling replaces any pending or enabled second factor.
+	UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTOTP, error)
 }
 
 var _ querier = (*sqlQuerier)(nil)
@@ -29,9 +29,22 @@ func (q *sqlQuerier) DeleteAPIKeyByID(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteAPIKeysByUserID = `-- name: DeleteAPIKeysByUserID :exec
+DELETE
+FROM
+	api_keys
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteAPIKeysByUserID, userID)
+	return err
+}
+
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, oauth_access_token, oauth_refresh_token, oauth_id_token, oauth_expiry, lifetime_seconds, ip_address
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, oauth_access_token, oauth_refresh_token, oauth_id_token, oauth_expiry, lifetime_seconds, ip_address, scopes, token_name
 FROM
 	api_keys
 WHERE
@@ -58,12 +71,58 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.OAuthExpiry,
 		&i.LifetimeSeconds,
 		&i.IPAddress,
+		pq.Array(&i.Scopes),
+		&i.TokenName,
 	)
 	return i, err
 }
 
+const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, oauth_access_token, oauth_refresh_token, oauth_id_token, oauth_expiry, lifetime_seconds, ip_address, scopes, token_name FROM api_keys WHERE user_id = $1 ORDER BY created_at ASC
+`
+
+func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getAPIKeysByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.HashedSecret,
+			&i.UserID,
+			&i.LastUsed,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LoginType,
+			&i.OAuthAccessToken,
+			&i.OAuthRefreshToken,
+			&i.OAuthIDToken,
+			&i.OAuthExpiry,
+			&i.LifetimeSeconds,
+			&i.IPAddress,
+			pq.Array(&i.Scopes),
+			&i.TokenName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, oauth_access_token, oauth_refresh_token, oauth_id_token, oauth_expiry, lifetime_seconds, ip_address FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, oauth_access_token, oauth_refresh_token, oauth_id_token, oauth_expiry, lifetime_seconds, ip_address, scopes, token_name FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -90,6 +149,8 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.OAuthExpiry,
 			&i.LifetimeSeconds,
 			&i.IPAddress,
+			pq.Array(&i.Scopes),
+			&i.TokenName,
 		); err != nil {
 			return nil, err
 		}
@@ -120,7 +181,9 @@ INSERT INTO
 		oauth_access_token,
 		oauth_refresh_token,
 		oauth_id_token,
-		oauth_expiry
+		oauth_expiry,
+		scopes,
+		token_name
 	)
 VALUES
 	($1,
@@ -129,7 +192,9 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, oauth_access_token, oauth_refresh_token, oauth_id_token, oauth_expiry, lifetime_seconds, ip_address
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+	 -- Keys are unrestricted unless scopes are provided.
+	 COALESCE($15 :: text[], '{all}'), $16) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, oauth_access_token, oauth_refresh_token, oauth_id_token, oauth_expiry, lifetime_seconds, ip_address, scopes, token_name
 `
 
 type InsertAPIKeyParams struct {
@@ -147,6 +212,8 @@ type InsertAPIKeyParams struct {
 	OAuthRefreshToken string      `db:"oauth_refresh_token" json:"oauth_refresh_token"`
 	OAuthIDToken      string      `db:"oauth_id_token" json:"oauth_id_token"`
 	OAuthExpiry       time.Time   `db:"oauth_expiry" json:"oauth_expiry"`
+	Scopes            []string    `db:"scopes" json:"scopes"`
+	TokenName         string      `db:"token_name" json:"token_name"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -165,6 +232,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.OAuthRefreshToken,
 		arg.OAuthIDToken,
 		arg.OAuthExpiry,
+		pq.Array(arg.Scopes),
+		arg.TokenName,
 	)
 	var i APIKey
 	err := row.Scan(
@@ -182,6 +251,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.OAuthExpiry,
 		&i.LifetimeSeconds,
 		&i.IPAddress,
+		pq.Array(&i.Scopes),
+		&i.TokenName,
 	)
 	return i, err
 }
@@ -223,6 +294,185 @@ func (q *sqlQuerier) UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDP
 	return err
 }
 
+const deleteAuditLogsByIDs = `-- name: DeleteAuditLogsByIDs :exec
+DELETE FROM
+	audit_logs
+WHERE
+	id = ANY($1 :: uuid[])
+`
+
+func (q *sqlQuerier) DeleteAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteAuditLogsByIDs, pq.Array(ids))
+	return err
+}
+
+const getAuditLogOrganizationIDs = `-- name: GetAuditLogOrganizationIDs :many
+SELECT
+	DISTINCT organization_id
+FROM
+	audit_logs
+`
+
+// GetAuditLogOrganizationIDs returns every organization ID referenced by an
+// audit log, including organizations that no longer exist.
+func (q *sqlQuerier) GetAuditLogOrganizationIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogOrganizationIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var organization_id uuid.UUID
+		if err := rows.Scan(&organization_id); err != nil {
+			return nil, err
+		}
+		items = append(items, organization_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuditLogs = `-- name: GetAuditLogs :many
+SELECT
+	id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code
+FROM
+	audit_logs
+WHERE
+	CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- The query is ordered by time descending, so select all rows older
+		-- than the cursor.
+		WHEN $1 :: uuid != '00000000-00000000-00000000-00000000' THEN (
+			("time", id) < (
+				SELECT
+					"time", id
+				FROM
+					audit_logs
+				WHERE
+					id = $1
+			)
+		)
+		ELSE true
+	END
+	-- Start filters
+	-- Filter by the user that performed the action
+	AND CASE
+		WHEN $2 :: uuid != '00000000-00000000-00000000-00000000' THEN
+			user_id = $2
+		ELSE true
+	END
+	-- Filter by resource_type. $3 needs to be a text because it
+	-- can be empty.
+	AND CASE
+		WHEN $3 :: text != '' THEN
+			resource_type = $3 :: resource_type
+		ELSE true
+	END
+	-- Filter by resource_id
+	AND CASE
+		WHEN $4 :: uuid != '00000000-00000000-00000000-00000000' THEN
+			resource_id = $4
+		ELSE true
+	END
+	-- Filter by action
+	AND CASE
+		WHEN $5 :: text != '' THEN
+			action = $5 :: audit_action
+		ELSE true
+	END
+	-- Filter by status_code
+	AND CASE
+		WHEN $6 :: int != 0 THEN
+			status_code = $6
+		ELSE true
+	END
+	-- Filter by time range. The lower bound is inclusive and the upper bound
+	-- is exclusive.
+	AND CASE
+		WHEN $7 :: timestamptz != '0001-01-01 00:00:00Z' THEN
+			"time" >= $7
+		ELSE true
+	END
+	AND CASE
+		WHEN $8 :: timestamptz != '0001-01-01 00:00:00Z' THEN
+			"time" < $8
+		ELSE true
+	END
+	-- End of filters
+ORDER BY
+	-- Newest first, with the id as a tie breaker to keep pagination stable.
+	("time", id) DESC OFFSET $9
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($10 :: int, 0)
+`
+
+type GetAuditLogsParams struct {
+	AfterID      uuid.UUID `db:"after_id" json:"after_id"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	ResourceType string    `db:"resource_type" json:"resource_type"`
+	ResourceID   uuid.UUID `db:"resource_id" json:"resource_id"`
+	Action       string    `db:"action" json:"action"`
+	StatusCode   int32     `db:"status_code" json:"status_code"`
+	TimeFrom     time.Time `db:"time_from" json:"time_from"`
+	TimeTo       time.Time `db:"time_to" json:"time_to"`
+	OffsetOpt    int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt     int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetAuditLogs(ctx context.Context, arg GetAuditLogsParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogs,
+		arg.AfterID,
+		arg.UserID,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Action,
+		arg.StatusCode,
+		arg.TimeFrom,
+		arg.TimeTo,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Time,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ResourceTarget,
+			&i.Action,
+			&i.Diff,
+			&i.StatusCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAuditLogsBefore = `-- name: GetAuditLogsBefore :many
 SELECT
 	id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code
@@ -280,6 +530,64 @@ func (q *sqlQuerier) GetAuditLogsBefore(ctx context.Context, arg GetAuditLogsBef
 	return items, nil
 }
 
+const getAuditLogsOlderThan = `-- name: GetAuditLogsOlderThan :many
+SELECT
+	id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code
+FROM
+	audit_logs
+WHERE
+	organization_id = $1
+	AND "time" < $2
+ORDER BY
+	("time", id) ASC
+LIMIT
+	$3
+`
+
+type GetAuditLogsOlderThanParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	TimeBefore     time.Time `db:"time_before" json:"time_before"`
+	RowLimit       int32     `db:"row_limit" json:"row_limit"`
+}
+
+// GetAuditLogsOlderThan returns the oldest audit logs in an organization
+// created before the provided time, oldest first.
+func (q *sqlQuerier) GetAuditLogsOlderThan(ctx context.Context, arg GetAuditLogsOlderThanParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogsOlderThan, arg.OrganizationID, arg.TimeBefore, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Time,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ResourceTarget,
+			&i.Action,
+			&i.Diff,
+			&i.StatusCode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertAuditLog = `-- name: InsertAuditLog :one
 INSERT INTO
 	audit_logs (
@@ -348,39 +656,231 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 	return i, err
 }
 
-const getFileByHash = `-- name: GetFileByHash :one
+const deleteCustomRole = `-- name: DeleteCustomRole :exec
+DELETE FROM
+	custom_roles
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteCustomRole(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteCustomRole, id)
+	return err
+}
+
+const getCustomRoleByName = `-- name: GetCustomRoleByName :one
 SELECT
-	hash, created_at, created_by, mimetype, data
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
 FROM
-	files
+	custom_roles
 WHERE
-	hash = $1
-LIMIT
-	1
+	name = $1
+	AND organization_id IS NOT DISTINCT FROM $2
 `
 
-func (q *sqlQuerier) GetFileByHash(ctx context.Context, hash string) (File, error) {
-	row := q.db.QueryRowContext(ctx, getFileByHash, hash)
-	var i File
+type GetCustomRoleByNameParams struct {
+	Name           string        `db:"name" json:"name"`
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) GetCustomRoleByName(ctx context.Context, arg GetCustomRoleByNameParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, getCustomRoleByName, arg.Name, arg.OrganizationID)
+	var i CustomRole
 	err := row.Scan(
-		&i.Hash,
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
 		&i.CreatedAt,
-		&i.CreatedBy,
-		&i.Mimetype,
-		&i.Data,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const insertFile = `-- name: InsertFile :one
-INSERT INTO
-	files (hash, created_at, created_by, mimetype, "data")
-VALUES
-	($1, $2, $3, $4, $5) RETURNING hash, created_at, created_by, mimetype, data
+const getCustomRoles = `-- name: GetCustomRoles :many
+SELECT
+	id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+FROM
+	custom_roles
+WHERE
+	organization_id IS NOT DISTINCT FROM $1
+ORDER BY
+	name
 `
 
-type InsertFileParams struct {
-	Hash      string    `db:"hash" json:"hash"`
+// Site roles are returned if organization_id is NULL.
+func (q *sqlQuerier) GetCustomRoles(ctx context.Context, organizationID uuid.NullUUID) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, getCustomRoles, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CustomRole
+	for rows.Next() {
+		var i CustomRole
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.OrganizationID,
+			&i.SitePermissions,
+			&i.OrgPermissions,
+			&i.UserPermissions,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertCustomRole = `-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+`
+
+type InsertCustomRoleParams struct {
+	ID              uuid.UUID       `db:"id" json:"id"`
+	Name            string          `db:"name" json:"name"`
+	DisplayName     string          `db:"display_name" json:"display_name"`
+	OrganizationID  uuid.NullUUID   `db:"organization_id" json:"organization_id"`
+	SitePermissions json.RawMessage `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  json.RawMessage `db:"org_permissions" json:"org_permissions"`
+	UserPermissions json.RawMessage `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertCustomRole(ctx context.Context, arg InsertCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, insertCustomRole,
+		arg.ID,
+		arg.Name,
+		arg.DisplayName,
+		arg.OrganizationID,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateCustomRole = `-- name: UpdateCustomRole :one
+UPDATE
+	custom_roles
+SET
+	display_name = $2,
+	site_permissions = $3,
+	org_permissions = $4,
+	user_permissions = $5,
+	updated_at = $6
+WHERE
+	id = $1
+RETURNING id, name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+`
+
+type UpdateCustomRoleParams struct {
+	ID              uuid.UUID       `db:"id" json:"id"`
+	DisplayName     string          `db:"display_name" json:"display_name"`
+	SitePermissions json.RawMessage `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  json.RawMessage `db:"org_permissions" json:"org_permissions"`
+	UserPermissions json.RawMessage `db:"user_permissions" json:"user_permissions"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, updateCustomRole,
+		arg.ID,
+		arg.DisplayName,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
+		arg.UpdatedAt,
+	)
+	var i CustomRole
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFileByHash = `-- name: GetFileByHash :one
+SELECT
+	hash, created_at, created_by, mimetype, data
+FROM
+	files
+WHERE
+	hash = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetFileByHash(ctx context.Context, hash string) (File, error) {
+	row := q.db.QueryRowContext(ctx, getFileByHash, hash)
+	var i File
+	err := row.Scan(
+		&i.Hash,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.Mimetype,
+		&i.Data,
+	)
+	return i, err
+}
+
+const insertFile = `-- name: InsertFile :one
+INSERT INTO
+	files (hash, created_at, created_by, mimetype, "data")
+VALUES
+	($1, $2, $3, $4, $5) RETURNING hash, created_at, created_by, mimetype, data
+`
+
+type InsertFileParams struct {
+	Hash      string    `db:"hash" json:"hash"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
 	Mimetype  string    `db:"mimetype" json:"mimetype"`
@@ -508,6 +1008,390 @@ func (q *sqlQuerier) UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyPar
 	return err
 }
 
+const deleteGroupByID = `-- name: DeleteGroupByID :exec
+DELETE FROM
+	groups
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupByID, id)
+	return err
+}
+
+const deleteGroupMember = `-- name: DeleteGroupMember :exec
+DELETE FROM
+	group_members
+WHERE
+	group_id = $1
+	AND user_id = $2
+`
+
+type DeleteGroupMemberParams struct {
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteGroupMember(ctx context.Context, arg DeleteGroupMemberParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupMember, arg.GroupID, arg.UserID)
+	return err
+}
+
+const deleteGroupMembersByOrganizationAndUser = `-- name: DeleteGroupMembersByOrganizationAndUser :exec
+DELETE FROM
+	group_members
+WHERE
+	user_id = $1
+	AND group_id = ANY(
+		SELECT
+			id
+		FROM
+			groups
+		WHERE
+			organization_id = $2
+	)
+`
+
+type DeleteGroupMembersByOrganizationAndUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Removes a user from every group of an organization they are leaving.
+func (q *sqlQuerier) DeleteGroupMembersByOrganizationAndUser(ctx context.Context, arg DeleteGroupMembersByOrganizationAndUserParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupMembersByOrganizationAndUser, arg.UserID, arg.OrganizationID)
+	return err
+}
+
+const deleteRoleFromGroups = `-- name: DeleteRoleFromGroups :exec
+UPDATE
+	groups
+SET
+	roles = array_remove(roles, $1 :: text)
+WHERE
+	organization_id = $2
+`
+
+type DeleteRoleFromGroupsParams struct {
+	RoleName       string    `db:"role_name" json:"role_name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Removes a deleted custom role from the groups it was granted to.
+func (q *sqlQuerier) DeleteRoleFromGroups(ctx context.Context, arg DeleteRoleFromGroupsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteRoleFromGroups, arg.RoleName, arg.OrganizationID)
+	return err
+}
+
+const getGroupByID = `-- name: GetGroupByID :one
+SELECT
+	id, name, organization_id, roles, created_at, updated_at
+FROM
+	groups
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (Group, error) {
+	row := q.db.QueryRowContext(ctx, getGroupByID, id)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		pq.Array(&i.Roles),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getGroupByOrganizationAndName = `-- name: GetGroupByOrganizationAndName :one
+SELECT
+	id, name, organization_id, roles, created_at, updated_at
+FROM
+	groups
+WHERE
+	organization_id = $1
+	AND name = $2
+LIMIT
+	1
+`
+
+type GetGroupByOrganizationAndNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetGroupByOrganizationAndName(ctx context.Context, arg GetGroupByOrganizationAndNameParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, getGroupByOrganizationAndName, arg.OrganizationID, arg.Name)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		pq.Array(&i.Roles),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getGroupMembers = `-- name: GetGroupMembers :many
+SELECT
+	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.last_seen_at
+FROM
+	users
+JOIN
+	group_members
+ON
+	group_members.user_id = users.id
+WHERE
+	group_members.group_id = $1
+ORDER BY
+	users.username
+`
+
+func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupMembers, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			pq.Array(&i.RBACRoles),
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGroupsByOrganizationID = `-- name: GetGroupsByOrganizationID :many
+SELECT
+	id, name, organization_id, roles, created_at, updated_at
+FROM
+	groups
+WHERE
+	organization_id = $1
+ORDER BY
+	name
+`
+
+func (q *sqlQuerier) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupsByOrganizationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Group
+	for rows.Next() {
+		var i Group
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OrganizationID,
+			pq.Array(&i.Roles),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertGroup = `-- name: InsertGroup :one
+INSERT INTO
+	groups (
+		id,
+		name,
+		organization_id,
+		roles,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, name, organization_id, roles, created_at, updated_at
+`
+
+type InsertGroupParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Roles          []string  `db:"roles" json:"roles"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, insertGroup,
+		arg.ID,
+		arg.Name,
+		arg.OrganizationID,
+		pq.Array(arg.Roles),
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		pq.Array(&i.Roles),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertGroupMember = `-- name: InsertGroupMember :exec
+INSERT INTO
+	group_members (group_id, user_id, created_at)
+VALUES
+	($1, $2, $3) ON CONFLICT DO NOTHING
+`
+
+type InsertGroupMemberParams struct {
+	GroupID   uuid.UUID `db:"group_id" json:"group_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error {
+	_, err := q.db.ExecContext(ctx, insertGroupMember, arg.GroupID, arg.UserID, arg.CreatedAt)
+	return err
+}
+
+const updateGroupByID = `-- name: UpdateGroupByID :one
+UPDATE
+	groups
+SET
+	name = $2,
+	updated_at = $3
+WHERE
+	id = $1
+RETURNING id, name, organization_id, roles, created_at, updated_at
+`
+
+type UpdateGroupByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, updateGroupByID, arg.ID, arg.Name, arg.UpdatedAt)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		pq.Array(&i.Roles),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateGroupRoles = `-- name: UpdateGroupRoles :one
+UPDATE
+	groups
+SET
+	-- Remove all duplicates from the roles.
+	roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[])),
+	updated_at = $2
+WHERE
+	id = $3
+RETURNING id, name, organization_id, roles, created_at, updated_at
+`
+
+type UpdateGroupRolesParams struct {
+	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	ID           uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateGroupRoles(ctx context.Context, arg UpdateGroupRolesParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, updateGroupRoles, pq.Array(arg.GrantedRoles), arg.UpdatedAt, arg.ID)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		pq.Array(&i.Roles),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteOrganizationMember = `-- name: DeleteOrganizationMember :exec
+DELETE FROM
+	organization_members
+WHERE
+	organization_id = $1
+	AND user_id = $2
+`
+
+type DeleteOrganizationMemberParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOrganizationMember, arg.OrganizationID, arg.UserID)
+	return err
+}
+
+const deleteRoleFromOrganizationMembers = `-- name: DeleteRoleFromOrganizationMembers :exec
+UPDATE
+	organization_members
+SET
+	roles = array_remove(roles, $1 :: text)
+WHERE
+	organization_id = $2
+`
+
+type DeleteRoleFromOrganizationMembersParams struct {
+	RoleName       string    `db:"role_name" json:"role_name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+// Removes a deleted custom role from the members it was assigned to.
+func (q *sqlQuerier) DeleteRoleFromOrganizationMembers(ctx context.Context, arg DeleteRoleFromOrganizationMembersParams) error {
+	_, err := q.db.ExecContext(ctx, deleteRoleFromOrganizationMembers, arg.RoleName, arg.OrganizationID)
+	return err
+}
+
 const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
 SELECT
     user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
@@ -577,6 +1461,46 @@ func (q *sqlQuerier) GetOrganizationMemberByUserID(ctx context.Context, arg GetO
 	return i, err
 }
 
+const getOrganizationMembersByOrganizationID = `-- name: GetOrganizationMembersByOrganizationID :many
+SELECT
+	user_id, organization_id, created_at, updated_at, roles
+FROM
+	organization_members
+WHERE
+	organization_id = $1
+ORDER BY
+	created_at
+`
+
+func (q *sqlQuerier) GetOrganizationMembersByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]OrganizationMember, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationMembersByOrganizationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrganizationMember
+	for rows.Next() {
+		var i OrganizationMember
+		if err := rows.Scan(
+			&i.UserID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			pq.Array(&i.Roles),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getOrganizationMembershipsByUserID = `-- name: GetOrganizationMembershipsByUserID :many
 SELECT
 	user_id, organization_id, created_at, updated_at, roles
@@ -686,6 +1610,18 @@ func (q *sqlQuerier) UpdateMemberRoles(ctx context.Context, arg UpdateMemberRole
 	return i, err
 }
 
+const deleteOrganizationByID = `-- name: DeleteOrganizationByID :exec
+DELETE FROM
+	organizations
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteOrganizationByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOrganizationByID, id)
+	return err
+}
+
 const getOrganizationByID = `-- name: GetOrganizationByID :one
 SELECT
 	id, name, description, created_at, updated_at
@@ -774,7 +1710,7 @@ SELECT
 FROM
 	organizations
 WHERE
-	id = (
+	id = ANY(
 		SELECT
 			organization_id
 		FROM
@@ -847,6 +1783,36 @@ func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizat
 	return i, err
 }
 
+const updateOrganizationByID = `-- name: UpdateOrganizationByID :one
+UPDATE
+	organizations
+SET
+	"name" = $2,
+	updated_at = $3
+WHERE
+	id = $1
+RETURNING id, name, description, created_at, updated_at
+`
+
+type UpdateOrganizationByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateOrganizationByID(ctx context.Context, arg UpdateOrganizationByIDParams) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, updateOrganizationByID, arg.ID, arg.Name, arg.UpdatedAt)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getParameterSchemasByJobID = `-- name: GetParameterSchemasByJobID :many
 SELECT
 	id, created_at, job_id, name, description, default_source_scheme, default_source_value, allow_override_source, default_destination_scheme, allow_override_destination, default_refresh, redisplay_value, validation_error, validation_condition, validation_type_system, validation_value_type
@@ -1788,6 +2754,222 @@ func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, a
 	return err
 }
 
+const acquireQuotaLock = `-- name: AcquireQuotaLock :exec
+SELECT pg_advisory_xact_lock(hashtext($1 :: uuid :: text))
+`
+
+// Blocks until the quota of a user or organization is locked for the rest of
+// the transaction, so concurrent builds are counted toward it one at a time.
+func (q *sqlQuerier) AcquireQuotaLock(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, acquireQuotaLock, id)
+	return err
+}
+
+const getOrganizationQuota = `-- name: GetOrganizationQuota :one
+SELECT
+	organization_id, max_workspaces, max_running_workspaces, max_cost_units, updated_at
+FROM
+	organization_quotas
+WHERE
+	organization_id = $1
+`
+
+func (q *sqlQuerier) GetOrganizationQuota(ctx context.Context, organizationID uuid.UUID) (OrganizationQuota, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationQuota, organizationID)
+	var i OrganizationQuota
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.MaxWorkspaces,
+		&i.MaxRunningWorkspaces,
+		&i.MaxCostUnits,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getQuotaUsage = `-- name: GetQuotaUsage :one
+SELECT
+	COUNT(*) AS workspaces,
+	COUNT(*) FILTER (WHERE running) AS running_workspaces,
+	COALESCE(SUM(cost_units) FILTER (WHERE running), 0) :: bigint AS cost_units
+FROM (
+	SELECT
+		templates.cost_units,
+		COALESCE(latest_builds.running, false) AS running
+	FROM
+		workspaces
+	JOIN
+		templates ON templates.id = workspaces.template_id
+	LEFT JOIN LATERAL (
+		SELECT
+			workspace_builds.transition = 'start'
+			AND provisioner_jobs.error IS NULL
+			AND provisioner_jobs.canceled_at IS NULL AS running
+		FROM
+			workspace_builds
+		JOIN
+			provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+		ORDER BY
+			workspace_builds.build_number DESC
+		LIMIT
+			1
+	) latest_builds ON true
+	WHERE
+		workspaces.deleted = false
+		-- Filter by owner_id
+		AND CASE
+			WHEN $1 :: uuid != '00000000-00000000-00000000-00000000' THEN
+				workspaces.owner_id = $1
+			ELSE true
+		END
+		-- Filter by organization_id
+		AND CASE
+			WHEN $2 :: uuid != '00000000-00000000-00000000-00000000' THEN
+				workspaces.organization_id = $2
+			ELSE true
+		END
+) AS quota_workspaces
+`
+
+type GetQuotaUsageParams struct {
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+type GetQuotaUsageRow struct {
+	Workspaces        int64 `db:"workspaces" json:"workspaces"`
+	RunningWorkspaces int64 `db:"running_workspaces" json:"running_workspaces"`
+	CostUnits         int64 `db:"cost_units" json:"cost_units"`
+}
+
+// Counts the workspaces of an owner or organization, and the workspaces and
+// template cost units of those that are running. Workspaces are running when
+// their latest build starts them and hasn't failed or been canceled.
+func (q *sqlQuerier) GetQuotaUsage(ctx context.Context, arg GetQuotaUsageParams) (GetQuotaUsageRow, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaUsage, arg.OwnerID, arg.OrganizationID)
+	var i GetQuotaUsageRow
+	err := row.Scan(&i.Workspaces, &i.RunningWorkspaces, &i.CostUnits)
+	return i, err
+}
+
+const getUserQuota = `-- name: GetUserQuota :one
+SELECT
+	user_id, max_workspaces, max_running_workspaces, max_cost_units, updated_at
+FROM
+	user_quotas
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserQuota(ctx context.Context, userID uuid.UUID) (UserQuota, error) {
+	row := q.db.QueryRowContext(ctx, getUserQuota, userID)
+	var i UserQuota
+	err := row.Scan(
+		&i.UserID,
+		&i.MaxWorkspaces,
+		&i.MaxRunningWorkspaces,
+		&i.MaxCostUnits,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const tryAcquireQuotaLock = `-- name: TryAcquireQuotaLock :one
+SELECT pg_try_advisory_xact_lock(hashtext($1 :: uuid :: text))
+`
+
+// Locks the quota of a user or organization for the rest of the transaction
+// like AcquireQuotaLock, but returns false instead of blocking if another
+// transaction holds the lock.
+func (q *sqlQuerier) TryAcquireQuotaLock(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, tryAcquireQuotaLock, id)
+	var pg_try_advisory_xact_lock bool
+	err := row.Scan(&pg_try_advisory_xact_lock)
+	return pg_try_advisory_xact_lock, err
+}
+
+const upsertOrganizationQuota = `-- name: UpsertOrganizationQuota :one
+INSERT INTO
+	organization_quotas (organization_id, max_workspaces, max_running_workspaces, max_cost_units, updated_at)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (organization_id) DO UPDATE SET
+	max_workspaces = $2,
+	max_running_workspaces = $3,
+	max_cost_units = $4,
+	updated_at = $5
+RETURNING organization_id, max_workspaces, max_running_workspaces, max_cost_units, updated_at
+`
+
+type UpsertOrganizationQuotaParams struct {
+	OrganizationID       uuid.UUID `db:"organization_id" json:"organization_id"`
+	MaxWorkspaces        int32     `db:"max_workspaces" json:"max_workspaces"`
+	MaxRunningWorkspaces int32     `db:"max_running_workspaces" json:"max_running_workspaces"`
+	MaxCostUnits         int32     `db:"max_cost_units" json:"max_cost_units"`
+	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertOrganizationQuota(ctx context.Context, arg UpsertOrganizationQuotaParams) (OrganizationQuota, error) {
+	row := q.db.QueryRowContext(ctx, upsertOrganizationQuota,
+		arg.OrganizationID,
+		arg.MaxWorkspaces,
+		arg.MaxRunningWorkspaces,
+		arg.MaxCostUnits,
+		arg.UpdatedAt,
+	)
+	var i OrganizationQuota
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.MaxWorkspaces,
+		&i.MaxRunningWorkspaces,
+		&i.MaxCostUnits,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUserQuota = `-- name: UpsertUserQuota :one
+INSERT INTO
+	user_quotas (user_id, max_workspaces, max_running_workspaces, max_cost_units, updated_at)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE SET
+	max_workspaces = $2,
+	max_running_workspaces = $3,
+	max_cost_units = $4,
+	updated_at = $5
+RETURNING user_id, max_workspaces, max_running_workspaces, max_cost_units, updated_at
+`
+
+type UpsertUserQuotaParams struct {
+	UserID               uuid.UUID `db:"user_id" json:"user_id"`
+	MaxWorkspaces        int32     `db:"max_workspaces" json:"max_workspaces"`
+	MaxRunningWorkspaces int32     `db:"max_running_workspaces" json:"max_running_workspaces"`
+	MaxCostUnits         int32     `db:"max_cost_units" json:"max_cost_units"`
+	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertUserQuota(ctx context.Context, arg UpsertUserQuotaParams) (UserQuota, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserQuota,
+		arg.UserID,
+		arg.MaxWorkspaces,
+		arg.MaxRunningWorkspaces,
+		arg.MaxCostUnits,
+		arg.UpdatedAt,
+	)
+	var i UserQuota
+	err := row.Scan(
+		&i.UserID,
+		&i.MaxWorkspaces,
+		&i.MaxRunningWorkspaces,
+		&i.MaxCostUnits,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getDeploymentID = `-- name: GetDeploymentID :one
 SELECT value FROM site_configs WHERE key = 'deployment_id'
 `
@@ -1808,9 +2990,24 @@ func (q *sqlQuerier) InsertDeploymentID(ctx context.Context, value string) error
 	return err
 }
 
+const deleteUserFromTemplateACLs = `-- name: DeleteUserFromTemplateACLs :exec
+UPDATE
+	templates
+SET
+	user_acl = user_acl - $1 :: text
+WHERE
+	user_acl ? $1 :: text
+`
+
+// Removes a deleted user from the access lists of templates.
+func (q *sqlQuerier) DeleteUserFromTemplateACLs(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteUserFromTemplateACLs, userID)
+	return err
+}
+
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, group_acl, user_acl, cost_units, inactivity_ttl, dormant_deletion_ttl, default_autostart_schedule, default_ttl, required_stop_schedule, allow_user_schedule_override
 FROM
 	templates
 WHERE
@@ -1835,13 +3032,22 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.MaxTtl,
 		&i.MinAutostartInterval,
 		&i.CreatedBy,
+		&i.GroupACL,
+		&i.UserACL,
+		&i.CostUnits,
+		&i.InactivityTtl,
+		&i.DormantDeletionTtl,
+		&i.DefaultAutostartSchedule,
+		&i.DefaultTtl,
+		&i.RequiredStopSchedule,
+		&i.AllowUserScheduleOverride,
 	)
 	return i, err
 }
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, group_acl, user_acl, cost_units, inactivity_ttl, dormant_deletion_ttl, default_autostart_schedule, default_ttl, required_stop_schedule, allow_user_schedule_override
 FROM
 	templates
 WHERE
@@ -1874,12 +3080,21 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.MaxTtl,
 		&i.MinAutostartInterval,
 		&i.CreatedBy,
+		&i.GroupACL,
+		&i.UserACL,
+		&i.CostUnits,
+		&i.InactivityTtl,
+		&i.DormantDeletionTtl,
+		&i.DefaultAutostartSchedule,
+		&i.DefaultTtl,
+		&i.RequiredStopSchedule,
+		&i.AllowUserScheduleOverride,
 	)
 	return i, err
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by FROM templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, group_acl, user_acl, cost_units, inactivity_ttl, dormant_deletion_ttl, default_autostart_schedule, default_ttl, required_stop_schedule, allow_user_schedule_override FROM templates
 `
 
 func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
@@ -1904,6 +3119,15 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.MaxTtl,
 			&i.MinAutostartInterval,
 			&i.CreatedBy,
+			&i.GroupACL,
+			&i.UserACL,
+			&i.CostUnits,
+			&i.InactivityTtl,
+			&i.DormantDeletionTtl,
+			&i.DefaultAutostartSchedule,
+			&i.DefaultTtl,
+			&i.RequiredStopSchedule,
+			&i.AllowUserScheduleOverride,
 		); err != nil {
 			return nil, err
 		}
@@ -1920,7 +3144,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, group_acl, user_acl, cost_units, inactivity_ttl, dormant_deletion_ttl, default_autostart_schedule, default_ttl, required_stop_schedule, allow_user_schedule_override
 FROM
 	templates
 WHERE
@@ -1980,6 +3204,15 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.MaxTtl,
 			&i.MinAutostartInterval,
 			&i.CreatedBy,
+			&i.GroupACL,
+			&i.UserACL,
+			&i.CostUnits,
+			&i.InactivityTtl,
+			&i.DormantDeletionTtl,
+			&i.DefaultAutostartSchedule,
+			&i.DefaultTtl,
+			&i.RequiredStopSchedule,
+			&i.AllowUserScheduleOverride,
 		); err != nil {
 			return nil, err
 		}
@@ -2007,24 +3240,42 @@ INSERT INTO
 		description,
 		max_ttl,
 		min_autostart_interval,
-		created_by
+		created_by,
+		group_acl,
+		user_acl,
+		cost_units,
+		inactivity_ttl,
+		dormant_deletion_ttl,
+		default_autostart_schedule,
+		default_ttl,
+		required_stop_schedule,
+		allow_user_schedule_override
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, group_acl, user_acl, cost_units, inactivity_ttl, dormant_deletion_ttl, default_autostart_schedule, default_ttl, required_stop_schedule, allow_user_schedule_override
 `
 
 type InsertTemplateParams struct {
-	ID                   uuid.UUID       `db:"id" json:"id"`
-	CreatedAt            time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt            time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID       uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Name                 string          `db:"name" json:"name"`
-	Provisioner          ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID      uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description          string          `db:"description" json:"description"`
-	MaxTtl               int64           `db:"max_ttl" json:"max_ttl"`
-	MinAutostartInterval int64           `db:"min_autostart_interval" json:"min_autostart_interval"`
-	CreatedBy            uuid.UUID       `db:"created_by" json:"created_by"`
+	ID                        uuid.UUID       `db:"id" json:"id"`
+	CreatedAt                 time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt                 time.Time       `db:"updated_at" json:"updated_at"`
+	OrganizationID            uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Name                      string          `db:"name" json:"name"`
+	Provisioner               ProvisionerType `db:"provisioner" json:"provisioner"`
+	ActiveVersionID           uuid.UUID       `db:"active_version_id" json:"active_version_id"`
+	Description               string          `db:"description" json:"description"`
+	MaxTtl                    int64           `db:"max_ttl" json:"max_ttl"`
+	MinAutostartInterval      int64           `db:"min_autostart_interval" json:"min_autostart_interval"`
+	CreatedBy                 uuid.UUID       `db:"created_by" json:"created_by"`
+	GroupACL                  TemplateACL     `db:"group_acl" json:"group_acl"`
+	UserACL                   TemplateACL     `db:"user_acl" json:"user_acl"`
+	CostUnits                 int32           `db:"cost_units" json:"cost_units"`
+	InactivityTtl             int64           `db:"inactivity_ttl" json:"inactivity_ttl"`
+	DormantDeletionTtl        int64           `db:"dormant_deletion_ttl" json:"dormant_deletion_ttl"`
+	DefaultAutostartSchedule  string          `db:"default_autostart_schedule" json:"default_autostart_schedule"`
+	DefaultTtl                int64           `db:"default_ttl" json:"default_ttl"`
+	RequiredStopSchedule      string          `db:"required_stop_schedule" json:"required_stop_schedule"`
+	AllowUserScheduleOverride bool            `db:"allow_user_schedule_override" json:"allow_user_schedule_override"`
 }
 
 func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) (Template, error) {
@@ -2040,6 +3291,15 @@ func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParam
 		arg.MaxTtl,
 		arg.MinAutostartInterval,
 		arg.CreatedBy,
+		arg.GroupACL,
+		arg.UserACL,
+		arg.CostUnits,
+		arg.InactivityTtl,
+		arg.DormantDeletionTtl,
+		arg.DefaultAutostartSchedule,
+		arg.DefaultTtl,
+		arg.RequiredStopSchedule,
+		arg.AllowUserScheduleOverride,
 	)
 	var i Template
 	err := row.Scan(
@@ -2055,10 +3315,40 @@ func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParam
 		&i.MaxTtl,
 		&i.MinAutostartInterval,
 		&i.CreatedBy,
+		&i.GroupACL,
+		&i.UserACL,
+		&i.CostUnits,
+		&i.InactivityTtl,
+		&i.DormantDeletionTtl,
+		&i.DefaultAutostartSchedule,
+		&i.DefaultTtl,
+		&i.RequiredStopSchedule,
+		&i.AllowUserScheduleOverride,
 	)
 	return i, err
 }
 
+const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :exec
+UPDATE
+	templates
+SET
+	group_acl = $2,
+	user_acl = $3
+WHERE
+	id = $1
+`
+
+type UpdateTemplateACLByIDParams struct {
+	ID       uuid.UUID   `db:"id" json:"id"`
+	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
+	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
+}
+
+func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateACLByID, arg.ID, arg.GroupACL, arg.UserACL)
+	return err
+}
+
 const updateTemplateActiveVersionByID = `-- name: UpdateTemplateActiveVersionByID :exec
 UPDATE
 	templates
@@ -2108,19 +3398,33 @@ SET
 	updated_at = $2,
 	description = $3,
 	max_ttl = $4,
-	min_autostart_interval = $5
+	min_autostart_interval = $5,
+	cost_units = $6,
+	inactivity_ttl = $7,
+	dormant_deletion_ttl = $8,
+	default_autostart_schedule = $9,
+	default_ttl = $10,
+	required_stop_schedule = $11,
+	allow_user_schedule_override = $12
 WHERE
 	id = $1
 RETURNING
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, max_ttl, min_autostart_interval, created_by, group_acl, user_acl, cost_units, inactivity_ttl, dormant_deletion_ttl, default_autostart_schedule, default_ttl, required_stop_schedule, allow_user_schedule_override
 `
 
 type UpdateTemplateMetaByIDParams struct {
-	ID                   uuid.UUID `db:"id" json:"id"`
-	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
-	Description          string    `db:"description" json:"description"`
-	MaxTtl               int64     `db:"max_ttl" json:"max_ttl"`
-	MinAutostartInterval int64     `db:"min_autostart_interval" json:"min_autostart_interval"`
+	ID                        uuid.UUID `db:"id" json:"id"`
+	UpdatedAt                 time.Time `db:"updated_at" json:"updated_at"`
+	Description               string    `db:"description" json:"description"`
+	MaxTtl                    int64     `db:"max_ttl" json:"max_ttl"`
+	MinAutostartInterval      int64     `db:"min_autostart_interval" json:"min_autostart_interval"`
+	CostUnits                 int32     `db:"cost_units" json:"cost_units"`
+	InactivityTtl             int64     `db:"inactivity_ttl" json:"inactivity_ttl"`
+	DormantDeletionTtl        int64     `db:"dormant_deletion_ttl" json:"dormant_deletion_ttl"`
+	DefaultAutostartSchedule  string    `db:"default_autostart_schedule" json:"default_autostart_schedule"`
+	DefaultTtl                int64     `db:"default_ttl" json:"default_ttl"`
+	RequiredStopSchedule      string    `db:"required_stop_schedule" json:"required_stop_schedule"`
+	AllowUserScheduleOverride bool      `db:"allow_user_schedule_override" json:"allow_user_schedule_override"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -2130,10 +3434,37 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.Description,
 		arg.MaxTtl,
 		arg.MinAutostartInterval,
+		arg.CostUnits,
+		arg.InactivityTtl,
+		arg.DormantDeletionTtl,
+		arg.DefaultAutostartSchedule,
+		arg.DefaultTtl,
+		arg.RequiredStopSchedule,
+		arg.AllowUserScheduleOverride,
 	)
 	return err
 }
 
+const updateTemplatesCreatedBy = `-- name: UpdateTemplatesCreatedBy :exec
+UPDATE
+	templates
+SET
+	created_by = $1
+WHERE
+	created_by = $2
+`
+
+type UpdateTemplatesCreatedByParams struct {
+	NewCreatedBy uuid.UUID `db:"new_created_by" json:"new_created_by"`
+	OldCreatedBy uuid.UUID `db:"old_created_by" json:"old_created_by"`
+}
+
+// Attributes the templates of a user to another, so the user can be deleted.
+func (q *sqlQuerier) UpdateTemplatesCreatedBy(ctx context.Context, arg UpdateTemplatesCreatedByParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplatesCreatedBy, arg.NewCreatedBy, arg.OldCreatedBy)
+	return err
+}
+
 const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
 SELECT
 	id, template_id, organization_id, created_at, updated_at, name, readme, job_id
@@ -2382,45 +3713,228 @@ func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTempla
 	return i, err
 }
 
-const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
-UPDATE
-	template_versions
-SET
-	template_id = $2,
-	updated_at = $3
-WHERE
-	id = $1
+const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
+UPDATE
+	template_versions
+SET
+	template_id = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateTemplateVersionByIDParams struct {
+	ID         uuid.UUID     `db:"id" json:"id"`
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID, arg.ID, arg.TemplateID, arg.UpdatedAt)
+	return err
+}
+
+const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
+UPDATE
+	template_versions
+SET
+	readme = $2,
+	updated_at = $3
+WHERE
+	job_id = $1
+`
+
+type UpdateTemplateVersionDescriptionByJobIDParams struct {
+	JobID     uuid.UUID `db:"job_id" json:"job_id"`
+	Readme    string    `db:"readme" json:"readme"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
+	return err
+}
+
+const deleteUserLockoutByUserID = `-- name: DeleteUserLockoutByUserID :exec
+DELETE FROM
+	user_lockouts
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserLockoutByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserLockoutByUserID, userID)
+	return err
+}
+
+const getUserLockoutByUserID = `-- name: GetUserLockoutByUserID :one
+SELECT
+	user_id, failed_attempts, locked_until, updated_at
+FROM
+	user_lockouts
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserLockoutByUserID(ctx context.Context, userID uuid.UUID) (UserLockout, error) {
+	row := q.db.QueryRowContext(ctx, getUserLockoutByUserID, userID)
+	var i UserLockout
+	err := row.Scan(
+		&i.UserID,
+		&i.FailedAttempts,
+		&i.LockedUntil,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const incrementUserLockoutFailedAttempts = `-- name: IncrementUserLockoutFailedAttempts :one
+INSERT INTO
+	user_lockouts (user_id, failed_attempts, updated_at)
+VALUES
+	($1, 1, $2)
+ON CONFLICT (user_id) DO UPDATE SET
+	failed_attempts = CASE
+		WHEN user_lockouts.updated_at < $3 :: timestamptz THEN 1
+		ELSE user_lockouts.failed_attempts + 1
+	END,
+	updated_at = $2
+RETURNING user_id, failed_attempts, locked_until, updated_at
+`
+
+type IncrementUserLockoutFailedAttemptsParams struct {
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ResetBefore time.Time `db:"reset_before" json:"reset_before"`
+}
+
+// Attempts start over when the last one failed before reset_before.
+func (q *sqlQuerier) IncrementUserLockoutFailedAttempts(ctx context.Context, arg IncrementUserLockoutFailedAttemptsParams) (UserLockout, error) {
+	row := q.db.QueryRowContext(ctx, incrementUserLockoutFailedAttempts, arg.UserID, arg.UpdatedAt, arg.ResetBefore)
+	var i UserLockout
+	err := row.Scan(
+		&i.UserID,
+		&i.FailedAttempts,
+		&i.LockedUntil,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateUserLockout = `-- name: UpdateUserLockout :exec
+UPDATE
+	user_lockouts
+SET
+	failed_attempts = $2,
+	locked_until = $3,
+	updated_at = $4
+WHERE
+	user_id = $1
+`
+
+type UpdateUserLockoutParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	FailedAttempts int32     `db:"failed_attempts" json:"failed_attempts"`
+	LockedUntil    time.Time `db:"locked_until" json:"locked_until"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserLockout(ctx context.Context, arg UpdateUserLockoutParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserLockout,
+		arg.UserID,
+		arg.FailedAttempts,
+		arg.LockedUntil,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const getUserPasswordHistory = `-- name: GetUserPasswordHistory :many
+SELECT
+	user_id, hashed_password, created_at
+FROM
+	user_password_history
+WHERE
+	user_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	$2
+`
+
+type GetUserPasswordHistoryParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Limit  int32     `db:"limit" json:"limit"`
+}
+
+// Returns the most recent previous passwords first.
+func (q *sqlQuerier) GetUserPasswordHistory(ctx context.Context, arg GetUserPasswordHistoryParams) ([]UserPasswordHistory, error) {
+	rows, err := q.db.QueryContext(ctx, getUserPasswordHistory, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserPasswordHistory
+	for rows.Next() {
+		var i UserPasswordHistory
+		if err := rows.Scan(
+			&i.UserID,
+			&i.HashedPassword,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUserPasswordHistory = `-- name: InsertUserPasswordHistory :exec
+INSERT INTO
+	user_password_history (user_id, hashed_password, created_at)
+VALUES
+	($1, $2, $3)
 `
 
-type UpdateTemplateVersionByIDParams struct {
-	ID         uuid.UUID     `db:"id" json:"id"`
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
+type InsertUserPasswordHistoryParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID, arg.ID, arg.TemplateID, arg.UpdatedAt)
+func (q *sqlQuerier) InsertUserPasswordHistory(ctx context.Context, arg InsertUserPasswordHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserPasswordHistory, arg.UserID, arg.HashedPassword, arg.CreatedAt)
 	return err
 }
 
-const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
+const deleteRoleFromUsers = `-- name: DeleteRoleFromUsers :exec
 UPDATE
-	template_versions
+	users
 SET
-	readme = $2,
-	updated_at = $3
-WHERE
-	job_id = $1
+	rbac_roles = array_remove(rbac_roles, $1 :: text)
 `
 
-type UpdateTemplateVersionDescriptionByJobIDParams struct {
-	JobID     uuid.UUID `db:"job_id" json:"job_id"`
-	Readme    string    `db:"readme" json:"readme"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+// Removes a deleted custom role from the users it was assigned to.
+func (q *sqlQuerier) DeleteRoleFromUsers(ctx context.Context, roleName string) error {
+	_, err := q.db.ExecContext(ctx, deleteRoleFromUsers, roleName)
+	return err
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
+const deleteUserByID = `-- name: DeleteUserByID :exec
+DELETE FROM
+	users
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteUserByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserByID, id)
 	return err
 }
 
@@ -2431,11 +3945,42 @@ SELECT
 	--	when suspended.
 	id, username, status,
 	array_cat(
-		-- All users are members
-			array_append(users.rbac_roles, 'member'),
-		-- All org_members get the org-member role for their orgs
-			array_append(organization_members.roles, 'organization-member:'||organization_members.organization_id::text)) :: text[]
-	    AS roles
+		array_cat(
+			-- All users are members
+				array_append(users.rbac_roles, 'member'),
+			-- All org_members get the org-member role for their orgs
+				array_append(organization_members.roles, 'organization-member:'||organization_members.organization_id::text)),
+		-- Members of groups get the roles granted to their groups
+		ARRAY(
+			SELECT
+				unnest(groups.roles)
+			FROM
+				groups
+			JOIN group_members
+				ON groups.id = group_members.group_id
+			WHERE
+				group_members.user_id = users.id
+		)) :: text[]
+	    AS roles,
+	-- Groups are matched against the access lists of objects, e.g. templates.
+	-- The ID of an organization is the group of all its members.
+	array_cat(
+		ARRAY(
+			SELECT
+				group_members.group_id :: text
+			FROM
+				group_members
+			WHERE
+				group_members.user_id = users.id
+		),
+		ARRAY(
+			SELECT
+				organization_members.organization_id :: text
+			FROM
+				organization_members
+			WHERE
+				organization_members.user_id = users.id
+		)) :: text[] AS groups
 FROM
 	users
 LEFT JOIN organization_members
@@ -2449,6 +3994,7 @@ type GetAuthorizationUserRolesRow struct {
 	Username string     `db:"username" json:"username"`
 	Status   UserStatus `db:"status" json:"status"`
 	Roles    []string   `db:"roles" json:"roles"`
+	Groups   []string   `db:"groups" json:"groups"`
 }
 
 // This function returns roles for authorization purposes. Implied member roles
@@ -2461,13 +4007,14 @@ func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.
 		&i.Username,
 		&i.Status,
 		pq.Array(&i.Roles),
+		pq.Array(&i.Groups),
 	)
 	return i, err
 }
 
 const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, last_seen_at
 FROM
 	users
 WHERE
@@ -2494,13 +4041,14 @@ func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserBy
 		&i.UpdatedAt,
 		&i.Status,
 		pq.Array(&i.RBACRoles),
+		&i.LastSeenAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, last_seen_at
 FROM
 	users
 WHERE
@@ -2521,6 +4069,7 @@ func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error
 		&i.UpdatedAt,
 		&i.Status,
 		pq.Array(&i.RBACRoles),
+		&i.LastSeenAt,
 	)
 	return i, err
 }
@@ -2541,7 +4090,7 @@ func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
 
 const getUsers = `-- name: GetUsers :many
 SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, last_seen_at
 FROM
 	users
 WHERE
@@ -2589,23 +4138,36 @@ WHERE
 		    rbac_roles && $4 :: text[]
 		ELSE true
 	END
+	-- Filter by last_seen_at
+	AND CASE
+		WHEN $5 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at <= $5
+		ELSE true
+	END
+	AND CASE
+		WHEN $6 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at >= $6
+		ELSE true
+	END
 	-- End of filters
 ORDER BY
     -- Deterministic and consistent ordering of all users, even if they share
     -- a timestamp. This is to ensure consistent pagination.
-	(created_at, id) ASC OFFSET $5
+	(created_at, id) ASC OFFSET $7
 LIMIT
 	-- A null limit means "no limit", so 0 means return all
-	NULLIF($6 :: int, 0)
+	NULLIF($8 :: int, 0)
 `
 
 type GetUsersParams struct {
-	AfterID   uuid.UUID    `db:"after_id" json:"after_id"`
-	Search    string       `db:"search" json:"search"`
-	Status    []UserStatus `db:"status" json:"status"`
-	RbacRole  []string     `db:"rbac_role" json:"rbac_role"`
-	OffsetOpt int32        `db:"offset_opt" json:"offset_opt"`
-	LimitOpt  int32        `db:"limit_opt" json:"limit_opt"`
+	AfterID        uuid.UUID    `db:"after_id" json:"after_id"`
+	Search         string       `db:"search" json:"search"`
+	Status         []UserStatus `db:"status" json:"status"`
+	RbacRole       []string     `db:"rbac_role" json:"rbac_role"`
+	LastSeenBefore time.Time    `db:"last_seen_before" json:"last_seen_before"`
+	LastSeenAfter  time.Time    `db:"last_seen_after" json:"last_seen_after"`
+	OffsetOpt      int32        `db:"offset_opt" json:"offset_opt"`
+	LimitOpt       int32        `db:"limit_opt" json:"limit_opt"`
 }
 
 func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error) {
@@ -2614,6 +4176,8 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]User,
 		arg.Search,
 		pq.Array(arg.Status),
 		pq.Array(arg.RbacRole),
+		arg.LastSeenBefore,
+		arg.LastSeenAfter,
 		arg.OffsetOpt,
 		arg.LimitOpt,
 	)
@@ -2633,6 +4197,7 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]User,
 			&i.UpdatedAt,
 			&i.Status,
 			pq.Array(&i.RBACRoles),
+			&i.LastSeenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -2648,7 +4213,7 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]User,
 }
 
 const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles FROM users WHERE id = ANY($1 :: uuid [ ])
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, last_seen_at FROM users WHERE id = ANY($1 :: uuid [ ])
 `
 
 func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
@@ -2669,6 +4234,7 @@ func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User
 			&i.UpdatedAt,
 			&i.Status,
 			pq.Array(&i.RBACRoles),
+			&i.LastSeenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -2695,7 +4261,7 @@ INSERT INTO
 		rbac_roles
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, last_seen_at
 `
 
 type InsertUserParams struct {
@@ -2728,6 +4294,7 @@ func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User
 		&i.UpdatedAt,
 		&i.Status,
 		pq.Array(&i.RBACRoles),
+		&i.LastSeenAt,
 	)
 	return i, err
 }
@@ -2741,115 +4308,369 @@ WHERE
 	id = $1
 `
 
-type UpdateUserHashedPasswordParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+type UpdateUserHashedPasswordParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+}
+
+func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+	return err
+}
+
+const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :exec
+UPDATE
+	users
+SET
+	last_seen_at = $2
+WHERE
+	id = $1
+`
+
+type UpdateUserLastSeenAtParams struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE
+	users
+SET
+	email = $2,
+	username = $3,
+	updated_at = $4
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, last_seen_at
+`
+
+type UpdateUserProfileParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Email     string    `db:"email" json:"email"`
+	Username  string    `db:"username" json:"username"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.UpdatedAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		pq.Array(&i.RBACRoles),
+		&i.LastSeenAt,
+	)
+	return i, err
+}
+
+const updateUserRoles = `-- name: UpdateUserRoles :one
+UPDATE
+    users
+SET
+	-- Remove all duplicates from the roles.
+	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+WHERE
+ 	id = $2
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, last_seen_at
+`
+
+type UpdateUserRolesParams struct {
+	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
+	ID           uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		pq.Array(&i.RBACRoles),
+		&i.LastSeenAt,
+	)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE
+	users
+SET
+	status = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, last_seen_at
+`
+
+type UpdateUserStatusParams struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Status    UserStatus `db:"status" json:"status"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		pq.Array(&i.RBACRoles),
+		&i.LastSeenAt,
+	)
+	return i, err
+}
+
+const deleteUserTOTPByUserID = `-- name: DeleteUserTOTPByUserID :exec
+DELETE FROM
+	user_totp
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserTOTPByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTOTPByUserID, userID)
+	return err
+}
+
+const deleteUserTOTPRecoveryCode = `-- name: DeleteUserTOTPRecoveryCode :one
+DELETE FROM
+	user_totp_recovery_codes
+WHERE
+	user_id = $1 AND hashed_code = $2
+RETURNING user_id, hashed_code
+`
+
+type DeleteUserTOTPRecoveryCodeParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	HashedCode []byte    `db:"hashed_code" json:"hashed_code"`
+}
+
+// Recovery codes are deleted when they're used, so they can only be used once.
+func (q *sqlQuerier) DeleteUserTOTPRecoveryCode(ctx context.Context, arg DeleteUserTOTPRecoveryCodeParams) (UserTOTPRecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, deleteUserTOTPRecoveryCode, arg.UserID, arg.HashedCode)
+	var i UserTOTPRecoveryCode
+	err := row.Scan(
+		&i.UserID,
+		&i.HashedCode,
+	)
+	return i, err
+}
+
+const deleteUserTOTPRecoveryCodesByUserID = `-- name: DeleteUserTOTPRecoveryCodesByUserID :exec
+DELETE FROM
+	user_totp_recovery_codes
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserTOTPRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTOTPRecoveryCodesByUserID, userID)
+	return err
+}
+
+const getUserTOTPByLoginChallenge = `-- name: GetUserTOTPByLoginChallenge :one
+SELECT
+	user_id, secret, enabled, last_used_step, login_challenge, login_challenge_expires_at, login_challenge_attempts, created_at, updated_at
+FROM
+	user_totp
+WHERE
+	login_challenge = $1
+`
+
+func (q *sqlQuerier) GetUserTOTPByLoginChallenge(ctx context.Context, loginChallenge []byte) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, getUserTOTPByLoginChallenge, loginChallenge)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Enabled,
+		&i.LastUsedStep,
+		&i.LoginChallenge,
+		&i.LoginChallengeExpiresAt,
+		&i.LoginChallengeAttempts,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserTOTPByUserID = `-- name: GetUserTOTPByUserID :one
+SELECT
+	user_id, secret, enabled, last_used_step, login_challenge, login_challenge_expires_at, login_challenge_attempts, created_at, updated_at
+FROM
+	user_totp
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserTOTPByUserID(ctx context.Context, userID uuid.UUID) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, getUserTOTPByUserID, userID)
+	var i UserTOTP
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Enabled,
+		&i.LastUsedStep,
+		&i.LoginChallenge,
+		&i.LoginChallengeExpiresAt,
+		&i.LoginChallengeAttempts,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserTOTPRecoveryCodeCount = `-- name: GetUserTOTPRecoveryCodeCount :one
+SELECT
+	COUNT(*)
+FROM
+	user_totp_recovery_codes
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserTOTPRecoveryCodeCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUserTOTPRecoveryCodeCount, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const insertUserTOTPRecoveryCode = `-- name: InsertUserTOTPRecoveryCode :exec
+INSERT INTO
+	user_totp_recovery_codes (user_id, hashed_code)
+VALUES
+	($1, $2)
+`
+
+type InsertUserTOTPRecoveryCodeParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	HashedCode []byte    `db:"hashed_code" json:"hashed_code"`
 }
 
-func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+func (q *sqlQuerier) InsertUserTOTPRecoveryCode(ctx context.Context, arg InsertUserTOTPRecoveryCodeParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserTOTPRecoveryCode, arg.UserID, arg.HashedCode)
 	return err
 }
 
-const updateUserProfile = `-- name: UpdateUserProfile :one
+const updateUserTOTP = `-- name: UpdateUserTOTP :exec
 UPDATE
-	users
+	user_totp
 SET
-	email = $2,
-	username = $3,
+	enabled = $2,
+	last_used_step = $3,
 	updated_at = $4
 WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles
+	user_id = $1
 `
 
-type UpdateUserProfileParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Email     string    `db:"email" json:"email"`
-	Username  string    `db:"username" json:"username"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+type UpdateUserTOTPParams struct {
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	Enabled      bool      `db:"enabled" json:"enabled"`
+	LastUsedStep int64     `db:"last_used_step" json:"last_used_step"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserProfile,
-		arg.ID,
-		arg.Email,
-		arg.Username,
+func (q *sqlQuerier) UpdateUserTOTP(ctx context.Context, arg UpdateUserTOTPParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserTOTP,
+		arg.UserID,
+		arg.Enabled,
+		arg.LastUsedStep,
 		arg.UpdatedAt,
 	)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		pq.Array(&i.RBACRoles),
-	)
-	return i, err
+	return err
 }
 
-const updateUserRoles = `-- name: UpdateUserRoles :one
+const updateUserTOTPLoginChallenge = `-- name: UpdateUserTOTPLoginChallenge :exec
 UPDATE
-    users
+	user_totp
 SET
-	-- Remove all duplicates from the roles.
-	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+	login_challenge = $2,
+	login_challenge_expires_at = $3,
+	login_challenge_attempts = $4
 WHERE
- 	id = $2
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles
+	user_id = $1
 `
 
-type UpdateUserRolesParams struct {
-	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
-	ID           uuid.UUID `db:"id" json:"id"`
+type UpdateUserTOTPLoginChallengeParams struct {
+	UserID                  uuid.UUID `db:"user_id" json:"user_id"`
+	LoginChallenge          []byte    `db:"login_challenge" json:"login_challenge"`
+	LoginChallengeExpiresAt time.Time `db:"login_challenge_expires_at" json:"login_challenge_expires_at"`
+	LoginChallengeAttempts  int32     `db:"login_challenge_attempts" json:"login_challenge_attempts"`
 }
 
-func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		pq.Array(&i.RBACRoles),
+func (q *sqlQuerier) UpdateUserTOTPLoginChallenge(ctx context.Context, arg UpdateUserTOTPLoginChallengeParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserTOTPLoginChallenge,
+		arg.UserID,
+		arg.LoginChallenge,
+		arg.LoginChallengeExpiresAt,
+		arg.LoginChallengeAttempts,
 	)
-	return i, err
+	return err
 }
 
-const updateUserStatus = `-- name: UpdateUserStatus :one
-UPDATE
-	users
-SET
-	status = $2,
-	updated_at = $3
-WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles
+const upsertUserTOTP = `-- name: UpsertUserTOTP :one
+INSERT INTO
+	user_totp (user_id, secret, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE SET
+	secret = $2,
+	enabled = false,
+	last_used_step = 0,
+	updated_at = $4
+RETURNING user_id, secret, enabled, last_used_step, login_challenge, login_challenge_expires_at, login_challenge_attempts, created_at, updated_at
 `
 
-type UpdateUserStatusParams struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	Status    UserStatus `db:"status" json:"status"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+type UpsertUserTOTPParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Secret    string    `db:"secret" json:"secret"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
-	var i User
+// Enrolling replaces any pending or enabled second factor.
+func (q *sqlQuerier) UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) (UserTOTP, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserTOTP,
+		arg.UserID,
+		arg.Secret,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i UserTOTP
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.UserID,
+		&i.Secret,
+		&i.Enabled,
+		&i.LastUsedStep,
+		&i.LoginChallenge,
+		&i.LoginChallengeExpiresAt,
+		&i.LoginChallengeAttempts,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Status,
-		pq.Array(&i.RBACRoles),
 	)
 	return i, err
 }
@@ -3161,6 +4982,27 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 	return i, err
 }
 
+const updateWorkspaceAgentAuthTokenByID = `-- name: UpdateWorkspaceAgentAuthTokenByID :exec
+UPDATE
+	workspace_agents
+SET
+	auth_token = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentAuthTokenByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	AuthToken uuid.UUID `db:"auth_token" json:"auth_token"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentAuthTokenByID, arg.ID, arg.AuthToken, arg.UpdatedAt)
+	return err
+}
+
 const updateWorkspaceAgentConnectionByID = `-- name: UpdateWorkspaceAgentConnectionByID :exec
 UPDATE
 	workspace_agents
@@ -3985,9 +5827,54 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 	return i, err
 }
 
+const deleteDeletedWorkspacesByOrganizationID = `-- name: DeleteDeletedWorkspacesByOrganizationID :exec
+DELETE FROM
+	workspaces
+WHERE
+	organization_id = $1
+	AND deleted = true
+`
+
+// Removes the workspaces of an organization that were already deleted, so the
+// organization itself can be deleted.
+func (q *sqlQuerier) DeleteDeletedWorkspacesByOrganizationID(ctx context.Context, organizationID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteDeletedWorkspacesByOrganizationID, organizationID)
+	return err
+}
+
+const deleteDeletedWorkspacesByOwnerID = `-- name: DeleteDeletedWorkspacesByOwnerID :exec
+DELETE FROM
+	workspaces
+WHERE
+	owner_id = $1
+	AND deleted = true
+`
+
+// Removes the workspaces of a user that were already deleted, so the user
+// itself can be deleted.
+func (q *sqlQuerier) DeleteDeletedWorkspacesByOwnerID(ctx context.Context, ownerID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteDeletedWorkspacesByOwnerID, ownerID)
+	return err
+}
+
+const deleteUserFromWorkspaceACLs = `-- name: DeleteUserFromWorkspaceACLs :exec
+UPDATE
+	workspaces
+SET
+	user_acl = user_acl - $1 :: text
+WHERE
+	user_acl ? $1 :: text
+`
+
+// Removes a deleted user from the users workspaces are shared with.
+func (q *sqlQuerier) DeleteUserFromWorkspaceACLs(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteUserFromWorkspaceACLs, userID)
+	return err
+}
+
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, user_acl, dormant_at, dormancy_opt_out
 FROM
 	workspaces
 WHERE
@@ -4010,13 +5897,16 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.Name,
 		&i.AutostartSchedule,
 		&i.Ttl,
+		&i.UserACL,
+		&i.DormantAt,
+		&i.DormancyOptOut,
 	)
 	return i, err
 }
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, user_acl, dormant_at, dormancy_opt_out
 FROM
 	workspaces
 WHERE
@@ -4046,6 +5936,9 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.Name,
 		&i.AutostartSchedule,
 		&i.Ttl,
+		&i.UserACL,
+		&i.DormantAt,
+		&i.DormancyOptOut,
 	)
 	return i, err
 }
@@ -4094,7 +5987,7 @@ func (q *sqlQuerier) GetWorkspaceOwnerCountsByTemplateIDs(ctx context.Context, i
 
 const getWorkspaces = `-- name: GetWorkspaces :many
 SELECT
-    id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl
+    id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, user_acl, dormant_at, dormancy_opt_out
 FROM
     workspaces
 WHERE
@@ -4132,15 +6025,22 @@ WHERE
 		    name ILIKE '%' || $6 || '%'
 		ELSE true
 	END
+	-- Filter by organization_id
+	AND CASE
+		WHEN $7 :: uuid != '00000000-00000000-00000000-00000000' THEN
+			organization_id = $7
+		ELSE true
+	END
 `
 
 type GetWorkspacesParams struct {
-	Deleted       bool        `db:"deleted" json:"deleted"`
-	OwnerID       uuid.UUID   `db:"owner_id" json:"owner_id"`
-	OwnerUsername string      `db:"owner_username" json:"owner_username"`
-	TemplateName  string      `db:"template_name" json:"template_name"`
-	TemplateIds   []uuid.UUID `db:"template_ids" json:"template_ids"`
-	Name          string      `db:"name" json:"name"`
+	Deleted        bool        `db:"deleted" json:"deleted"`
+	OwnerID        uuid.UUID   `db:"owner_id" json:"owner_id"`
+	OwnerUsername  string      `db:"owner_username" json:"owner_username"`
+	TemplateName   string      `db:"template_name" json:"template_name"`
+	TemplateIds    []uuid.UUID `db:"template_ids" json:"template_ids"`
+	Name           string      `db:"name" json:"name"`
+	OrganizationID uuid.UUID   `db:"organization_id" json:"organization_id"`
 }
 
 func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams) ([]Workspace, error) {
@@ -4151,6 +6051,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 		arg.TemplateName,
 		pq.Array(arg.TemplateIds),
 		arg.Name,
+		arg.OrganizationID,
 	)
 	if err != nil {
 		return nil, err
@@ -4170,6 +6071,9 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.Name,
 			&i.AutostartSchedule,
 			&i.Ttl,
+			&i.UserACL,
+			&i.DormantAt,
+			&i.DormancyOptOut,
 		); err != nil {
 			return nil, err
 		}
@@ -4186,16 +6090,23 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 
 const getWorkspacesAutostart = `-- name: GetWorkspacesAutostart :many
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.user_acl, workspaces.dormant_at, workspaces.dormancy_opt_out
 FROM
 	workspaces
+INNER JOIN
+	templates ON workspaces.template_id = templates.id
+INNER JOIN
+	-- Suspended users can't use their workspaces, so they aren't started.
+	users ON workspaces.owner_id = users.id AND users.status = 'active'
 WHERE
-	deleted = false
+	workspaces.deleted = false
 AND
 (
-	(autostart_schedule IS NOT NULL AND autostart_schedule <> '')
+	(workspaces.autostart_schedule IS NOT NULL AND workspaces.autostart_schedule <> '')
+	OR
+	(workspaces.ttl IS NOT NULL AND workspaces.ttl > 0)
 	OR
-	(ttl IS NOT NULL AND ttl > 0)
+	templates.required_stop_schedule <> ''
 )
 `
 
@@ -4219,6 +6130,67 @@ func (q *sqlQuerier) GetWorkspacesAutostart(ctx context.Context) ([]Workspace, e
 			&i.Name,
 			&i.AutostartSchedule,
 			&i.Ttl,
+			&i.UserACL,
+			&i.DormantAt,
+			&i.DormancyOptOut,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspacesEligibleForDormancy = `-- name: GetWorkspacesEligibleForDormancy :many
+SELECT
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.user_acl, workspaces.dormant_at, workspaces.dormancy_opt_out
+FROM
+	workspaces
+INNER JOIN
+	templates ON workspaces.template_id = templates.id
+WHERE
+	workspaces.deleted = false
+AND
+	workspaces.dormancy_opt_out = false
+AND
+(
+	templates.inactivity_ttl > 0
+	OR
+	workspaces.dormant_at IS NOT NULL
+)
+`
+
+// Returns workspaces that may become dormant or be deleted because their
+// template has an inactivity TTL, or because they already are dormant.
+func (q *sqlQuerier) GetWorkspacesEligibleForDormancy(ctx context.Context) ([]Workspace, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacesEligibleForDormancy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Workspace
+	for rows.Next() {
+		var i Workspace
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OwnerID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.Deleted,
+			&i.Name,
+			&i.AutostartSchedule,
+			&i.Ttl,
+			&i.UserACL,
+			&i.DormantAt,
+			&i.DormancyOptOut,
 		); err != nil {
 			return nil, err
 		}
@@ -4247,7 +6219,7 @@ INSERT INTO
 		ttl
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, user_acl, dormant_at, dormancy_opt_out
 `
 
 type InsertWorkspaceParams struct {
@@ -4286,6 +6258,9 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.Name,
 		&i.AutostartSchedule,
 		&i.Ttl,
+		&i.UserACL,
+		&i.DormantAt,
+		&i.DormancyOptOut,
 	)
 	return i, err
 }
@@ -4328,6 +6303,87 @@ func (q *sqlQuerier) UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateW
 	return err
 }
 
+const updateWorkspaceDormancyOptOut = `-- name: UpdateWorkspaceDormancyOptOut :exec
+UPDATE
+	workspaces
+SET
+	dormancy_opt_out = $2,
+	dormant_at = NULL
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceDormancyOptOutParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	DormancyOptOut bool      `db:"dormancy_opt_out" json:"dormancy_opt_out"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceDormancyOptOut(ctx context.Context, arg UpdateWorkspaceDormancyOptOutParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceDormancyOptOut, arg.ID, arg.DormancyOptOut)
+	return err
+}
+
+const updateWorkspaceDormantAt = `-- name: UpdateWorkspaceDormantAt :exec
+UPDATE
+	workspaces
+SET
+	dormant_at = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceDormantAtParams struct {
+	ID        uuid.UUID    `db:"id" json:"id"`
+	DormantAt sql.NullTime `db:"dormant_at" json:"dormant_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceDormantAt(ctx context.Context, arg UpdateWorkspaceDormantAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceDormantAt, arg.ID, arg.DormantAt)
+	return err
+}
+
+const updateWorkspaceName = `-- name: UpdateWorkspaceName :exec
+UPDATE
+	workspaces
+SET
+	name = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceNameParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceName(ctx context.Context, arg UpdateWorkspaceNameParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceName, arg.ID, arg.Name, arg.UpdatedAt)
+	return err
+}
+
+const updateWorkspaceOwner = `-- name: UpdateWorkspaceOwner :exec
+UPDATE
+	workspaces
+SET
+	owner_id = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceOwnerParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	OwnerID   uuid.UUID `db:"owner_id" json:"owner_id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceOwner(ctx context.Context, arg UpdateWorkspaceOwnerParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceOwner, arg.ID, arg.OwnerID, arg.UpdatedAt)
+	return err
+}
+
 const updateWorkspaceTTL = `-- name: UpdateWorkspaceTTL :exec
 UPDATE
 	workspaces
@@ -4346,3 +6402,22 @@ func (q *sqlQuerier) UpdateWorkspaceTTL(ctx context.Context, arg UpdateWorkspace
 	_, err := q.db.ExecContext(ctx, updateWorkspaceTTL, arg.ID, arg.Ttl)
 	return err
 }
+
+const updateWorkspaceUserACL = `-- name: UpdateWorkspaceUserACL :exec
+UPDATE
+	workspaces
+SET
+	user_acl = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceUserACLParams struct {
+	ID      uuid.UUID    `db:"id" json:"id"`
+	UserACL WorkspaceACL `db:"user_acl" json:"user_acl"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceUserACL(ctx context.Context, arg UpdateWorkspaceUserACLParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceUserACL, arg.ID, arg.UserACL)
+	return err
+}
@@ -11,6 +11,9 @@ LIMIT
 -- name: GetAPIKeysLastUsedAfter :many
 SELECT * FROM api_keys WHERE last_used > $1;
 
+-- name: GetAPIKeysByUserID :many
+SELECT * FROM api_keys WHERE user_id = $1 ORDER BY created_at ASC;
+
 -- name: InsertAPIKey :one
 INSERT INTO
 	api_keys (
@@ -27,7 +30,9 @@ INSERT INTO
 		oauth_access_token,
 		oauth_refresh_token,
 		oauth_id_token,
-		oauth_expiry
+		oauth_expiry,
+		scopes,
+		token_name
 	)
 VALUES
 	(@id,
@@ -36,7 +41,9 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE @lifetime_seconds::bigint
 	 END
-	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @oauth_access_token, @oauth_refresh_token, @oauth_id_token, @oauth_expiry) RETURNING *;
+	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @oauth_access_token, @oauth_refresh_token, @oauth_id_token, @oauth_expiry,
+	 -- Keys are unrestricted unless scopes are provided.
+	 COALESCE(@scopes :: text[], '{all}'), @token_name) RETURNING *;
 
 -- name: UpdateAPIKeyByID :exec
 UPDATE
@@ -57,3 +64,10 @@ FROM
 	api_keys
 WHERE
 	id = $1;
+
+-- name: DeleteAPIKeysByUserID :exec
+DELETE
+FROM
+	api_keys
+WHERE
+	user_id = $1;
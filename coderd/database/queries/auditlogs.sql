@@ -30,3 +30,106 @@ INSERT INTO
     )
 VALUES
 	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+
+-- name: GetAuditLogs :many
+SELECT
+	*
+FROM
+	audit_logs
+WHERE
+	CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- The query is ordered by time descending, so select all rows older
+		-- than the cursor.
+		WHEN @after_id :: uuid != '00000000-00000000-00000000-00000000' THEN (
+			("time", id) < (
+				SELECT
+					"time", id
+				FROM
+					audit_logs
+				WHERE
+					id = @after_id
+			)
+		)
+		ELSE true
+	END
+	-- Start filters
+	-- Filter by the user that performed the action
+	AND CASE
+		WHEN @user_id :: uuid != '00000000-00000000-00000000-00000000' THEN
+			user_id = @user_id
+		ELSE true
+	END
+	-- Filter by resource_type. @resource_type needs to be a text because it
+	-- can be empty.
+	AND CASE
+		WHEN @resource_type :: text != '' THEN
+			resource_type = @resource_type :: resource_type
+		ELSE true
+	END
+	-- Filter by resource_id
+	AND CASE
+		WHEN @resource_id :: uuid != '00000000-00000000-00000000-00000000' THEN
+			resource_id = @resource_id
+		ELSE true
+	END
+	-- Filter by action
+	AND CASE
+		WHEN @action :: text != '' THEN
+			action = @action :: audit_action
+		ELSE true
+	END
+	-- Filter by status_code
+	AND CASE
+		WHEN @status_code :: int != 0 THEN
+			status_code = @status_code
+		ELSE true
+	END
+	-- Filter by time range. The lower bound is inclusive and the upper bound
+	-- is exclusive.
+	AND CASE
+		WHEN @time_from :: timestamptz != '0001-01-01 00:00:00Z' THEN
+			"time" >= @time_from
+		ELSE true
+	END
+	AND CASE
+		WHEN @time_to :: timestamptz != '0001-01-01 00:00:00Z' THEN
+			"time" < @time_to
+		ELSE true
+	END
+	-- End of filters
+ORDER BY
+	-- Newest first, with the id as a tie breaker to keep pagination stable.
+	("time", id) DESC OFFSET @offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- GetAuditLogOrganizationIDs returns every organization ID referenced by an
+-- audit log, including organizations that no longer exist.
+-- name: GetAuditLogOrganizationIDs :many
+SELECT
+	DISTINCT organization_id
+FROM
+	audit_logs;
+
+-- GetAuditLogsOlderThan returns the oldest audit logs in an organization
+-- created before the provided time, oldest first.
+-- name: GetAuditLogsOlderThan :many
+SELECT
+	*
+FROM
+	audit_logs
+WHERE
+	organization_id = @organization_id
+	AND "time" < @time_before
+ORDER BY
+	("time", id) ASC
+LIMIT
+	@row_limit;
+
+-- name: DeleteAuditLogsByIDs :exec
+DELETE FROM
+	audit_logs
+WHERE
+	id = ANY(@ids :: uuid[]);
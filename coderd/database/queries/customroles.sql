@@ -0,0 +1,54 @@
+-- name: GetCustomRoles :many
+-- Site roles are returned if organization_id is NULL.
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	organization_id IS NOT DISTINCT FROM @organization_id
+ORDER BY
+	name;
+
+-- name: GetCustomRoleByName :one
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	name = @name
+	AND organization_id IS NOT DISTINCT FROM @organization_id;
+
+-- name: InsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		id,
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: UpdateCustomRole :one
+UPDATE
+	custom_roles
+SET
+	display_name = $2,
+	site_permissions = $3,
+	org_permissions = $4,
+	user_permissions = $5,
+	updated_at = $6
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteCustomRole :exec
+DELETE FROM
+	custom_roles
+WHERE
+	id = $1;
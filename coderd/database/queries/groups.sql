@@ -0,0 +1,121 @@
+-- name: GetGroupByID :one
+SELECT
+	*
+FROM
+	groups
+WHERE
+	id = $1
+LIMIT
+	1;
+
+-- name: GetGroupByOrganizationAndName :one
+SELECT
+	*
+FROM
+	groups
+WHERE
+	organization_id = $1
+	AND name = $2
+LIMIT
+	1;
+
+-- name: GetGroupsByOrganizationID :many
+SELECT
+	*
+FROM
+	groups
+WHERE
+	organization_id = $1
+ORDER BY
+	name;
+
+-- name: InsertGroup :one
+INSERT INTO
+	groups (
+		id,
+		name,
+		organization_id,
+		roles,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: UpdateGroupByID :one
+UPDATE
+	groups
+SET
+	name = $2,
+	updated_at = $3
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: UpdateGroupRoles :one
+UPDATE
+	groups
+SET
+	-- Remove all duplicates from the roles.
+	roles = ARRAY(SELECT DISTINCT UNNEST(@granted_roles :: text[])),
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteGroupByID :exec
+DELETE FROM
+	groups
+WHERE
+	id = $1;
+
+-- name: GetGroupMembers :many
+SELECT
+	users.*
+FROM
+	users
+JOIN
+	group_members
+ON
+	group_members.user_id = users.id
+WHERE
+	group_members.group_id = $1
+ORDER BY
+	users.username;
+
+-- name: InsertGroupMember :exec
+INSERT INTO
+	group_members (group_id, user_id, created_at)
+VALUES
+	($1, $2, $3) ON CONFLICT DO NOTHING;
+
+-- name: DeleteGroupMember :exec
+DELETE FROM
+	group_members
+WHERE
+	group_id = $1
+	AND user_id = $2;
+
+-- name: DeleteGroupMembersByOrganizationAndUser :exec
+-- Removes a user from every group of an organization they are leaving.
+DELETE FROM
+	group_members
+WHERE
+	user_id = @user_id
+	AND group_id = ANY(
+		SELECT
+			id
+		FROM
+			groups
+		WHERE
+			organization_id = @organization_id
+	);
+
+-- name: DeleteRoleFromGroups :exec
+-- Removes a deleted custom role from the groups it was granted to.
+UPDATE
+	groups
+SET
+	roles = array_remove(roles, @role_name :: text)
+WHERE
+	organization_id = @organization_id;
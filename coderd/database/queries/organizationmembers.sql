@@ -9,6 +9,16 @@ WHERE
 LIMIT
 	1;
 
+-- name: GetOrganizationMembersByOrganizationID :many
+SELECT
+	*
+FROM
+	organization_members
+WHERE
+	organization_id = $1
+ORDER BY
+	created_at;
+
 -- name: InsertOrganizationMember :one
 INSERT INTO
 	organization_members (
@@ -50,3 +60,19 @@ WHERE
 	user_id = @user_id
 	AND organization_id = @org_id
 RETURNING *;
+
+-- name: DeleteRoleFromOrganizationMembers :exec
+-- Removes a deleted custom role from the members it was assigned to.
+UPDATE
+	organization_members
+SET
+	roles = array_remove(roles, @role_name :: text)
+WHERE
+	organization_id = @organization_id;
+
+-- name: DeleteOrganizationMember :exec
+DELETE FROM
+	organization_members
+WHERE
+	organization_id = $1
+	AND user_id = $2;
@@ -28,7 +28,7 @@ SELECT
 FROM
 	organizations
 WHERE
-	id = (
+	id = ANY(
 		SELECT
 			organization_id
 		FROM
@@ -42,3 +42,19 @@ INSERT INTO
 	organizations (id, "name", description, created_at, updated_at)
 VALUES
 	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateOrganizationByID :one
+UPDATE
+	organizations
+SET
+	"name" = $2,
+	updated_at = $3
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteOrganizationByID :exec
+DELETE FROM
+	organizations
+WHERE
+	id = $1;
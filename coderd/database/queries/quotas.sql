@@ -0,0 +1,98 @@
+-- name: GetOrganizationQuota :one
+SELECT
+	*
+FROM
+	organization_quotas
+WHERE
+	organization_id = $1;
+
+-- name: UpsertOrganizationQuota :one
+INSERT INTO
+	organization_quotas (organization_id, max_workspaces, max_running_workspaces, max_cost_units, updated_at)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (organization_id) DO UPDATE SET
+	max_workspaces = $2,
+	max_running_workspaces = $3,
+	max_cost_units = $4,
+	updated_at = $5
+RETURNING *;
+
+-- name: GetUserQuota :one
+SELECT
+	*
+FROM
+	user_quotas
+WHERE
+	user_id = $1;
+
+-- name: UpsertUserQuota :one
+INSERT INTO
+	user_quotas (user_id, max_workspaces, max_running_workspaces, max_cost_units, updated_at)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE SET
+	max_workspaces = $2,
+	max_running_workspaces = $3,
+	max_cost_units = $4,
+	updated_at = $5
+RETURNING *;
+
+-- name: GetQuotaUsage :one
+-- Counts the workspaces of an owner or organization, and the workspaces and
+-- template cost units of those that are running. Workspaces are running when
+-- their latest build starts them and hasn't failed or been canceled.
+SELECT
+	COUNT(*) AS workspaces,
+	COUNT(*) FILTER (WHERE running) AS running_workspaces,
+	COALESCE(SUM(cost_units) FILTER (WHERE running), 0) :: bigint AS cost_units
+FROM (
+	SELECT
+		templates.cost_units,
+		COALESCE(latest_builds.running, false) AS running
+	FROM
+		workspaces
+	JOIN
+		templates ON templates.id = workspaces.template_id
+	LEFT JOIN LATERAL (
+		SELECT
+			workspace_builds.transition = 'start'
+			AND provisioner_jobs.error IS NULL
+			AND provisioner_jobs.canceled_at IS NULL AS running
+		FROM
+			workspace_builds
+		JOIN
+			provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+		ORDER BY
+			workspace_builds.build_number DESC
+		LIMIT
+			1
+	) latest_builds ON true
+	WHERE
+		workspaces.deleted = false
+		-- Filter by owner_id
+		AND CASE
+			WHEN @owner_id :: uuid != '00000000-00000000-00000000-00000000' THEN
+				workspaces.owner_id = @owner_id
+			ELSE true
+		END
+		-- Filter by organization_id
+		AND CASE
+			WHEN @organization_id :: uuid != '00000000-00000000-00000000-00000000' THEN
+				workspaces.organization_id = @organization_id
+			ELSE true
+		END
+) AS quota_workspaces;
+
+-- name: AcquireQuotaLock :exec
+-- Blocks until the quota of a user or organization is locked for the rest of
+-- the transaction, so concurrent builds are counted toward it one at a time.
+SELECT pg_advisory_xact_lock(hashtext(@id :: uuid :: text));
+
+-- name: TryAcquireQuotaLock :one
+-- Locks the quota of a user or organization for the rest of the transaction
+-- like AcquireQuotaLock, but returns false instead of blocking if another
+-- transaction holds the lock.
+SELECT pg_try_advisory_xact_lock(hashtext(@id :: uuid :: text));
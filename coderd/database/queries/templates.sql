@@ -64,10 +64,37 @@ INSERT INTO
 		description,
 		max_ttl,
 		min_autostart_interval,
-		created_by
+		created_by,
+		group_acl,
+		user_acl,
+		cost_units,
+		inactivity_ttl,
+		dormant_deletion_ttl,
+		default_autostart_schedule,
+		default_ttl,
+		required_stop_schedule,
+		allow_user_schedule_override
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20) RETURNING *;
+
+-- name: DeleteUserFromTemplateACLs :exec
+-- Removes a deleted user from the access lists of templates.
+UPDATE
+	templates
+SET
+	user_acl = user_acl - @user_id :: text
+WHERE
+	user_acl ? @user_id :: text;
+
+-- name: UpdateTemplateACLByID :exec
+UPDATE
+	templates
+SET
+	group_acl = $2,
+	user_acl = $3
+WHERE
+	id = $1;
 
 -- name: UpdateTemplateActiveVersionByID :exec
 UPDATE
@@ -94,8 +121,24 @@ SET
 	updated_at = $2,
 	description = $3,
 	max_ttl = $4,
-	min_autostart_interval = $5
+	min_autostart_interval = $5,
+	cost_units = $6,
+	inactivity_ttl = $7,
+	dormant_deletion_ttl = $8,
+	default_autostart_schedule = $9,
+	default_ttl = $10,
+	required_stop_schedule = $11,
+	allow_user_schedule_override = $12
 WHERE
 	id = $1
 RETURNING
 	*;
+
+-- name: UpdateTemplatesCreatedBy :exec
+-- Attributes the templates of a user to another, so the user can be deleted.
+UPDATE
+	templates
+SET
+	created_by = @new_created_by
+WHERE
+	created_by = @old_created_by;
@@ -0,0 +1,37 @@
+-- name: GetUserLockoutByUserID :one
+SELECT
+	*
+FROM
+	user_lockouts
+WHERE
+	user_id = $1;
+
+-- name: IncrementUserLockoutFailedAttempts :one
+-- Attempts start over when the last one failed before reset_before.
+INSERT INTO
+	user_lockouts (user_id, failed_attempts, updated_at)
+VALUES
+	(@user_id, 1, @updated_at)
+ON CONFLICT (user_id) DO UPDATE SET
+	failed_attempts = CASE
+		WHEN user_lockouts.updated_at < @reset_before :: timestamptz THEN 1
+		ELSE user_lockouts.failed_attempts + 1
+	END,
+	updated_at = @updated_at
+RETURNING *;
+
+-- name: UpdateUserLockout :exec
+UPDATE
+	user_lockouts
+SET
+	failed_attempts = $2,
+	locked_until = $3,
+	updated_at = $4
+WHERE
+	user_id = $1;
+
+-- name: DeleteUserLockoutByUserID :exec
+DELETE FROM
+	user_lockouts
+WHERE
+	user_id = $1;
@@ -0,0 +1,18 @@
+-- name: GetUserPasswordHistory :many
+-- Returns the most recent previous passwords first.
+SELECT
+	*
+FROM
+	user_password_history
+WHERE
+	user_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	$2;
+
+-- name: InsertUserPasswordHistory :exec
+INSERT INTO
+	user_password_history (user_id, hashed_password, created_at)
+VALUES
+	($1, $2, $3);
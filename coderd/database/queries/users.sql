@@ -62,6 +62,13 @@ WHERE
  	id = @id
 RETURNING *;
 
+-- name: DeleteRoleFromUsers :exec
+-- Removes a deleted custom role from the users it was assigned to.
+UPDATE
+	users
+SET
+	rbac_roles = array_remove(rbac_roles, @role_name :: text);
+
 -- name: UpdateUserHashedPassword :exec
 UPDATE
 	users
@@ -120,6 +127,17 @@ WHERE
 		    rbac_roles && @rbac_role :: text[]
 		ELSE true
 	END
+	-- Filter by last_seen_at
+	AND CASE
+		WHEN @last_seen_before :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at <= @last_seen_before
+		ELSE true
+	END
+	AND CASE
+		WHEN @last_seen_after :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at >= @last_seen_after
+		ELSE true
+	END
 	-- End of filters
 ORDER BY
     -- Deterministic and consistent ordering of all users, even if they share
@@ -129,6 +147,12 @@ LIMIT
 	-- A null limit means "no limit", so 0 means return all
 	NULLIF(@limit_opt :: int, 0);
 
+-- name: DeleteUserByID :exec
+DELETE FROM
+	users
+WHERE
+	id = $1;
+
 -- name: UpdateUserStatus :one
 UPDATE
 	users
@@ -139,6 +163,14 @@ WHERE
 	id = $1 RETURNING *;
 
 
+-- name: UpdateUserLastSeenAt :exec
+UPDATE
+	users
+SET
+	last_seen_at = $2
+WHERE
+	id = $1;
+
 -- name: GetAuthorizationUserRoles :one
 -- This function returns roles for authorization purposes. Implied member roles
 -- are included.
@@ -148,11 +180,42 @@ SELECT
 	--	when suspended.
 	id, username, status,
 	array_cat(
-		-- All users are members
-			array_append(users.rbac_roles, 'member'),
-		-- All org_members get the org-member role for their orgs
-			array_append(organization_members.roles, 'organization-member:'||organization_members.organization_id::text)) :: text[]
-	    AS roles
+		array_cat(
+			-- All users are members
+				array_append(users.rbac_roles, 'member'),
+			-- All org_members get the org-member role for their orgs
+				array_append(organization_members.roles, 'organization-member:'||organization_members.organization_id::text)),
+		-- Members of groups get the roles granted to their groups
+		ARRAY(
+			SELECT
+				unnest(groups.roles)
+			FROM
+				groups
+			JOIN group_members
+				ON groups.id = group_members.group_id
+			WHERE
+				group_members.user_id = users.id
+		)) :: text[]
+	    AS roles,
+	-- Groups are matched against the access lists of objects, e.g. templates.
+	-- The ID of an organization is the group of all its members.
+	array_cat(
+		ARRAY(
+			SELECT
+				group_members.group_id :: text
+			FROM
+				group_members
+			WHERE
+				group_members.user_id = users.id
+		),
+		ARRAY(
+			SELECT
+				organization_members.organization_id :: text
+			FROM
+				organization_members
+			WHERE
+				organization_members.user_id = users.id
+		)) :: text[] AS groups
 FROM
 	users
 LEFT JOIN organization_members
@@ -0,0 +1,82 @@
+-- name: GetUserTOTPByUserID :one
+SELECT
+	*
+FROM
+	user_totp
+WHERE
+	user_id = $1;
+
+-- name: GetUserTOTPByLoginChallenge :one
+SELECT
+	*
+FROM
+	user_totp
+WHERE
+	login_challenge = $1;
+
+-- name: UpsertUserTOTP :one
+-- Enrolling replaces any pending or enabled second factor.
+INSERT INTO
+	user_totp (user_id, secret, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE SET
+	secret = $2,
+	enabled = false,
+	last_used_step = 0,
+	updated_at = $4
+RETURNING *;
+
+-- name: UpdateUserTOTP :exec
+UPDATE
+	user_totp
+SET
+	enabled = $2,
+	last_used_step = $3,
+	updated_at = $4
+WHERE
+	user_id = $1;
+
+-- name: UpdateUserTOTPLoginChallenge :exec
+UPDATE
+	user_totp
+SET
+	login_challenge = $2,
+	login_challenge_expires_at = $3,
+	login_challenge_attempts = $4
+WHERE
+	user_id = $1;
+
+-- name: DeleteUserTOTPByUserID :exec
+DELETE FROM
+	user_totp
+WHERE
+	user_id = $1;
+
+-- name: InsertUserTOTPRecoveryCode :exec
+INSERT INTO
+	user_totp_recovery_codes (user_id, hashed_code)
+VALUES
+	($1, $2);
+
+-- name: GetUserTOTPRecoveryCodeCount :one
+SELECT
+	COUNT(*)
+FROM
+	user_totp_recovery_codes
+WHERE
+	user_id = $1;
+
+-- name: DeleteUserTOTPRecoveryCode :one
+-- Recovery codes are deleted when they're used, so they can only be used once.
+DELETE FROM
+	user_totp_recovery_codes
+WHERE
+	user_id = $1 AND hashed_code = $2
+RETURNING *;
+
+-- name: DeleteUserTOTPRecoveryCodesByUserID :exec
+DELETE FROM
+	user_totp_recovery_codes
+WHERE
+	user_id = $1;
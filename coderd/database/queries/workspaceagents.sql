@@ -61,6 +61,15 @@ INSERT INTO
 VALUES
 	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING *;
 
+-- name: UpdateWorkspaceAgentAuthTokenByID :exec
+UPDATE
+	workspace_agents
+SET
+	auth_token = $2,
+	updated_at = $3
+WHERE
+	id = $1;
+
 -- name: UpdateWorkspaceAgentConnectionByID :exec
 UPDATE
 	workspace_agents
@@ -48,20 +48,53 @@ WHERE
 		    name ILIKE '%' || @name || '%'
 		ELSE true
 	END
+	-- Filter by organization_id
+	AND CASE
+		WHEN @organization_id :: uuid != '00000000-00000000-00000000-00000000' THEN
+			organization_id = @organization_id
+		ELSE true
+	END
 ;
 
 -- name: GetWorkspacesAutostart :many
 SELECT
-	*
+	workspaces.*
+FROM
+	workspaces
+INNER JOIN
+	templates ON workspaces.template_id = templates.id
+INNER JOIN
+	-- Suspended users can't use their workspaces, so they aren't started.
+	users ON workspaces.owner_id = users.id AND users.status = 'active'
+WHERE
+	workspaces.deleted = false
+AND
+(
+	(workspaces.autostart_schedule IS NOT NULL AND workspaces.autostart_schedule <> '')
+	OR
+	(workspaces.ttl IS NOT NULL AND workspaces.ttl > 0)
+	OR
+	templates.required_stop_schedule <> ''
+);
+
+-- name: GetWorkspacesEligibleForDormancy :many
+-- Returns workspaces that may become dormant or be deleted because their
+-- template has an inactivity TTL, or because they already are dormant.
+SELECT
+	workspaces.*
 FROM
 	workspaces
+INNER JOIN
+	templates ON workspaces.template_id = templates.id
 WHERE
-	deleted = false
+	workspaces.deleted = false
+AND
+	workspaces.dormancy_opt_out = false
 AND
 (
-	(autostart_schedule IS NOT NULL AND autostart_schedule <> '')
+	templates.inactivity_ttl > 0
 	OR
-	(ttl IS NOT NULL AND ttl > 0)
+	workspaces.dormant_at IS NOT NULL
 );
 
 -- name: GetWorkspaceByOwnerIDAndName :one
@@ -104,6 +137,33 @@ INSERT INTO
 VALUES
 	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
 
+-- name: DeleteDeletedWorkspacesByOrganizationID :exec
+-- Removes the workspaces of an organization that were already deleted, so the
+-- organization itself can be deleted.
+DELETE FROM
+	workspaces
+WHERE
+	organization_id = $1
+	AND deleted = true;
+
+-- name: DeleteDeletedWorkspacesByOwnerID :exec
+-- Removes the workspaces of a user that were already deleted, so the user
+-- itself can be deleted.
+DELETE FROM
+	workspaces
+WHERE
+	owner_id = $1
+	AND deleted = true;
+
+-- name: DeleteUserFromWorkspaceACLs :exec
+-- Removes a deleted user from the users workspaces are shared with.
+UPDATE
+	workspaces
+SET
+	user_acl = user_acl - @user_id :: text
+WHERE
+	user_acl ? @user_id :: text;
+
 -- name: UpdateWorkspaceDeletedByID :exec
 UPDATE
 	workspaces
@@ -120,6 +180,41 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspaceDormancyOptOut :exec
+UPDATE
+	workspaces
+SET
+	dormancy_opt_out = $2,
+	dormant_at = NULL
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceDormantAt :exec
+UPDATE
+	workspaces
+SET
+	dormant_at = $2
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceName :exec
+UPDATE
+	workspaces
+SET
+	name = $2,
+	updated_at = $3
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceOwner :exec
+UPDATE
+	workspaces
+SET
+	owner_id = $2,
+	updated_at = $3
+WHERE
+	id = $1;
+
 -- name: UpdateWorkspaceTTL :exec
 UPDATE
 	workspaces
@@ -127,3 +222,11 @@ SET
 	ttl = $2
 WHERE
 	id = $1;
+
+-- name: UpdateWorkspaceUserACL :exec
+UPDATE
+	workspaces
+SET
+	user_acl = $2
+WHERE
+	id = $1;
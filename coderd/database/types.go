@@ -0,0 +1,56 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/rbac"
+)
+
+// TemplateACL maps the IDs of groups or users to the actions they can
+// perform on a template.
+type TemplateACL map[string][]rbac.Action
+
+// Scan is so TemplateACL can be read from the database.
+func (t *TemplateACL) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	case []byte:
+		return json.Unmarshal(v, t)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+// Value is so TemplateACL can be inserted into the database.
+func (t TemplateACL) Value() (driver.Value, error) {
+	if t == nil {
+		t = TemplateACL{}
+	}
+	return json.Marshal(t)
+}
+
+// WorkspaceACL maps the IDs of users a workspace is shared with to the
+// actions they can perform on it.
+type WorkspaceACL map[string][]rbac.Action
+
+// Scan is so WorkspaceACL can be read from the database.
+func (w *WorkspaceACL) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	case []byte:
+		return json.Unmarshal(v, w)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+// Value is so WorkspaceACL can be inserted into the database.
+func (w WorkspaceACL) Value() (driver.Value, error) {
+	if w == nil {
+		w = WorkspaceACL{}
+	}
+	return json.Marshal(w)
+}
@@ -0,0 +1,222 @@
+// Package dormantusers suspends users who haven't used Coder for a while, and
+// stops the workspaces of suspended users.
+package dormantusers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/autobuild/executor"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/util/slice"
+)
+
+// Options configures when users are considered dormant.
+type Options struct {
+	// InactiveFor is how long users must not have been seen before they're
+	// suspended. Users who were never seen are given this long after they're
+	// created.
+	InactiveFor time.Duration
+	// Auditor records the suspensions. No audit logs are exported if it's
+	// nil.
+	Auditor audit.Auditor
+}
+
+// Suspender suspends dormant users.
+type Suspender struct {
+	ctx     context.Context
+	db      database.Store
+	log     slog.Logger
+	tick    <-chan time.Time
+	opts    Options
+	statsCh chan<- Stats
+}
+
+// Stats contains information about one run of Suspender.
+type Stats struct {
+	// Suspended are the IDs of the users that were suspended.
+	Suspended []uuid.UUID
+	// Stopped are the IDs of the workspaces of suspended users that were
+	// stopped.
+	Stopped []uuid.UUID
+	Elapsed time.Duration
+	Error   error
+}
+
+// New returns a new dormant user suspender.
+func New(ctx context.Context, db database.Store, log slog.Logger, tick <-chan time.Time, opts Options) *Suspender {
+	if opts.Auditor == nil {
+		opts.Auditor = audit.NewNop()
+	}
+	return &Suspender{
+		ctx:  ctx,
+		db:   db,
+		log:  log,
+		tick: tick,
+		opts: opts,
+	}
+}
+
+// WithStatsChannel will cause Suspender to push a Stats to ch after every
+// tick.
+func (s *Suspender) WithStatsChannel(ch chan<- Stats) *Suspender {
+	s.statsCh = ch
+	return s
+}
+
+// Run will cause the suspender to suspend dormant users and stop the workspaces
+// of suspended users on every tick from its channel. It will stop when its
+// channel is closed.
+func (s *Suspender) Run() {
+	go func() {
+		for t := range s.tick {
+			stats := s.runOnce(t)
+			if stats.Error != nil {
+				s.log.Error(s.ctx, "error suspending dormant users", slog.Error(stats.Error))
+			}
+			if s.statsCh != nil {
+				s.statsCh <- stats
+			}
+			s.log.Debug(s.ctx, "run stats", slog.F("elapsed", stats.Elapsed), slog.F("suspended", stats.Suspended), slog.F("stopped", stats.Stopped))
+		}
+	}()
+}
+
+func (s *Suspender) runOnce(t time.Time) Stats {
+	var err error
+	stats := Stats{}
+	defer func() {
+		stats.Elapsed = time.Since(t)
+		stats.Error = err
+	}()
+
+	cutoff := t.Add(-s.opts.InactiveFor)
+	users, err := s.db.GetUsers(s.ctx, database.GetUsersParams{
+		Status:         []database.UserStatus{database.UserStatusActive},
+		LastSeenBefore: cutoff,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		err = xerrors.Errorf("get dormant users: %w", err)
+		return stats
+	}
+
+	for _, user := range users {
+		if user.CreatedAt.After(cutoff) {
+			continue
+		}
+		// Owners are never suspended, so the deployment can't be locked out.
+		if slice.Contains(user.RBACRoles, rbac.RoleAdmin()) {
+			continue
+		}
+		suspended, err := s.db.UpdateUserStatus(s.ctx, database.UpdateUserStatusParams{
+			ID:        user.ID,
+			Status:    database.UserStatusSuspended,
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			// Other dormant users are still suspended.
+			s.log.Error(s.ctx, "suspend dormant user",
+				slog.F("user_id", user.ID),
+				slog.Error(err),
+			)
+			continue
+		}
+		audit.BackgroundAudit(s.ctx, &audit.BackgroundAuditParams[database.User]{
+			Auditor: s.opts.Auditor,
+			Log:     s.log,
+			Action:  database.AuditActionWrite,
+			Old:     user,
+			New:     suspended,
+		})
+		s.log.Info(s.ctx, "suspended dormant user",
+			slog.F("user_id", user.ID),
+			slog.F("last_seen_at", user.LastSeenAt),
+		)
+		stats.Suspended = append(stats.Suspended, user.ID)
+	}
+
+	// Workspaces of every suspended user are stopped, not only of the users
+	// suspended above, so workspaces that were being built when their owner
+	// was suspended are stopped on a later tick.
+	suspendedUsers, err := s.db.GetUsers(s.ctx, database.GetUsersParams{
+		Status: []database.UserStatus{database.UserStatusSuspended},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		err = xerrors.Errorf("get suspended users: %w", err)
+		return stats
+	}
+	for _, user := range suspendedUsers {
+		var stopped []uuid.UUID
+		err := s.db.InTx(func(db database.Store) error {
+			var err error
+			stopped, err = s.stopWorkspaces(db, user)
+			return err
+		})
+		if err != nil {
+			s.log.Error(s.ctx, "stop workspaces of suspended user",
+				slog.F("user_id", user.ID),
+				slog.Error(err),
+			)
+			continue
+		}
+		if len(stopped) > 0 {
+			s.log.Info(s.ctx, "stopped workspaces of suspended user",
+				slog.F("user_id", user.ID),
+				slog.F("stopped_workspaces", len(stopped)),
+			)
+		}
+		stats.Stopped = append(stats.Stopped, stopped...)
+	}
+	return stats
+}
+
+// stopWorkspaces stops the running workspaces of a user. Workspaces with a
+// build in progress are left alone until a later tick.
+func (s *Suspender) stopWorkspaces(db database.Store, user database.User) ([]uuid.UUID, error) {
+	workspaces, err := db.GetWorkspaces(s.ctx, database.GetWorkspacesParams{
+		OwnerID: user.ID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get workspaces: %w", err)
+	}
+	stopped := make([]uuid.UUID, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		build, err := db.GetLatestWorkspaceBuildByWorkspaceID(s.ctx, workspace.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("get latest workspace build: %w", err)
+		}
+		if build.Transition != database.WorkspaceTransitionStart {
+			continue
+		}
+		job, err := db.GetProvisionerJobByID(s.ctx, build.JobID)
+		if err != nil {
+			return nil, xerrors.Errorf("get provisioner job: %w", err)
+		}
+		if !job.CompletedAt.Valid {
+			s.log.Debug(s.ctx, "workspace build is in progress, skipping",
+				slog.F("workspace_id", workspace.ID),
+			)
+			continue
+		}
+		err = executor.Build(s.ctx, db, workspace, database.WorkspaceTransitionStop, build, job)
+		if err != nil {
+			return nil, xerrors.Errorf("stop workspace %q: %w", workspace.Name, err)
+		}
+		stopped = append(stopped, workspace.ID)
+	}
+	return stopped, nil
+}
@@ -0,0 +1,271 @@
+package dormantusers_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/databasefake"
+	"github.com/coder/coder/coderd/dormantusers"
+	"github.com/coder/coder/coderd/rbac"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestSuspender(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		db      = databasefake.New()
+		now     = time.Now()
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan dormantusers.Stats)
+		auditor = audit.NewMock()
+		month   = 30 * 24 * time.Hour
+	)
+	dormant := insertUser(t, db, "dormant", now.Add(-2*month), now.Add(-2*month))
+	active := insertUser(t, db, "active", now.Add(-2*month), now.Add(-time.Hour))
+	neverSeen := insertUser(t, db, "never-seen", now.Add(-2*month), time.Time{})
+	created := insertUser(t, db, "created", now.Add(-time.Hour), time.Time{})
+	owner := insertUser(t, db, "owner", now.Add(-2*month), now.Add(-2*month), rbac.RoleAdmin())
+	running := insertWorkspace(t, db, dormant, database.WorkspaceTransitionStart)
+	stopped := insertWorkspace(t, db, dormant, database.WorkspaceTransitionStop)
+
+	dormantusers.New(ctx, db, slogtest.Make(t, nil), tickCh, dormantusers.Options{
+		InactiveFor: month,
+		Auditor:     auditor,
+	}).WithStatsChannel(statsCh).Run()
+	t.Cleanup(func() {
+		close(tickCh)
+	})
+
+	tickCh <- now
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.ElementsMatch(t, []uuid.UUID{dormant.ID, neverSeen.ID}, stats.Suspended)
+	require.Equal(t, []uuid.UUID{running.ID}, stats.Stopped)
+
+	// Every suspension is audited.
+	logs := auditor.AuditLogs()
+	require.Len(t, logs, 2)
+	audited := make([]uuid.UUID, 0, len(logs))
+	for _, alog := range logs {
+		require.Equal(t, database.ResourceTypeUser, alog.ResourceType)
+		require.Equal(t, database.AuditActionWrite, alog.Action)
+		audited = append(audited, alog.ResourceID)
+	}
+	require.ElementsMatch(t, []uuid.UUID{dormant.ID, neverSeen.ID}, audited)
+
+	for _, user := range []database.User{dormant, neverSeen} {
+		user, err := db.GetUserByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.UserStatusSuspended, user.Status, user.Username)
+	}
+	for _, user := range []database.User{active, created, owner} {
+		user, err := db.GetUserByID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.UserStatusActive, user.Status, user.Username)
+	}
+
+	build, err := db.GetLatestWorkspaceBuildByWorkspaceID(ctx, running.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.WorkspaceTransitionStop, build.Transition)
+	require.Equal(t, int32(2), build.BuildNumber)
+	build, err = db.GetLatestWorkspaceBuildByWorkspaceID(ctx, stopped.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), build.BuildNumber)
+
+	// Suspended users aren't suspended again.
+	tickCh <- now
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.Suspended)
+	require.Empty(t, stats.Stopped)
+	require.Len(t, auditor.AuditLogs(), 2)
+}
+
+func TestSuspenderBuildInProgress(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		db      = databasefake.New()
+		now     = time.Now()
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan dormantusers.Stats)
+		month   = 30 * 24 * time.Hour
+	)
+	dormant := insertUser(t, db, "dormant", now.Add(-2*month), now.Add(-2*month))
+	starting, job := insertWorkspaceBuild(t, db, dormant, database.WorkspaceTransitionStart, false)
+
+	dormantusers.New(ctx, db, slogtest.Make(t, nil), tickCh, dormantusers.Options{
+		InactiveFor: month,
+	}).WithStatsChannel(statsCh).Run()
+	t.Cleanup(func() {
+		close(tickCh)
+	})
+
+	// The workspace is still starting, so it isn't stopped yet.
+	tickCh <- now
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{dormant.ID}, stats.Suspended)
+	require.Empty(t, stats.Stopped)
+
+	// Once it started, it's stopped even though its owner was already
+	// suspended.
+	completeJob(t, db, job.ID)
+	tickCh <- now
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.Suspended)
+	require.Equal(t, []uuid.UUID{starting.ID}, stats.Stopped)
+	build, err := db.GetLatestWorkspaceBuildByWorkspaceID(ctx, starting.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.WorkspaceTransitionStop, build.Transition)
+}
+
+func TestSuspenderError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		db      = databasefake.New()
+		now     = time.Now()
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan dormantusers.Stats)
+		month   = 30 * 24 * time.Hour
+	)
+	failing := insertUser(t, db, "failing", now.Add(-2*month), now.Add(-2*month))
+	dormant := insertUser(t, db, "dormant", now.Add(-2*month), now.Add(-2*month))
+
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	dormantusers.New(ctx, failingStore{Store: db, userID: failing.ID}, logger, tickCh, dormantusers.Options{
+		InactiveFor: month,
+	}).WithStatsChannel(statsCh).Run()
+	t.Cleanup(func() {
+		close(tickCh)
+	})
+
+	// Failing to suspend a user doesn't stop others from being suspended.
+	tickCh <- now
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{dormant.ID}, stats.Suspended)
+	user, err := db.GetUserByID(ctx, failing.ID)
+	require.NoError(t, err)
+	require.Equal(t, database.UserStatusActive, user.Status)
+}
+
+// failingStore fails to update the status of a user.
+type failingStore struct {
+	database.Store
+	userID uuid.UUID
+}
+
+func (s failingStore) InTx(fn func(database.Store) error) error {
+	return s.Store.InTx(func(db database.Store) error {
+		return fn(failingStore{Store: db, userID: s.userID})
+	})
+}
+
+func (s failingStore) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
+	if arg.ID == s.userID {
+		return database.User{}, xerrors.New("failed")
+	}
+	return s.Store.UpdateUserStatus(ctx, arg)
+}
+
+func insertUser(t *testing.T, db database.Store, username string, createdAt, lastSeenAt time.Time, roles ...string) database.User {
+	t.Helper()
+	user, err := db.InsertUser(context.Background(), database.InsertUserParams{
+		ID:        uuid.New(),
+		Email:     username + "@coder.com",
+		Username:  username,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		RBACRoles: roles,
+	})
+	require.NoError(t, err)
+	err = db.UpdateUserLastSeenAt(context.Background(), database.UpdateUserLastSeenAtParams{
+		ID:         user.ID,
+		LastSeenAt: lastSeenAt,
+	})
+	require.NoError(t, err)
+	user.LastSeenAt = lastSeenAt
+	return user
+}
+
+// insertWorkspace inserts a workspace whose only build completed a transition.
+func insertWorkspace(t *testing.T, db database.Store, owner database.User, transition database.WorkspaceTransition) database.Workspace {
+	t.Helper()
+	workspace, _ := insertWorkspaceBuild(t, db, owner, transition, true)
+	return workspace
+}
+
+// insertWorkspaceBuild inserts a workspace with a single build for a
+// transition, and returns the job of the build.
+func insertWorkspaceBuild(t *testing.T, db database.Store, owner database.User, transition database.WorkspaceTransition, completed bool) (database.Workspace, database.ProvisionerJob) {
+	t.Helper()
+	ctx := context.Background()
+	template, err := db.InsertTemplate(ctx, database.InsertTemplateParams{
+		ID:          uuid.New(),
+		Name:        uuid.NewString()[:8],
+		Provisioner: database.ProvisionerTypeEcho,
+		CreatedBy:   owner.ID,
+	})
+	require.NoError(t, err)
+	workspace, err := db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
+		ID:         uuid.New(),
+		OwnerID:    owner.ID,
+		TemplateID: template.ID,
+		Name:       uuid.NewString()[:8],
+	})
+	require.NoError(t, err)
+	job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+		ID:            uuid.New(),
+		InitiatorID:   owner.ID,
+		Provisioner:   database.ProvisionerTypeEcho,
+		Type:          database.ProvisionerJobTypeWorkspaceBuild,
+		StorageMethod: database.ProvisionerStorageMethodFile,
+		Input:         []byte("{}"),
+	})
+	require.NoError(t, err)
+	if completed {
+		completeJob(t, db, job.ID)
+	}
+	_, err = db.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
+		ID:          uuid.New(),
+		WorkspaceID: workspace.ID,
+		BuildNumber: 1,
+		Transition:  transition,
+		InitiatorID: owner.ID,
+		JobID:       job.ID,
+		Reason:      database.BuildReasonInitiator,
+	})
+	require.NoError(t, err)
+	return workspace, job
+}
+
+func completeJob(t *testing.T, db database.Store, jobID uuid.UUID) {
+	t.Helper()
+	now := database.Now()
+	err := db.UpdateProvisionerJobWithCompleteByID(context.Background(), database.UpdateProvisionerJobWithCompleteByIDParams{
+		ID:          jobID,
+		UpdatedAt:   now,
+		CompletedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+}
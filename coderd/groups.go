@@ -0,0 +1,449 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+func (api *API) postGroupByOrganization(rw http.ResponseWriter, r *http.Request) {
+	aReq, commitAudit := audit.InitRequest[database.Group](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+	organization := httpmw.OrganizationParam(r)
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceGroup.InOrg(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateGroupRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if !api.authorizeGroupRoles(rw, r, organization.ID, nil, req.Roles) {
+		return
+	}
+
+	_, err := api.Database.GetGroupByOrganizationAndName(r.Context(), database.GetGroupByOrganizationAndNameParams{
+		OrganizationID: organization.ID,
+		Name:           req.Name,
+	})
+	if err == nil {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Group %q already exists.", req.Name),
+		})
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	roles := req.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	now := database.Now()
+	group, err := api.Database.InsertGroup(r.Context(), database.InsertGroupParams{
+		ID:             uuid.New(),
+		Name:           req.Name,
+		OrganizationID: organization.ID,
+		Roles:          roles,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting group.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = group
+
+	httpapi.Write(rw, http.StatusCreated, convertGroup(group, nil, nil, nil))
+}
+
+func (api *API) groupsByOrganization(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	groups, err := api.Database.GetGroupsByOrganizationID(r.Context(), organization.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching groups.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	groups = AuthorizeFilter(api, r, rbac.ActionRead, groups)
+	converted := make([]codersdk.Group, 0, len(groups))
+	for _, group := range groups {
+		c, err := api.convertGroupWithMembers(r.Context(), group)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group members.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		converted = append(converted, c)
+	}
+	httpapi.Write(rw, http.StatusOK, converted)
+}
+
+func (api *API) groupByOrganizationAndName(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	group, err := api.Database.GetGroupByOrganizationAndName(r.Context(), database.GetGroupByOrganizationAndNameParams{
+		OrganizationID: organization.ID,
+		Name:           chi.URLParam(r, "groupname"),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.writeGroup(rw, r, group)
+}
+
+func (api *API) group(rw http.ResponseWriter, r *http.Request) {
+	api.writeGroup(rw, r, httpmw.GroupParam(r))
+}
+
+func (api *API) writeGroup(rw http.ResponseWriter, r *http.Request, group database.Group) {
+	if !api.Authorize(r, rbac.ActionRead, group) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	converted, err := api.convertGroupWithMembers(r.Context(), group)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group members.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, converted)
+}
+
+// patchGroup renames a group, and adds or removes its members.
+func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
+	aReq, commitAudit := audit.InitRequest[database.Group](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	group := httpmw.GroupParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, group) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = group
+
+	var req codersdk.PatchGroupRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	if req.Name != "" && req.Name != group.Name {
+		_, err := api.Database.GetGroupByOrganizationAndName(r.Context(), database.GetGroupByOrganizationAndNameParams{
+			OrganizationID: group.OrganizationID,
+			Name:           req.Name,
+		})
+		if err == nil {
+			httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("Group %q already exists.", req.Name),
+			})
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	addUsers, ok := parseUserIDs(rw, "add_users", req.AddUsers)
+	if !ok {
+		return
+	}
+	removeUsers, ok := parseUserIDs(rw, "remove_users", req.RemoveUsers)
+	if !ok {
+		return
+	}
+	for _, userID := range addUsers {
+		_, err := api.Database.GetOrganizationMemberByUserID(r.Context(), database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: group.OrganizationID,
+			UserID:         userID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("User %q must be a member of the organization to be added to the group.", userID),
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching organization member.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	err := api.Database.InTx(func(store database.Store) error {
+		if req.Name != "" && req.Name != group.Name {
+			var err error
+			group, err = store.UpdateGroupByID(r.Context(), database.UpdateGroupByIDParams{
+				ID:        group.ID,
+				Name:      req.Name,
+				UpdatedAt: database.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("update group: %w", err)
+			}
+		}
+		for _, userID := range addUsers {
+			err := store.InsertGroupMember(r.Context(), database.InsertGroupMemberParams{
+				GroupID:   group.ID,
+				UserID:    userID,
+				CreatedAt: database.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert group member %q: %w", userID, err)
+			}
+		}
+		for _, userID := range removeUsers {
+			err := store.DeleteGroupMember(r.Context(), database.DeleteGroupMemberParams{
+				GroupID: group.ID,
+				UserID:  userID,
+			})
+			if err != nil {
+				return xerrors.Errorf("delete group member %q: %w", userID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating group.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = group
+
+	converted, err := api.convertGroupWithMembers(r.Context(), group)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group members.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, converted)
+}
+
+// putGroupRoles replaces the organization roles granted to the members of a
+// group.
+func (api *API) putGroupRoles(rw http.ResponseWriter, r *http.Request) {
+	aReq, commitAudit := audit.InitRequest[database.Group](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	group := httpmw.GroupParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, group) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = group
+
+	var req codersdk.UpdateRoles
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if !api.authorizeGroupRoles(rw, r, group.OrganizationID, group.Roles, req.Roles) {
+		return
+	}
+
+	group, err := api.Database.UpdateGroupRoles(r.Context(), database.UpdateGroupRolesParams{
+		GrantedRoles: req.Roles,
+		UpdatedAt:    database.Now(),
+		ID:           group.ID,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating group roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = group
+
+	converted, err := api.convertGroupWithMembers(r.Context(), group)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group members.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, converted)
+}
+
+func (api *API) deleteGroup(rw http.ResponseWriter, r *http.Request) {
+	aReq, commitAudit := audit.InitRequest[database.Group](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDelete,
+	})
+	defer commitAudit()
+	group := httpmw.GroupParam(r)
+	if !api.Authorize(r, rbac.ActionDelete, group) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = group
+
+	err := api.Database.DeleteGroupByID(r.Context(), group.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting group.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "Group has been deleted!",
+	})
+}
+
+// authorizeGroupRoles ensures the roles granted to a group are organization
+// roles, and that the user is allowed to assign the roles that change.
+func (api *API) authorizeGroupRoles(rw http.ResponseWriter, r *http.Request, organizationID uuid.UUID, current, granted []string) bool {
+	err := api.validateOrganizationRoles(r.Context(), organizationID, granted)
+	if err != nil {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: err.Error(),
+		})
+		return false
+	}
+
+	added, removed := rbac.ChangeRoleSet(current, granted)
+	for _, roleName := range added {
+		// Assigning a role requires the create permission.
+		if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceOrgRoleAssignment.WithID(roleName).InOrg(organizationID)) {
+			httpapi.Forbidden(rw)
+			return false
+		}
+	}
+	for _, roleName := range removed {
+		// Removing a role requires the delete permission.
+		if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceOrgRoleAssignment.WithID(roleName).InOrg(organizationID)) {
+			httpapi.Forbidden(rw)
+			return false
+		}
+	}
+	return true
+}
+
+func parseUserIDs(rw http.ResponseWriter, field string, ids []string) ([]uuid.UUID, bool) {
+	parsed := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid user ID.",
+				Validations: []codersdk.ValidationError{{
+					Field:  field,
+					Detail: fmt.Sprintf("%q is not a valid UUID.", id),
+				}},
+			})
+			return nil, false
+		}
+		parsed = append(parsed, userID)
+	}
+	return parsed, true
+}
+
+func (api *API) convertGroupWithMembers(ctx context.Context, group database.Group) (codersdk.Group, error) {
+	members, err := api.Database.GetGroupMembers(ctx, group.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return codersdk.Group{}, xerrors.Errorf("get group members: %w", err)
+	}
+	userIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.ID)
+	}
+	organizationIDsByUserID := map[uuid.UUID][]uuid.UUID{}
+	if len(userIDs) > 0 {
+		rows, err := api.Database.GetOrganizationIDsByMemberIDs(ctx, userIDs)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return codersdk.Group{}, xerrors.Errorf("get organization ids: %w", err)
+		}
+		for _, row := range rows {
+			organizationIDsByUserID[row.UserID] = row.OrganizationIDs
+		}
+	}
+	return convertGroup(group, members, organizationIDsByUserID, api.siteRoleDisplayNames(ctx)), nil
+}
+
+// everyoneGroup is the group of all members of an organization. It isn't
+// stored, its ID is the ID of the organization.
+func everyoneGroup(organizationID uuid.UUID) codersdk.Group {
+	return codersdk.Group{
+		ID:             organizationID,
+		Name:           "Everyone",
+		OrganizationID: organizationID,
+		Roles:          []string{},
+		Members:        []codersdk.User{},
+	}
+}
+
+func convertGroup(group database.Group, members []database.User, organizationIDsByUserID map[uuid.UUID][]uuid.UUID, roleDisplayNames map[string]string) codersdk.Group {
+	roles := group.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	return codersdk.Group{
+		ID:             group.ID,
+		Name:           group.Name,
+		OrganizationID: group.OrganizationID,
+		Roles:          roles,
+		Members:        convertUsers(members, organizationIDsByUserID, roleDisplayNames),
+	}
+}
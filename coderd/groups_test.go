@@ -0,0 +1,413 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestGroups(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "developers",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "developers", group.Name)
+		require.Empty(t, group.Members)
+
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			Name:     "engineers",
+			AddUsers: []string{memberUser.ID.String()},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "engineers", group.Name)
+		require.Len(t, group.Members, 1)
+		require.Equal(t, memberUser.ID, group.Members[0].ID)
+
+		found, err := client.GroupByOrgAndName(ctx, admin.OrganizationID, "engineers")
+		require.NoError(t, err)
+		require.Equal(t, group.ID, found.ID)
+
+		groups, err := client.GroupsByOrganization(ctx, admin.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			RemoveUsers: []string{memberUser.ID.String()},
+		})
+		require.NoError(t, err)
+		require.Empty(t, group.Members)
+
+		err = client.DeleteGroup(ctx, group.ID)
+		require.NoError(t, err)
+		_, err = client.Group(ctx, group.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("AuditLog", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		admin := coderdtest.CreateFirstUser(t, client)
+
+		group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "developers",
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			Name: "engineers",
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateGroupRoles(ctx, group.ID, codersdk.UpdateRoles{
+			Roles: []string{rbac.RoleOrgAdmin(admin.OrganizationID)},
+		})
+		require.NoError(t, err)
+		err = client.DeleteGroup(ctx, group.ID)
+		require.NoError(t, err)
+
+		actions := []database.AuditAction{}
+		for _, alog := range auditor.AuditLogs() {
+			if alog.ResourceType != database.ResourceTypeGroup {
+				continue
+			}
+			require.Equal(t, group.ID, alog.ResourceID)
+			require.Equal(t, admin.OrganizationID, alog.OrganizationID)
+			actions = append(actions, alog.Action)
+		}
+		require.Equal(t, []database.AuditAction{
+			database.AuditActionCreate,
+			database.AuditActionWrite,
+			database.AuditActionWrite,
+			database.AuditActionDelete,
+		}, actions)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{Name: "developers"})
+		require.NoError(t, err)
+		_, err = client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{Name: "developers"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("NotOrganizationMember", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		other, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "other"})
+		require.NoError(t, err)
+		outsider := coderdtest.CreateAnotherUser(t, client, other.ID)
+		outsiderUser, err := outsider.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{Name: "developers"})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{outsiderUser.ID.String()},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+		_, err := member.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{Name: "developers"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Roles", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		check := codersdk.UserAuthorizationRequest{
+			Checks: map[string]codersdk.UserAuthorization{
+				"update-templates": {
+					Object: codersdk.UserAuthorizationObject{
+						ResourceType:   rbac.ResourceTemplate.Type,
+						OrganizationID: admin.OrganizationID.String(),
+					},
+					Action: string(rbac.ActionUpdate),
+				},
+			},
+		}
+		allowed, err := member.CheckPermissions(ctx, check)
+		require.NoError(t, err)
+		require.False(t, allowed["update-templates"])
+
+		group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{
+			Name:  "admins",
+			Roles: []string{rbac.RoleOrgAdmin(admin.OrganizationID)},
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{memberUser.ID.String()},
+		})
+		require.NoError(t, err)
+
+		allowed, err = member.CheckPermissions(ctx, check)
+		require.NoError(t, err)
+		require.True(t, allowed["update-templates"])
+
+		group, err = client.UpdateGroupRoles(ctx, group.ID, codersdk.UpdateRoles{})
+		require.NoError(t, err)
+		require.Empty(t, group.Roles)
+		allowed, err = member.CheckPermissions(ctx, check)
+		require.NoError(t, err)
+		require.False(t, allowed["update-templates"])
+
+		// Only organization roles can be granted to groups.
+		_, err = client.UpdateGroupRoles(ctx, group.ID, codersdk.UpdateRoles{
+			Roles: []string{rbac.RoleAdmin()},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestTemplateACL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GroupAdmin", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+
+		_, err = member.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "updated",
+		})
+		require.Error(t, err)
+
+		group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{Name: "template-admins"})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{memberUser.ID.String()},
+		})
+		require.NoError(t, err)
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			GroupPerms: map[string]codersdk.TemplateRole{
+				group.ID.String(): codersdk.TemplateRoleAdmin,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := client.TemplateACL(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Groups, 2)
+		require.Equal(t, group.ID, acl.Groups[1].Group.ID)
+		require.Equal(t, codersdk.TemplateRoleAdmin, acl.Groups[1].Role)
+
+		updated, err := member.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "updated",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "updated", updated.Description)
+
+		// Revoking the grant removes access.
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			GroupPerms: map[string]codersdk.TemplateRole{
+				group.ID.String(): codersdk.TemplateRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+		acl, err = client.TemplateACL(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Groups, 1)
+		_, err = member.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "again",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Everyone", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+
+		// New templates can be used by every member of the organization.
+		acl, err := client.TemplateACL(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, acl.Users)
+		require.Len(t, acl.Groups, 1)
+		require.Equal(t, admin.OrganizationID, acl.Groups[0].Group.ID)
+		require.Equal(t, "Everyone", acl.Groups[0].Group.Name)
+		require.Equal(t, codersdk.TemplateRoleUse, acl.Groups[0].Role)
+		_, err = member.Template(ctx, template.ID)
+		require.NoError(t, err)
+
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			GroupPerms: map[string]codersdk.TemplateRole{
+				admin.OrganizationID.String(): codersdk.TemplateRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+		_, err = member.Template(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Restricted", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			GroupPerms: map[string]codersdk.TemplateRole{
+				admin.OrganizationID.String(): codersdk.TemplateRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+
+		templates, err := member.TemplatesByOrganization(ctx, admin.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, templates)
+		_, err = member.TemplateVersion(ctx, version.ID)
+		require.Error(t, err)
+		_, err = member.CreateWorkspace(ctx, admin.OrganizationID, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "restricted",
+		})
+		require.Error(t, err)
+
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				memberUser.ID.String(): codersdk.TemplateRoleUse,
+			},
+		})
+		require.NoError(t, err)
+		acl, err := client.TemplateACL(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, memberUser.ID, acl.Users[0].User.ID)
+		require.Equal(t, codersdk.TemplateRoleUse, acl.Users[0].Role)
+
+		templates, err = member.TemplatesByOrganization(ctx, admin.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		_, err = member.TemplateVersion(ctx, version.ID)
+		require.NoError(t, err)
+		workspace := coderdtest.CreateWorkspace(t, member, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+		// "use" doesn't allow changing the template.
+		_, err = member.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "updated",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("NotOrganizationMember", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		other, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "other"})
+		require.NoError(t, err)
+		outsider, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          "outsider@coder.com",
+			Username:       "outsider",
+			Password:       "testpass",
+			OrganizationID: other.ID,
+		})
+		require.NoError(t, err)
+
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				outsider.ID.String(): codersdk.TemplateRoleUse,
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidRole", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		group, err := client.CreateGroup(ctx, admin.OrganizationID, codersdk.CreateGroupRequest{Name: "developers"})
+		require.NoError(t, err)
+
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			GroupPerms: map[string]codersdk.TemplateRole{
+				group.ID.String(): "owner",
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
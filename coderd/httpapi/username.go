@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	usernameValid   = regexp.MustCompile("^[a-zA-Z0-9]+(?:-[a-zA-Z0-9]+)*$")
+	usernameReplace = regexp.MustCompile("[^a-zA-Z0-9-]+")
+)
+
+// UsernameValid returns whether the input string is a valid username.
+func UsernameValid(str string) bool {
+	if len(str) > 32 {
+		return false
+	}
+	if len(str) < 1 {
+		return false
+	}
+	return usernameValid.MatchString(str)
+}
+
+// UsernameFrom returns a best-effort username from the provided string.
+//
+// It first attempts to validate the incoming string, which will
+// be returned if it is valid. Otherwise, invalid characters are
+// replaced with dashes and the result is trimmed to 32 characters.
+// An empty string is returned if nothing valid remains.
+func UsernameFrom(str string) string {
+	if UsernameValid(str) {
+		return str
+	}
+	str = usernameReplace.ReplaceAllString(str, "-")
+	// Collapse repeated dashes, which are invalid.
+	for strings.Contains(str, "--") {
+		str = strings.ReplaceAll(str, "--", "-")
+	}
+	str = strings.Trim(str, "-")
+	if len(str) > 32 {
+		str = strings.TrimRight(str[:32], "-")
+	}
+	return str
+}
@@ -0,0 +1,37 @@
+package httpapi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/httpapi"
+)
+
+func TestUsernameFrom(t *testing.T) {
+	t.Parallel()
+
+	for _, testCase := range []struct {
+		Input    string
+		Expected string
+	}{
+		{"kyle", "kyle"},
+		{"kyle.carberry", "kyle-carberry"},
+		{"kyle@coder.com", "kyle-coder-com"},
+		{"__kyle__", "kyle"},
+		{"kyle..--..carberry", "kyle-carberry"},
+		{"😀", ""},
+		{"abcdefghijklmnopqrstuvwxyz0123456789", "abcdefghijklmnopqrstuvwxyz012345"},
+		{"abcdefghijklmnopqrstuvwxyz01234.6789", "abcdefghijklmnopqrstuvwxyz01234"},
+	} {
+		testCase := testCase
+		t.Run(testCase.Input, func(t *testing.T) {
+			t.Parallel()
+			converted := httpapi.UsernameFrom(testCase.Input)
+			require.Equal(t, testCase.Expected, converted)
+			if converted != "" {
+				require.True(t, httpapi.UsernameValid(converted))
+			}
+		})
+	}
+}
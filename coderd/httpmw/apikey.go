@@ -18,6 +18,7 @@ import (
 
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -32,6 +33,14 @@ func APIKey(r *http.Request) database.APIKey {
 	return apiKey
 }
 
+// APIKeyOptional may return an API key from the ExtractAPIKey handler.
+// Unauthenticated routes can use this to attribute actions when a key
+// happens to be present.
+func APIKeyOptional(r *http.Request) (database.APIKey, bool) {
+	apiKey, ok := r.Context().Value(apiKeyContextKey{}).(database.APIKey)
+	return apiKey, ok
+}
+
 // User roles are the 'subject' field of Authorize()
 type userRolesKey struct{}
 
@@ -49,6 +58,13 @@ func AuthorizationUserRoles(r *http.Request) database.GetAuthorizationUserRolesR
 // This should be extended to support other authentication types in the future.
 type OAuth2Configs struct {
 	Github OAuth2Config
+	OIDC   OAuth2Config
+	// Resync is called when ExtractAPIKey refreshes the OAuth token of an API
+	// key, or extends an API key created by an OAuth login, with the key's
+	// current token. It syncs the user with the identity provider, so changes
+	// made there apply to existing sessions. The request is rejected if it
+	// fails.
+	Resync func(r *http.Request, key database.APIKey, token *oauth2.Token) error
 }
 
 // ExtractAPIKey requires authentication using a valid API key.
@@ -133,17 +149,34 @@ func ExtractAPIKey(db database.Store, oauth *OAuth2Configs, redirectToLogin bool
 				})
 				return
 			}
+			// Scopes are validated here so an invalid scope can't fail
+			// open in handlers that don't authorize.
+			_, err = rbac.ScopePermissions(key.Scopes)
+			if err != nil {
+				write(http.StatusUnauthorized, codersdk.Response{
+					Message: "API key has an invalid scope.",
+					Detail:  err.Error(),
+				})
+				return
+			}
 			now := database.Now()
 			// Tracks if the API key has properties updated!
 			changed := false
+			// Tracks if the user was seen since the key was last used.
+			seen := false
+			// Tracks if the user must be synced with the identity provider.
+			resync := false
+			var oauthToken *oauth2.Token
 
-			if key.LoginType != database.LoginTypePassword {
+			if key.LoginType != database.LoginTypePassword && key.LoginType != database.LoginTypeToken {
 				// Check if the OAuth token is expired!
 				if key.OAuthExpiry.Before(now) && !key.OAuthExpiry.IsZero() {
 					var oauthConfig OAuth2Config
 					switch key.LoginType {
 					case database.LoginTypeGithub:
 						oauthConfig = oauth.Github
+					case database.LoginTypeOIDC:
+						oauthConfig = oauth.OIDC
 					default:
 						write(http.StatusInternalServerError, codersdk.Response{
 							Message: fmt.Sprintf("Unexpected authentication type %q.", key.LoginType),
@@ -168,6 +201,8 @@ func ExtractAPIKey(db database.Store, oauth *OAuth2Configs, redirectToLogin bool
 					key.OAuthExpiry = token.Expiry
 					key.ExpiresAt = token.Expiry
 					changed = true
+					resync = true
+					oauthToken = token
 				}
 			}
 
@@ -196,13 +231,35 @@ func ExtractAPIKey(db database.Store, oauth *OAuth2Configs, redirectToLogin bool
 					Valid: true,
 				}
 				changed = true
+				seen = true
 			}
 			// Only update the ExpiresAt once an hour to prevent database spam.
-			// We extend the ExpiresAt to reduce re-authentication.
+			// We extend the ExpiresAt to reduce re-authentication. Tokens
+			// always expire at the time they were created with.
 			apiKeyLifetime := time.Duration(key.LifetimeSeconds) * time.Second
-			if key.ExpiresAt.Sub(now) <= apiKeyLifetime-time.Hour {
+			if key.LoginType != database.LoginTypeToken && key.ExpiresAt.Sub(now) <= apiKeyLifetime-time.Hour {
 				key.ExpiresAt = now.Add(apiKeyLifetime)
 				changed = true
+				resync = true
+			}
+			// Sessions of OAuth logins are synced with the identity provider
+			// whenever they're extended, so they can't outlive changes there.
+			if resync && (key.LoginType == database.LoginTypeGithub || key.LoginType == database.LoginTypeOIDC) && oauth != nil && oauth.Resync != nil {
+				if oauthToken == nil {
+					oauthToken = &oauth2.Token{
+						AccessToken:  key.OAuthAccessToken,
+						RefreshToken: key.OAuthRefreshToken,
+						Expiry:       key.OAuthExpiry,
+					}
+				}
+				err := oauth.Resync(r, key, oauthToken)
+				if err != nil {
+					write(http.StatusUnauthorized, codersdk.Response{
+						Message: "Could not sync the user with the identity provider.",
+						Detail:  err.Error(),
+					})
+					return
+				}
 			}
 			if changed {
 				err := db.UpdateAPIKeyByID(r.Context(), database.UpdateAPIKeyByIDParams{
@@ -221,6 +278,18 @@ func ExtractAPIKey(db database.Store, oauth *OAuth2Configs, redirectToLogin bool
 					return
 				}
 			}
+			if seen {
+				err := db.UpdateUserLastSeenAt(r.Context(), database.UpdateUserLastSeenAtParams{
+					ID:         key.UserID,
+					LastSeenAt: now,
+				})
+				if err != nil {
+					write(http.StatusInternalServerError, codersdk.Response{
+						Message: fmt.Sprintf("User couldn't update: %s.", err.Error()),
+					})
+					return
+				}
+			}
 
 			// If the key is valid, we also fetch the user roles and status.
 			// The roles are used for RBAC authorize checks, and the status
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/databasefake"
@@ -300,6 +301,10 @@ func TestAPIKey(t *testing.T) {
 
 		require.NotEqual(t, sentAPIKey.LastUsed, gotAPIKey.LastUsed)
 		require.Equal(t, sentAPIKey.ExpiresAt, gotAPIKey.ExpiresAt)
+
+		gotUser, err := db.GetUserByID(r.Context(), user.ID)
+		require.NoError(t, err)
+		require.Equal(t, gotAPIKey.LastUsed, gotUser.LastSeenAt)
 	})
 
 	t.Run("ValidUpdateExpiry", func(t *testing.T) {
@@ -337,6 +342,71 @@ func TestAPIKey(t *testing.T) {
 		require.NotEqual(t, sentAPIKey.ExpiresAt, gotAPIKey.ExpiresAt)
 	})
 
+	t.Run("TokenNotExtended", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db         = databasefake.New()
+			id, secret = randomAPIKeyParts()
+			hashed     = sha256.Sum256([]byte(secret))
+			r          = httptest.NewRequest("GET", "/", nil)
+			rw         = httptest.NewRecorder()
+			user       = createUser(r.Context(), t, db)
+		)
+		r.AddCookie(&http.Cookie{
+			Name:  codersdk.SessionTokenKey,
+			Value: fmt.Sprintf("%s-%s", id, secret),
+		})
+
+		sentAPIKey, err := db.InsertAPIKey(r.Context(), database.InsertAPIKeyParams{
+			ID:           id,
+			HashedSecret: hashed[:],
+			LastUsed:     database.Now(),
+			ExpiresAt:    database.Now().Add(time.Minute),
+			UserID:       user.ID,
+			LoginType:    database.LoginTypeToken,
+		})
+		require.NoError(t, err)
+		httpmw.ExtractAPIKey(db, nil, false)(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		gotAPIKey, err := db.GetAPIKeyByID(r.Context(), id)
+		require.NoError(t, err)
+		require.Equal(t, sentAPIKey.ExpiresAt, gotAPIKey.ExpiresAt)
+	})
+
+	t.Run("InvalidScope", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db         = databasefake.New()
+			id, secret = randomAPIKeyParts()
+			hashed     = sha256.Sum256([]byte(secret))
+			r          = httptest.NewRequest("GET", "/", nil)
+			rw         = httptest.NewRecorder()
+			user       = createUser(r.Context(), t, db)
+		)
+		r.AddCookie(&http.Cookie{
+			Name:  codersdk.SessionTokenKey,
+			Value: fmt.Sprintf("%s-%s", id, secret),
+		})
+
+		_, err := db.InsertAPIKey(r.Context(), database.InsertAPIKeyParams{
+			ID:           id,
+			HashedSecret: hashed[:],
+			LastUsed:     database.Now(),
+			ExpiresAt:    database.Now().Add(time.Hour),
+			UserID:       user.ID,
+			LoginType:    database.LoginTypeToken,
+			Scopes:       []string{"workspace:destroy"},
+		})
+		require.NoError(t, err)
+		httpmw.ExtractAPIKey(db, nil, false)(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
 	t.Run("OAuthNotExpired", func(t *testing.T) {
 		t.Parallel()
 		var (
@@ -421,6 +491,96 @@ func TestAPIKey(t *testing.T) {
 		require.Equal(t, token.AccessToken, gotAPIKey.OAuthAccessToken)
 	})
 
+	t.Run("OAuthRefreshResync", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db         = databasefake.New()
+			id, secret = randomAPIKeyParts()
+			hashed     = sha256.Sum256([]byte(secret))
+			r          = httptest.NewRequest("GET", "/", nil)
+			rw         = httptest.NewRecorder()
+			user       = createUser(r.Context(), t, db)
+		)
+		r.AddCookie(&http.Cookie{
+			Name:  codersdk.SessionTokenKey,
+			Value: fmt.Sprintf("%s-%s", id, secret),
+		})
+
+		_, err := db.InsertAPIKey(r.Context(), database.InsertAPIKeyParams{
+			ID:           id,
+			HashedSecret: hashed[:],
+			LoginType:    database.LoginTypeOIDC,
+			LastUsed:     database.Now(),
+			OAuthExpiry:  database.Now().AddDate(0, 0, -1),
+			UserID:       user.ID,
+		})
+		require.NoError(t, err)
+		token := &oauth2.Token{
+			AccessToken:  "wow",
+			RefreshToken: "moo",
+			Expiry:       database.Now().AddDate(0, 0, 1),
+		}
+		var resynced *oauth2.Token
+		httpmw.ExtractAPIKey(db, &httpmw.OAuth2Configs{
+			OIDC: &oauth2Config{
+				tokenSource: oauth2TokenSource(func() (*oauth2.Token, error) {
+					return token, nil
+				}),
+			},
+			Resync: func(r *http.Request, key database.APIKey, token *oauth2.Token) error {
+				require.Equal(t, user.ID, key.UserID)
+				resynced = token
+				return nil
+			},
+		}, false)(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, token, resynced)
+	})
+
+	t.Run("OAuthExtendResyncFails", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db         = databasefake.New()
+			id, secret = randomAPIKeyParts()
+			hashed     = sha256.Sum256([]byte(secret))
+			r          = httptest.NewRequest("GET", "/", nil)
+			rw         = httptest.NewRecorder()
+			user       = createUser(r.Context(), t, db)
+		)
+		r.AddCookie(&http.Cookie{
+			Name:  codersdk.SessionTokenKey,
+			Value: fmt.Sprintf("%s-%s", id, secret),
+		})
+
+		sentAPIKey, err := db.InsertAPIKey(r.Context(), database.InsertAPIKeyParams{
+			ID:               id,
+			HashedSecret:     hashed[:],
+			LoginType:        database.LoginTypeGithub,
+			LastUsed:         database.Now(),
+			ExpiresAt:        database.Now().Add(time.Minute),
+			LifetimeSeconds:  int64((24 * time.Hour).Seconds()),
+			OAuthAccessToken: "wow",
+			UserID:           user.ID,
+		})
+		require.NoError(t, err)
+		httpmw.ExtractAPIKey(db, &httpmw.OAuth2Configs{
+			Resync: func(r *http.Request, key database.APIKey, token *oauth2.Token) error {
+				require.Equal(t, "wow", token.AccessToken)
+				return xerrors.New("removed from the organization")
+			},
+		}, false)(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+		// The session isn't extended.
+		gotAPIKey, err := db.GetAPIKeyByID(r.Context(), id)
+		require.NoError(t, err)
+		require.Equal(t, sentAPIKey.ExpiresAt, gotAPIKey.ExpiresAt)
+	})
+
 	t.Run("RemoteIPUpdates", func(t *testing.T) {
 		t.Parallel()
 		var (
@@ -0,0 +1,77 @@
+package httpmw_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/database/databasefake"
+	"github.com/coder/coder/coderd/httpmw"
+)
+
+func TestGroupParam(t *testing.T) {
+	t.Parallel()
+
+	setup := func() (*http.Request, chi.Router, database.Store) {
+		db := databasefake.New()
+		rtr := chi.NewRouter()
+		rtr.Use(httpmw.ExtractGroupParam(db))
+		rtr.Get("/", func(rw http.ResponseWriter, r *http.Request) {
+			group := httpmw.GroupParam(r)
+			require.Equal(t, group.OrganizationID.String(), chi.URLParam(r, "organization"))
+			rw.WriteHeader(http.StatusOK)
+		})
+		r := httptest.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, chi.NewRouteContext()))
+		return r, rtr, db
+	}
+
+	t.Run("None", func(t *testing.T) {
+		t.Parallel()
+		r, rtr, _ := setup()
+		rw := httptest.NewRecorder()
+		rtr.ServeHTTP(rw, r)
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		r, rtr, _ := setup()
+		chi.RouteContext(r.Context()).URLParams.Add("group", uuid.NewString())
+		rw := httptest.NewRecorder()
+		rtr.ServeHTTP(rw, r)
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		t.Parallel()
+		r, rtr, db := setup()
+		group, err := db.InsertGroup(context.Background(), database.InsertGroupParams{
+			ID:             uuid.New(),
+			Name:           "developers",
+			OrganizationID: uuid.New(),
+			CreatedAt:      database.Now(),
+			UpdatedAt:      database.Now(),
+		})
+		require.NoError(t, err)
+		chi.RouteContext(r.Context()).URLParams.Add("group", group.ID.String())
+		rw := httptest.NewRecorder()
+		rtr.ServeHTTP(rw, r)
+
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
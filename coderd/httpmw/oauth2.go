@@ -2,6 +2,8 @@ package httpmw
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -23,6 +25,17 @@ type oauth2StateKey struct{}
 type OAuth2State struct {
 	Token    *oauth2.Token
 	Redirect string
+	// Nonce was sent with the authorization request. OpenID Connect
+	// providers include it in the ID token.
+	Nonce string
+}
+
+// oauth2Nonce derives the nonce sent with an authorization request from its
+// state. The state is bound to the browser by a cookie, so an ID token is only
+// accepted by the browser that started the login.
+func oauth2Nonce(state string) string {
+	hash := sha256.Sum256([]byte("nonce:" + state))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
 }
 
 // OAuth2Config exposes a subset of *oauth2.Config functions for easier testing.
@@ -87,7 +100,9 @@ func ExtractOAuth2(config OAuth2Config) func(http.Handler) http.Handler {
 					SameSite: http.SameSiteLaxMode,
 				})
 
-				http.Redirect(rw, r, config.AuthCodeURL(state, oauth2.AccessTypeOffline), http.StatusTemporaryRedirect)
+				// Providers that don't support OpenID Connect ignore the nonce.
+				nonce := oauth2.SetAuthURLParam("nonce", oauth2Nonce(state))
+				http.Redirect(rw, r, config.AuthCodeURL(state, oauth2.AccessTypeOffline, nonce), http.StatusTemporaryRedirect)
 				return
 			}
 
@@ -130,6 +145,7 @@ func ExtractOAuth2(config OAuth2Config) func(http.Handler) http.Handler {
 			ctx := context.WithValue(r.Context(), oauth2StateKey{}, OAuth2State{
 				Token:    oauthToken,
 				Redirect: redirect,
+				Nonce:    oauth2Nonce(state),
 			})
 			next.ServeHTTP(rw, r.WithContext(ctx))
 		})
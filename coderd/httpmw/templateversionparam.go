@@ -24,7 +24,8 @@ func TemplateVersionParam(r *http.Request) database.TemplateVersion {
 	return templateVersion
 }
 
-// ExtractTemplateVersionParam grabs template version from the "templateversion" URL parameter.
+// ExtractTemplateVersionParam grabs template version from the "templateversion" URL parameter,
+// and the template it belongs to.
 func ExtractTemplateVersionParam(db database.Store) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
@@ -45,7 +46,23 @@ func ExtractTemplateVersionParam(db database.Store) func(http.Handler) http.Hand
 				return
 			}
 
+			// Versions are authorized with the access lists of their
+			// template. TemplateParam returns an empty template for versions
+			// that aren't attached to one.
+			var template database.Template
+			if templateVersion.TemplateID.Valid {
+				template, err = db.GetTemplateByID(r.Context(), templateVersion.TemplateID.UUID)
+				if err != nil {
+					httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+						Message: "Internal error fetching template.",
+						Detail:  err.Error(),
+					})
+					return
+				}
+			}
+
 			ctx := context.WithValue(r.Context(), templateVersionParamContextKey{}, templateVersion)
+			ctx = context.WithValue(ctx, templateParamContextKey{}, template)
 			chi.RouteContext(ctx).URLParams.Add("organization", templateVersion.OrganizationID.String())
 			next.ServeHTTP(rw, r.WithContext(ctx))
 		})
@@ -2,6 +2,9 @@ package coderd
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -10,17 +13,213 @@ import (
 
 	"github.com/coder/coder/coderd/rbac"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/codersdk"
 )
 
+// organizationMembers lists the members of an organization with their roles.
+func (api *API) organizationMembers(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+
+	members, err := api.Database.GetOrganizationMembersByOrganizationID(r.Context(), organization.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization members.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	members = AuthorizeFilter(api, r, rbac.ActionRead, members)
+
+	userIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	users, err := api.Database.GetUsersByIDs(r.Context(), userIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching users.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	organizationIDsByMemberIDsRows, err := api.Database.GetOrganizationIDsByMemberIDs(r.Context(), userIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user organizations.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	organizationIDsByUserID := map[uuid.UUID][]uuid.UUID{}
+	for _, organizationIDsByMemberIDsRow := range organizationIDsByMemberIDsRows {
+		organizationIDsByUserID[organizationIDsByMemberIDsRow.UserID] = organizationIDsByMemberIDsRow.OrganizationIDs
+	}
+
+	converted := make([]codersdk.OrganizationMemberWithUser, 0, len(members))
+	for _, member := range members {
+		user := findUser(member.UserID, users)
+		if user == nil {
+			continue
+		}
+		converted = append(converted, codersdk.OrganizationMemberWithUser{
+			User:      convertUser(*user, organizationIDsByUserID[user.ID], api.siteRoleDisplayNames(r.Context())),
+			Roles:     api.convertOrganizationMemberRoles(r.Context(), member.Roles),
+			CreatedAt: member.CreatedAt,
+		})
+	}
+	httpapi.Write(rw, http.StatusOK, converted)
+}
+
+// postOrganizationMember adds an existing user to an organization.
+func (api *API) postOrganizationMember(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+	organization := httpmw.OrganizationParam(r)
+	aReq, commitAudit := audit.InitRequest[database.OrganizationMember](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceOrganizationMember.InOrg(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	_, err := api.Database.GetOrganizationMemberByUserID(r.Context(), database.GetOrganizationMemberByUserIDParams{
+		OrganizationID: organization.ID,
+		UserID:         user.ID,
+	})
+	if err == nil {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("User %q is already a member of this organization.", user.Username),
+		})
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization member.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	member, err := api.Database.InsertOrganizationMember(r.Context(), database.InsertOrganizationMemberParams{
+		OrganizationID: organization.ID,
+		UserID:         user.ID,
+		CreatedAt:      database.Now(),
+		UpdatedAt:      database.Now(),
+		Roles:          []string{},
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting organization member.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = member
+
+	httpapi.Write(rw, http.StatusCreated, convertOrganizationMember(member))
+}
+
+// deleteOrganizationMember removes a user from an organization. Users that
+// own workspaces in the organization can't be removed.
+func (api *API) deleteOrganizationMember(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+	organization := httpmw.OrganizationParam(r)
+	member := httpmw.OrganizationMemberParam(r)
+	apiKey := httpmw.APIKey(r)
+	aReq, commitAudit := audit.InitRequest[database.OrganizationMember](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDelete,
+	})
+	defer commitAudit()
+	aReq.Old = member
+
+	if !api.Authorize(r, rbac.ActionDelete, member) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if apiKey.UserID == member.UserID {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot remove yourself from an organization.",
+		})
+		return
+	}
+
+	workspaces, err := api.Database.GetWorkspaces(r.Context(), database.GetWorkspacesParams{
+		OwnerID:        user.ID,
+		OrganizationID: organization.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(workspaces) > 0 {
+		httpapi.Write(rw, http.StatusPreconditionFailed, codersdk.Response{
+			Message: fmt.Sprintf("User %q must delete or transfer their workspaces before leaving the organization.", user.Username),
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(store database.Store) error {
+		// Groups grant roles in the organization, so the user must leave
+		// them too.
+		err := store.DeleteGroupMembersByOrganizationAndUser(r.Context(), database.DeleteGroupMembersByOrganizationAndUserParams{
+			UserID:         user.ID,
+			OrganizationID: organization.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete group members: %w", err)
+		}
+		err = store.DeleteOrganizationMember(r.Context(), database.DeleteOrganizationMemberParams{
+			OrganizationID: organization.ID,
+			UserID:         user.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete organization member: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error removing organization member.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "User has been removed from the organization!",
+	})
+}
+
 func (api *API) putMemberRoles(rw http.ResponseWriter, r *http.Request) {
 	user := httpmw.UserParam(r)
 	organization := httpmw.OrganizationParam(r)
 	member := httpmw.OrganizationMemberParam(r)
 	apiKey := httpmw.APIKey(r)
+	aReq, commitAudit := audit.InitRequest[database.OrganizationMember](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = member
 
 	if apiKey.UserID == member.UserID {
 		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
@@ -63,38 +262,63 @@ func (api *API) putMemberRoles(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	aReq.New = updatedUser
 
 	httpapi.Write(rw, http.StatusOK, convertOrganizationMember(updatedUser))
 }
 
 func (api *API) updateOrganizationMemberRoles(ctx context.Context, args database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
+	err := api.validateOrganizationRoles(ctx, args.OrgID, args.GrantedRoles)
+	if err != nil {
+		return database.OrganizationMember{}, err
+	}
+
+	updatedUser, err := api.Database.UpdateMemberRoles(ctx, args)
+	if err != nil {
+		return database.OrganizationMember{}, xerrors.Errorf("Update site roles: %w", err)
+	}
+	return updatedUser, nil
+}
+
+// validateOrganizationRoles ensures the roles exist and are scoped to the
+// organization.
+func (api *API) validateOrganizationRoles(ctx context.Context, orgID uuid.UUID, roles []string) error {
 	// Enforce only site wide roles
-	for _, r := range args.GrantedRoles {
+	for _, r := range roles {
 		// Must be an org role for the org in the args
-		orgID, ok := rbac.IsOrgRole(r)
+		roleOrgID, ok := rbac.IsOrgRole(r)
 		if !ok {
-			return database.OrganizationMember{}, xerrors.Errorf("must only update organization roles")
+			return xerrors.Errorf("must only update organization roles")
 		}
 
-		roleOrg, err := uuid.Parse(orgID)
+		roleOrg, err := uuid.Parse(roleOrgID)
 		if err != nil {
-			return database.OrganizationMember{}, xerrors.Errorf("Role must have proper UUIDs for organization, %q does not", r)
+			return xerrors.Errorf("Role must have proper UUIDs for organization, %q does not", r)
 		}
 
-		if roleOrg != args.OrgID {
-			return database.OrganizationMember{}, xerrors.Errorf("Must only pass roles for org %q", args.OrgID.String())
+		if roleOrg != orgID {
+			return xerrors.Errorf("Must only pass roles for org %q", orgID.String())
 		}
 
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.OrganizationMember{}, xerrors.Errorf("%q is not a supported role", r)
+		if _, err := roleByName(ctx, api.Database, r); err != nil {
+			return xerrors.Errorf("%q is not a supported role", r)
 		}
 	}
+	return nil
+}
 
-	updatedUser, err := api.Database.UpdateMemberRoles(ctx, args)
-	if err != nil {
-		return database.OrganizationMember{}, xerrors.Errorf("Update site roles: %w", err)
+// convertOrganizationMemberRoles returns the roles of a member that are shown
+// to users, looking up the display names of custom roles.
+func (api *API) convertOrganizationMemberRoles(ctx context.Context, roleNames []string) []codersdk.Role {
+	roles := make([]rbac.Role, 0, len(roleNames))
+	for _, roleName := range roleNames {
+		role, err := roleByName(ctx, api.Database, roleName)
+		if err != nil {
+			role = rbac.Role{Name: roleName, DisplayName: roleName}
+		}
+		roles = append(roles, role)
 	}
-	return updatedUser, nil
+	return convertRoles(roles)
 }
 
 func convertOrganizationMember(mem database.OrganizationMember) codersdk.OrganizationMember {
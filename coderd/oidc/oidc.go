@@ -0,0 +1,105 @@
+// Package oidc implements the parts of OpenID Connect required to log in
+// users: provider discovery and ID token verification.
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"golang.org/x/xerrors"
+)
+
+// ScopeOpenID must be requested for a provider to return an ID token.
+const ScopeOpenID = oidc.ScopeOpenID
+
+// supportedAlgorithms are the signing algorithms accepted for ID tokens.
+// Symmetric algorithms are excluded, since the signing key would have to be
+// the client secret.
+var supportedAlgorithms = []string{
+	oidc.RS256, oidc.RS384, oidc.RS512,
+	oidc.ES256, oidc.ES384, oidc.ES512,
+	oidc.PS256, oidc.PS384, oidc.PS512,
+}
+
+// IDToken is a verified ID token.
+type IDToken = oidc.IDToken
+
+// Provider is an OpenID Connect provider.
+type Provider struct {
+	Issuer string
+
+	provider *oidc.Provider
+}
+
+// Discover fetches the configuration of the provider at issuer from its
+// "/.well-known/openid-configuration" document. The issuer in the document
+// must exactly match issuer. If client is nil, http.DefaultClient is used
+// for discovery and to fetch the provider's signing keys.
+func Discover(ctx context.Context, client *http.Client, issuer string) (*Provider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, client), issuer)
+	if err != nil {
+		return nil, xerrors.Errorf("discover provider: %w", err)
+	}
+	return &Provider{
+		Issuer:   issuer,
+		provider: provider,
+	}, nil
+}
+
+// Endpoint returns the OAuth2 endpoints of the provider.
+func (p *Provider) Endpoint() oauth2.Endpoint {
+	return p.provider.Endpoint()
+}
+
+// Verifier returns a verifier for ID tokens issued to clientID. The
+// provider's signing keys are fetched again when a token is signed by an
+// unknown key, since providers rotate their keys.
+func (p *Provider) Verifier(clientID string) *Verifier {
+	return &Verifier{
+		verifier: p.provider.Verifier(&oidc.Config{
+			ClientID:             clientID,
+			SupportedSigningAlgs: supportedAlgorithms,
+		}),
+	}
+}
+
+// Verifier verifies ID tokens issued by a provider.
+type Verifier struct {
+	verifier *oidc.IDTokenVerifier
+}
+
+// Verify parses a raw ID token, verifies its signature, and checks that it
+// was issued by the provider for the client, hasn't expired, and contains
+// nonce. The nonce is sent with the authorization request, so a token that
+// was issued for another login can't be replayed.
+func (v *Verifier) Verify(ctx context.Context, rawIDToken, nonce string) (*IDToken, error) {
+	if nonce == "" {
+		return nil, xerrors.New("nonce must be provided")
+	}
+	idToken, err := v.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, xerrors.Errorf("verify id token: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(nonce)) != 1 {
+		return nil, xerrors.New("id token nonce mismatched")
+	}
+	return idToken, nil
+}
+
+// VerifyRefreshed parses and verifies a raw ID token returned when a token is
+// refreshed. It's checked like Verify, except for the nonce: refreshed ID
+// tokens aren't issued for an authorization request, so they don't contain
+// one. Callers must check that the token is about the user who logged in.
+func (v *Verifier) VerifyRefreshed(ctx context.Context, rawIDToken string) (*IDToken, error) {
+	idToken, err := v.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, xerrors.Errorf("verify id token: %w", err)
+	}
+	return idToken, nil
+}
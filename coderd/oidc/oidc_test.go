@@ -0,0 +1,183 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/oidc"
+	"github.com/coder/coder/coderd/oidc/oidctest"
+)
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		issuer := oidctest.NewIssuer(t)
+		provider, err := oidc.Discover(context.Background(), nil, issuer.URL())
+		require.NoError(t, err)
+		require.Equal(t, issuer.URL(), provider.Issuer)
+		require.Equal(t, issuer.URL()+"/authorize", provider.Endpoint().AuthURL)
+		require.Equal(t, issuer.URL()+"/token", provider.Endpoint().TokenURL)
+	})
+
+	t.Run("IssuerMismatch", func(t *testing.T) {
+		t.Parallel()
+		issuer := oidctest.NewIssuer(t)
+		_, err := oidc.Discover(context.Background(), nil, issuer.URL()+"/")
+		require.ErrorContains(t, err, "did not match")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(srv.Close)
+		_, err := oidc.Discover(context.Background(), nil, srv.URL)
+		require.Error(t, err)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*oidctest.Issuer, *oidc.Verifier) {
+		issuer := oidctest.NewIssuer(t)
+		provider, err := oidc.Discover(context.Background(), nil, issuer.URL())
+		require.NoError(t, err)
+		return issuer, provider.Verifier(oidctest.ClientID)
+	}
+	const nonce = "nonce"
+	claims := func(issuer *oidctest.Issuer) map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   issuer.URL(),
+			"aud":   oidctest.ClientID,
+			"sub":   "kyle",
+			"email": "kyle@coder.com",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": nonce,
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		issuer, verifier := setup(t)
+		token, err := verifier.Verify(context.Background(), issuer.Sign(claims(issuer)), nonce)
+		require.NoError(t, err)
+		require.Equal(t, "kyle", token.Subject)
+		var custom struct {
+			Email string `json:"email"`
+		}
+		require.NoError(t, token.Claims(&custom))
+		require.Equal(t, "kyle@coder.com", custom.Email)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		t.Parallel()
+		issuer, verifier := setup(t)
+		expired := claims(issuer)
+		expired["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err := verifier.Verify(context.Background(), issuer.Sign(expired), nonce)
+		require.Error(t, err)
+	})
+
+	t.Run("NoExpiry", func(t *testing.T) {
+		t.Parallel()
+		issuer, verifier := setup(t)
+		noExpiry := claims(issuer)
+		delete(noExpiry, "exp")
+		_, err := verifier.Verify(context.Background(), issuer.Sign(noExpiry), nonce)
+		require.Error(t, err)
+	})
+
+	t.Run("WrongAudience", func(t *testing.T) {
+		t.Parallel()
+		issuer, verifier := setup(t)
+		wrongAudience := claims(issuer)
+		wrongAudience["aud"] = "someone-else"
+		_, err := verifier.Verify(context.Background(), issuer.Sign(wrongAudience), nonce)
+		require.Error(t, err)
+	})
+
+	t.Run("WrongIssuer", func(t *testing.T) {
+		t.Parallel()
+		issuer, verifier := setup(t)
+		wrongIssuer := claims(issuer)
+		wrongIssuer["iss"] = "https://example.com"
+		_, err := verifier.Verify(context.Background(), issuer.Sign(wrongIssuer), nonce)
+		require.Error(t, err)
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		t.Parallel()
+		issuer, verifier := setup(t)
+		other := oidctest.NewIssuer(t)
+		_, err := verifier.Verify(context.Background(), other.Sign(claims(issuer)), nonce)
+		require.Error(t, err)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		t.Parallel()
+		_, verifier := setup(t)
+		_, err := verifier.Verify(context.Background(), "not-a-token", nonce)
+		require.Error(t, err)
+	})
+
+	t.Run("WrongNonce", func(t *testing.T) {
+		t.Parallel()
+		issuer, verifier := setup(t)
+		_, err := verifier.Verify(context.Background(), issuer.Sign(claims(issuer)), "other")
+		require.ErrorContains(t, err, "nonce")
+	})
+
+	t.Run("NoNonce", func(t *testing.T) {
+		t.Parallel()
+		issuer, verifier := setup(t)
+		noNonce := claims(issuer)
+		delete(noNonce, "nonce")
+		_, err := verifier.Verify(context.Background(), issuer.Sign(noNonce), nonce)
+		require.ErrorContains(t, err, "nonce")
+		_, err = verifier.Verify(context.Background(), issuer.Sign(noNonce), "")
+		require.ErrorContains(t, err, "nonce")
+	})
+
+	t.Run("RotatedKey", func(t *testing.T) {
+		t.Parallel()
+		issuer, verifier := setup(t)
+		_, err := verifier.Verify(context.Background(), issuer.Sign(claims(issuer)), nonce)
+		require.NoError(t, err)
+
+		// Keys are fetched again when a token is signed by an unknown key.
+		issuer.RotateKey()
+		_, err = verifier.Verify(context.Background(), issuer.Sign(claims(issuer)), nonce)
+		require.NoError(t, err)
+	})
+}
+
+func TestVerifyRefreshed(t *testing.T) {
+	t.Parallel()
+
+	issuer := oidctest.NewIssuer(t)
+	provider, err := oidc.Discover(context.Background(), nil, issuer.URL())
+	require.NoError(t, err)
+	verifier := provider.Verifier(oidctest.ClientID)
+	claims := map[string]interface{}{
+		"iss": issuer.URL(),
+		"aud": oidctest.ClientID,
+		"sub": "kyle",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+
+	// Refreshed tokens don't contain a nonce.
+	token, err := verifier.VerifyRefreshed(context.Background(), issuer.Sign(claims))
+	require.NoError(t, err)
+	require.Equal(t, "kyle", token.Subject)
+
+	other := oidctest.NewIssuer(t)
+	_, err = verifier.VerifyRefreshed(context.Background(), other.Sign(claims))
+	require.Error(t, err)
+}
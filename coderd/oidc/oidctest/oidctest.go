@@ -0,0 +1,206 @@
+// Package oidctest provides an in-process OpenID Connect issuer for tests.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// ClientID is the client that ID tokens are issued to by default.
+const ClientID = "coder"
+
+// Issuer is a fake OpenID Connect provider. It authorizes every request,
+// issuing ID tokens that contain the claims set with SetClaims and the nonce
+// of the authorization request.
+type Issuer struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mutex  sync.Mutex
+	key    *rsa.PrivateKey
+	keyID  string
+	claims map[string]interface{}
+	// expiresIn is how long access tokens are valid for.
+	expiresIn time.Duration
+	// nonces are the nonces of authorization requests by code.
+	nonces map[string]string
+}
+
+// NewIssuer starts a fake issuer that's closed when the test completes.
+func NewIssuer(t *testing.T) *Issuer {
+	t.Helper()
+
+	issuer := &Issuer{
+		t:         t,
+		claims:    map[string]interface{}{},
+		expiresIn: time.Hour,
+		nonces:    map[string]string{},
+	}
+	issuer.RotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("/authorize", issuer.authorize)
+	mux.HandleFunc("/token", issuer.token)
+	mux.HandleFunc("/keys", issuer.keys)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+// URL returns the issuer identifier, which is also the base URL of the
+// issuer.
+func (i *Issuer) URL() string {
+	return i.server.URL
+}
+
+// SetClaims sets the claims of ID tokens issued by the token endpoint. The
+// "iss", "aud", "iat", "exp" and "nonce" claims are filled in unless they're
+// set.
+func (i *Issuer) SetClaims(claims map[string]interface{}) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.claims = claims
+}
+
+// SetTokenExpiry sets how long access tokens issued by the token endpoint are
+// valid for. Tokens issued with a negative duration have already expired, so
+// clients refresh them the next time they're used.
+func (i *Issuer) SetTokenExpiry(expiresIn time.Duration) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.expiresIn = expiresIn
+}
+
+// RotateKey replaces the signing key. Tokens signed by the previous key can't
+// be verified anymore.
+func (i *Issuer) RotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(i.t, err)
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.key = key
+	i.keyID = uuid.NewString()
+}
+
+// Sign returns an ID token containing claims exactly as provided, signed by
+// the issuer's current key.
+func (i *Issuer) Sign(claims map[string]interface{}) string {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.sign(claims)
+}
+
+func (i *Issuer) sign(claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.RS256,
+		Key: jose.JSONWebKey{
+			Key:   i.key,
+			KeyID: i.keyID,
+		},
+	}, nil)
+	require.NoError(i.t, err)
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(i.t, err)
+	return token
+}
+
+func (i *Issuer) discovery(rw http.ResponseWriter, _ *http.Request) {
+	writeJSON(rw, map[string]string{
+		"issuer":                 i.URL(),
+		"authorization_endpoint": i.URL() + "/authorize",
+		"token_endpoint":         i.URL() + "/token",
+		"jwks_uri":               i.URL() + "/keys",
+	})
+}
+
+// authorize immediately redirects back to the client with a code.
+func (i *Issuer) authorize(rw http.ResponseWriter, r *http.Request) {
+	redirect, err := url.Parse(r.URL.Query().Get("redirect_uri"))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	code := uuid.NewString()
+	i.mutex.Lock()
+	i.nonces[code] = r.URL.Query().Get("nonce")
+	i.mutex.Unlock()
+
+	query := redirect.Query()
+	query.Set("code", code)
+	query.Set("state", r.URL.Query().Get("state"))
+	redirect.RawQuery = query.Encode()
+	http.Redirect(rw, r, redirect.String(), http.StatusTemporaryRedirect)
+}
+
+func (i *Issuer) token(rw http.ResponseWriter, r *http.Request) {
+	clientID, _, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.FormValue("client_id")
+	}
+
+	i.mutex.Lock()
+	nonce := i.nonces[r.FormValue("code")]
+	delete(i.nonces, r.FormValue("code"))
+	claims := make(map[string]interface{}, len(i.claims)+5)
+	for key, value := range i.claims {
+		claims[key] = value
+	}
+	now := time.Now()
+	defaults := map[string]interface{}{
+		"iss": i.URL(),
+		"aud": clientID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	if nonce != "" {
+		defaults["nonce"] = nonce
+	}
+	for key, value := range defaults {
+		if _, ok := claims[key]; !ok {
+			claims[key] = value
+		}
+	}
+	idToken := i.sign(claims)
+	expiresIn := i.expiresIn
+	i.mutex.Unlock()
+
+	writeJSON(rw, map[string]interface{}{
+		"access_token":  "access-token",
+		"refresh_token": "refresh-token",
+		"token_type":    "Bearer",
+		"expires_in":    int64(expiresIn.Seconds()),
+		"id_token":      idToken,
+	})
+}
+
+func (i *Issuer) keys(rw http.ResponseWriter, _ *http.Request) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	writeJSON(rw, jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{
+			Key:       i.key.Public(),
+			KeyID:     i.keyID,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}},
+	})
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(v)
+}
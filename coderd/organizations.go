@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
@@ -29,8 +31,180 @@ func (api *API) organization(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(rw, http.StatusOK, convertOrganization(organization))
 }
 
+// organizations lists the organizations the user can read.
+func (api *API) organizations(rw http.ResponseWriter, r *http.Request) {
+	organizations, err := api.Database.GetOrganizations(r.Context())
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organizations.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	organizations = AuthorizeFilter(api, r, rbac.ActionRead, organizations)
+	sort.Slice(organizations, func(i, j int) bool {
+		return organizations[i].Name < organizations[j].Name
+	})
+
+	publicOrganizations := make([]codersdk.Organization, 0, len(organizations))
+	for _, organization := range organizations {
+		publicOrganizations = append(publicOrganizations, convertOrganization(organization))
+	}
+	httpapi.Write(rw, http.StatusOK, publicOrganizations)
+}
+
+func (api *API) patchOrganization(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	aReq, commitAudit := audit.InitRequest[database.Organization](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = organization
+
+	if !api.Authorize(r, rbac.ActionUpdate, organization) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.UpdateOrganizationRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	existing, err := api.Database.GetOrganizationByName(r.Context(), req.Name)
+	if err == nil && existing.ID != organization.ID {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: "Organization already exists with that name.",
+		})
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: fmt.Sprintf("Internal error fetching organization %q.", req.Name),
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	updated, err := api.Database.UpdateOrganizationByID(r.Context(), database.UpdateOrganizationByIDParams{
+		ID:        organization.ID,
+		Name:      req.Name,
+		UpdatedAt: database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating organization.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+
+	httpapi.Write(rw, http.StatusOK, convertOrganization(updated))
+}
+
+func (api *API) deleteOrganization(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	aReq, commitAudit := audit.InitRequest[database.Organization](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDelete,
+	})
+	defer commitAudit()
+	aReq.Old = organization
+
+	if !api.Authorize(r, rbac.ActionRead, organization) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	// Like creating one, deleting an organization requires the site wide
+	// permission.
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceOrganization.WithID(organization.ID.String())) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	templates, err := api.Database.GetTemplatesWithFilter(r.Context(), database.GetTemplatesWithFilterParams{
+		OrganizationID: organization.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching templates.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(templates) > 0 {
+		httpapi.Write(rw, http.StatusPreconditionFailed, codersdk.Response{
+			Message: "All templates must be deleted before an organization can be removed.",
+		})
+		return
+	}
+	workspaces, err := api.Database.GetWorkspaces(r.Context(), database.GetWorkspacesParams{
+		OrganizationID: organization.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(workspaces) > 0 {
+		httpapi.Write(rw, http.StatusPreconditionFailed, codersdk.Response{
+			Message: "All workspaces must be deleted before an organization can be removed.",
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(store database.Store) error {
+		// Deleted workspaces are kept around, but would stop the
+		// organization from being deleted.
+		err := store.DeleteDeletedWorkspacesByOrganizationID(r.Context(), organization.ID)
+		if err != nil {
+			return xerrors.Errorf("delete deleted workspaces: %w", err)
+		}
+		err = store.DeleteOrganizationByID(r.Context(), organization.ID)
+		if err != nil {
+			return xerrors.Errorf("delete organization: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting organization.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "Organization has been deleted!",
+	})
+}
+
 func (api *API) postOrganizations(rw http.ResponseWriter, r *http.Request) {
 	apiKey := httpmw.APIKey(r)
+	aReq, commitAudit := audit.InitRequest[database.Organization](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
 	// Create organization uses the organization resource without an OrgID.
 	// This means you need the site wide permission to make a new organization.
 	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceOrganization) {
@@ -90,6 +264,7 @@ func (api *API) postOrganizations(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	aReq.New = organization
 
 	httpapi.Write(rw, http.StatusCreated, convertOrganization(organization))
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -85,3 +86,218 @@ func TestPostOrganizationsByUser(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestOrganizations(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	org, err := client.CreateOrganization(context.Background(), codersdk.CreateOrganizationRequest{
+		Name: "another",
+	})
+	require.NoError(t, err)
+
+	orgs, err := client.Organizations(context.Background())
+	require.NoError(t, err)
+	require.Len(t, orgs, 2)
+	orgs, err = client.OrganizationsByUser(context.Background(), codersdk.Me)
+	require.NoError(t, err)
+	require.Len(t, orgs, 2)
+
+	// Members only see the organizations they belong to.
+	orgs, err = member.Organizations(context.Background())
+	require.NoError(t, err)
+	require.Len(t, orgs, 1)
+	require.NotEqual(t, org.ID, orgs[0].ID)
+}
+
+func TestPatchOrganization(t *testing.T) {
+	t.Parallel()
+	t.Run("Rename", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		org, err := client.UpdateOrganization(context.Background(), user.OrganizationID, codersdk.UpdateOrganizationRequest{
+			Name: "renamed",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "renamed", org.Name)
+		_, err = client.OrganizationByName(context.Background(), codersdk.Me, "renamed")
+		require.NoError(t, err)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		org, err := client.CreateOrganization(context.Background(), codersdk.CreateOrganizationRequest{
+			Name: "another",
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateOrganization(context.Background(), user.OrganizationID, codersdk.UpdateOrganizationRequest{
+			Name: org.Name,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err := member.UpdateOrganization(context.Background(), user.OrganizationID, codersdk.UpdateOrganizationRequest{
+			Name: "renamed",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
+func TestDeleteOrganization(t *testing.T) {
+	t.Parallel()
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		org, err := client.CreateOrganization(context.Background(), codersdk.CreateOrganizationRequest{
+			Name: "another",
+		})
+		require.NoError(t, err)
+		_, err = client.CreateGroup(context.Background(), org.ID, codersdk.CreateGroupRequest{
+			Name: "developers",
+		})
+		require.NoError(t, err)
+
+		err = client.DeleteOrganization(context.Background(), org.ID)
+		require.NoError(t, err)
+		_, err = client.Organization(context.Background(), org.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("TemplatesRemain", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		err := client.DeleteOrganization(context.Background(), user.OrganizationID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusPreconditionFailed, apiErr.StatusCode())
+
+		err = client.DeleteTemplate(context.Background(), template.ID)
+		require.NoError(t, err)
+		err = client.DeleteOrganization(context.Background(), user.OrganizationID)
+		require.NoError(t, err)
+	})
+
+	t.Run("NotSiteAdmin", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		orgAdmin := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleOrgAdmin(user.OrganizationID))
+
+		err := orgAdmin.DeleteOrganization(context.Background(), user.OrganizationID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+func TestOrganizationMembers(t *testing.T) {
+	t.Parallel()
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		orgAdmin := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleOrgAdmin(user.OrganizationID))
+		orgAdminUser, err := orgAdmin.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+
+		members, err := orgAdmin.OrganizationMembers(context.Background(), user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		require.Equal(t, user.UserID, members[0].User.ID)
+		require.Equal(t, orgAdminUser.ID, members[1].User.ID)
+		require.Len(t, members[1].Roles, 1)
+		require.Equal(t, rbac.RoleOrgAdmin(user.OrganizationID), members[1].Roles[0].Name)
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		memberUser, err := member.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		org, err := client.CreateOrganization(context.Background(), codersdk.CreateOrganizationRequest{
+			Name: "another",
+		})
+		require.NoError(t, err)
+
+		added, err := client.AddOrganizationMember(context.Background(), org.ID, memberUser.Username)
+		require.NoError(t, err)
+		require.Equal(t, memberUser.ID, added.UserID)
+		_, err = member.Organization(context.Background(), org.ID)
+		require.NoError(t, err)
+
+		_, err = client.AddOrganizationMember(context.Background(), org.ID, memberUser.Username)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		memberUser, err := member.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		group, err := client.CreateGroup(context.Background(), user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "developers",
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(context.Background(), group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{memberUser.ID.String()},
+		})
+		require.NoError(t, err)
+
+		err = client.RemoveOrganizationMember(context.Background(), user.OrganizationID, memberUser.Username)
+		require.NoError(t, err)
+		members, err := client.OrganizationMembers(context.Background(), user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		group, err = client.Group(context.Background(), group.ID)
+		require.NoError(t, err)
+		require.Empty(t, group.Members)
+		_, err = member.Organization(context.Background(), user.OrganizationID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("RemoveWorkspaceOwner", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		workspace := coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+
+		err := client.RemoveOrganizationMember(context.Background(), user.OrganizationID, workspace.OwnerName)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusPreconditionFailed, apiErr.StatusCode())
+	})
+}
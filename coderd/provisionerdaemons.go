@@ -22,6 +22,7 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/coderd/autobuild/executor"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbtypes"
 	"github.com/coder/coder/coderd/httpapi"
@@ -637,6 +638,13 @@ func (server *provisionerdServer) CompleteJob(ctx context.Context, completed *pr
 				if workspace.Ttl.Valid {
 					workspaceDeadline = now.Add(time.Duration(workspace.Ttl.Int64))
 				}
+				if workspaceBuild.Transition == database.WorkspaceTransitionStart {
+					template, err := db.GetTemplateByID(ctx, workspace.TemplateID)
+					if err != nil {
+						return xerrors.Errorf("get workspace template: %w", err)
+					}
+					workspaceDeadline = executor.Deadline(template, workspace, workspaceDeadline, now)
+				}
 			} else {
 				// Huh? Did the workspace get deleted?
 				// In any case, since this is just for the TTL, try and continue anyway.
@@ -0,0 +1,154 @@
+// Package quota limits the workspaces of users and organizations.
+package quota
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/database"
+)
+
+// ErrLocked is returned by TryCheck when another transaction is building a
+// workspace for the same owner or organization.
+var ErrLocked = xerrors.New("quota is locked by another transaction")
+
+// ExceededError is returned when building a workspace would exceed the quota
+// of its owner or organization.
+type ExceededError struct {
+	Detail string
+}
+
+func (e *ExceededError) Error() string {
+	return e.Detail
+}
+
+// Workspace describes a workspace being built for quotas. New workspaces count
+// toward the maximum number of workspaces, and workspaces being started that
+// aren't already running count toward the running workspaces and cost units.
+type Workspace struct {
+	OwnerID uuid.UUID
+	// OrganizationID is uuid.Nil if the workspace already counts toward the
+	// quota of its organization, e.g. because it's transferred to another
+	// owner.
+	OrganizationID uuid.UUID
+	Template       database.Template
+	New            bool
+	Starting       bool
+}
+
+// Check locks the quotas of the owner and organization of the workspace for
+// the rest of the transaction of db, and returns an *ExceededError if building
+// the workspace would exceed either of them. The workspace must be inserted or
+// built in the same transaction for concurrent builds to be counted.
+func Check(ctx context.Context, db database.Store, workspace Workspace) error {
+	return check(ctx, db, workspace, true)
+}
+
+// TryCheck is like Check, but returns ErrLocked instead of waiting for another
+// transaction that holds the quotas. Transactions that build many workspaces
+// use it so they can't deadlock with others.
+func TryCheck(ctx context.Context, db database.Store, workspace Workspace) error {
+	return check(ctx, db, workspace, false)
+}
+
+func check(ctx context.Context, db database.Store, workspace Workspace, wait bool) error {
+	if !workspace.New && !workspace.Starting {
+		return nil
+	}
+
+	// Locks are always acquired in the same order so transactions that wait
+	// for them can't deadlock.
+	lockIDs := []uuid.UUID{workspace.OwnerID}
+	if workspace.OrganizationID != uuid.Nil {
+		lockIDs = []uuid.UUID{workspace.OrganizationID, workspace.OwnerID}
+	}
+	for _, id := range lockIDs {
+		if wait {
+			err := db.AcquireQuotaLock(ctx, id)
+			if err != nil {
+				return xerrors.Errorf("acquire quota lock: %w", err)
+			}
+			continue
+		}
+		ok, err := db.TryAcquireQuotaLock(ctx, id)
+		if err != nil {
+			return xerrors.Errorf("try acquire quota lock: %w", err)
+		}
+		if !ok {
+			return ErrLocked
+		}
+	}
+
+	userQuota, err := db.GetUserQuota(ctx, workspace.OwnerID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get user quota: %w", err)
+	}
+	if err == nil {
+		usage, err := db.GetQuotaUsage(ctx, database.GetQuotaUsageParams{
+			OwnerID: workspace.OwnerID,
+		})
+		if err != nil {
+			return xerrors.Errorf("get user quota usage: %w", err)
+		}
+		reason := exceeded(userQuota.MaxWorkspaces, userQuota.MaxRunningWorkspaces, userQuota.MaxCostUnits, usage, workspace)
+		if reason != "" {
+			user, err := db.GetUserByID(ctx, workspace.OwnerID)
+			if err != nil {
+				return xerrors.Errorf("get user: %w", err)
+			}
+			return &ExceededError{
+				Detail: fmt.Sprintf("User %q is limited to %s.", user.Username, reason),
+			}
+		}
+	}
+
+	if workspace.OrganizationID == uuid.Nil {
+		return nil
+	}
+	organizationQuota, err := db.GetOrganizationQuota(ctx, workspace.OrganizationID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get organization quota: %w", err)
+	}
+	if err == nil {
+		usage, err := db.GetQuotaUsage(ctx, database.GetQuotaUsageParams{
+			OrganizationID: workspace.OrganizationID,
+		})
+		if err != nil {
+			return xerrors.Errorf("get organization quota usage: %w", err)
+		}
+		reason := exceeded(organizationQuota.MaxWorkspaces, organizationQuota.MaxRunningWorkspaces, organizationQuota.MaxCostUnits, usage, workspace)
+		if reason != "" {
+			organization, err := db.GetOrganizationByID(ctx, workspace.OrganizationID)
+			if err != nil {
+				return xerrors.Errorf("get organization: %w", err)
+			}
+			return &ExceededError{
+				Detail: fmt.Sprintf("Organization %q is limited to %s.", organization.Name, reason),
+			}
+		}
+	}
+	return nil
+}
+
+// exceeded returns the limit the workspace would exceed, if any.
+func exceeded(maxWorkspaces, maxRunningWorkspaces, maxCostUnits int32, usage database.GetQuotaUsageRow, workspace Workspace) string {
+	if workspace.New && maxWorkspaces > 0 && usage.Workspaces+1 > int64(maxWorkspaces) {
+		return fmt.Sprintf("%d workspaces", maxWorkspaces)
+	}
+	if !workspace.Starting {
+		return ""
+	}
+	if maxRunningWorkspaces > 0 && usage.RunningWorkspaces+1 > int64(maxRunningWorkspaces) {
+		return fmt.Sprintf("%d running workspaces", maxRunningWorkspaces)
+	}
+	if maxCostUnits > 0 && usage.CostUnits+int64(workspace.Template.CostUnits) > int64(maxCostUnits) {
+		return fmt.Sprintf("%d cost units of running workspaces, and %d are in use. The template %q costs %d",
+			maxCostUnits, usage.CostUnits, workspace.Template.Name, workspace.Template.CostUnits)
+	}
+	return ""
+}
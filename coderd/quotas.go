@@ -0,0 +1,238 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/quota"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+func (api *API) userQuota(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceUserData.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	quota, err := api.Database.GetUserQuota(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	usage, err := api.Database.GetQuotaUsage(r.Context(), database.GetQuotaUsageParams{
+		OwnerID: user.ID,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, convertQuota(quota.MaxWorkspaces, quota.MaxRunningWorkspaces, quota.MaxCostUnits, usage))
+}
+
+func (api *API) putUserQuota(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+	aReq, commitAudit := audit.InitRequest[database.UserQuota](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = database.UserQuota{UserID: user.ID}
+
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceUser.WithID(user.ID.String())) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.QuotaLimits
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	old, err := api.Database.GetUserQuota(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		aReq.Old = old
+	}
+
+	updated, err := api.Database.UpsertUserQuota(r.Context(), database.UpsertUserQuotaParams{
+		UserID:               user.ID,
+		MaxWorkspaces:        req.MaxWorkspaces,
+		MaxRunningWorkspaces: req.MaxRunningWorkspaces,
+		MaxCostUnits:         req.MaxCostUnits,
+		UpdatedAt:            database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+
+	usage, err := api.Database.GetQuotaUsage(r.Context(), database.GetQuotaUsageParams{
+		OwnerID: user.ID,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, convertQuota(updated.MaxWorkspaces, updated.MaxRunningWorkspaces, updated.MaxCostUnits, usage))
+}
+
+func (api *API) organizationQuota(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+
+	if !api.Authorize(r, rbac.ActionRead, organization) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	quota, err := api.Database.GetOrganizationQuota(r.Context(), organization.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	usage, err := api.Database.GetQuotaUsage(r.Context(), database.GetQuotaUsageParams{
+		OrganizationID: organization.ID,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, convertQuota(quota.MaxWorkspaces, quota.MaxRunningWorkspaces, quota.MaxCostUnits, usage))
+}
+
+func (api *API) putOrganizationQuota(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	aReq, commitAudit := audit.InitRequest[database.OrganizationQuota](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = database.OrganizationQuota{OrganizationID: organization.ID}
+
+	if !api.Authorize(r, rbac.ActionRead, organization) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	// Organization admins can't raise their own quota, so it requires the
+	// site wide permission.
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceOrganization.WithID(organization.ID.String())) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.QuotaLimits
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	old, err := api.Database.GetOrganizationQuota(r.Context(), organization.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		aReq.Old = old
+	}
+
+	updated, err := api.Database.UpsertOrganizationQuota(r.Context(), database.UpsertOrganizationQuotaParams{
+		OrganizationID:       organization.ID,
+		MaxWorkspaces:        req.MaxWorkspaces,
+		MaxRunningWorkspaces: req.MaxRunningWorkspaces,
+		MaxCostUnits:         req.MaxCostUnits,
+		UpdatedAt:            database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+
+	usage, err := api.Database.GetQuotaUsage(r.Context(), database.GetQuotaUsageParams{
+		OrganizationID: organization.ID,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching quota usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, convertQuota(updated.MaxWorkspaces, updated.MaxRunningWorkspaces, updated.MaxCostUnits, usage))
+}
+
+// writeWorkspaceQuotaError writes an error and returns true if err is
+// because building a workspace would exceed a quota.
+func writeWorkspaceQuotaError(rw http.ResponseWriter, err error) bool {
+	var exceeded *quota.ExceededError
+	if !xerrors.As(err, &exceeded) {
+		return false
+	}
+	httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+		Message: codersdk.WorkspaceQuotaExceeded,
+		Detail:  exceeded.Detail,
+	})
+	return true
+}
+
+func convertQuota(maxWorkspaces, maxRunningWorkspaces, maxCostUnits int32, usage database.GetQuotaUsageRow) codersdk.Quota {
+	return codersdk.Quota{
+		Limits: codersdk.QuotaLimits{
+			MaxWorkspaces:        maxWorkspaces,
+			MaxRunningWorkspaces: maxRunningWorkspaces,
+			MaxCostUnits:         maxCostUnits,
+		},
+		Usage: codersdk.QuotaUsage{
+			Workspaces:        usage.Workspaces,
+			RunningWorkspaces: usage.RunningWorkspaces,
+			CostUnits:         usage.CostUnits,
+		},
+	}
+}
@@ -0,0 +1,204 @@
+package coderd_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/moby/moby/pkg/namesgenerator"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestWorkspaceQuota(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, costUnits int32) (*codersdk.Client, *codersdk.Client, codersdk.CreateFirstUserResponse, codersdk.Template) {
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		first := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, first.OrganizationID, version.ID, func(req *codersdk.CreateTemplateRequest) {
+			req.CostUnits = costUnits
+		})
+		return client, member, first, template
+	}
+	createWorkspace := func(client *codersdk.Client, organizationID, templateID uuid.UUID) (codersdk.Workspace, error) {
+		return client.CreateWorkspace(context.Background(), organizationID, codersdk.CreateWorkspaceRequest{
+			TemplateID: templateID,
+			Name:       strings.ReplaceAll(namesgenerator.GetRandomName(0), "_", "-"),
+		})
+	}
+	requireQuotaExceeded := func(t *testing.T, err error, detail string) {
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		require.Equal(t, codersdk.WorkspaceQuotaExceeded, apiErr.Message)
+		require.Contains(t, apiErr.Detail, detail)
+	}
+
+	t.Run("MaxWorkspaces", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client, member, first, template := setup(t, 0)
+		_, err := client.UpdateUserQuota(ctx, codersdk.Me, codersdk.QuotaLimits{MaxWorkspaces: 1})
+		require.NoError(t, err)
+
+		// Users can't raise their own quota.
+		_, err = member.UpdateUserQuota(ctx, codersdk.Me, codersdk.QuotaLimits{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		workspace, err := createWorkspace(client, first.OrganizationID, template.ID)
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		_, err = createWorkspace(client, first.OrganizationID, template.ID)
+		requireQuotaExceeded(t, err, "limited to 1 workspaces")
+
+		// Other users aren't affected by the quota.
+		_, err = createWorkspace(member, first.OrganizationID, template.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("MaxRunningWorkspaces", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client, member, first, template := setup(t, 0)
+		_, err := client.UpdateOrganizationQuota(ctx, first.OrganizationID, codersdk.QuotaLimits{MaxRunningWorkspaces: 1})
+		require.NoError(t, err)
+
+		workspace, err := createWorkspace(client, first.OrganizationID, template.ID)
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		_, err = createWorkspace(member, first.OrganizationID, template.ID)
+		requireQuotaExceeded(t, err, "limited to 1 running workspaces")
+
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+		other, err := createWorkspace(member, first.OrganizationID, template.ID)
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, member, other.LatestBuild.ID)
+
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		requireQuotaExceeded(t, err, "limited to 1 running workspaces")
+
+		// Restarting a running workspace doesn't count it twice.
+		build = coderdtest.CreateWorkspaceBuild(t, member, other, database.WorkspaceTransitionStart)
+		coderdtest.AwaitWorkspaceBuildJob(t, member, build.ID)
+
+		quota, err := client.OrganizationQuota(ctx, first.OrganizationID)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, quota.Usage.Workspaces)
+		require.EqualValues(t, 1, quota.Usage.RunningWorkspaces)
+	})
+
+	t.Run("CostUnits", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client, member, first, template := setup(t, 2)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.UpdateUserQuota(ctx, memberUser.ID.String(), codersdk.QuotaLimits{MaxCostUnits: 3})
+		require.NoError(t, err)
+
+		workspace, err := createWorkspace(member, first.OrganizationID, template.ID)
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+		_, err = createWorkspace(member, first.OrganizationID, template.ID)
+		requireQuotaExceeded(t, err, "3 cost units")
+
+		quota, err := member.UserQuota(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, quota.Limits.MaxCostUnits)
+		require.EqualValues(t, 1, quota.Usage.Workspaces)
+		require.EqualValues(t, 2, quota.Usage.CostUnits)
+
+		// Cheaper templates still fit.
+		costUnits := int32(1)
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{CostUnits: &costUnits})
+		require.NoError(t, err)
+		_, err = createWorkspace(member, first.OrganizationID, template.ID)
+		require.NoError(t, err)
+	})
+	t.Run("Transfer", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client, member, first, template := setup(t, 0)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.UpdateUserQuota(ctx, memberUser.ID.String(), codersdk.QuotaLimits{MaxWorkspaces: 1})
+		require.NoError(t, err)
+
+		owned, err := createWorkspace(member, first.OrganizationID, template.ID)
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, member, owned.LatestBuild.ID)
+		workspace, err := createWorkspace(client, first.OrganizationID, template.ID)
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		_, err = client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			OwnerID: memberUser.ID,
+		})
+		requireQuotaExceeded(t, err, "limited to 1 workspaces")
+
+		// The workspace already counts toward the quota of the organization.
+		_, err = client.UpdateUserQuota(ctx, memberUser.ID.String(), codersdk.QuotaLimits{})
+		require.NoError(t, err)
+		_, err = client.UpdateOrganizationQuota(ctx, first.OrganizationID, codersdk.QuotaLimits{MaxWorkspaces: 2})
+		require.NoError(t, err)
+		_, err = client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			OwnerID: memberUser.ID,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("AuditLog", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		first := coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.UpdateUserQuota(ctx, first.UserID.String(), codersdk.QuotaLimits{MaxWorkspaces: 1})
+		require.NoError(t, err)
+		_, err = client.UpdateUserQuota(ctx, first.UserID.String(), codersdk.QuotaLimits{MaxWorkspaces: 2})
+		require.NoError(t, err)
+		_, err = client.UpdateOrganizationQuota(ctx, first.OrganizationID, codersdk.QuotaLimits{MaxRunningWorkspaces: 3})
+		require.NoError(t, err)
+
+		var userQuotaLogs, organizationQuotaLogs []database.AuditLog
+		for _, alog := range auditor.AuditLogs() {
+			switch alog.ResourceType {
+			case database.ResourceTypeUserQuota:
+				userQuotaLogs = append(userQuotaLogs, alog)
+			case database.ResourceTypeOrganizationQuota:
+				organizationQuotaLogs = append(organizationQuotaLogs, alog)
+			}
+		}
+		require.Len(t, userQuotaLogs, 2)
+		for _, alog := range userQuotaLogs {
+			require.Equal(t, database.AuditActionWrite, alog.Action)
+			require.Equal(t, first.UserID, alog.ResourceID)
+		}
+		var diff audit.Map
+		require.NoError(t, json.Unmarshal(userQuotaLogs[1].Diff, &diff))
+		require.EqualValues(t, 2, diff["max_workspaces"])
+		require.Len(t, organizationQuotaLogs, 1)
+		require.Equal(t, first.OrganizationID, organizationQuotaLogs[0].ResourceID)
+		require.Equal(t, first.OrganizationID, organizationQuotaLogs[0].OrganizationID)
+		diff = audit.Map{}
+		require.NoError(t, json.Unmarshal(organizationQuotaLogs[0].Diff, &diff))
+		require.EqualValues(t, 3, diff["max_running_workspaces"])
+	})
+}
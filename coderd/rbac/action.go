@@ -9,3 +9,11 @@ const (
 	ActionUpdate = "update"
 	ActionDelete = "delete"
 )
+
+var allActions = map[string]Action{
+	ActionCreate:   ActionCreate,
+	ActionRead:     ActionRead,
+	ActionUpdate:   ActionUpdate,
+	ActionDelete:   ActionDelete,
+	WildcardSymbol: WildcardSymbol,
+}
@@ -10,7 +10,7 @@ import (
 )
 
 type Authorizer interface {
-	ByRoleName(ctx context.Context, subjectID string, roleNames []string, action Action, object Object) error
+	ByRoleName(ctx context.Context, subjectID string, roleNames []string, scopes []string, groups []string, action Action, object Object) error
 }
 
 // Filter takes in a list of objects, and will filter the list removing all
@@ -18,12 +18,12 @@ type Authorizer interface {
 // Filter does not allocate a new slice, and will use the existing one
 // passed in. This can cause memory leaks if the slice is held for a prolonged
 // period of time.
-func Filter[O Objecter](ctx context.Context, auth Authorizer, subjID string, subjRoles []string, action Action, objects []O) []O {
+func Filter[O Objecter](ctx context.Context, auth Authorizer, subjID string, subjRoles []string, subjScopes []string, subjGroups []string, action Action, objects []O) []O {
 	filtered := make([]O, 0)
 
 	for i := range objects {
 		object := objects[i]
-		err := auth.ByRoleName(ctx, subjID, subjRoles, action, object.RBACObject())
+		err := auth.ByRoleName(ctx, subjID, subjRoles, subjScopes, subjGroups, action, object.RBACObject())
 		if err == nil {
 			filtered = append(filtered, object)
 		}
@@ -31,9 +31,14 @@ func Filter[O Objecter](ctx context.Context, auth Authorizer, subjID string, sub
 	return filtered
 }
 
+// RoleLookup expands the names of roles that aren't built in, e.g. custom
+// roles stored in the database.
+type RoleLookup func(ctx context.Context, roleName string) (Role, error)
+
 // RegoAuthorizer will use a prepared rego query for performing authorize()
 type RegoAuthorizer struct {
-	query rego.PreparedEvalQuery
+	query  rego.PreparedEvalQuery
+	lookup RoleLookup
 }
 
 // Load the policy from policy.rego in this directory.
@@ -55,33 +60,65 @@ func NewAuthorizer() (*RegoAuthorizer, error) {
 	return &RegoAuthorizer{query: query}, nil
 }
 
+// WithRoleLookup returns an authorizer that expands role names that aren't
+// built in with lookup.
+func (a RegoAuthorizer) WithRoleLookup(lookup RoleLookup) *RegoAuthorizer {
+	a.lookup = lookup
+	return &a
+}
+
 type authSubject struct {
 	ID    string `json:"id"`
 	Roles []Role `json:"roles"`
+	// Scope is omitted if the subject isn't restricted by a scope.
+	Scope []Permission `json:"scope,omitempty"`
+	// Groups are the IDs of the groups the subject is a member of. They're
+	// matched against the access list of the object.
+	Groups []string `json:"groups"`
 }
 
-// ByRoleName will expand all roleNames into roles before calling Authorize().
+// ByRoleName will expand all roleNames into roles and scopes into
+// permissions before authorizing.
 // This is the function intended to be used outside this package.
-// The role is fetched from the builtin map located in memory.
-func (a RegoAuthorizer) ByRoleName(ctx context.Context, subjectID string, roleNames []string, action Action, object Object) error {
+// The role is fetched from the builtin map located in memory, or with the
+// RoleLookup if it isn't built in.
+func (a RegoAuthorizer) ByRoleName(ctx context.Context, subjectID string, roleNames []string, scopes []string, groups []string, action Action, object Object) error {
 	roles := make([]Role, 0, len(roleNames))
 	for _, n := range roleNames {
-		r, err := RoleByName(n)
+		var (
+			r   Role
+			err error
+		)
+		if a.lookup != nil && !IsBuiltInRole(n) {
+			r, err = a.lookup(ctx, n)
+		} else {
+			r, err = RoleByName(n)
+		}
 		if err != nil {
 			return xerrors.Errorf("get role permissions: %w", err)
 		}
 		roles = append(roles, r)
 	}
-	return a.Authorize(ctx, subjectID, roles, action, object)
+	scope, err := ScopePermissions(scopes)
+	if err != nil {
+		return xerrors.Errorf("get scope permissions: %w", err)
+	}
+	return a.authorize(ctx, subjectID, roles, scope, groups, action, object)
 }
 
 // Authorize allows passing in custom Roles.
 // This is really helpful for unit testing, as we can create custom roles to exercise edge cases.
 func (a RegoAuthorizer) Authorize(ctx context.Context, subjectID string, roles []Role, action Action, object Object) error {
+	return a.authorize(ctx, subjectID, roles, nil, nil, action, object)
+}
+
+func (a RegoAuthorizer) authorize(ctx context.Context, subjectID string, roles []Role, scope []Permission, groups []string, action Action, object Object) error {
 	input := map[string]interface{}{
 		"subject": authSubject{
-			ID:    subjectID,
-			Roles: roles,
+			ID:     subjectID,
+			Roles:  roles,
+			Scope:  scope,
+			Groups: groups,
 		},
 		"object": object,
 		"action": action,
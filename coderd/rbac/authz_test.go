@@ -104,7 +104,7 @@ func TestFilter(t *testing.T) {
 				},
 			}
 
-			filtered := rbac.Filter(context.Background(), authorizer, "me", []string{}, rbac.ActionRead, c.List)
+			filtered := rbac.Filter(context.Background(), authorizer, "me", []string{}, []string{rbac.ScopeAll}, nil, rbac.ActionRead, c.List)
 			require.ElementsMatch(t, c.Expected, filtered, "expect same list")
 			require.Equal(t, len(c.Expected), len(filtered), "same length list")
 		})
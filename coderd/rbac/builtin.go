@@ -53,8 +53,8 @@ var (
 			return Role{
 				Name:        admin,
 				DisplayName: "Admin",
-				Site: permissions(map[Object][]Action{
-					ResourceWildcard: {WildcardSymbol},
+				Site: permissions(map[string][]Action{
+					ResourceWildcard.Type: {WildcardSymbol},
 				}),
 			}
 		},
@@ -64,15 +64,15 @@ var (
 			return Role{
 				Name:        member,
 				DisplayName: "",
-				Site: permissions(map[Object][]Action{
+				Site: permissions(map[string][]Action{
 					// All users can read all other users and know they exist.
-					ResourceUser:           {ActionRead},
-					ResourceRoleAssignment: {ActionRead},
+					ResourceUser.Type:           {ActionRead},
+					ResourceRoleAssignment.Type: {ActionRead},
 					// All users can see the provisioner daemons.
-					ResourceProvisionerDaemon: {ActionRead},
+					ResourceProvisionerDaemon.Type: {ActionRead},
 				}),
-				User: permissions(map[Object][]Action{
-					ResourceWildcard: {WildcardSymbol},
+				User: permissions(map[string][]Action{
+					ResourceWildcard.Type: {WildcardSymbol},
 				}),
 			}
 		},
@@ -84,10 +84,11 @@ var (
 			return Role{
 				Name:        "auditor",
 				DisplayName: "Auditor",
-				Site: permissions(map[Object][]Action{
+				Site: permissions(map[string][]Action{
 					// Should be able to read all template details, even in orgs they
 					// are not in.
-					ResourceTemplate: {ActionRead},
+					ResourceTemplate.Type: {ActionRead},
+					ResourceAuditLog.Type: {ActionRead},
 				}),
 			}
 		},
@@ -131,12 +132,6 @@ var (
 							Action:       ActionRead,
 							ResourceID:   "*",
 						},
-						{
-							// All org members can read templates in the org
-							ResourceType: ResourceTemplate.Type,
-							Action:       ActionRead,
-							ResourceID:   "*",
-						},
 						{
 							// Can read available roles.
 							ResourceType: ResourceOrgRoleAssignment.Type,
@@ -182,6 +177,17 @@ func IsOrgRole(roleName string) (string, bool) {
 	return "", false
 }
 
+// IsBuiltInRole returns whether the role name refers to a built-in role. The
+// names of built-in roles can't be used by custom roles.
+func IsBuiltInRole(roleName string) bool {
+	name, _, err := roleSplit(roleName)
+	if err != nil {
+		return false
+	}
+	_, ok := builtInRoles[name]
+	return ok
+}
+
 // OrganizationRoles lists all roles that can be applied to an organization user
 // in the given organization. This is the list of available roles,
 // and specific to an organization.
@@ -284,14 +290,14 @@ func roleSplit(role string) (name string, orgID string, err error) {
 
 // permissions is just a helper function to make building roles that list out resources
 // and actions a bit easier.
-func permissions(perms map[Object][]Action) []Permission {
+func permissions(perms map[string][]Action) []Permission {
 	list := make([]Permission, 0, len(perms))
 	for k, actions := range perms {
 		for _, act := range actions {
 			act := act
 			list = append(list, Permission{
 				Negate:       false,
-				ResourceType: k.Type,
+				ResourceType: k,
 				ResourceID:   WildcardSymbol,
 				Action:       act,
 			})
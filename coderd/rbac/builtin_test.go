@@ -40,6 +40,7 @@ func TestRolePermissions(t *testing.T) {
 
 	otherOrgMember := authSubject{Name: "org_member_other", UserID: uuid.NewString(), Roles: []string{rbac.RoleMember(), rbac.RoleOrgMember(otherOrg)}}
 	otherOrgAdmin := authSubject{Name: "org_admin_other", UserID: uuid.NewString(), Roles: []string{rbac.RoleMember(), rbac.RoleOrgMember(otherOrg), rbac.RoleOrgAdmin(otherOrg)}}
+	auditor := authSubject{Name: "auditor", UserID: uuid.NewString(), Roles: []string{rbac.RoleMember(), "auditor"}}
 
 	// requiredSubjects are required to be asserted in each test case. This is
 	// to make sure one is not forgotten.
@@ -99,6 +100,18 @@ func TestRolePermissions(t *testing.T) {
 			Name:     "ReadTemplates",
 			Actions:  []rbac.Action{rbac.ActionRead},
 			Resource: rbac.ResourceTemplate.InOrg(orgID).WithID(uuid.NewString()),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {admin, orgAdmin},
+				false: {memberMe, orgMemberMe, otherOrgAdmin, otherOrgMember},
+			},
+		},
+		{
+			// Access lists only apply to members of the organization.
+			Name:    "ReadTemplatesUserACL",
+			Actions: []rbac.Action{rbac.ActionRead},
+			Resource: rbac.ResourceTemplate.InOrg(orgID).WithID(uuid.NewString()).WithUserACL(map[string][]rbac.Action{
+				currentUser.String(): {rbac.ActionRead},
+			}),
 			AuthorizeMap: map[bool][]authSubject{
 				true:  {admin, orgMemberMe, orgAdmin},
 				false: {memberMe, otherOrgAdmin, otherOrgMember},
@@ -221,6 +234,24 @@ func TestRolePermissions(t *testing.T) {
 				false: {memberMe, otherOrgAdmin, otherOrgMember},
 			},
 		},
+		{
+			Name:     "ReadAuditLogs",
+			Actions:  []rbac.Action{rbac.ActionRead},
+			Resource: rbac.ResourceAuditLog,
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {admin, auditor},
+				false: {orgAdmin, orgMemberMe, memberMe, otherOrgAdmin, otherOrgMember},
+			},
+		},
+		{
+			Name:     "ManageAuditLogs",
+			Actions:  []rbac.Action{rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+			Resource: rbac.ResourceAuditLog,
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {admin},
+				false: {auditor, orgAdmin, orgMemberMe, memberMe, otherOrgAdmin, otherOrgMember},
+			},
+		},
 	}
 
 	for _, c := range testCases {
@@ -237,7 +268,7 @@ func TestRolePermissions(t *testing.T) {
 					for _, subj := range subjs {
 						delete(remainingSubjs, subj.Name)
 						msg := fmt.Sprintf("%s as %q doing %q on %q", c.Name, subj.Name, action, c.Resource.Type)
-						err := auth.ByRoleName(context.Background(), subj.UserID, subj.Roles, action, c.Resource)
+						err := auth.ByRoleName(context.Background(), subj.UserID, subj.Roles, []string{rbac.ScopeAll}, nil, action, c.Resource)
 						if result {
 							assert.NoError(t, err, fmt.Sprintf("Should pass: %s", msg))
 						} else {
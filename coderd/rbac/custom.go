@@ -0,0 +1,50 @@
+package rbac
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// CustomRole returns a role that isn't built in, e.g. one stored in the
+// database. Organization roles are named "<name>:<organization id>" and only
+// have organization permissions. Site roles have site and user permissions.
+func CustomRole(name, displayName, organizationID string, site, org, user []Permission) Role {
+	if organizationID != "" {
+		return Role{
+			Name:        roleName(name, organizationID),
+			DisplayName: displayName,
+			Org: map[string][]Permission{
+				organizationID: org,
+			},
+		}
+	}
+	return Role{
+		Name:        name,
+		DisplayName: displayName,
+		Site:        site,
+		User:        user,
+	}
+}
+
+// SplitRoleName returns the name of the role and the ID of the organization
+// it's scoped to. The organization ID is empty for site roles.
+func SplitRoleName(role string) (name string, organizationID string, err error) {
+	return roleSplit(role)
+}
+
+// ValidatePermissions returns an error if a permission has an unknown
+// resource type or action. Permissions of custom roles apply to every
+// resource of the type, so the resource ID must be empty or "*".
+func ValidatePermissions(perms []Permission) error {
+	for _, perm := range perms {
+		if _, ok := allResources[perm.ResourceType]; !ok {
+			return xerrors.Errorf("unknown resource type %q", perm.ResourceType)
+		}
+		if _, ok := allActions[string(perm.Action)]; !ok {
+			return xerrors.Errorf("unknown action %q", perm.Action)
+		}
+		if perm.ResourceID != "" && perm.ResourceID != WildcardSymbol {
+			return xerrors.Errorf("permissions must apply to all resources of a type, got resource id %q", perm.ResourceID)
+		}
+	}
+	return nil
+}
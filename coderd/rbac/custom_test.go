@@ -0,0 +1,81 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/rbac"
+)
+
+func TestCustomRoles(t *testing.T) {
+	t.Parallel()
+
+	var (
+		userID   = uuid.NewString()
+		orgID    = uuid.New()
+		template = rbac.ResourceTemplate.InOrg(orgID).WithID(uuid.NewString())
+		author   = rbac.CustomRole("template-author", "Template Author", orgID.String(), nil, []rbac.Permission{{
+			ResourceType: rbac.ResourceTemplate.Type,
+			ResourceID:   rbac.WildcardSymbol,
+			Action:       rbac.WildcardSymbol,
+		}}, nil)
+	)
+	require.Equal(t, "template-author:"+orgID.String(), author.Name)
+
+	base, err := rbac.NewAuthorizer()
+	require.NoError(t, err)
+	auth := base.WithRoleLookup(func(_ context.Context, roleName string) (rbac.Role, error) {
+		if roleName == author.Name {
+			return author, nil
+		}
+		return rbac.Role{}, xerrors.Errorf("role %q not found", roleName)
+	})
+	ctx := context.Background()
+	roles := []string{rbac.RoleMember(), rbac.RoleOrgMember(orgID), author.Name}
+
+	err = auth.ByRoleName(ctx, userID, roles, []string{rbac.ScopeAll}, nil, rbac.ActionUpdate, template)
+	require.NoError(t, err)
+	err = auth.ByRoleName(ctx, userID, roles[:2], []string{rbac.ScopeAll}, nil, rbac.ActionUpdate, template)
+	require.Error(t, err)
+	// The role only applies to the organization it's scoped to.
+	err = auth.ByRoleName(ctx, userID, roles, []string{rbac.ScopeAll}, nil, rbac.ActionUpdate, rbac.ResourceTemplate.InOrg(uuid.New()))
+	require.Error(t, err)
+	// Unknown roles fail authorization.
+	err = auth.ByRoleName(ctx, userID, append(roles, "unknown"), []string{rbac.ScopeAll}, nil, rbac.ActionRead, template)
+	require.Error(t, err)
+	// Without a lookup custom roles can't be resolved.
+	err = base.ByRoleName(ctx, userID, roles, []string{rbac.ScopeAll}, nil, rbac.ActionRead, template)
+	require.Error(t, err)
+}
+
+func TestValidatePermissions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name  string
+		Perm  rbac.Permission
+		Valid bool
+	}{
+		{Name: "Valid", Perm: rbac.Permission{ResourceType: rbac.ResourceWorkspace.Type, Action: rbac.ActionRead}, Valid: true},
+		{Name: "Wildcard", Perm: rbac.Permission{ResourceType: rbac.WildcardSymbol, ResourceID: rbac.WildcardSymbol, Action: rbac.WildcardSymbol}, Valid: true},
+		{Name: "UnknownResource", Perm: rbac.Permission{ResourceType: "spaceship", Action: rbac.ActionRead}},
+		{Name: "UnknownAction", Perm: rbac.Permission{ResourceType: rbac.ResourceWorkspace.Type, Action: "launch"}},
+		{Name: "ResourceID", Perm: rbac.Permission{ResourceType: rbac.ResourceWorkspace.Type, ResourceID: uuid.NewString(), Action: rbac.ActionRead}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.ValidatePermissions([]rbac.Permission{tc.Perm})
+			if tc.Valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/rbac"
+)
+
+func TestGroupACL(t *testing.T) {
+	t.Parallel()
+
+	auth, err := rbac.NewAuthorizer()
+	require.NoError(t, err)
+
+	var (
+		userID   = uuid.NewString()
+		orgID    = uuid.New()
+		users    = uuid.NewString()
+		admins   = uuid.NewString()
+		roles    = []string{rbac.RoleMember(), rbac.RoleOrgMember(orgID)}
+		template = rbac.ResourceTemplate.InOrg(orgID).WithID(uuid.NewString()).WithGroupACL(map[string][]rbac.Action{
+			users:  {rbac.ActionRead},
+			admins: {rbac.WildcardSymbol},
+		})
+	)
+
+	testCases := []struct {
+		Name    string
+		Groups  []string
+		Scopes  []string
+		Action  rbac.Action
+		Allowed bool
+	}{
+		{Name: "NoGroups", Action: rbac.ActionUpdate},
+		{Name: "OtherGroup", Groups: []string{uuid.NewString()}, Action: rbac.ActionUpdate},
+		{Name: "UseReads", Groups: []string{users}, Action: rbac.ActionRead, Allowed: true},
+		{Name: "UseCantUpdate", Groups: []string{users}, Action: rbac.ActionUpdate},
+		{Name: "AdminDeletes", Groups: []string{admins}, Action: rbac.ActionDelete, Allowed: true},
+		{Name: "AnyGroup", Groups: []string{uuid.NewString(), admins}, Action: rbac.ActionUpdate, Allowed: true},
+		// Scopes limit the access granted by groups too.
+		{Name: "Scoped", Groups: []string{admins}, Scopes: []string{"workspace:read"}, Action: rbac.ActionUpdate},
+	}
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			scopes := c.Scopes
+			if scopes == nil {
+				scopes = []string{rbac.ScopeAll}
+			}
+			err := auth.ByRoleName(context.Background(), userID, roles, scopes, c.Groups, c.Action, template)
+			if c.Allowed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -96,12 +96,56 @@ var (
 		Type: "organization_member",
 	}
 
+	// ResourceAuditLog is site wide and has no owners. Audit logs are
+	// written by coderd itself, so there is nothing to create or update.
+	//	read	= View audit logs
+	ResourceAuditLog = Object{
+		Type: "audit_log",
+	}
+
+	// ResourceRole is a custom role stored in the database. Site roles have
+	// no owner or org, organization roles have an org owner.
+	//	create/delete = Create/delete a custom role
+	//	update	= Update the permissions of a custom role
+	//	read	= View custom roles and their permissions
+	ResourceRole = Object{
+		Type: "role",
+	}
+
+	// ResourceGroup is a group of organization members.
+	//	create/delete = Create/delete a group
+	//	update	= Rename a group, change its members or roles
+	//	read	= View groups and their members
+	ResourceGroup = Object{
+		Type: "group",
+	}
+
 	// ResourceWildcard represents all resource types
 	ResourceWildcard = Object{
 		Type: WildcardSymbol,
 	}
 )
 
+// allResources are the resources that can be named in scopes and the
+// permissions of custom roles.
+var allResources = map[string]Object{
+	ResourceWorkspace.Type:          ResourceWorkspace,
+	ResourceTemplate.Type:           ResourceTemplate,
+	ResourceFile.Type:               ResourceFile,
+	ResourceProvisionerDaemon.Type:  ResourceProvisionerDaemon,
+	ResourceOrganization.Type:       ResourceOrganization,
+	ResourceRoleAssignment.Type:     ResourceRoleAssignment,
+	ResourceOrgRoleAssignment.Type:  ResourceOrgRoleAssignment,
+	ResourceAPIKey.Type:             ResourceAPIKey,
+	ResourceUser.Type:               ResourceUser,
+	ResourceUserData.Type:           ResourceUserData,
+	ResourceOrganizationMember.Type: ResourceOrganizationMember,
+	ResourceAuditLog.Type:           ResourceAuditLog,
+	ResourceRole.Type:               ResourceRole,
+	ResourceGroup.Type:              ResourceGroup,
+	ResourceWildcard.Type:           ResourceWildcard,
+}
+
 // Object is used to create objects for authz checks when you have none in
 // hand to run the check on.
 // An example is if you want to list all workspaces, you can create a Object
@@ -115,7 +159,12 @@ type Object struct {
 
 	// Type is "workspace", "project", "app", etc
 	Type string `json:"type"`
-	// TODO: SharedUsers?
+	// ACLGroupList maps the IDs of groups to the actions their members can
+	// perform on the object, in addition to the actions granted by roles.
+	ACLGroupList map[string][]Action `json:"acl_group_list"`
+	// ACLUserList maps the IDs of users to the actions they can perform on
+	// the object.
+	ACLUserList map[string][]Action `json:"acl_user_list"`
 }
 
 func (z Object) RBACObject() Object {
@@ -135,29 +184,59 @@ func (z Object) All() Object {
 // InOrg adds an org OwnerID to the resource
 func (z Object) InOrg(orgID uuid.UUID) Object {
 	return Object{
-		ResourceID: z.ResourceID,
-		Owner:      z.Owner,
-		OrgID:      orgID.String(),
-		Type:       z.Type,
+		ResourceID:   z.ResourceID,
+		Owner:        z.Owner,
+		OrgID:        orgID.String(),
+		Type:         z.Type,
+		ACLGroupList: z.ACLGroupList,
+		ACLUserList:  z.ACLUserList,
 	}
 }
 
 // WithOwner adds an OwnerID to the resource
 func (z Object) WithOwner(ownerID string) Object {
 	return Object{
-		ResourceID: z.ResourceID,
-		Owner:      ownerID,
-		OrgID:      z.OrgID,
-		Type:       z.Type,
+		ResourceID:   z.ResourceID,
+		Owner:        ownerID,
+		OrgID:        z.OrgID,
+		Type:         z.Type,
+		ACLGroupList: z.ACLGroupList,
+		ACLUserList:  z.ACLUserList,
 	}
 }
 
 // WithID adds a ResourceID to the resource
 func (z Object) WithID(resourceID string) Object {
 	return Object{
-		ResourceID: resourceID,
-		Owner:      z.Owner,
-		OrgID:      z.OrgID,
-		Type:       z.Type,
+		ResourceID:   resourceID,
+		Owner:        z.Owner,
+		OrgID:        z.OrgID,
+		Type:         z.Type,
+		ACLGroupList: z.ACLGroupList,
+		ACLUserList:  z.ACLUserList,
+	}
+}
+
+// WithGroupACL grants the members of groups actions on the resource.
+func (z Object) WithGroupACL(groups map[string][]Action) Object {
+	return Object{
+		ResourceID:   z.ResourceID,
+		Owner:        z.Owner,
+		OrgID:        z.OrgID,
+		Type:         z.Type,
+		ACLGroupList: groups,
+		ACLUserList:  z.ACLUserList,
+	}
+}
+
+// WithUserACL grants users actions on the resource.
+func (z Object) WithUserACL(users map[string][]Action) Object {
+	return Object{
+		ResourceID:   z.ResourceID,
+		Owner:        z.Owner,
+		OrgID:        z.OrgID,
+		Type:         z.Type,
+		ACLGroupList: z.ACLGroupList,
+		ACLUserList:  users,
 	}
 }
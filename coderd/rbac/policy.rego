@@ -103,6 +103,33 @@ org = set {
     set = {false}
 }
 
+# API keys may be restricted by a scope, which is a list of permissions. An
+# action is only allowed if the scope grants it, in addition to the roles.
+# Subjects without a scope are unrestricted.
+default scope_allow = false
+scope_allow {
+    not input.subject.scope
+}
+
+scope_allow {
+    true in perms_grant(input.subject.scope)
+}
+
+# Access lists grant actions on a single object to the members of groups,
+# regardless of their roles.
+default group_acl_allow = false
+group_acl_allow {
+    group := input.subject.groups[_]
+    perm := input.object.acl_group_list[group][_]
+    perm in [input.action, "*"]
+}
+
+default user_acl_allow = false
+user_acl_allow {
+    perm := input.object.acl_user_list[input.subject.id][_]
+    perm in [input.action, "*"]
+}
+
 # The allow block is quite simple. Any set with `false` cascades down in levels.
 # Authorization looks for any `allow` statement that is true. Multiple can be true!
 # Note that the absense of `allow` means "unauthorized".
@@ -110,6 +137,7 @@ org = set {
 
 # site allow
 allow {
+    scope_allow
     # No site wide deny
     not false in site
     # And all permissions are positive
@@ -120,6 +148,7 @@ allow {
 
 # org allow
 allow {
+    scope_allow
     # No site or org deny
     not false in site
     not false in org
@@ -131,10 +160,33 @@ allow {
 
 # user allow
 allow {
+    scope_allow
     # No site, org, or user deny
     not false in site
     not false in org
     not false in user
     # And all permissions are positive
     user[_]
-}
\ No newline at end of file
+}
+
+# OR
+
+# group acl allow
+allow {
+    scope_allow
+    # No site or org deny
+    not false in site
+    not false in org
+    group_acl_allow
+}
+
+# OR
+
+# user acl allow
+allow {
+    scope_allow
+    # No site or org deny
+    not false in site
+    not false in org
+    user_acl_allow
+}
@@ -0,0 +1,95 @@
+package rbac
+
+import (
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// ScopeAll doesn't restrict the permissions of an API key. It's the scope of
+// every key that isn't created with explicit scopes.
+const ScopeAll = "all"
+
+// Named scopes grant the permissions required for common tasks. They may be
+// suffixed with ":<resource id>" to restrict them to a single resource.
+var builtInScopes = map[string]func(resourceID string) []Permission{
+	// template:push grants what's required to create templates and push new
+	// versions of them, e.g. from CI.
+	"template:push": func(resourceID string) []Permission {
+		perms := scopePermissions(ResourceTemplate, resourceID, WildcardSymbol)
+		perms = append(perms, scopePermissions(ResourceFile, WildcardSymbol, ActionCreate, ActionRead)...)
+		// The CLI looks up the user and their organizations to find the
+		// template.
+		perms = append(perms, scopePermissions(ResourceUser, WildcardSymbol, ActionRead)...)
+		return append(perms, scopePermissions(ResourceOrganization, WildcardSymbol, ActionRead)...)
+	},
+	// agent:connect grants what's required to connect to workspace agents.
+	"agent:connect": func(resourceID string) []Permission {
+		return scopePermissions(ResourceWorkspace, resourceID, ActionRead, ActionUpdate)
+	},
+}
+
+// ScopePermissions expands scopes into the permissions they grant. An action
+// is only allowed if it's allowed by both the subject's roles and its scopes.
+// Nil is returned if the scopes don't restrict the subject's permissions.
+//
+// Scopes are formatted as "<resource>:<action>", e.g. "workspace:read", or
+// are one of the named scopes, e.g. "template:push". Either may be suffixed
+// with ":<resource id>" to restrict it to a single resource. "*" matches any
+// resource or action.
+func ScopePermissions(scopes []string) ([]Permission, error) {
+	if len(scopes) == 0 {
+		return nil, xerrors.New("at least one scope must be provided")
+	}
+	permissions := make([]Permission, 0, len(scopes))
+	for _, scope := range scopes {
+		if scope == ScopeAll {
+			return nil, nil
+		}
+		perms, err := scopeByName(scope)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, perms...)
+	}
+	return permissions, nil
+}
+
+func scopeByName(scope string) ([]Permission, error) {
+	parts := strings.Split(scope, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, xerrors.Errorf("scope %q must be formatted as <resource>:<action>[:<resource id>]", scope)
+	}
+	resourceID := WildcardSymbol
+	if len(parts) == 3 {
+		if parts[2] == "" {
+			return nil, xerrors.Errorf("scope %q has an empty resource id", scope)
+		}
+		resourceID = parts[2]
+	}
+
+	if builtIn, ok := builtInScopes[parts[0]+":"+parts[1]]; ok {
+		return builtIn(resourceID), nil
+	}
+	resource, ok := allResources[parts[0]]
+	if !ok {
+		return nil, xerrors.Errorf("scope %q has an unknown resource %q", scope, parts[0])
+	}
+	action, ok := allActions[parts[1]]
+	if !ok {
+		return nil, xerrors.Errorf("scope %q has an unknown action %q", scope, parts[1])
+	}
+	return scopePermissions(resource, resourceID, action), nil
+}
+
+func scopePermissions(resource Object, resourceID string, actions ...Action) []Permission {
+	perms := make([]Permission, 0, len(actions))
+	for _, action := range actions {
+		perms = append(perms, Permission{
+			ResourceType: resource.Type,
+			ResourceID:   resourceID,
+			Action:       action,
+		})
+	}
+	return perms
+}
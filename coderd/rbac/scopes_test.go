@@ -0,0 +1,101 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/rbac"
+)
+
+func TestScopes(t *testing.T) {
+	t.Parallel()
+
+	auth, err := rbac.NewAuthorizer()
+	require.NoError(t, err)
+
+	var (
+		userID      = uuid.NewString()
+		orgID       = uuid.New()
+		workspaceID = uuid.NewString()
+		roles       = []string{rbac.RoleMember(), rbac.RoleOrgAdmin(orgID)}
+		workspace   = rbac.ResourceWorkspace.InOrg(orgID).WithOwner(userID).WithID(workspaceID)
+		other       = rbac.ResourceWorkspace.InOrg(orgID).WithOwner(userID).WithID(uuid.NewString())
+		template    = rbac.ResourceTemplate.InOrg(orgID).WithID(uuid.NewString())
+	)
+
+	testCases := []struct {
+		Name    string
+		Scopes  []string
+		Action  rbac.Action
+		Object  rbac.Object
+		Allowed bool
+	}{
+		{Name: "AllDeletesWorkspace", Scopes: []string{rbac.ScopeAll}, Action: rbac.ActionDelete, Object: workspace, Allowed: true},
+		{Name: "ReadReadsWorkspace", Scopes: []string{"workspace:read"}, Action: rbac.ActionRead, Object: workspace, Allowed: true},
+		{Name: "ReadCantDeleteWorkspace", Scopes: []string{"workspace:read"}, Action: rbac.ActionDelete, Object: workspace},
+		{Name: "ReadCantReadTemplate", Scopes: []string{"workspace:read"}, Action: rbac.ActionRead, Object: template},
+		{Name: "WildcardAction", Scopes: []string{"workspace:*"}, Action: rbac.ActionDelete, Object: workspace, Allowed: true},
+		{Name: "WildcardResource", Scopes: []string{"*:read"}, Action: rbac.ActionRead, Object: template, Allowed: true},
+		{Name: "MultipleScopes", Scopes: []string{"workspace:read", "template:read"}, Action: rbac.ActionRead, Object: template, Allowed: true},
+		{Name: "TemplatePushUpdatesTemplate", Scopes: []string{"template:push"}, Action: rbac.ActionUpdate, Object: template, Allowed: true},
+		{Name: "TemplatePushCantDeleteWorkspace", Scopes: []string{"template:push"}, Action: rbac.ActionDelete, Object: workspace},
+		{Name: "AgentConnect", Scopes: []string{"agent:connect:" + workspaceID}, Action: rbac.ActionUpdate, Object: workspace, Allowed: true},
+		{Name: "AgentConnectOtherWorkspace", Scopes: []string{"agent:connect:" + workspaceID}, Action: rbac.ActionUpdate, Object: other},
+		{Name: "AgentConnectCantDelete", Scopes: []string{"agent:connect:" + workspaceID}, Action: rbac.ActionDelete, Object: workspace},
+		// Scopes never grant more than the roles do.
+		{Name: "ScopeDoesntGrant", Scopes: []string{"audit_log:read"}, Action: rbac.ActionRead, Object: rbac.ResourceAuditLog},
+	}
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			err := auth.ByRoleName(context.Background(), userID, roles, c.Scopes, nil, c.Action, c.Object)
+			if c.Allowed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestScopePermissions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("All", func(t *testing.T) {
+		t.Parallel()
+		perms, err := rbac.ScopePermissions([]string{"workspace:read", rbac.ScopeAll})
+		require.NoError(t, err)
+		require.Nil(t, perms)
+	})
+
+	t.Run("ResourceID", func(t *testing.T) {
+		t.Parallel()
+		perms, err := rbac.ScopePermissions([]string{"workspace:read:abc"})
+		require.NoError(t, err)
+		require.Equal(t, []rbac.Permission{{
+			ResourceType: rbac.ResourceWorkspace.Type,
+			ResourceID:   "abc",
+			Action:       rbac.ActionRead,
+		}}, perms)
+	})
+
+	for _, scopes := range [][]string{
+		{},
+		{"workspace"},
+		{"workspace:read:"},
+		{"workspace:read:abc:def"},
+		{"hotdog:read"},
+		{"workspace:destroy"},
+	} {
+		scopes := scopes
+		t.Run("Invalid", func(t *testing.T) {
+			t.Parallel()
+			_, err := rbac.ScopePermissions(scopes)
+			require.Error(t, err)
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package coderd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
 	"github.com/coder/coder/coderd/httpmw"
 	"github.com/coder/coder/codersdk"
 
@@ -21,7 +25,14 @@ func (api *API) assignableSiteRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	roles := rbac.SiteRoles()
+	roles, err := api.withCustomRoles(r.Context(), rbac.SiteRoles(), uuid.NullUUID{})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 	httpapi.Write(rw, http.StatusOK, convertRoles(roles))
 }
 
@@ -36,10 +47,37 @@ func (api *API) assignableOrgRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	roles := rbac.OrganizationRoles(organization.ID)
+	roles, err := api.withCustomRoles(r.Context(), rbac.OrganizationRoles(organization.ID), uuid.NullUUID{
+		UUID:  organization.ID,
+		Valid: true,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 	httpapi.Write(rw, http.StatusOK, convertRoles(roles))
 }
 
+// withCustomRoles appends the custom roles of the site, or of an organization,
+// to the built-in roles.
+func (api *API) withCustomRoles(ctx context.Context, roles []rbac.Role, organizationID uuid.NullUUID) ([]rbac.Role, error) {
+	customRoles, err := api.Database.GetCustomRoles(ctx, organizationID)
+	if err != nil {
+		return nil, xerrors.Errorf("get custom roles: %w", err)
+	}
+	for _, customRole := range customRoles {
+		role, err := convertCustomRoleToRBAC(customRole)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
 func (api *API) checkPermissions(rw http.ResponseWriter, r *http.Request) {
 	user := httpmw.UserParam(r)
 
@@ -72,7 +110,7 @@ func (api *API) checkPermissions(rw http.ResponseWriter, r *http.Request) {
 		if v.Object.OwnerID == "me" {
 			v.Object.OwnerID = roles.ID.String()
 		}
-		err := api.Authorizer.ByRoleName(r.Context(), roles.ID.String(), roles.Roles, rbac.Action(v.Action),
+		err := api.Authorizer.ByRoleName(r.Context(), roles.ID.String(), roles.Roles, httpmw.APIKey(r).Scopes, roles.Groups, rbac.Action(v.Action),
 			rbac.Object{
 				ResourceID: v.Object.ResourceID,
 				Owner:      v.Object.OwnerID,
@@ -0,0 +1,248 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/rbac"
+)
+
+// GroupRoles maps groups in an identity provider to the roles granted to
+// their members. Site roles are named as usual, e.g. "admin". Organization
+// roles are suffixed with the name or ID of the organization, e.g.
+// "organization-admin:<organization>".
+//
+// Roles are synced on every login, and again whenever a session is extended or
+// its OAuth token refreshed. A role that appears anywhere in the mapping is
+// managed by the identity provider: it's granted if the user is a member of a
+// mapped group, and revoked otherwise. Roles that aren't mapped are left
+// untouched, so they can still be assigned manually.
+//
+// Identity providers don't notify Coder when group memberships change, so
+// removing a user from a group revokes their roles within the hour for Github,
+// and when their OAuth token expires for OpenID Connect. Sessions of users who
+// can't be synced anymore, e.g. because they were removed from the provider,
+// are rejected.
+type GroupRoles map[string][]string
+
+// syncGroupRoles updates the roles of user to match the groups they're a
+// member of in the identity provider. Organization roles are only synced for
+// organizations the user is already a member of. Every change is audited on
+// behalf of the user.
+func (api *API) syncGroupRoles(r *http.Request, user database.User, groupRoles GroupRoles, groups []string) error {
+	if len(groupRoles) == 0 {
+		return nil
+	}
+	ctx := r.Context()
+	inGroup := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		inGroup[group] = true
+	}
+
+	organizationIDs := map[string]uuid.UUID{}
+	var (
+		managedSite = map[string]bool{}
+		grantedSite = map[string]bool{}
+		managedOrg  = map[uuid.UUID]map[string]bool{}
+		grantedOrg  = map[uuid.UUID]map[string]bool{}
+	)
+	for group, roles := range groupRoles {
+		for _, role := range roles {
+			organization, ok := rbac.IsOrgRole(role)
+			if !ok {
+				managedSite[role] = true
+				if inGroup[group] {
+					grantedSite[role] = true
+				}
+				continue
+			}
+
+			organizationID, ok := organizationIDs[organization]
+			if !ok {
+				var err error
+				organizationID, err = api.organizationIDByNameOrID(ctx, organization)
+				if errors.Is(err, sql.ErrNoRows) {
+					api.Logger.Warn(ctx, "role mapped to unknown organization", slog.F("group", group), slog.F("role", role))
+					continue
+				}
+				if err != nil {
+					return xerrors.Errorf("get organization %q: %w", organization, err)
+				}
+				organizationIDs[organization] = organizationID
+			}
+			name := role[:len(role)-len(organization)] + organizationID.String()
+			if managedOrg[organizationID] == nil {
+				managedOrg[organizationID] = map[string]bool{}
+				grantedOrg[organizationID] = map[string]bool{}
+			}
+			managedOrg[organizationID][name] = true
+			if inGroup[group] {
+				grantedOrg[organizationID][name] = true
+			}
+		}
+	}
+
+	siteRoles, changed := syncRoles(user.RBACRoles, managedSite, grantedSite)
+	if changed {
+		updatedUser, err := api.updateSiteUserRoles(ctx, database.UpdateUserRolesParams{
+			GrantedRoles: siteRoles,
+			ID:           user.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("update site roles: %w", err)
+		}
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.User]{
+			Auditor:    api.Auditor,
+			Log:        api.Logger,
+			UserID:     user.ID,
+			RemoteAddr: r.RemoteAddr,
+			UserAgent:  r.UserAgent(),
+			Action:     database.AuditActionWrite,
+			Old:        user,
+			New:        updatedUser,
+		})
+	}
+
+	for organizationID, managed := range managedOrg {
+		member, err := api.Database.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: organizationID,
+			UserID:         user.ID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("get organization member: %w", err)
+		}
+		memberRoles, changed := syncRoles(member.Roles, managed, grantedOrg[organizationID])
+		if !changed {
+			continue
+		}
+		updatedMember, err := api.updateOrganizationMemberRoles(ctx, database.UpdateMemberRolesParams{
+			GrantedRoles: memberRoles,
+			UserID:       member.UserID,
+			OrgID:        member.OrganizationID,
+		})
+		if err != nil {
+			return xerrors.Errorf("update organization roles: %w", err)
+		}
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.OrganizationMember]{
+			Auditor:    api.Auditor,
+			Log:        api.Logger,
+			UserID:     user.ID,
+			RemoteAddr: r.RemoteAddr,
+			UserAgent:  r.UserAgent(),
+			Action:     database.AuditActionWrite,
+			Old:        member,
+			New:        updatedMember,
+		})
+	}
+	return nil
+}
+
+// resyncGroupRoles syncs the roles of the owner of an API key created by an
+// OAuth login with the identity provider. It's called by ExtractAPIKey when a
+// session is extended or its OAuth token refreshed.
+func (api *API) resyncGroupRoles(r *http.Request, key database.APIKey, token *oauth2.Token) error {
+	ctx := r.Context()
+	switch key.LoginType {
+	case database.LoginTypeGithub:
+		if api.GithubOAuth2Config == nil || len(api.GithubOAuth2Config.TeamRoles) == 0 {
+			return nil
+		}
+		groups, err := api.githubTeams(ctx, oauth2.NewClient(ctx, oauth2.StaticTokenSource(token)))
+		if err != nil {
+			return err
+		}
+		user, err := api.Database.GetUserByID(ctx, key.UserID)
+		if err != nil {
+			return xerrors.Errorf("get user: %w", err)
+		}
+		return api.syncGroupRoles(r, user, api.GithubOAuth2Config.TeamRoles, groups)
+	case database.LoginTypeOIDC:
+		if api.OIDCConfig == nil || len(api.OIDCConfig.GroupRoles) == 0 {
+			return nil
+		}
+		// Only tokens that were just refreshed contain an ID token, so
+		// sessions are synced again once their OAuth token expires.
+		rawIDToken, ok := token.Extra("id_token").(string)
+		if !ok {
+			return nil
+		}
+		idToken, err := api.OIDCConfig.Verifier.VerifyRefreshed(ctx, rawIDToken)
+		if err != nil {
+			return xerrors.Errorf("verify refreshed id token: %w", err)
+		}
+		var claims map[string]interface{}
+		err = idToken.Claims(&claims)
+		if err != nil {
+			return xerrors.Errorf("extract claims: %w", err)
+		}
+		user, err := api.Database.GetUserByID(ctx, key.UserID)
+		if err != nil {
+			return xerrors.Errorf("get user: %w", err)
+		}
+		email, _ := claims[api.OIDCConfig.emailField()].(string)
+		if !strings.EqualFold(email, user.Email) {
+			return xerrors.Errorf("refreshed id token is for %q instead of %q", email, user.Email)
+		}
+		return api.syncGroupRoles(r, user, api.OIDCConfig.GroupRoles, api.OIDCConfig.groups(claims))
+	default:
+		return nil
+	}
+}
+
+// syncRoles returns roles without the managed roles that aren't granted, and
+// with the granted roles added.
+func syncRoles(roles []string, managed, granted map[string]bool) ([]string, bool) {
+	changed := false
+	synced := make([]string, 0, len(roles)+len(granted))
+	has := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		if managed[role] && !granted[role] {
+			changed = true
+			continue
+		}
+		has[role] = true
+		synced = append(synced, role)
+	}
+	added := make([]string, 0, len(granted))
+	for role := range granted {
+		if !has[role] {
+			added = append(added, role)
+		}
+	}
+	// Sorted so roles are stored in a consistent order.
+	sort.Strings(added)
+	if len(added) > 0 {
+		changed = true
+	}
+	return append(synced, added...), changed
+}
+
+func (api *API) organizationIDByNameOrID(ctx context.Context, nameOrID string) (uuid.UUID, error) {
+	organizationID, err := uuid.Parse(nameOrID)
+	if err == nil {
+		organization, err := api.Database.GetOrganizationByID(ctx, organizationID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return organization.ID, nil
+	}
+	organization, err := api.Database.GetOrganizationByName(ctx, nameOrID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return organization.ID, nil
+}
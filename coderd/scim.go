@@ -0,0 +1,353 @@
+package coderd
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/scim"
+	"github.com/coder/coder/codersdk"
+)
+
+// scimAuthorize only allows requests bearing the SCIM API key. The SCIM
+// endpoints don't exist when no key is configured.
+func (api *API) scimAuthorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if len(api.SCIMAPIKey) == 0 {
+			scimWriteError(rw, http.StatusNotFound, "", "SCIM provisioning is disabled.")
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), api.SCIMAPIKey) != 1 {
+			scimWriteError(rw, http.StatusUnauthorized, "", "Invalid SCIM API key.")
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// scimGetUsers lists users, optionally filtered by username or email. Identity
+// providers often use emails as usernames, so a username that's an email is
+// matched against the email of users.
+func (api *API) scimGetUsers(rw http.ResponseWriter, r *http.Request) {
+	var (
+		users []database.User
+		err   error
+	)
+	query := r.URL.Query()
+	if raw := query.Get("filter"); raw != "" {
+		filter, err := scim.ParseFilter(raw)
+		if err != nil {
+			scimWriteError(rw, http.StatusBadRequest, "invalidFilter", err.Error())
+			return
+		}
+		params := database.GetUserByEmailOrUsernameParams{}
+		switch strings.ToLower(filter.Attribute) {
+		case "username":
+			if strings.Contains(filter.Value, "@") {
+				params.Email = filter.Value
+			} else {
+				params.Username = filter.Value
+			}
+		case "emails", "emails.value":
+			params.Email = filter.Value
+		default:
+			scimWriteError(rw, http.StatusBadRequest, "invalidFilter", fmt.Sprintf("Filtering by %q isn't supported.", filter.Attribute))
+			return
+		}
+		user, err := api.Database.GetUserByEmailOrUsername(r.Context(), params)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			scimWriteError(rw, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+		if err == nil {
+			users = append(users, user)
+		}
+	} else {
+		users, err = api.Database.GetUsers(r.Context(), database.GetUsersParams{})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			scimWriteError(rw, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+	}
+
+	// Indexes are 1-based.
+	startIndex, err := strconv.Atoi(query.Get("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(query.Get("count"))
+	if err != nil || count < 0 {
+		count = len(users)
+	}
+	page := users
+	if startIndex-1 < len(page) {
+		page = page[startIndex-1:]
+	} else {
+		page = nil
+	}
+	if count < len(page) {
+		page = page[:count]
+	}
+
+	resources := make([]scim.User, 0, len(page))
+	for _, user := range page {
+		resources = append(resources, convertSCIMUser(user))
+	}
+	scimWrite(rw, http.StatusOK, scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: len(users),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func (api *API) scimGetUser(rw http.ResponseWriter, r *http.Request) {
+	user, ok := api.scimUserParam(rw, r)
+	if !ok {
+		return
+	}
+	scimWrite(rw, http.StatusOK, convertSCIMUser(user))
+}
+
+// scimPostUser provisions a user. They join the first organization, and have
+// no password since they're expected to log in through the identity provider.
+// Users are unique by email, and the username is derived from the SCIM
+// username, with a suffix if another user already has it.
+func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
+	aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+
+	var req scim.User
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		scimWriteError(rw, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return
+	}
+	email := req.PrimaryEmail()
+	if email == "" {
+		scimWriteError(rw, http.StatusBadRequest, "invalidValue", "An email is required.")
+		return
+	}
+	username := scimUsername(req.UserName)
+	if username == "" {
+		username = scimUsername(email)
+	}
+	if username == "" {
+		scimWriteError(rw, http.StatusBadRequest, "invalidValue", fmt.Sprintf("Couldn't create a valid username from %q.", req.UserName))
+		return
+	}
+
+	_, err = api.Database.GetUserByEmailOrUsername(r.Context(), database.GetUserByEmailOrUsernameParams{
+		Email: email,
+	})
+	if err == nil {
+		scimWriteError(rw, http.StatusConflict, "uniqueness", "A user with this email already exists.")
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		scimWriteError(rw, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	username, err = api.scimUniqueUsername(r.Context(), username)
+	if err != nil {
+		scimWriteError(rw, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+
+	organizations, err := api.Database.GetOrganizations(r.Context())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		scimWriteError(rw, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	if len(organizations) == 0 {
+		scimWriteError(rw, http.StatusInternalServerError, "", "No organization exists to add the user to. Create the first user before provisioning users.")
+		return
+	}
+	user, _, err := api.createUser(r.Context(), codersdk.CreateUserRequest{
+		Email:          email,
+		Username:       username,
+		OrganizationID: organizations[0].ID,
+	})
+	if err != nil {
+		scimWriteError(rw, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	if req.Active != nil && !*req.Active {
+		user, err = api.Database.UpdateUserStatus(r.Context(), database.UpdateUserStatusParams{
+			ID:        user.ID,
+			Status:    database.UserStatusSuspended,
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			scimWriteError(rw, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+	}
+	aReq.New = user
+
+	scimWrite(rw, http.StatusCreated, convertSCIMUser(user))
+}
+
+// scimPatchUser activates or suspends a user. Changes to other attributes are
+// ignored, since Coder doesn't store them or users manage them.
+func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
+	aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+
+	user, ok := api.scimUserParam(rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = user
+
+	var req scim.PatchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		scimWriteError(rw, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return
+	}
+	status := user.Status
+	for _, operation := range req.Operations {
+		active, ok, err := operation.Active()
+		if err != nil {
+			scimWriteError(rw, http.StatusBadRequest, "invalidValue", err.Error())
+			return
+		}
+		if !ok {
+			continue
+		}
+		status = database.UserStatusSuspended
+		if active {
+			status = database.UserStatusActive
+		}
+	}
+
+	if status != user.Status {
+		user, err = api.Database.UpdateUserStatus(r.Context(), database.UpdateUserStatusParams{
+			ID:        user.ID,
+			Status:    status,
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			scimWriteError(rw, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+	}
+	aReq.New = user
+
+	scimWrite(rw, http.StatusOK, convertSCIMUser(user))
+}
+
+func (api *API) scimUserParam(rw http.ResponseWriter, r *http.Request) (database.User, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		scimWriteError(rw, http.StatusNotFound, "", "User not found.")
+		return database.User{}, false
+	}
+	user, err := api.Database.GetUserByID(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		scimWriteError(rw, http.StatusNotFound, "", "User not found.")
+		return database.User{}, false
+	}
+	if err != nil {
+		scimWriteError(rw, http.StatusInternalServerError, "", err.Error())
+		return database.User{}, false
+	}
+	return user, true
+}
+
+// scimUsername returns the username of a SCIM user. Identity providers often
+// use emails as usernames, so only the part before the @ is kept.
+func scimUsername(userName string) string {
+	return httpapi.UsernameFrom(strings.Split(userName, "@")[0])
+}
+
+// scimUniqueUsername returns username, or username with a numeric suffix if
+// it's taken. Different emails can have the same local part, so usernames
+// derived from them aren't unique.
+func (api *API) scimUniqueUsername(ctx context.Context, username string) (string, error) {
+	for i := 1; i <= 100; i++ {
+		candidate := username
+		if i > 1 {
+			suffix := "-" + strconv.Itoa(i)
+			if len(candidate)+len(suffix) > 32 {
+				candidate = strings.TrimRight(candidate[:32-len(suffix)], "-")
+			}
+			candidate += suffix
+		}
+		_, err := api.Database.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
+			Username: candidate,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", xerrors.Errorf("get user by username %q: %w", candidate, err)
+		}
+	}
+	return "", xerrors.Errorf("couldn't find an unused username for %q", username)
+}
+
+func convertSCIMUser(user database.User) scim.User {
+	active := user.Status == database.UserStatusActive
+	return scim.User{
+		Schemas:  []string{scim.SchemaUser},
+		ID:       user.ID.String(),
+		UserName: user.Username,
+		Emails: []scim.Email{{
+			Value:   user.Email,
+			Primary: true,
+		}},
+		Active: &active,
+		Meta: &scim.Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+		},
+	}
+}
+
+func scimWrite(rw http.ResponseWriter, status int, response interface{}) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", scim.ContentType)
+	rw.WriteHeader(status)
+	_, _ = rw.Write(data)
+}
+
+func scimWriteError(rw http.ResponseWriter, status int, scimType, detail string) {
+	scimWrite(rw, status, scim.Error{
+		Schemas:  []string{scim.SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
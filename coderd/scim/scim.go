@@ -0,0 +1,167 @@
+// Package scim contains the SCIM 2.0 resources identity providers use to
+// provision users. See RFC 7643 and RFC 7644.
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ContentType is the media type of SCIM requests and responses.
+	ContentType = "application/scim+json"
+
+	SchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// User is a SCIM user resource. Only the attributes Coder stores are kept.
+type User struct {
+	Schemas    []string `json:"schemas"`
+	ID         string   `json:"id,omitempty"`
+	ExternalID string   `json:"externalId,omitempty"`
+	UserName   string   `json:"userName"`
+	Name       *Name    `json:"name,omitempty"`
+	Emails     []Email  `json:"emails,omitempty"`
+	// Active is a pointer since users are active when it's omitted.
+	Active *bool `json:"active,omitempty"`
+	Meta   *Meta `json:"meta,omitempty"`
+}
+
+// PrimaryEmail returns the email marked as primary, or the first one.
+func (u User) PrimaryEmail() string {
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+type Name struct {
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	Formatted  string `json:"formatted,omitempty"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// ListResponse is a page of resources matching a query.
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []User   `json:"Resources"`
+}
+
+// PatchRequest modifies the attributes of a resource.
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	// Op is one of "add", "replace" or "remove". Some identity providers
+	// capitalize it.
+	Op string `json:"op"`
+	// Path is the attribute being modified. Without it, Value is an object
+	// of attributes.
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Active returns the value an operation sets the active attribute to, and
+// whether it sets it at all.
+func (o PatchOperation) Active() (bool, bool, error) {
+	op := strings.ToLower(o.Op)
+	if op != "add" && op != "replace" {
+		return false, false, nil
+	}
+	value := o.Value
+	if o.Path == "" {
+		var attributes map[string]json.RawMessage
+		err := json.Unmarshal(o.Value, &attributes)
+		if err != nil {
+			return false, false, xerrors.Errorf("unmarshal attributes: %w", err)
+		}
+		var ok bool
+		value, ok = attributes["active"]
+		if !ok {
+			return false, false, nil
+		}
+	} else if !strings.EqualFold(o.Path, "active") {
+		return false, false, nil
+	}
+
+	var active bool
+	err := json.Unmarshal(value, &active)
+	if err == nil {
+		return active, true, nil
+	}
+	// Azure Active Directory sends booleans as strings.
+	var raw string
+	err = json.Unmarshal(value, &raw)
+	if err != nil {
+		return false, false, xerrors.Errorf("active must be a boolean: %w", err)
+	}
+	active, err = strconv.ParseBool(raw)
+	if err != nil {
+		return false, false, xerrors.Errorf("active must be a boolean: %w", err)
+	}
+	return active, true, nil
+}
+
+// Error is the body of an unsuccessful response.
+type Error struct {
+	Schemas []string `json:"schemas"`
+	// Status is the HTTP status code as a string.
+	Status   string `json:"status"`
+	ScimType string `json:"scimType,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Filter is a comparison of an attribute to a value, like
+// `userName eq "kyle"`. Only equality is supported.
+type Filter struct {
+	Attribute string
+	Value     string
+}
+
+// ParseFilter parses a filter query parameter.
+func ParseFilter(filter string) (Filter, error) {
+	parts := strings.SplitN(strings.TrimSpace(filter), " ", 3)
+	if len(parts) != 3 {
+		return Filter{}, xerrors.Errorf("filter %q must be in the form `attribute eq \"value\"`", filter)
+	}
+	if !strings.EqualFold(parts[1], "eq") {
+		return Filter{}, xerrors.Errorf("operator %q isn't supported", parts[1])
+	}
+	value, err := strconv.Unquote(parts[2])
+	if err != nil {
+		return Filter{}, xerrors.Errorf("value %s must be quoted: %w", parts[2], err)
+	}
+	return Filter{
+		Attribute: parts[0],
+		Value:     value,
+	}, nil
+}
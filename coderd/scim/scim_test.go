@@ -0,0 +1,60 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/scim"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := scim.ParseFilter(`userName eq "kyle@coder.com"`)
+	require.NoError(t, err)
+	require.Equal(t, scim.Filter{Attribute: "userName", Value: "kyle@coder.com"}, filter)
+
+	for _, invalid := range []string{
+		`userName`,
+		`userName sw "kyle"`,
+		`userName eq kyle`,
+	} {
+		_, err := scim.ParseFilter(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestPatchOperationActive(t *testing.T) {
+	t.Parallel()
+
+	for _, testCase := range []struct {
+		Name      string
+		Operation scim.PatchOperation
+		Active    bool
+		Set       bool
+		Error     bool
+	}{
+		{"Path", scim.PatchOperation{Op: "replace", Path: "active", Value: json.RawMessage(`false`)}, false, true, false},
+		{"String", scim.PatchOperation{Op: "Replace", Path: "active", Value: json.RawMessage(`"True"`)}, true, true, false},
+		{"Attributes", scim.PatchOperation{Op: "replace", Value: json.RawMessage(`{"active":false}`)}, false, true, false},
+		{"OtherAttributes", scim.PatchOperation{Op: "replace", Value: json.RawMessage(`{"displayName":"Kyle"}`)}, false, false, false},
+		{"OtherPath", scim.PatchOperation{Op: "replace", Path: "displayName", Value: json.RawMessage(`"Kyle"`)}, false, false, false},
+		{"Remove", scim.PatchOperation{Op: "remove", Path: "active"}, false, false, false},
+		{"Invalid", scim.PatchOperation{Op: "replace", Path: "active", Value: json.RawMessage(`"maybe"`)}, false, false, true},
+	} {
+		testCase := testCase
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Parallel()
+			active, set, err := testCase.Operation.Active()
+			if testCase.Error {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.Active, active)
+			require.Equal(t, testCase.Set, set)
+		})
+	}
+}
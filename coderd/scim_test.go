@@ -0,0 +1,194 @@
+package coderd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/scim"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestSCIM(t *testing.T) {
+	t.Parallel()
+
+	const apiKey = "hunter2"
+	// request makes a SCIM request, decoding the response into out.
+	request := func(t *testing.T, client *codersdk.Client, key, method, path string, body, out interface{}) int {
+		t.Helper()
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		url, err := client.URL.Parse("/api/v2/scim/v2" + path)
+		require.NoError(t, err)
+		req, err := http.NewRequestWithContext(context.Background(), method, url.String(), bytes.NewReader(data))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+key)
+		req.Header.Set("Content-Type", scim.ContentType)
+		res, err := client.HTTPClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, scim.ContentType, res.Header.Get("Content-Type"))
+		if out != nil {
+			require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+		}
+		return res.StatusCode
+	}
+	newUser := func(userName, email string) scim.User {
+		return scim.User{
+			Schemas:  []string{scim.SchemaUser},
+			UserName: userName,
+			Emails:   []scim.Email{{Value: email, Primary: true}},
+		}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		status := request(t, client, "", http.MethodGet, "/Users", nil, nil)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{SCIMAPIKey: []byte(apiKey)})
+		coderdtest.CreateFirstUser(t, client)
+		var scimErr scim.Error
+		status := request(t, client, "wrong", http.MethodGet, "/Users", nil, &scimErr)
+		require.Equal(t, http.StatusUnauthorized, status)
+		require.Equal(t, "401", scimErr.Status)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{SCIMAPIKey: []byte(apiKey)})
+		admin := coderdtest.CreateFirstUser(t, client)
+
+		var created scim.User
+		status := request(t, client, apiKey, http.MethodPost, "/Users", newUser("kyle@coder.com", "kyle@coder.com"), &created)
+		require.Equal(t, http.StatusCreated, status)
+		require.Equal(t, "kyle", created.UserName)
+		require.NotNil(t, created.Active)
+		require.True(t, *created.Active)
+
+		user, err := client.User(context.Background(), created.ID)
+		require.NoError(t, err)
+		require.Equal(t, "kyle@coder.com", user.Email)
+		require.Equal(t, []uuid.UUID{admin.OrganizationID}, user.OrganizationIDs)
+
+		var fetched scim.User
+		status = request(t, client, apiKey, http.MethodGet, "/Users/"+created.ID, nil, &fetched)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, created.ID, fetched.ID)
+
+		var scimErr scim.Error
+		status = request(t, client, apiKey, http.MethodPost, "/Users", newUser("kyle", "kyle@coder.com"), &scimErr)
+		require.Equal(t, http.StatusConflict, status)
+		require.Equal(t, "uniqueness", scimErr.ScimType)
+
+		// Usernames are derived from the local part of emails, so they're
+		// made unique.
+		status = request(t, client, apiKey, http.MethodPost, "/Users", newUser("kyle@example.com", "kyle@example.com"), &created)
+		require.Equal(t, http.StatusCreated, status)
+		require.Equal(t, "kyle-2", created.UserName)
+	})
+
+	t.Run("NoOrganization", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{SCIMAPIKey: []byte(apiKey)})
+		status := request(t, client, apiKey, http.MethodPost, "/Users", newUser("kyle", "kyle@coder.com"), nil)
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{SCIMAPIKey: []byte(apiKey)})
+		coderdtest.CreateFirstUser(t, client)
+		status := request(t, client, apiKey, http.MethodPost, "/Users", newUser("kyle", "kyle@coder.com"), nil)
+		require.Equal(t, http.StatusCreated, status)
+
+		var list scim.ListResponse
+		status = request(t, client, apiKey, http.MethodGet, "/Users", nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 2, list.TotalResults)
+		require.Len(t, list.Resources, 2)
+
+		status = request(t, client, apiKey, http.MethodGet, "/Users?startIndex=2&count=5", nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 2, list.TotalResults)
+		require.Len(t, list.Resources, 1)
+
+		status = request(t, client, apiKey, http.MethodGet, `/Users?filter=userName+eq+"kyle@coder.com"`, nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 1, list.TotalResults)
+		require.Equal(t, "kyle", list.Resources[0].UserName)
+
+		// Emails with the same local part are other users.
+		status = request(t, client, apiKey, http.MethodGet, `/Users?filter=userName+eq+"kyle@example.com"`, nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 0, list.TotalResults)
+
+		status = request(t, client, apiKey, http.MethodGet, `/Users?filter=userName+eq+"kyle"`, nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 1, list.TotalResults)
+
+		status = request(t, client, apiKey, http.MethodGet, `/Users?filter=emails.value+eq+"nobody@coder.com"`, nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 0, list.TotalResults)
+		require.Empty(t, list.Resources)
+
+		status = request(t, client, apiKey, http.MethodGet, `/Users?filter=title+eq+"cto"`, nil, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Deactivate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{SCIMAPIKey: []byte(apiKey)})
+		coderdtest.CreateFirstUser(t, client)
+		var created scim.User
+		status := request(t, client, apiKey, http.MethodPost, "/Users", newUser("kyle", "kyle@coder.com"), &created)
+		require.Equal(t, http.StatusCreated, status)
+
+		var patched scim.User
+		status = request(t, client, apiKey, http.MethodPatch, "/Users/"+created.ID, scim.PatchRequest{
+			Schemas: []string{scim.SchemaPatchOp},
+			Operations: []scim.PatchOperation{{
+				Op:    "Replace",
+				Path:  "active",
+				Value: json.RawMessage(`"False"`),
+			}},
+		}, &patched)
+		require.Equal(t, http.StatusOK, status)
+		require.False(t, *patched.Active)
+		user, err := client.User(context.Background(), created.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserStatusSuspended, user.Status)
+
+		status = request(t, client, apiKey, http.MethodPatch, "/Users/"+created.ID, scim.PatchRequest{
+			Schemas: []string{scim.SchemaPatchOp},
+			Operations: []scim.PatchOperation{{
+				Op:    "replace",
+				Value: json.RawMessage(`{"active":true}`),
+			}},
+		}, &patched)
+		require.Equal(t, http.StatusOK, status)
+		require.True(t, *patched.Active)
+		user, err = client.User(context.Background(), created.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserStatusActive, user.Status)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{SCIMAPIKey: []byte(apiKey)})
+		coderdtest.CreateFirstUser(t, client)
+		status := request(t, client, apiKey, http.MethodGet, "/Users/"+uuid.NewString(), nil, nil)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+}
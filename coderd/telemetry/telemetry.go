@@ -41,6 +41,7 @@ type Options struct {
 	BuiltinPostgres   bool
 	DeploymentID      string
 	GitHubOAuth       bool
+	OIDCAuth          bool
 	Prometheus        bool
 	STUN              bool
 	SnapshotFrequency time.Duration
@@ -229,6 +230,7 @@ func (r *remoteReporter) deployment() error {
 		BuiltinPostgres: r.options.BuiltinPostgres,
 		Containerized:   containerized,
 		GitHubOAuth:     r.options.GitHubOAuth,
+		OIDCAuth:        r.options.OIDCAuth,
 		Prometheus:      r.options.Prometheus,
 		STUN:            r.options.STUN,
 		Tunnel:          r.options.Tunnel,
@@ -601,6 +603,7 @@ type Deployment struct {
 	Containerized   bool       `json:"containerized"`
 	Tunnel          bool       `json:"tunnel"`
 	GitHubOAuth     bool       `json:"github_oauth"`
+	OIDCAuth        bool       `json:"oidc_auth"`
 	Prometheus      bool       `json:"prometheus"`
 	STUN            bool       `json:"stun"`
 	OSType          string     `json:"os_type"`
@@ -6,12 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/autobuild/schedule"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
@@ -71,6 +75,15 @@ func (api *API) template(rw http.ResponseWriter, r *http.Request) {
 
 func (api *API) deleteTemplate(rw http.ResponseWriter, r *http.Request) {
 	template := httpmw.TemplateParam(r)
+	aReq, commitAudit := audit.InitRequest[database.Template](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDelete,
+	})
+	defer commitAudit()
+	aReq.Old = template
+
 	if !api.Authorize(r, rbac.ActionDelete, template) {
 		httpapi.ResourceNotFound(rw)
 		return
@@ -117,6 +130,14 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	var createTemplate codersdk.CreateTemplateRequest
 	organization := httpmw.OrganizationParam(r)
 	apiKey := httpmw.APIKey(r)
+	aReq, commitAudit := audit.InitRequest[database.Template](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+
 	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceTemplate.InOrg(organization.ID)) {
 		httpapi.ResourceNotFound(rw)
 		return
@@ -182,22 +203,67 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 		minAutostartInterval = time.Duration(*createTemplate.MinAutostartIntervalMillis) * time.Millisecond
 	}
 
+	var validErrs []codersdk.ValidationError
+	if createTemplate.InactivityTTLMillis != nil && *createTemplate.InactivityTTLMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "inactivity_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	if createTemplate.DormantDeletionTTLMillis != nil && *createTemplate.DormantDeletionTTLMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "dormant_deletion_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	defaultAutostartSchedule := ptr.NilToEmpty(createTemplate.DefaultAutostartSchedule)
+	requiredStopSchedule := ptr.NilToEmpty(createTemplate.RequiredStopSchedule)
+	var defaultTTL time.Duration
+	if createTemplate.DefaultTTLMillis != nil {
+		defaultTTL = time.Duration(*createTemplate.DefaultTTLMillis) * time.Millisecond
+	}
+	allowUserScheduleOverride := true
+	if createTemplate.AllowUserScheduleOverride != nil {
+		allowUserScheduleOverride = *createTemplate.AllowUserScheduleOverride
+	}
+	validErrs = append(validErrs, validTemplateSchedules(defaultAutostartSchedule, defaultTTL, minAutostartInterval, maxTTL, requiredStopSchedule)...)
+	if len(validErrs) > 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to create template!",
+			Validations: validErrs,
+		})
+		return
+	}
+	var inactivityTTL, dormantDeletionTTL time.Duration
+	if createTemplate.InactivityTTLMillis != nil {
+		inactivityTTL = time.Duration(*createTemplate.InactivityTTLMillis) * time.Millisecond
+	}
+	if createTemplate.DormantDeletionTTLMillis != nil {
+		dormantDeletionTTL = time.Duration(*createTemplate.DormantDeletionTTLMillis) * time.Millisecond
+	}
+
 	var dbTemplate database.Template
 	var template codersdk.Template
 	err = api.Database.InTx(func(db database.Store) error {
 		now := database.Now()
 		dbTemplate, err = db.InsertTemplate(r.Context(), database.InsertTemplateParams{
-			ID:                   uuid.New(),
-			CreatedAt:            now,
-			UpdatedAt:            now,
-			OrganizationID:       organization.ID,
-			Name:                 createTemplate.Name,
-			Provisioner:          importJob.Provisioner,
-			ActiveVersionID:      templateVersion.ID,
-			Description:          createTemplate.Description,
-			MaxTtl:               int64(maxTTL),
-			MinAutostartInterval: int64(minAutostartInterval),
-			CreatedBy:            apiKey.UserID,
+			ID:                        uuid.New(),
+			CreatedAt:                 now,
+			UpdatedAt:                 now,
+			OrganizationID:            organization.ID,
+			Name:                      createTemplate.Name,
+			Provisioner:               importJob.Provisioner,
+			ActiveVersionID:           templateVersion.ID,
+			Description:               createTemplate.Description,
+			MaxTtl:                    int64(maxTTL),
+			MinAutostartInterval:      int64(minAutostartInterval),
+			CreatedBy:                 apiKey.UserID,
+			CostUnits:                 createTemplate.CostUnits,
+			InactivityTtl:             int64(inactivityTTL),
+			DormantDeletionTtl:        int64(dormantDeletionTTL),
+			DefaultAutostartSchedule:  defaultAutostartSchedule,
+			DefaultTtl:                int64(defaultTTL),
+			RequiredStopSchedule:      requiredStopSchedule,
+			AllowUserScheduleOverride: allowUserScheduleOverride,
+			// New templates can be used by every member of the
+			// organization until their access list is changed.
+			GroupACL: database.TemplateACL{
+				organization.ID.String(): []rbac.Action{rbac.ActionRead},
+			},
 		})
 		if err != nil {
 			return xerrors.Errorf("insert template: %s", err)
@@ -246,6 +312,7 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	aReq.New = dbTemplate
 
 	api.Telemetry.Report(&telemetry.Snapshot{
 		Templates:        []telemetry.Template{telemetry.ConvertTemplate(dbTemplate)},
@@ -359,6 +426,15 @@ func (api *API) templateByOrganizationAndName(rw http.ResponseWriter, r *http.Re
 
 func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	template := httpmw.TemplateParam(r)
+	aReq, commitAudit := audit.InitRequest[database.Template](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = template
+
 	if !api.Authorize(r, rbac.ActionUpdate, template) {
 		httpapi.ResourceNotFound(rw)
 		return
@@ -376,7 +452,60 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.MinAutostartIntervalMillis < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "min_autostart_interval_ms", Detail: "Must be a positive integer."})
 	}
+	if req.CostUnits != nil && *req.CostUnits < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "cost_units", Detail: "Must be a positive integer."})
+	}
+	if req.InactivityTTLMillis != nil && *req.InactivityTTLMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "inactivity_ttl_ms", Detail: "Must be a positive integer."})
+	}
+	if req.DormantDeletionTTLMillis != nil && *req.DormantDeletionTTLMillis < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "dormant_deletion_ttl_ms", Detail: "Must be a positive integer."})
+	}
+
+	// Update template metadata -- empty fields are not overwritten.
+	desc := req.Description
+	maxTTL := time.Duration(req.MaxTTLMillis) * time.Millisecond
+	minAutostartInterval := time.Duration(req.MinAutostartIntervalMillis) * time.Millisecond
+	costUnits := template.CostUnits
+	inactivityTTL := time.Duration(template.InactivityTtl)
+	dormantDeletionTTL := time.Duration(template.DormantDeletionTtl)
+	defaultAutostartSchedule := template.DefaultAutostartSchedule
+	defaultTTL := time.Duration(template.DefaultTtl)
+	requiredStopSchedule := template.RequiredStopSchedule
+	allowUserScheduleOverride := template.AllowUserScheduleOverride
+
+	if desc == "" {
+		desc = template.Description
+	}
+	if maxTTL == 0 {
+		maxTTL = time.Duration(template.MaxTtl)
+	}
+	if minAutostartInterval == 0 {
+		minAutostartInterval = time.Duration(template.MinAutostartInterval)
+	}
+	if req.CostUnits != nil {
+		costUnits = *req.CostUnits
+	}
+	if req.InactivityTTLMillis != nil {
+		inactivityTTL = time.Duration(*req.InactivityTTLMillis) * time.Millisecond
+	}
+	if req.DormantDeletionTTLMillis != nil {
+		dormantDeletionTTL = time.Duration(*req.DormantDeletionTTLMillis) * time.Millisecond
+	}
+	if req.DefaultAutostartSchedule != nil {
+		defaultAutostartSchedule = *req.DefaultAutostartSchedule
+	}
+	if req.DefaultTTLMillis != nil {
+		defaultTTL = time.Duration(*req.DefaultTTLMillis) * time.Millisecond
+	}
+	if req.RequiredStopSchedule != nil {
+		requiredStopSchedule = *req.RequiredStopSchedule
+	}
+	if req.AllowUserScheduleOverride != nil {
+		allowUserScheduleOverride = *req.AllowUserScheduleOverride
+	}
 
+	validErrs = append(validErrs, validTemplateSchedules(defaultAutostartSchedule, defaultTTL, minAutostartInterval, maxTTL, requiredStopSchedule)...)
 	if len(validErrs) > 0 {
 		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
 			Message:     "Invalid request to update template metadata!",
@@ -403,31 +532,30 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 
 		if req.Description == template.Description &&
 			req.MaxTTLMillis == time.Duration(template.MaxTtl).Milliseconds() &&
-			req.MinAutostartIntervalMillis == time.Duration(template.MinAutostartInterval).Milliseconds() {
+			req.MinAutostartIntervalMillis == time.Duration(template.MinAutostartInterval).Milliseconds() &&
+			(req.CostUnits == nil || *req.CostUnits == template.CostUnits) &&
+			(req.InactivityTTLMillis == nil || *req.InactivityTTLMillis == time.Duration(template.InactivityTtl).Milliseconds()) &&
+			(req.DormantDeletionTTLMillis == nil || *req.DormantDeletionTTLMillis == time.Duration(template.DormantDeletionTtl).Milliseconds()) &&
+			defaultAutostartSchedule == template.DefaultAutostartSchedule &&
+			int64(defaultTTL) == template.DefaultTtl &&
+			requiredStopSchedule == template.RequiredStopSchedule &&
+			allowUserScheduleOverride == template.AllowUserScheduleOverride {
 			return nil
 		}
 
-		// Update template metadata -- empty fields are not overwritten.
-		desc := req.Description
-		maxTTL := time.Duration(req.MaxTTLMillis) * time.Millisecond
-		minAutostartInterval := time.Duration(req.MinAutostartIntervalMillis) * time.Millisecond
-
-		if desc == "" {
-			desc = template.Description
-		}
-		if maxTTL == 0 {
-			maxTTL = time.Duration(template.MaxTtl)
-		}
-		if minAutostartInterval == 0 {
-			minAutostartInterval = time.Duration(template.MinAutostartInterval)
-		}
-
 		if err := s.UpdateTemplateMetaByID(r.Context(), database.UpdateTemplateMetaByIDParams{
-			ID:                   template.ID,
-			UpdatedAt:            database.Now(),
-			Description:          desc,
-			MaxTtl:               int64(maxTTL),
-			MinAutostartInterval: int64(minAutostartInterval),
+			ID:                        template.ID,
+			UpdatedAt:                 database.Now(),
+			Description:               desc,
+			MaxTtl:                    int64(maxTTL),
+			MinAutostartInterval:      int64(minAutostartInterval),
+			CostUnits:                 costUnits,
+			InactivityTtl:             int64(inactivityTTL),
+			DormantDeletionTtl:        int64(dormantDeletionTTL),
+			DefaultAutostartSchedule:  defaultAutostartSchedule,
+			DefaultTtl:                int64(defaultTTL),
+			RequiredStopSchedule:      requiredStopSchedule,
+			AllowUserScheduleOverride: allowUserScheduleOverride,
 		}); err != nil {
 			return err
 		}
@@ -447,9 +575,11 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	if updated.UpdatedAt.IsZero() {
+		aReq.New = template
 		httpapi.Write(rw, http.StatusNotModified, nil)
 		return
 	}
+	aReq.New = updated
 
 	createdByNameMap, err := getCreatedByNamesByTemplateIDs(r.Context(), api.Database, []database.Template{updated})
 	if err != nil {
@@ -463,6 +593,236 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(rw, http.StatusOK, convertTemplate(updated, count, createdByNameMap[updated.ID.String()]))
 }
 
+// templateACL lists the users and groups granted access to a template.
+func (api *API) templateACL(rw http.ResponseWriter, r *http.Request) {
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionRead, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	acl := codersdk.TemplateACL{
+		Users:  make([]codersdk.TemplateUser, 0, len(template.UserACL)),
+		Groups: make([]codersdk.TemplateGroup, 0, len(template.GroupACL)),
+	}
+	for userID, actions := range template.UserACL {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			continue
+		}
+		user, err := api.Database.GetUserByID(r.Context(), id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching user.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		acl.Users = append(acl.Users, codersdk.TemplateUser{
+			User: convertUser(user, []uuid.UUID{template.OrganizationID}, api.siteRoleDisplayNames(r.Context())),
+			Role: convertTemplateRole(actions),
+		})
+	}
+	for groupID, actions := range template.GroupACL {
+		id, err := uuid.Parse(groupID)
+		if err != nil {
+			continue
+		}
+		if id == template.OrganizationID {
+			acl.Groups = append(acl.Groups, codersdk.TemplateGroup{
+				Group: everyoneGroup(template.OrganizationID),
+				Role:  convertTemplateRole(actions),
+			})
+			continue
+		}
+		group, err := api.Database.GetGroupByID(r.Context(), id)
+		if errors.Is(err, sql.ErrNoRows) {
+			// The group has been deleted.
+			continue
+		}
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		converted, err := api.convertGroupWithMembers(r.Context(), group)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching group members.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		acl.Groups = append(acl.Groups, codersdk.TemplateGroup{
+			Group: converted,
+			Role:  convertTemplateRole(actions),
+		})
+	}
+	sort.Slice(acl.Users, func(i, j int) bool {
+		return acl.Users[i].User.Username < acl.Users[j].User.Username
+	})
+	sort.Slice(acl.Groups, func(i, j int) bool {
+		return acl.Groups[i].Group.Name < acl.Groups[j].Group.Name
+	})
+	httpapi.Write(rw, http.StatusOK, acl)
+}
+
+// patchTemplateACL grants or revokes the access of users and groups to a
+// template.
+func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
+	template := httpmw.TemplateParam(r)
+	aReq, commitAudit := audit.InitRequest[database.Template](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = template
+
+	if !api.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.UpdateTemplateACL
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	var (
+		userACL   = copyTemplateACL(template.UserACL)
+		groupACL  = copyTemplateACL(template.GroupACL)
+		validErrs []codersdk.ValidationError
+	)
+	for userID, role := range req.UserPerms {
+		actions, ok := templateRoleActions(role)
+		if !ok {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "user_perms", Detail: fmt.Sprintf("%q is not a valid template role.", role)})
+			continue
+		}
+		if role == codersdk.TemplateRoleDeleted {
+			delete(userACL, userID)
+			continue
+		}
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "user_perms", Detail: fmt.Sprintf("%q is not a valid UUID.", userID)})
+			continue
+		}
+		_, err = api.Database.GetOrganizationMemberByUserID(r.Context(), database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: template.OrganizationID,
+			UserID:         id,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "user_perms", Detail: fmt.Sprintf("User %q is not a member of the organization.", userID)})
+			continue
+		}
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching organization member.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		userACL[userID] = actions
+	}
+	for groupID, role := range req.GroupPerms {
+		actions, ok := templateRoleActions(role)
+		if !ok {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "group_perms", Detail: fmt.Sprintf("%q is not a valid template role.", role)})
+			continue
+		}
+		if role == codersdk.TemplateRoleDeleted {
+			delete(groupACL, groupID)
+			continue
+		}
+		id, err := uuid.Parse(groupID)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "group_perms", Detail: fmt.Sprintf("%q is not a valid UUID.", groupID)})
+			continue
+		}
+		if id != template.OrganizationID {
+			group, err := api.Database.GetGroupByID(r.Context(), id)
+			if errors.Is(err, sql.ErrNoRows) || (err == nil && group.OrganizationID != template.OrganizationID) {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "group_perms", Detail: fmt.Sprintf("Group %q does not exist in the organization.", groupID)})
+				continue
+			}
+			if err != nil {
+				httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching group.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+		}
+		groupACL[groupID] = actions
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update template ACL!",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	err := api.Database.UpdateTemplateACLByID(r.Context(), database.UpdateTemplateACLByIDParams{
+		ID:       template.ID,
+		GroupACL: groupACL,
+		UserACL:  userACL,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template ACL.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	updated := template
+	updated.GroupACL = groupACL
+	updated.UserACL = userACL
+	aReq.New = updated
+
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "Successfully updated template ACL!",
+	})
+}
+
+// templateRoleActions returns the actions a template role allows.
+func templateRoleActions(role codersdk.TemplateRole) ([]rbac.Action, bool) {
+	switch role {
+	case codersdk.TemplateRoleAdmin:
+		return []rbac.Action{rbac.WildcardSymbol}, true
+	case codersdk.TemplateRoleUse:
+		return []rbac.Action{rbac.ActionRead}, true
+	case codersdk.TemplateRoleDeleted:
+		return nil, true
+	}
+	return nil, false
+}
+
+func convertTemplateRole(actions []rbac.Action) codersdk.TemplateRole {
+	for _, action := range actions {
+		if action == rbac.WildcardSymbol {
+			return codersdk.TemplateRoleAdmin
+		}
+	}
+	return codersdk.TemplateRoleUse
+}
+
+func copyTemplateACL(acl database.TemplateACL) database.TemplateACL {
+	copied := make(database.TemplateACL, len(acl))
+	for id, actions := range acl {
+		copied[id] = actions
+	}
+	return copied
+}
+
 func getCreatedByNamesByTemplateIDs(ctx context.Context, db database.Store, templates []database.Template) (map[string]string, error) {
 	creators := make(map[string]string, len(templates))
 	for _, template := range templates {
@@ -509,5 +869,35 @@ func convertTemplate(template database.Template, workspaceOwnerCount uint32, cre
 		MinAutostartIntervalMillis: time.Duration(template.MinAutostartInterval).Milliseconds(),
 		CreatedByID:                template.CreatedBy,
 		CreatedByName:              createdByName,
+		CostUnits:                  template.CostUnits,
+		InactivityTTLMillis:        time.Duration(template.InactivityTtl).Milliseconds(),
+		DormantDeletionTTLMillis:   time.Duration(template.DormantDeletionTtl).Milliseconds(),
+		DefaultAutostartSchedule:   template.DefaultAutostartSchedule,
+		DefaultTTLMillis:           time.Duration(template.DefaultTtl).Milliseconds(),
+		RequiredStopSchedule:       template.RequiredStopSchedule,
+		AllowUserScheduleOverride:  template.AllowUserScheduleOverride,
+	}
+}
+
+// validTemplateSchedules validates the default autostart schedule and TTL of
+// a template against its limits, and its required stop schedule.
+func validTemplateSchedules(defaultAutostartSchedule string, defaultTTL, minAutostartInterval, maxTTL time.Duration, requiredStopSchedule string) []codersdk.ValidationError {
+	var validErrs []codersdk.ValidationError
+	if _, err := validWorkspaceSchedule(&defaultAutostartSchedule, minAutostartInterval); err != nil {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "default_autostart_schedule", Detail: err.Error()})
+	}
+	if defaultTTL != 0 {
+		if _, err := validWorkspaceTTLMillis(ptr.Ref(defaultTTL.Milliseconds()), maxTTL); err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "default_ttl_ms", Detail: err.Error()})
+		}
+	}
+	if requiredStopSchedule != "" {
+		// The timezone is the one of each workspace owner.
+		if strings.HasPrefix(requiredStopSchedule, "CRON_TZ=") {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "required_stop_schedule", Detail: "Must not specify a timezone."})
+		} else if _, err := schedule.Weekly(requiredStopSchedule); err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "required_stop_schedule", Detail: err.Error()})
+		}
 	}
+	return validErrs
 }
@@ -10,7 +10,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
@@ -243,6 +245,80 @@ func TestPatchTemplateMeta(t *testing.T) {
 		assert.Equal(t, template.MinAutostartIntervalMillis, updated.MinAutostartIntervalMillis)
 	})
 
+	t.Run("Dormancy", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.InactivityTTLMillis = ptr.Ref((7 * 24 * time.Hour).Milliseconds())
+		})
+		assert.Equal(t, (7 * 24 * time.Hour).Milliseconds(), template.InactivityTTLMillis)
+		assert.Zero(t, template.DormantDeletionTTLMillis)
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DormantDeletionTTLMillis: ptr.Ref((30 * 24 * time.Hour).Milliseconds()),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, template.InactivityTTLMillis, updated.InactivityTTLMillis)
+		assert.Equal(t, (30 * 24 * time.Hour).Milliseconds(), updated.DormantDeletionTTLMillis)
+
+		// Zero disables dormancy.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			InactivityTTLMillis: ptr.Ref(int64(0)),
+		})
+		require.NoError(t, err)
+		assert.Zero(t, updated.InactivityTTLMillis)
+		assert.Equal(t, (30 * 24 * time.Hour).Milliseconds(), updated.DormantDeletionTTLMillis)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			InactivityTTLMillis: ptr.Ref(int64(-1)),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Schedules", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.RequiredStopSchedule = ptr.Ref("0 2 * * *")
+		})
+		assert.Equal(t, "0 2 * * *", template.RequiredStopSchedule)
+		assert.True(t, template.AllowUserScheduleOverride)
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			DefaultAutostartSchedule:  ptr.Ref("CRON_TZ=Europe/Dublin 30 9 * * 1-5"),
+			DefaultTTLMillis:          ptr.Ref((4 * time.Hour).Milliseconds()),
+			AllowUserScheduleOverride: ptr.Ref(false),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "CRON_TZ=Europe/Dublin 30 9 * * 1-5", updated.DefaultAutostartSchedule)
+		assert.Equal(t, (4 * time.Hour).Milliseconds(), updated.DefaultTTLMillis)
+		assert.Equal(t, "0 2 * * *", updated.RequiredStopSchedule)
+		assert.False(t, updated.AllowUserScheduleOverride)
+
+		// The required stop is in the timezone of each owner.
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			RequiredStopSchedule: ptr.Ref("CRON_TZ=Europe/Dublin 0 2 * * *"),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// The default TTL must be below the maximum.
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxTTLMillis: time.Hour.Milliseconds(),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
 	t.Run("Invalid", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
@@ -288,6 +364,28 @@ func TestDeleteTemplate(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("AuditLog", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		err := client.DeleteTemplate(context.Background(), template.ID)
+		require.NoError(t, err)
+
+		logs := auditor.AuditLogs()
+		require.NotEmpty(t, logs)
+		alog := logs[len(logs)-1]
+		require.Equal(t, database.AuditActionDelete, alog.Action)
+		require.Equal(t, database.ResourceTypeTemplate, alog.ResourceType)
+		require.Equal(t, template.ID, alog.ResourceID)
+		require.Equal(t, template.Name, alog.ResourceTarget)
+		require.Equal(t, user.UserID, alog.UserID)
+		require.Equal(t, user.OrganizationID, alog.OrganizationID)
+		require.Equal(t, int32(http.StatusOK), alog.StatusCode)
+	})
+
 	t.Run("Workspaces", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
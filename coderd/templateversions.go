@@ -12,6 +12,7 @@ import (
 	"github.com/moby/moby/pkg/namesgenerator"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
@@ -22,7 +23,8 @@ import (
 
 func (api *API) templateVersion(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionRead, templateVersion.RBACObject(template)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -41,7 +43,8 @@ func (api *API) templateVersion(rw http.ResponseWriter, r *http.Request) {
 
 func (api *API) patchCancelTemplateVersion(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionUpdate, templateVersion) {
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionUpdate, templateVersion.RBACObject(template)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -87,7 +90,8 @@ func (api *API) patchCancelTemplateVersion(rw http.ResponseWriter, r *http.Reque
 
 func (api *API) templateVersionSchema(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionRead, templateVersion.RBACObject(template)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -135,7 +139,8 @@ func (api *API) templateVersionSchema(rw http.ResponseWriter, r *http.Request) {
 func (api *API) templateVersionParameters(rw http.ResponseWriter, r *http.Request) {
 	apiKey := httpmw.APIKey(r)
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionRead, templateVersion.RBACObject(template)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -179,7 +184,8 @@ func (api *API) templateVersionParameters(rw http.ResponseWriter, r *http.Reques
 func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Request) {
 	apiKey := httpmw.APIKey(r)
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionRead, templateVersion.RBACObject(template)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -341,9 +347,10 @@ func (api *API) patchTemplateVersionDryRunCancel(rw http.ResponseWriter, r *http
 func (api *API) fetchTemplateVersionDryRunJob(rw http.ResponseWriter, r *http.Request) (database.ProvisionerJob, bool) {
 	var (
 		templateVersion = httpmw.TemplateVersionParam(r)
+		template        = httpmw.TemplateParam(r)
 		jobID           = chi.URLParam(r, "jobID")
 	)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	if !api.Authorize(r, rbac.ActionRead, templateVersion.RBACObject(template)) {
 		httpapi.ResourceNotFound(rw)
 		return database.ProvisionerJob{}, false
 	}
@@ -530,6 +537,15 @@ func (api *API) templateVersionByName(rw http.ResponseWriter, r *http.Request) {
 
 func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Request) {
 	template := httpmw.TemplateParam(r)
+	aReq, commitAudit := audit.InitRequest[database.Template](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = template
+
 	if !api.Authorize(r, rbac.ActionUpdate, template) {
 		httpapi.ResourceNotFound(rw)
 		return
@@ -578,6 +594,10 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	newTemplate := template
+	newTemplate.ActiveVersionID = req.ID
+	aReq.New = newTemplate
+
 	httpapi.Write(rw, http.StatusOK, codersdk.Response{
 		Message: "Updated the active template version!",
 	})
@@ -587,6 +607,14 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *http.Request) {
 	apiKey := httpmw.APIKey(r)
 	organization := httpmw.OrganizationParam(r)
+	aReq, commitAudit := audit.InitRequest[database.TemplateVersion](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+
 	var req codersdk.CreateTemplateVersionRequest
 	if !httpapi.Read(rw, r, &req) {
 		return
@@ -747,6 +775,7 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 		})
 		return
 	}
+	aReq.New = templateVersion
 
 	httpapi.Write(rw, http.StatusCreated, convertTemplateVersion(templateVersion, convertProvisionerJob(provisionerJob)))
 }
@@ -758,7 +787,8 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 // return agents associated with any particular workspace.
 func (api *API) templateVersionResources(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionRead, templateVersion.RBACObject(template)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -780,7 +810,8 @@ func (api *API) templateVersionResources(rw http.ResponseWriter, r *http.Request
 // Eg: Logs returned from 'terraform plan' when uploading a new terraform file.
 func (api *API) templateVersionLogs(rw http.ResponseWriter, r *http.Request) {
 	templateVersion := httpmw.TemplateVersionParam(r)
-	if !api.Authorize(r, rbac.ActionRead, templateVersion) {
+	template := httpmw.TemplateParam(r)
+	if !api.Authorize(r, rbac.ActionRead, templateVersion.RBACObject(template)) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -0,0 +1,94 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/totp"
+)
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors from RFC 6238 Appendix B, truncated to 6 digits.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	for _, c := range []struct {
+		Time int64
+		Code string
+	}{
+		{Time: 59, Code: "287082"},
+		{Time: 1111111109, Code: "081804"},
+		{Time: 1111111111, Code: "050471"},
+		{Time: 1234567890, Code: "005924"},
+		{Time: 2000000000, Code: "279037"},
+		{Time: 20000000000, Code: "353130"},
+	} {
+		code, err := totp.Code(secret, time.Unix(c.Time, 0))
+		require.NoError(t, err)
+		require.Equal(t, c.Code, code, "time %d", c.Time)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	now := time.Unix(1660000000, 0)
+
+	t.Run("Current", func(t *testing.T) {
+		t.Parallel()
+		code, err := totp.Code(secret, now)
+		require.NoError(t, err)
+		step, ok, err := totp.Validate(secret, code, now)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, totp.Step(now), step)
+	})
+
+	t.Run("Skew", func(t *testing.T) {
+		t.Parallel()
+		code, err := totp.Code(secret, now.Add(-totp.Period))
+		require.NoError(t, err)
+		step, ok, err := totp.Validate(secret, code, now)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, totp.Step(now)-1, step)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		t.Parallel()
+		code, err := totp.Code(secret, now.Add(-2*totp.Period))
+		require.NoError(t, err)
+		_, ok, err := totp.Validate(secret, code, now)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		t.Parallel()
+		_, ok, err := totp.Validate(secret, "12345", now)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("InvalidSecret", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := totp.Validate("not base32!", "123456", now)
+		require.Error(t, err)
+	})
+}
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+	u, err := url.Parse(totp.URL("Coder", "user@coder.com", "SECRET"))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Coder:user@coder.com", u.Path)
+	require.Equal(t, "SECRET", u.Query().Get("secret"))
+	require.Equal(t, "Coder", u.Query().Get("issuer"))
+}
@@ -6,14 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/go-github/v43/github"
 	"github.com/google/uuid"
 	"golang.org/x/oauth2"
+	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/oidc"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -30,16 +33,72 @@ type GithubOAuth2Config struct {
 	ListEmails                  func(ctx context.Context, client *http.Client) ([]*github.UserEmail, error)
 	ListOrganizationMemberships func(ctx context.Context, client *http.Client) ([]*github.Membership, error)
 	Team                        func(ctx context.Context, client *http.Client, org, team string) (*github.Team, error)
+	ListTeams                   func(ctx context.Context, client *http.Client) ([]*github.Team, error)
 
 	AllowSignups       bool
 	AllowOrganizations []string
 	AllowTeams         []GithubOAuth2Team
+	// TeamRoles grants roles to members of Github teams, formatted as
+	// "<organization>/<team>".
+	TeamRoles GroupRoles
+}
+
+// OIDCConfig exposes required functions for the OpenID Connect authentication flow.
+type OIDCConfig struct {
+	httpmw.OAuth2Config
+	Verifier *oidc.Verifier
+
+	AllowSignups bool
+	// EmailDomains restricts logins to users with an email address in one of
+	// these domains. All domains are allowed if empty.
+	EmailDomains []string
+	// UsernameField is the ID token claim used as the username of new users.
+	// Defaults to "preferred_username". If the claim is missing, the local
+	// part of the email address is used.
+	UsernameField string
+	// EmailField is the ID token claim containing the user's email address.
+	// Defaults to "email".
+	EmailField string
+	// GroupsField is the ID token claim listing the groups the user is a
+	// member of. Defaults to "groups".
+	GroupsField string
+	// GroupRoles grants roles to members of the groups in GroupsField.
+	GroupRoles GroupRoles
+}
+
+func (c *OIDCConfig) emailField() string {
+	if c.EmailField == "" {
+		return "email"
+	}
+	return c.EmailField
+}
+
+// groups returns the groups listed in the GroupsField claim.
+func (c *OIDCConfig) groups(claims map[string]interface{}) []string {
+	groupsField := c.GroupsField
+	if groupsField == "" {
+		groupsField = "groups"
+	}
+	// Providers send a single group as a string rather than a list.
+	var groups []string
+	switch claim := claims[groupsField].(type) {
+	case string:
+		groups = []string{claim}
+	case []interface{}:
+		for _, group := range claim {
+			if group, ok := group.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
 }
 
 func (api *API) userAuthMethods(rw http.ResponseWriter, _ *http.Request) {
 	httpapi.Write(rw, http.StatusOK, codersdk.AuthMethods{
 		Password: true,
 		Github:   api.GithubOAuth2Config != nil,
+		OIDC:     api.OIDCConfig != nil,
 	})
 }
 
@@ -187,6 +246,25 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if len(api.GithubOAuth2Config.TeamRoles) > 0 {
+		groups, err := api.githubTeams(r.Context(), oauthClient)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching authenticated Github user teams.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		err = api.syncGroupRoles(r, user, api.GithubOAuth2Config.TeamRoles, groups)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error syncing roles from Github teams.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
 	_, created := api.createAPIKey(rw, r, database.InsertAPIKeyParams{
 		UserID:            user.ID,
 		LoginType:         database.LoginTypeGithub,
@@ -204,3 +282,182 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 	}
 	http.Redirect(rw, r, redirect, http.StatusTemporaryRedirect)
 }
+
+// githubTeams returns the teams of the authenticated Github user, formatted as
+// "<organization>/<team>".
+func (api *API) githubTeams(ctx context.Context, client *http.Client) ([]string, error) {
+	teams, err := api.GithubOAuth2Config.ListTeams(ctx, client)
+	if err != nil {
+		return nil, xerrors.Errorf("list teams: %w", err)
+	}
+	groups := make([]string, 0, len(teams))
+	for _, team := range teams {
+		groups = append(groups, team.GetOrganization().GetLogin()+"/"+team.GetSlug())
+	}
+	return groups, nil
+}
+
+func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
+	state := httpmw.OAuth2(r)
+
+	rawIDToken, ok := state.Token.Extra("id_token").(string)
+	if !ok {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "id_token not found in response payload. Ensure your OIDC callback is configured correctly!",
+		})
+		return
+	}
+
+	idToken, err := api.OIDCConfig.Verifier.Verify(r.Context(), rawIDToken, state.Nonce)
+	if err != nil {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to verify OIDC token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var claims map[string]interface{}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to extract OIDC claims.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	emailField := api.OIDCConfig.emailField()
+	email, _ := claims[emailField].(string)
+	if email == "" {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("OIDC token is missing the %q claim.", emailField),
+		})
+		return
+	}
+	// The email_verified claim is optional, so only reject emails that are
+	// explicitly unverified.
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Verify the %q email address on your OIDC provider to authenticate!", email),
+		})
+		return
+	}
+	if len(api.OIDCConfig.EmailDomains) > 0 {
+		allowed := false
+		for _, domain := range api.OIDCConfig.EmailDomains {
+			if strings.HasSuffix(strings.ToLower(email), "@"+strings.ToLower(domain)) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+				Message: fmt.Sprintf("Your email %q is not in the allowed domains %q!", email, strings.Join(api.OIDCConfig.EmailDomains, ", ")),
+			})
+			return
+		}
+	}
+
+	user, err := api.Database.GetUserByEmailOrUsername(r.Context(), database.GetUserByEmailOrUsernameParams{
+		Email: email,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: fmt.Sprintf("Internal error fetching user by email %q.", email),
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// If the user doesn't exist, create a new one!
+	if errors.Is(err, sql.ErrNoRows) {
+		if !api.OIDCConfig.AllowSignups {
+			httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+				Message: "Signups are disabled for OIDC authentication!",
+			})
+			return
+		}
+
+		usernameField := api.OIDCConfig.UsernameField
+		if usernameField == "" {
+			usernameField = "preferred_username"
+		}
+		username, _ := claims[usernameField].(string)
+		if username == "" {
+			username = strings.Split(email, "@")[0]
+		}
+		username = httpapi.UsernameFrom(username)
+		if username == "" {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Couldn't create a valid username from the %q claim.", usernameField),
+			})
+			return
+		}
+		_, err = api.Database.GetUserByEmailOrUsername(r.Context(), database.GetUserByEmailOrUsernameParams{
+			Username: username,
+		})
+		if err == nil {
+			httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("The username %q is already taken by another user. Contact an administrator to create your account.", username),
+			})
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: fmt.Sprintf("Internal error fetching user by username %q.", username),
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		var organizationID uuid.UUID
+		organizations, _ := api.Database.GetOrganizations(r.Context())
+		if len(organizations) > 0 {
+			// Add the user to the first organization. Once multi-organization
+			// support is added, we should enable a configuration map of user
+			// email to organization.
+			organizationID = organizations[0].ID
+		}
+		user, _, err = api.createUser(r.Context(), codersdk.CreateUserRequest{
+			Email:          email,
+			Username:       username,
+			OrganizationID: organizationID,
+		})
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error creating user.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	if len(api.OIDCConfig.GroupRoles) > 0 {
+		err = api.syncGroupRoles(r, user, api.OIDCConfig.GroupRoles, api.OIDCConfig.groups(claims))
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error syncing roles from OIDC groups.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	_, created := api.createAPIKey(rw, r, database.InsertAPIKeyParams{
+		UserID:            user.ID,
+		LoginType:         database.LoginTypeOIDC,
+		OAuthAccessToken:  state.Token.AccessToken,
+		OAuthRefreshToken: state.Token.RefreshToken,
+		OAuthExpiry:       state.Token.Expiry,
+	})
+	if !created {
+		return
+	}
+
+	redirect := state.Redirect
+	if redirect == "" {
+		redirect = "/"
+	}
+	http.Redirect(rw, r, redirect, http.StatusTemporaryRedirect)
+}
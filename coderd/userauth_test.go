@@ -3,8 +3,11 @@ package coderd_test
 import (
 	"context"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v43/github"
 	"github.com/stretchr/testify/require"
@@ -12,7 +15,12 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/coderd"
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/oidc"
+	"github.com/coder/coder/coderd/oidc/oidctest"
+	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -51,6 +59,17 @@ func TestUserAuthMethods(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, methods.Password)
 		require.True(t, methods.Github)
+		require.False(t, methods.OIDC)
+	})
+	t.Run("OIDC", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			OIDCConfig: &coderd.OIDCConfig{},
+		})
+		methods, err := client.AuthMethods(context.Background())
+		require.NoError(t, err)
+		require.True(t, methods.Password)
+		require.True(t, methods.OIDC)
 	})
 }
 
@@ -242,6 +261,466 @@ func TestUserOAuth2Github(t *testing.T) {
 		resp := oauth2Callback(t, client)
 		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
 	})
+	t.Run("TeamRoles", func(t *testing.T) {
+		t.Parallel()
+		var teams atomic.Value
+		teams.Store([]*github.Team{{
+			Slug: github.String("ops"),
+			Organization: &github.Organization{
+				Login: github.String("coder"),
+			},
+		}})
+		client := coderdtest.New(t, &coderdtest.Options{
+			GithubOAuth2Config: &coderd.GithubOAuth2Config{
+				OAuth2Config:       &oauth2Config{},
+				AllowOrganizations: []string{"coder"},
+				AllowSignups:       true,
+				TeamRoles: coderd.GroupRoles{
+					"coder/ops": {rbac.RoleAdmin()},
+				},
+				ListOrganizationMemberships: func(ctx context.Context, client *http.Client) ([]*github.Membership, error) {
+					return []*github.Membership{{
+						Organization: &github.Organization{
+							Login: github.String("coder"),
+						},
+					}}, nil
+				},
+				ListTeams: func(ctx context.Context, client *http.Client) ([]*github.Team, error) {
+					return teams.Load().([]*github.Team), nil
+				},
+				AuthenticatedUser: func(ctx context.Context, client *http.Client) (*github.User, error) {
+					return &github.User{
+						Login: github.String("kyle"),
+					}, nil
+				},
+				ListEmails: func(ctx context.Context, client *http.Client) ([]*github.UserEmail, error) {
+					return []*github.UserEmail{{
+						Email:    github.String("kyle@coder.com"),
+						Verified: github.Bool(true),
+						Primary:  github.Bool(true),
+					}}, nil
+				},
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		userClient := codersdk.New(client.URL)
+		resp := oauth2Callback(t, userClient)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		userClient.SessionToken = authCookieValue(resp.Cookies())
+		roles, err := userClient.GetUserRoles(context.Background(), "me")
+		require.NoError(t, err)
+		require.Contains(t, roles.Roles, rbac.RoleAdmin())
+
+		// Roles that aren't mapped are left alone when the team is removed.
+		_, err = client.UpdateUserRoles(context.Background(), "kyle", codersdk.UpdateRoles{
+			Roles: []string{rbac.RoleAdmin(), "auditor"},
+		})
+		require.NoError(t, err)
+		teams.Store([]*github.Team{})
+		resp = oauth2Callback(t, userClient)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		roles, err = userClient.GetUserRoles(context.Background(), "me")
+		require.NoError(t, err)
+		require.NotContains(t, roles.Roles, rbac.RoleAdmin())
+		require.Contains(t, roles.Roles, "auditor")
+	})
+}
+
+func TestUserOIDC(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Name          string
+		Claims        map[string]interface{}
+		AllowSignups  bool
+		EmailDomains  []string
+		UsernameField string
+		EmailField    string
+		Username      string
+		StatusCode    int
+	}{{
+		Name: "EmailOnly",
+		Claims: map[string]interface{}{
+			"email": "kyle@coder.com",
+		},
+		AllowSignups: true,
+		Username:     "kyle",
+		StatusCode:   http.StatusTemporaryRedirect,
+	}, {
+		Name: "PreferredUsername",
+		Claims: map[string]interface{}{
+			"email":              "kyle@coder.com",
+			"email_verified":     true,
+			"preferred_username": "hotdog",
+		},
+		AllowSignups: true,
+		Username:     "hotdog",
+		StatusCode:   http.StatusTemporaryRedirect,
+	}, {
+		Name: "InvalidUsernameNormalized",
+		Claims: map[string]interface{}{
+			"email":              "kyle@coder.com",
+			"preferred_username": "kyle.carberry",
+		},
+		AllowSignups: true,
+		Username:     "kyle-carberry",
+		StatusCode:   http.StatusTemporaryRedirect,
+	}, {
+		Name: "CustomFields",
+		Claims: map[string]interface{}{
+			"mail":  "kyle@coder.com",
+			"login": "kc",
+		},
+		AllowSignups:  true,
+		UsernameField: "login",
+		EmailField:    "mail",
+		Username:      "kc",
+		StatusCode:    http.StatusTemporaryRedirect,
+	}, {
+		Name: "EmailDomain",
+		Claims: map[string]interface{}{
+			"email": "kyle@Coder.com",
+		},
+		AllowSignups: true,
+		EmailDomains: []string{"example.com", "coder.com"},
+		Username:     "kyle",
+		StatusCode:   http.StatusTemporaryRedirect,
+	}, {
+		Name: "EmailNotInDomain",
+		Claims: map[string]interface{}{
+			"email": "kyle@kcd.com",
+		},
+		AllowSignups: true,
+		EmailDomains: []string{"coder.com"},
+		StatusCode:   http.StatusForbidden,
+	}, {
+		Name: "EmailNotVerified",
+		Claims: map[string]interface{}{
+			"email":          "kyle@coder.com",
+			"email_verified": false,
+		},
+		AllowSignups: true,
+		StatusCode:   http.StatusForbidden,
+	}, {
+		Name:         "EmailMissing",
+		Claims:       map[string]interface{}{},
+		AllowSignups: true,
+		StatusCode:   http.StatusBadRequest,
+	}, {
+		Name: "SignupsDisabled",
+		Claims: map[string]interface{}{
+			"email": "kyle@coder.com",
+		},
+		StatusCode: http.StatusForbidden,
+	}, {
+		Name: "ExistingUser",
+		Claims: map[string]interface{}{
+			"email": coderdtest.FirstUserParams.Email,
+		},
+		Username:   coderdtest.FirstUserParams.Username,
+		StatusCode: http.StatusTemporaryRedirect,
+	}, {
+		Name: "UsernameTaken",
+		Claims: map[string]interface{}{
+			"email":              "kyle@coder.com",
+			"preferred_username": coderdtest.FirstUserParams.Username,
+		},
+		AllowSignups: true,
+		StatusCode:   http.StatusConflict,
+	}, {
+		Name: "WrongAudience",
+		Claims: map[string]interface{}{
+			"email": "kyle@coder.com",
+			"aud":   "someone-else",
+		},
+		AllowSignups: true,
+		StatusCode:   http.StatusBadRequest,
+	}} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			issuer := oidctest.NewIssuer(t)
+			issuer.SetClaims(tc.Claims)
+			config := oidcConfig(t, issuer)
+			config.AllowSignups = tc.AllowSignups
+			config.EmailDomains = tc.EmailDomains
+			config.UsernameField = tc.UsernameField
+			config.EmailField = tc.EmailField
+
+			client := coderdtest.New(t, &coderdtest.Options{
+				OIDCConfig: config,
+			})
+			_ = coderdtest.CreateFirstUser(t, client)
+
+			resp := oidcCallback(t, client)
+			require.Equal(t, tc.StatusCode, resp.StatusCode)
+			if tc.Username != "" {
+				client.SessionToken = authCookieValue(resp.Cookies())
+				user, err := client.User(context.Background(), "me")
+				require.NoError(t, err)
+				require.Equal(t, tc.Username, user.Username)
+			}
+		})
+	}
+
+	t.Run("Redirect", func(t *testing.T) {
+		t.Parallel()
+		issuer := oidctest.NewIssuer(t)
+		issuer.SetClaims(map[string]interface{}{
+			"email": "kyle@coder.com",
+		})
+		config := oidcConfig(t, issuer)
+		config.AllowSignups = true
+		client := coderdtest.New(t, &coderdtest.Options{
+			OIDCConfig: config,
+		})
+		// The callback redirects to the issuer, which redirects back to the
+		// callback with a code.
+		oauthConfig, ok := config.OAuth2Config.(*oauth2.Config)
+		require.True(t, ok)
+		oauthConfig.RedirectURL = client.URL.String() + "/api/v2/users/oidc/callback"
+
+		jar, err := cookiejar.New(nil)
+		require.NoError(t, err)
+		client.HTTPClient.Jar = jar
+		res, err := client.Request(context.Background(), http.MethodGet, "/api/v2/users/oidc/callback?redirect=%2Fworkspaces", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "/workspaces", res.Request.URL.Path)
+
+		client.SessionToken = authCookieValue(jar.Cookies(client.URL))
+		user, err := client.User(context.Background(), "me")
+		require.NoError(t, err)
+		require.Equal(t, "kyle", user.Username)
+	})
+
+	t.Run("GroupRoles", func(t *testing.T) {
+		t.Parallel()
+		issuer := oidctest.NewIssuer(t)
+		issuer.SetClaims(map[string]interface{}{
+			"email":  "kyle@coder.com",
+			"groups": []string{"ops", "eng"},
+		})
+		config := oidcConfig(t, issuer)
+		config.AllowSignups = true
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			OIDCConfig: config,
+			Auditor:    auditor,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		org, err := client.Organization(context.Background(), first.OrganizationID)
+		require.NoError(t, err)
+		config.GroupRoles = coderd.GroupRoles{
+			"ops": {rbac.RoleAdmin()},
+			"eng": {"organization-admin:" + org.Name},
+		}
+
+		resp := oidcCallback(t, client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		userClient := codersdk.New(client.URL)
+		userClient.SessionToken = authCookieValue(resp.Cookies())
+		user, err := userClient.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		// Both the site and organization role changes are audited on behalf
+		// of the user.
+		var audited []database.ResourceType
+		for _, log := range auditor.AuditLogs() {
+			if log.Action == database.AuditActionWrite {
+				require.Equal(t, user.ID, log.UserID)
+				audited = append(audited, log.ResourceType)
+			}
+		}
+		require.ElementsMatch(t, []database.ResourceType{
+			database.ResourceTypeUser,
+			database.ResourceTypeOrganizationMember,
+		}, audited)
+		roles, err := userClient.GetUserRoles(context.Background(), "me")
+		require.NoError(t, err)
+		require.Contains(t, roles.Roles, rbac.RoleAdmin())
+		require.Contains(t, roles.OrganizationRoles[org.ID], rbac.RoleOrgAdmin(org.ID))
+
+		// A single group may be sent as a string.
+		issuer.SetClaims(map[string]interface{}{
+			"email":  "kyle@coder.com",
+			"groups": "eng",
+		})
+		resp = oidcCallback(t, client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		roles, err = userClient.GetUserRoles(context.Background(), "me")
+		require.NoError(t, err)
+		require.NotContains(t, roles.Roles, rbac.RoleAdmin())
+		require.Contains(t, roles.OrganizationRoles[org.ID], rbac.RoleOrgAdmin(org.ID))
+
+		issuer.SetClaims(map[string]interface{}{
+			"email": "kyle@coder.com",
+		})
+		resp = oidcCallback(t, client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		roles, err = userClient.GetUserRoles(context.Background(), "me")
+		require.NoError(t, err)
+		require.NotContains(t, roles.OrganizationRoles[org.ID], rbac.RoleOrgAdmin(org.ID))
+	})
+
+	t.Run("GroupRolesResync", func(t *testing.T) {
+		t.Parallel()
+		issuer := oidctest.NewIssuer(t)
+		issuer.SetClaims(map[string]interface{}{
+			"email":  "kyle@coder.com",
+			"groups": []string{"ops"},
+		})
+		// The OAuth token is refreshed the next time the session is used.
+		issuer.SetTokenExpiry(-time.Minute)
+		config := oidcConfig(t, issuer)
+		config.AllowSignups = true
+		config.GroupRoles = coderd.GroupRoles{
+			"ops": {rbac.RoleAdmin()},
+		}
+		client := coderdtest.New(t, &coderdtest.Options{
+			OIDCConfig: config,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		resp := oidcCallback(t, client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		userClient := codersdk.New(client.URL)
+		userClient.SessionToken = authCookieValue(resp.Cookies())
+
+		// Removing the user from the group in the provider revokes the role
+		// from their existing session.
+		issuer.SetClaims(map[string]interface{}{
+			"email": "kyle@coder.com",
+		})
+		issuer.SetTokenExpiry(time.Hour)
+		roles, err := userClient.GetUserRoles(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		require.NotContains(t, roles.Roles, rbac.RoleAdmin())
+
+		// Sessions can't be synced with ID tokens of other users.
+		issuer.SetClaims(map[string]interface{}{
+			"email": "colin@coder.com",
+		})
+		issuer.SetTokenExpiry(-time.Minute)
+		resp = oidcCallback(t, client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		otherClient := codersdk.New(client.URL)
+		otherClient.SessionToken = authCookieValue(resp.Cookies())
+		issuer.SetClaims(map[string]interface{}{
+			"email": "kyle@coder.com",
+		})
+		_, err = otherClient.GetUserRoles(context.Background(), codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("NonceMismatch", func(t *testing.T) {
+		t.Parallel()
+		issuer := oidctest.NewIssuer(t)
+		issuer.SetClaims(map[string]interface{}{
+			"email": "kyle@coder.com",
+		})
+		config := oidcConfig(t, issuer)
+		config.AllowSignups = true
+		client := coderdtest.New(t, &coderdtest.Options{
+			OIDCConfig: config,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		// A code obtained without going through the login, so the ID token
+		// doesn't contain the nonce bound to the state.
+		client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		state := "somestate"
+		oauthURL, err := client.URL.Parse("/api/v2/users/oidc/callback?code=asd&state=" + state)
+		require.NoError(t, err)
+		req, err := http.NewRequest("GET", oauthURL.String(), nil)
+		require.NoError(t, err)
+		req.AddCookie(&http.Cookie{
+			Name:  "oauth_state",
+			Value: state,
+		})
+		res, err := client.HTTPClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		resp := oidcCallback(t, client)
+		require.Equal(t, http.StatusPreconditionRequired, resp.StatusCode)
+	})
+}
+
+func oidcConfig(t *testing.T, issuer *oidctest.Issuer) *coderd.OIDCConfig {
+	t.Helper()
+	provider, err := oidc.Discover(context.Background(), nil, issuer.URL())
+	require.NoError(t, err)
+	return &coderd.OIDCConfig{
+		OAuth2Config: &oauth2.Config{
+			ClientID: oidctest.ClientID,
+			Endpoint: provider.Endpoint(),
+			Scopes:   []string{oidc.ScopeOpenID, "email", "profile"},
+		},
+		Verifier: provider.Verifier(oidctest.ClientID),
+	}
+}
+
+func authCookieValue(cookies []*http.Cookie) string {
+	for _, cookie := range cookies {
+		if cookie.Name == codersdk.SessionTokenKey {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// oidcCallback logs in through the issuer, returning the response of the
+// callback that completes the login.
+func oidcCallback(t *testing.T, client *codersdk.Client) *http.Response {
+	t.Helper()
+	client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	callbackURL, err := client.URL.Parse("/api/v2/users/oidc/callback")
+	require.NoError(t, err)
+
+	// Starting the login sets the state cookie and redirects to the issuer.
+	start, err := client.HTTPClient.Get(callbackURL.String())
+	require.NoError(t, err)
+	_ = start.Body.Close()
+	if start.StatusCode != http.StatusTemporaryRedirect {
+		return start
+	}
+	authorizeURL, err := start.Location()
+	require.NoError(t, err)
+	query := authorizeURL.Query()
+	query.Set("redirect_uri", callbackURL.String())
+	authorizeURL.RawQuery = query.Encode()
+
+	authorize, err := client.HTTPClient.Get(authorizeURL.String())
+	require.NoError(t, err)
+	_ = authorize.Body.Close()
+	require.Equal(t, http.StatusTemporaryRedirect, authorize.StatusCode)
+	codeURL, err := authorize.Location()
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("GET", codeURL.String(), nil)
+	require.NoError(t, err)
+	for _, cookie := range start.Cookies() {
+		req.AddCookie(cookie)
+	}
+	res, err := client.HTTPClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = res.Body.Close()
+	})
+	return res
 }
 
 func oauth2Callback(t *testing.T, client *codersdk.Client) *http.Response {
@@ -0,0 +1,138 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+func (api *API) userLockout(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceUserData.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	lockout, err := api.Database.GetUserLockoutByUserID(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching lockout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, api.convertUserLockout(lockout))
+}
+
+// deleteUserLockout unlocks a user, so they can log in again before the
+// lockout expires.
+func (api *API) deleteUserLockout(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+	aReq, commitAudit := audit.InitRequest[database.UserLockout](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDelete,
+	})
+	defer commitAudit()
+	aReq.Old = database.UserLockout{UserID: user.ID}
+
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceUser.WithID(user.ID.String())) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	lockout, err := api.Database.GetUserLockoutByUserID(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching lockout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		aReq.Old = lockout
+	}
+
+	err = api.Database.DeleteUserLockoutByUserID(r.Context(), user.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting lockout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "User has been unlocked!",
+	})
+}
+
+// loginLockout returns the lockout of a user logging in with a password. It's
+// empty when lockouts are disabled or the user doesn't exist.
+func (api *API) loginLockout(ctx context.Context, userID uuid.UUID) (database.UserLockout, error) {
+	if api.LoginMaxAttempts <= 0 || userID == uuid.Nil {
+		return database.UserLockout{}, nil
+	}
+	lockout, err := api.Database.GetUserLockoutByUserID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.UserLockout{}, nil
+	}
+	return lockout, err
+}
+
+// recordFailedLogin counts a failed password login, and locks the user out
+// once they reach the maximum number of attempts. Attempts start over when
+// the lockout expires, or when the last one failed longer than the lockout
+// duration ago.
+func (api *API) recordFailedLogin(ctx context.Context, userID uuid.UUID) error {
+	if api.LoginMaxAttempts <= 0 || userID == uuid.Nil {
+		return nil
+	}
+	now := api.Now()
+	lockout, err := api.Database.IncrementUserLockoutFailedAttempts(ctx, database.IncrementUserLockoutFailedAttemptsParams{
+		UserID:      userID,
+		UpdatedAt:   now,
+		ResetBefore: now.Add(-api.LoginLockoutDuration),
+	})
+	if err != nil {
+		return xerrors.Errorf("increment failed attempts: %w", err)
+	}
+	if lockout.FailedAttempts < int32(api.LoginMaxAttempts) {
+		return nil
+	}
+	err = api.Database.UpdateUserLockout(ctx, database.UpdateUserLockoutParams{
+		UserID:         userID,
+		FailedAttempts: 0,
+		LockedUntil:    now.Add(api.LoginLockoutDuration),
+		UpdatedAt:      now,
+	})
+	if err != nil {
+		return xerrors.Errorf("lock user: %w", err)
+	}
+	return nil
+}
+
+func (api *API) convertUserLockout(lockout database.UserLockout) codersdk.UserLockout {
+	converted := codersdk.UserLockout{
+		FailedAttempts: lockout.FailedAttempts,
+	}
+	if lockout.LockedUntil.After(api.Now()) {
+		lockedUntil := lockout.LockedUntil
+		converted.LockedUntil = &lockedUntil
+	}
+	return converted
+}
@@ -0,0 +1,158 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestUserLockout(t *testing.T) {
+	t.Parallel()
+
+	login := func(client *codersdk.Client, email, password string) error {
+		_, err := client.LoginWithPassword(context.Background(), codersdk.LoginWithPasswordRequest{
+			Email:    email,
+			Password: password,
+		})
+		return err
+	}
+
+	t.Run("LockedOut", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Now:                  clock.Now,
+			LoginMaxAttempts:     3,
+			LoginLockoutDuration: time.Hour,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			require.Error(t, login(client, memberUser.Email, "badpassword"))
+		}
+		lockout, err := member.UserLockout(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, lockout.FailedAttempts)
+		require.Nil(t, lockout.LockedUntil)
+
+		require.Error(t, login(client, memberUser.Email, "badpassword"))
+		lockout, err = client.UserLockout(ctx, memberUser.ID.String())
+		require.NoError(t, err)
+		require.NotNil(t, lockout.LockedUntil)
+
+		// The correct password is rejected while locked out, the same way as
+		// an incorrect one, so lockouts don't reveal that users exist.
+		err = login(client, memberUser.Email, "testpass")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		require.Equal(t, "Incorrect email or password.", apiErr.Message)
+
+		clock.Add(time.Hour)
+		require.NoError(t, login(client, memberUser.Email, "testpass"))
+	})
+
+	t.Run("AttemptsExpire", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Now:                  clock.Now,
+			LoginMaxAttempts:     2,
+			LoginLockoutDuration: time.Hour,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		require.Error(t, login(client, memberUser.Email, "badpassword"))
+		// A failed login is forgotten once none has failed for the lockout
+		// duration.
+		clock.Add(time.Hour + time.Second)
+		require.Error(t, login(client, memberUser.Email, "badpassword"))
+		lockout, err := member.UserLockout(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, lockout.FailedAttempts)
+		require.Nil(t, lockout.LockedUntil)
+
+		clock.Add(time.Minute)
+		require.Error(t, login(client, memberUser.Email, "badpassword"))
+		lockout, err = member.UserLockout(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.NotNil(t, lockout.LockedUntil)
+	})
+
+	t.Run("SuccessResetsAttempts", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{
+			LoginMaxAttempts: 2,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		require.Error(t, login(client, memberUser.Email, "badpassword"))
+		require.NoError(t, login(client, memberUser.Email, "testpass"))
+		require.Error(t, login(client, memberUser.Email, "badpassword"))
+		require.NoError(t, login(client, memberUser.Email, "testpass"))
+	})
+
+	t.Run("Unlock", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			LoginMaxAttempts: 1,
+			Auditor:          auditor,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		require.Error(t, login(client, memberUser.Email, "badpassword"))
+		require.Error(t, login(client, memberUser.Email, "testpass"))
+
+		// Members can't unlock themselves.
+		err = member.UnlockUser(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = client.UnlockUser(ctx, memberUser.ID.String())
+		require.NoError(t, err)
+		logs := auditor.AuditLogs()
+		require.NotEmpty(t, logs)
+		alog := logs[len(logs)-1]
+		require.Equal(t, database.ResourceTypeUserLockout, alog.ResourceType)
+		require.Equal(t, database.AuditActionDelete, alog.Action)
+		require.Equal(t, memberUser.ID, alog.ResourceID)
+		require.Equal(t, int32(http.StatusOK), alog.StatusCode)
+		require.NoError(t, login(client, memberUser.Email, "testpass"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		for i := 0; i < 10; i++ {
+			require.Error(t, login(client, coderdtest.FirstUserParams.Email, "badpassword"))
+		}
+		require.NoError(t, login(client, coderdtest.FirstUserParams.Email, coderdtest.FirstUserParams.Password))
+	})
+}
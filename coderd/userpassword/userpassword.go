@@ -1,18 +1,23 @@
 package userpassword
 
 import (
+	"bufio"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/cryptorand"
 )
 
 var (
@@ -122,18 +127,127 @@ func hashWithSaltAndIter(password string, salt []byte, iter int) string {
 	return fmt.Sprintf("$%s$%d$%s$%s", hashScheme, iter, encSalt, encHash)
 }
 
+// DefaultMinLength is the minimum length of passwords when a Policy doesn't
+// set one.
+const DefaultMinLength = 8
+
+// Policy is the requirements passwords must meet when they're set.
+type Policy struct {
+	// MinLength is the minimum number of characters. It's DefaultMinLength
+	// when unset.
+	MinLength int
+	// MinCharacterClasses is the number of character classes passwords must
+	// contain: lowercase letters, uppercase letters, digits, and symbols.
+	MinCharacterClasses int
+	// Breached are known-breached passwords that are rejected. They're
+	// compared case-insensitively. See ReadBreached.
+	Breached map[string]struct{}
+	// History is the number of previous passwords, including the current
+	// one, that can't be reused.
+	History int
+}
+
 // Validate checks that the plain text password meets the minimum password requirements.
 // It returns properly formatted errors for detailed form validation on the client.
 func Validate(password string) error {
-	const (
-		minLength = 8
-		maxLength = 64
-	)
+	return Policy{}.Validate(password)
+}
+
+// Validate checks that the plain text password meets the requirements of the
+// policy. Like the package level Validate, errors are formatted for the client.
+func (p Policy) Validate(password string) error {
+	const maxLength = 64
+	minLength := p.MinLength
+	if minLength <= 0 {
+		minLength = DefaultMinLength
+	}
 	if len(password) < minLength {
 		return xerrors.Errorf("Password must be at least %d characters.", minLength)
 	}
 	if len(password) > maxLength {
 		return xerrors.Errorf("Password must be no more than %d characters.", maxLength)
 	}
+	if p.MinCharacterClasses > 0 && characterClasses(password) < p.MinCharacterClasses {
+		return xerrors.Errorf("Password must contain at least %d of: lowercase letters, uppercase letters, digits, and symbols.", p.MinCharacterClasses)
+	}
+	if _, ok := p.Breached[strings.ToLower(password)]; ok {
+		return xerrors.New("Password is known to have been breached. Choose another password.")
+	}
 	return nil
 }
+
+// generatedSymbols are the symbols generated passwords contain. They don't
+// need to be quoted in shells.
+const generatedSymbols = "%+-.:=@^_"
+
+// Generate returns a random password of length characters that contains all
+// character classes, so it meets any Policy of that minimum length.
+func Generate(length int) (string, error) {
+	classes := []string{cryptorand.Lower, cryptorand.Upper, cryptorand.Numeric, generatedSymbols}
+	if length < len(classes) {
+		return "", xerrors.Errorf("length must be at least %d", len(classes))
+	}
+	password := make([]byte, 0, length)
+	for _, class := range classes {
+		char, err := cryptorand.StringCharset(class, 1)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, char...)
+	}
+	rest, err := cryptorand.StringCharset(strings.Join(classes, ""), length-len(classes))
+	if err != nil {
+		return "", err
+	}
+	password = append(password, rest...)
+	// Shuffle, so the first characters aren't predictable by class.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := cryptorand.Intn(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// ReadBreached reads known-breached passwords for a Policy, one per line.
+func ReadBreached(r io.Reader) (map[string]struct{}, error) {
+	breached := map[string]struct{}{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		password := strings.TrimSpace(scanner.Text())
+		if password == "" {
+			continue
+		}
+		breached[strings.ToLower(password)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("read breached passwords: %w", err)
+	}
+	return breached, nil
+}
+
+// characterClasses counts the character classes in the password.
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	count := 0
+	for _, ok := range []bool{lower, upper, digit, symbol} {
+		if ok {
+			count++
+		}
+	}
+	return count
+}
@@ -5,6 +5,7 @@
 package userpassword_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -55,3 +56,53 @@ func TestUserPassword(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+		require.Error(t, userpassword.Policy{}.Validate("short"))
+		require.Error(t, userpassword.Policy{}.Validate(strings.Repeat("a", 65)))
+		require.NoError(t, userpassword.Policy{}.Validate("password"))
+	})
+
+	t.Run("MinLength", func(t *testing.T) {
+		t.Parallel()
+		policy := userpassword.Policy{MinLength: 12}
+		require.Error(t, policy.Validate("password"))
+		require.NoError(t, policy.Validate("passwordpassword"))
+	})
+
+	t.Run("MinCharacterClasses", func(t *testing.T) {
+		t.Parallel()
+		policy := userpassword.Policy{MinCharacterClasses: 3}
+		require.Error(t, policy.Validate("password"))
+		require.Error(t, policy.Validate("Password"))
+		require.NoError(t, policy.Validate("Password1"))
+		require.NoError(t, policy.Validate("password1!"))
+	})
+
+	t.Run("Breached", func(t *testing.T) {
+		t.Parallel()
+		breached, err := userpassword.ReadBreached(strings.NewReader("password1\n\n  Letmein123 \n"))
+		require.NoError(t, err)
+		require.Len(t, breached, 2)
+		policy := userpassword.Policy{Breached: breached}
+		require.Error(t, policy.Validate("Password1"))
+		require.Error(t, policy.Validate("letmein123"))
+		require.NoError(t, policy.Validate("something-else"))
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	policy := userpassword.Policy{MinLength: 16, MinCharacterClasses: 4}
+	for i := 0; i < 100; i++ {
+		password, err := userpassword.Generate(16)
+		require.NoError(t, err)
+		require.Len(t, password, 16)
+		require.NoError(t, policy.Validate(password))
+	}
+	_, err := userpassword.Generate(3)
+	require.Error(t, err)
+}
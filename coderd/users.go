@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/tabbed/pqtype"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/gitsshkey"
 	"github.com/coder/coder/coderd/httpapi"
@@ -54,10 +56,21 @@ func (api *API) firstUser(rw http.ResponseWriter, r *http.Request) {
 
 // Creates the initial user for a Coder deployment.
 func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
+	aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+
 	var createUser codersdk.CreateFirstUserRequest
 	if !httpapi.Read(rw, r, &createUser) {
 		return
 	}
+	if !api.validatePassword(rw, createUser.Password) {
+		return
+	}
 
 	// This should only function for the first user.
 	userCount, err := api.Database.GetUserCount(r.Context())
@@ -101,7 +114,7 @@ func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
 	// 	the user. Maybe I add this ability to grant roles in the createUser api
 	//	and add some rbac bypass when calling api functions this way??
 	// Add the admin role to this first user.
-	_, err = api.Database.UpdateUserRoles(r.Context(), database.UpdateUserRolesParams{
+	user, err = api.Database.UpdateUserRoles(r.Context(), database.UpdateUserRolesParams{
 		GrantedRoles: []string{rbac.RoleAdmin()},
 		ID:           user.ID,
 	})
@@ -112,6 +125,7 @@ func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	aReq.New = user
 
 	httpapi.Write(rw, http.StatusCreated, codersdk.CreateFirstUserResponse{
 		UserID:         user.ID,
@@ -142,6 +156,9 @@ func (api *API) users(rw http.ResponseWriter, r *http.Request) {
 		Search:    params.Search,
 		Status:    params.Status,
 		RbacRole:  params.RbacRole,
+
+		LastSeenBefore: params.LastSeenBefore,
+		LastSeenAfter:  params.LastSeenAfter,
 	})
 	if errors.Is(err, sql.ErrNoRows) {
 		httpapi.Write(rw, http.StatusOK, []codersdk.User{})
@@ -177,11 +194,19 @@ func (api *API) users(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	render.Status(r, http.StatusOK)
-	render.JSON(rw, r, convertUsers(users, organizationIDsByUserID))
+	render.JSON(rw, r, convertUsers(users, organizationIDsByUserID, api.siteRoleDisplayNames(r.Context())))
 }
 
 // Creates a new user.
 func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
+	aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+
 	// Create the user on the site.
 	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceUser) {
 		httpapi.Forbidden(rw)
@@ -192,6 +217,9 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(rw, r, &createUser) {
 		return
 	}
+	if !api.validatePassword(rw, createUser.Password) {
+		return
+	}
 
 	// Create the organization member in the org.
 	if !api.Authorize(r, rbac.ActionCreate,
@@ -243,13 +271,14 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	aReq.New = user
 
 	// Report when users are added!
 	api.Telemetry.Report(&telemetry.Snapshot{
 		Users: []telemetry.User{telemetry.ConvertUser(user)},
 	})
 
-	httpapi.Write(rw, http.StatusCreated, convertUser(user, []uuid.UUID{createUser.OrganizationID}))
+	httpapi.Write(rw, http.StatusCreated, convertUser(user, []uuid.UUID{createUser.OrganizationID}, api.siteRoleDisplayNames(r.Context())))
 }
 
 // Returns the parameterized user requested. All validation
@@ -271,11 +300,19 @@ func (api *API) userByName(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(rw, http.StatusOK, convertUser(user, organizationIDs))
+	httpapi.Write(rw, http.StatusOK, convertUser(user, organizationIDs, api.siteRoleDisplayNames(r.Context())))
 }
 
 func (api *API) putUserProfile(rw http.ResponseWriter, r *http.Request) {
 	user := httpmw.UserParam(r)
+	aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = user
 
 	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceUser.WithID(user.ID.String())) {
 		httpapi.ResourceNotFound(rw)
@@ -327,6 +364,7 @@ func (api *API) putUserProfile(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	aReq.New = updatedUserProfile
 
 	organizationIDs, err := userOrganizationIDs(r.Context(), api, user)
 	if err != nil {
@@ -337,13 +375,21 @@ func (api *API) putUserProfile(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(rw, http.StatusOK, convertUser(updatedUserProfile, organizationIDs))
+	httpapi.Write(rw, http.StatusOK, convertUser(updatedUserProfile, organizationIDs, api.siteRoleDisplayNames(r.Context())))
 }
 
 func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseWriter, r *http.Request) {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		user := httpmw.UserParam(r)
 		apiKey := httpmw.APIKey(r)
+		aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Auditor: api.Auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+		defer commitAudit()
+		aReq.Old = user
 
 		if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceUser.WithID(user.ID.String())) {
 			httpapi.ResourceNotFound(rw)
@@ -370,6 +416,7 @@ func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseW
 			})
 			return
 		}
+		aReq.New = suspendedUser
 
 		organizations, err := userOrganizationIDs(r.Context(), api, user)
 		if err != nil {
@@ -380,15 +427,253 @@ func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseW
 			return
 		}
 
-		httpapi.Write(rw, http.StatusOK, convertUser(suspendedUser, organizations))
+		httpapi.Write(rw, http.StatusOK, convertUser(suspendedUser, organizations, api.siteRoleDisplayNames(r.Context())))
+	}
+}
+
+// deleteUser deletes a user, freeing their username and email. Users who own
+// workspaces are only deleted once the builds queued to destroy their
+// workspaces complete.
+func (api *API) deleteUser(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+	apiKey := httpmw.APIKey(r)
+
+	var destroyWorkspaces bool
+	if raw := r.URL.Query().Get("destroy_workspaces"); raw != "" {
+		var err error
+		destroyWorkspaces, err = strconv.ParseBool(raw)
+		if err != nil {
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid boolean value %q for \"destroy_workspaces\" query param.", raw),
+				Validations: []codersdk.ValidationError{
+					{Field: "destroy_workspaces", Detail: "Must be a valid boolean"},
+				},
+			})
+			return
+		}
+	}
+
+	workspaces, err := api.Database.GetWorkspaces(r.Context(), database.GetWorkspacesParams{
+		OwnerID: user.ID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(workspaces) > 0 && destroyWorkspaces {
+		// The user isn't deleted until their workspaces are destroyed, so
+		// destroying each workspace is audited instead.
+		api.destroyUserWorkspaces(rw, r, user, workspaces)
+		return
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDelete,
+	})
+	defer commitAudit()
+	aReq.Old = user
+
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceUser.WithID(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if user.ID == apiKey.UserID {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot delete yourself.",
+		})
+		return
+	}
+	if len(workspaces) > 0 {
+		httpapi.Write(rw, http.StatusPreconditionFailed, codersdk.Response{
+			Message: fmt.Sprintf("User %q owns %d workspaces. Destroy them first.", user.Username, len(workspaces)),
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(store database.Store) error {
+		err := store.DeleteDeletedWorkspacesByOwnerID(r.Context(), user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete deleted workspaces: %w", err)
+		}
+		// Templates and workspaces shared with the user outlive them too.
+		err = store.DeleteUserFromTemplateACLs(r.Context(), user.ID.String())
+		if err != nil {
+			return xerrors.Errorf("delete user from template acls: %w", err)
+		}
+		err = store.DeleteUserFromWorkspaceACLs(r.Context(), user.ID.String())
+		if err != nil {
+			return xerrors.Errorf("delete user from workspace acls: %w", err)
+		}
+		// Templates outlive their authors.
+		err = store.UpdateTemplatesCreatedBy(r.Context(), database.UpdateTemplatesCreatedByParams{
+			NewCreatedBy: apiKey.UserID,
+			OldCreatedBy: user.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("update templates created by: %w", err)
+		}
+		err = store.DeleteAPIKeysByUserID(r.Context(), user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete api keys: %w", err)
+		}
+		err = store.DeleteGitSSHKey(r.Context(), user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete git ssh key: %w", err)
+		}
+		err = store.DeleteUserByID(r.Context(), user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "User has been deleted!",
+	})
+}
+
+// destroyUserWorkspaces queues builds that destroy the workspaces of a user
+// who is being deleted, and responds with them. The user must be deleted
+// again once the builds complete.
+func (api *API) destroyUserWorkspaces(rw http.ResponseWriter, r *http.Request, user database.User, workspaces []database.Workspace) {
+	apiKey := httpmw.APIKey(r)
+	commitAudits := make([]func(), 0, len(workspaces))
+	defer func() {
+		for _, commitAudit := range commitAudits {
+			commitAudit()
+		}
+	}()
+	for _, workspace := range workspaces {
+		aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Auditor: api.Auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+		aReq.Old = workspace
+		commitAudits = append(commitAudits, commitAudit)
+	}
+
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceUser.WithID(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if user.ID == apiKey.UserID {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot delete yourself.",
+		})
+		return
+	}
+
+	type priorBuild struct {
+		workspace database.Workspace
+		template  database.Template
+		build     database.WorkspaceBuild
+		job       database.ProvisionerJob
+	}
+	priorBuilds := make([]priorBuild, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		if !api.Authorize(r, rbac.ActionDelete, workspace) {
+			httpapi.Forbidden(rw)
+			return
+		}
+		build, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(r.Context(), workspace.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching the latest workspace build.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		job, err := api.Database.GetProvisionerJobByID(r.Context(), build.JobID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching provisioner job.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		// Workspaces that are already being destroyed are waited on.
+		if convertProvisionerJob(job).Status.Active() && build.Transition != database.WorkspaceTransitionDelete {
+			httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("Workspace %q has an active build. Wait for it to complete, or cancel it.", workspace.Name),
+			})
+			return
+		}
+		template, err := api.Database.GetTemplateByID(r.Context(), workspace.TemplateID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		priorBuilds = append(priorBuilds, priorBuild{
+			workspace: workspace,
+			template:  template,
+			build:     build,
+			job:       job,
+		})
+	}
+	initiator, err := api.Database.GetUserByID(r.Context(), apiKey.UserID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	builds := make([]codersdk.WorkspaceBuild, 0, len(priorBuilds))
+	err = api.Database.InTx(func(store database.Store) error {
+		for _, prior := range priorBuilds {
+			if convertProvisionerJob(prior.job).Status.Active() {
+				builds = append(builds, convertWorkspaceBuild(&user, &initiator, prior.workspace, prior.build, prior.job))
+				continue
+			}
+			build, job, err := queueWorkspaceBuild(r.Context(), store, prior.template, prior.workspace, prior.build, prior.job, database.WorkspaceTransitionDelete, apiKey.UserID)
+			if err != nil {
+				return xerrors.Errorf("queue build for workspace %q: %w", prior.workspace.Name, err)
+			}
+			builds = append(builds, convertWorkspaceBuild(&user, &initiator, prior.workspace, build, job))
+		}
+		return nil
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error destroying workspaces.",
+			Detail:  err.Error(),
+		})
+		return
 	}
+	httpapi.Write(rw, http.StatusAccepted, builds)
 }
 
 func (api *API) putUserPassword(rw http.ResponseWriter, r *http.Request) {
 	var (
-		user   = httpmw.UserParam(r)
-		params codersdk.UpdateUserPasswordRequest
+		user              = httpmw.UserParam(r)
+		params            codersdk.UpdateUserPasswordRequest
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Auditor: api.Auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
 	)
+	defer commitAudit()
+	aReq.Old = user
 
 	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(user.ID.String())) {
 		httpapi.ResourceNotFound(rw)
@@ -399,17 +684,7 @@ func (api *API) putUserPassword(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := userpassword.Validate(params.Password)
-	if err != nil {
-		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Invalid password.",
-			Validations: []codersdk.ValidationError{
-				{
-					Field:  "password",
-					Detail: err.Error(),
-				},
-			},
-		})
+	if !api.validatePassword(rw, params.Password) {
 		return
 	}
 
@@ -443,6 +718,30 @@ func (api *API) putUserPassword(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if api.PasswordPolicy.History > 0 {
+		reused, err := api.passwordReused(r.Context(), user, params.Password)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error checking password history.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if reused {
+			detail := fmt.Sprintf("Password can't be one of the last %d passwords.", api.PasswordPolicy.History)
+			httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid password.",
+				Validations: []codersdk.ValidationError{
+					{
+						Field:  "password",
+						Detail: detail,
+					},
+				},
+			})
+			return
+		}
+	}
+
 	hashedPassword, err := userpassword.Hash(params.Password)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
@@ -451,9 +750,26 @@ func (api *API) putUserPassword(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	err = api.Database.UpdateUserHashedPassword(r.Context(), database.UpdateUserHashedPasswordParams{
-		ID:             user.ID,
-		HashedPassword: []byte(hashedPassword),
+	err = api.Database.InTx(func(db database.Store) error {
+		err := db.UpdateUserHashedPassword(r.Context(), database.UpdateUserHashedPasswordParams{
+			ID:             user.ID,
+			HashedPassword: []byte(hashedPassword),
+		})
+		if err != nil {
+			return xerrors.Errorf("update password: %w", err)
+		}
+		// Previous passwords are only kept while the policy checks them.
+		if api.PasswordPolicy.History > 0 && len(user.HashedPassword) > 0 {
+			err = db.InsertUserPasswordHistory(r.Context(), database.InsertUserPasswordHistoryParams{
+				UserID:         user.ID,
+				HashedPassword: user.HashedPassword,
+				CreatedAt:      database.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert password history: %w", err)
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
@@ -463,9 +779,76 @@ func (api *API) putUserPassword(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	newUser := user
+	newUser.HashedPassword = []byte(hashedPassword)
+	aReq.New = newUser
+
 	httpapi.Write(rw, http.StatusNoContent, nil)
 }
 
+// passwordPolicy returns the requirements passwords must meet, so clients can
+// generate passwords that meet them.
+func (api *API) passwordPolicy(rw http.ResponseWriter, _ *http.Request) {
+	minLength := api.PasswordPolicy.MinLength
+	if minLength <= 0 {
+		minLength = userpassword.DefaultMinLength
+	}
+	httpapi.Write(rw, http.StatusOK, codersdk.PasswordPolicy{
+		MinLength:           minLength,
+		MinCharacterClasses: api.PasswordPolicy.MinCharacterClasses,
+	})
+}
+
+// validatePassword writes an error if the password doesn't meet the password
+// policy.
+func (api *API) validatePassword(rw http.ResponseWriter, password string) bool {
+	err := api.PasswordPolicy.Validate(password)
+	if err != nil {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid password.",
+			Validations: []codersdk.ValidationError{
+				{
+					Field:  "password",
+					Detail: err.Error(),
+				},
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// passwordReused returns whether the password is the user's current password,
+// or one of the previous passwords the password policy doesn't allow reusing.
+func (api *API) passwordReused(ctx context.Context, user database.User, password string) (bool, error) {
+	hashes := make([][]byte, 0, api.PasswordPolicy.History)
+	if len(user.HashedPassword) > 0 {
+		hashes = append(hashes, user.HashedPassword)
+	}
+	if api.PasswordPolicy.History > 1 {
+		history, err := api.Database.GetUserPasswordHistory(ctx, database.GetUserPasswordHistoryParams{
+			UserID: user.ID,
+			Limit:  int32(api.PasswordPolicy.History - 1),
+		})
+		if err != nil {
+			return false, xerrors.Errorf("get password history: %w", err)
+		}
+		for _, previous := range history {
+			hashes = append(hashes, previous.HashedPassword)
+		}
+	}
+	for _, hashed := range hashes {
+		equal, err := userpassword.Compare(string(hashed), password)
+		if err != nil {
+			return false, xerrors.Errorf("compare password: %w", err)
+		}
+		if equal {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (api *API) userRoles(rw http.ResponseWriter, r *http.Request) {
 	user := httpmw.UserParam(r)
 
@@ -506,6 +889,14 @@ func (api *API) putUserRoles(rw http.ResponseWriter, r *http.Request) {
 	user := httpmw.UserParam(r)
 	roles := httpmw.AuthorizationUserRoles(r)
 	apiKey := httpmw.APIKey(r)
+	aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = user
 
 	if apiKey.UserID == user.ID {
 		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
@@ -552,6 +943,7 @@ func (api *API) putUserRoles(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	aReq.New = updatedUser
 
 	organizationIDs, err := userOrganizationIDs(r.Context(), api, user)
 	if err != nil {
@@ -562,7 +954,7 @@ func (api *API) putUserRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(rw, http.StatusOK, convertUser(updatedUser, organizationIDs))
+	httpapi.Write(rw, http.StatusOK, convertUser(updatedUser, organizationIDs, api.siteRoleDisplayNames(r.Context())))
 }
 
 // updateSiteUserRoles will ensure only site wide roles are passed in as arguments.
@@ -574,7 +966,7 @@ func (api *API) updateSiteUserRoles(ctx context.Context, args database.UpdateUse
 			return database.User{}, xerrors.Errorf("Must only update site wide roles")
 		}
 
-		if _, err := rbac.RoleByName(r); err != nil {
+		if _, err := roleByName(ctx, api.Database, r); err != nil {
 			return database.User{}, xerrors.Errorf("%q is not a supported role", r)
 		}
 	}
@@ -657,6 +1049,16 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Users are locked out for a while after too many failed logins, to slow
+	// down guessing their password.
+	lockout, err := api.loginLockout(r.Context(), user.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+
 	// If the user doesn't exist, it will be a default struct.
 	equal, err := userpassword.Compare(string(user.HashedPassword), loginWithPassword.Password)
 	if err != nil {
@@ -665,7 +1067,23 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	// Locked out users get the same response as incorrect passwords, so it
+	// can't be used to tell whether users are registered or a guess was
+	// correct. Admins can see lockouts and clear them.
+	if lockout.LockedUntil.After(api.Now()) {
+		httpapi.Write(rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Incorrect email or password.",
+		})
+		return
+	}
 	if !equal {
+		err = api.recordFailedLogin(r.Context(), user.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error.",
+			})
+			return
+		}
 		// This message is the same as above to remove ease in detecting whether
 		// users are registered or not. Attackers still could with a timing attack.
 		httpapi.Write(rw, http.StatusUnauthorized, codersdk.Response{
@@ -682,6 +1100,31 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userTOTP, err := api.Database.GetUserTOTPByUserID(r.Context(), user.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	// Users with a second factor, or that are required to have one, exchange
+	// a TOTP token for a session with postLoginTOTP. Failed attempts are
+	// reset once they provide a correct code.
+	if userTOTP.Enabled || api.totpRequired(user) {
+		api.writeTOTPChallenge(rw, r, user, userTOTP)
+		return
+	}
+
+	if lockout.FailedAttempts > 0 {
+		err = api.Database.DeleteUserLockoutByUserID(r.Context(), user.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error.",
+			})
+			return
+		}
+	}
+
 	sessionToken, created := api.createAPIKey(rw, r, database.InsertAPIKeyParams{
 		UserID:    user.ID,
 		LoginType: database.LoginTypePassword,
@@ -703,6 +1146,9 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
+	if !api.requireUnscopedAPIKey(rw, r) {
+		return
+	}
 
 	lifeTime := time.Hour * 24 * 7
 	sessionToken, created := api.createAPIKey(rw, r, database.InsertAPIKeyParams{
@@ -720,6 +1166,113 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(rw, http.StatusCreated, codersdk.GenerateAPIKeyResponse{Key: sessionToken})
 }
 
+// Creates a long-lived API key that expires at a fixed time and may be
+// restricted by scopes.
+func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceAPIKey.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if !api.requireUnscopedAPIKey(rw, r) {
+		return
+	}
+
+	var req codersdk.CreateTokenRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if req.LifetimeSeconds < 0 {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Token lifetime must be positive.",
+		})
+		return
+	}
+	// Seconds are compared before converting them to a duration, which
+	// would overflow for very long lifetimes.
+	if req.LifetimeSeconds > int64(api.MaxTokenLifetime/time.Second) {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Token lifetime must be at most %s.", api.MaxTokenLifetime),
+			Validations: []codersdk.ValidationError{{
+				Field:  "lifetime_seconds",
+				Detail: fmt.Sprintf("Must be at most %d.", int64(api.MaxTokenLifetime/time.Second)),
+			}},
+		})
+		return
+	}
+	lifeTime := time.Duration(req.LifetimeSeconds) * time.Second
+	if lifeTime == 0 {
+		lifeTime = 30 * 24 * time.Hour
+		if lifeTime > api.MaxTokenLifetime {
+			lifeTime = api.MaxTokenLifetime
+		}
+	}
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{rbac.ScopeAll}
+	}
+	_, err := rbac.ScopePermissions(scopes)
+	if err != nil {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid token scopes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if req.TokenName != "" {
+		keys, err := api.Database.GetAPIKeysByUserID(r.Context(), user.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching API keys.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		for _, key := range keys {
+			if key.TokenName == req.TokenName {
+				httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+					Message: fmt.Sprintf("A token named %q already exists.", req.TokenName),
+					Validations: []codersdk.ValidationError{{
+						Field:  "token_name",
+						Detail: "This value is already in use and should be unique.",
+					}},
+				})
+				return
+			}
+		}
+	}
+
+	sessionToken, created := api.createAPIKey(rw, r, database.InsertAPIKeyParams{
+		UserID:          user.ID,
+		LoginType:       database.LoginTypeToken,
+		ExpiresAt:       database.Now().Add(lifeTime),
+		LifetimeSeconds: int64(lifeTime.Seconds()),
+		Scopes:          scopes,
+		TokenName:       req.TokenName,
+	})
+	if !created {
+		return
+	}
+
+	httpapi.Write(rw, http.StatusCreated, codersdk.GenerateAPIKeyResponse{Key: sessionToken})
+}
+
+// requireUnscopedAPIKey writes an error if the request was authenticated by
+// an API key restricted by scopes. It's used to prevent restricted keys from
+// creating keys with more permissions than themselves.
+func (*API) requireUnscopedAPIKey(rw http.ResponseWriter, r *http.Request) bool {
+	scope, err := rbac.ScopePermissions(httpmw.APIKey(r).Scopes)
+	if err != nil || scope != nil {
+		httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+			Message: "API keys restricted by scopes can't create API keys.",
+		})
+		return false
+	}
+	return true
+}
+
 func (api *API) apiKey(rw http.ResponseWriter, r *http.Request) {
 	var (
 		ctx  = r.Context()
@@ -748,6 +1301,135 @@ func (api *API) apiKey(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(rw, http.StatusOK, convertAPIKey(key))
 }
 
+// Lists the API keys of a user, including their login sessions.
+func (api *API) apiKeys(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceAPIKey.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	keys, err := api.Database.GetAPIKeysByUserID(r.Context(), user.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching API keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiKeys := make([]codersdk.APIKey, 0, len(keys))
+	for _, key := range keys {
+		apiKeys = append(apiKeys, convertAPIKey(key))
+	}
+	httpapi.Write(rw, http.StatusOK, apiKeys)
+}
+
+func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+	aReq, commitAudit := audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDelete,
+	})
+	defer commitAudit()
+
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceAPIKey.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	keyID := chi.URLParam(r, "keyid")
+	key, err := api.Database.GetAPIKeyByID(ctx, keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Keys are looked up by ID alone, so make sure it belongs to the user
+	// that was authorized.
+	if key.UserID != user.ID {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	aReq.Old = key
+
+	err = api.Database.DeleteAPIKeyByID(ctx, key.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting API key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "API key has been deleted!",
+	})
+}
+
+// Revokes every API key of a user, signing them out everywhere. It's used by
+// admins to end the sessions of suspended users.
+func (api *API) deleteAPIKeys(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceAPIKey.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	keys, err := api.Database.GetAPIKeysByUserID(r.Context(), user.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching API keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Every revoked key is audited, like it would be if it was deleted on its
+	// own.
+	commitAudits := make([]func(), 0, len(keys))
+	defer func() {
+		for _, commitAudit := range commitAudits {
+			commitAudit()
+		}
+	}()
+	for _, key := range keys {
+		aReq, commitAudit := audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Auditor: api.Auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+		aReq.Old = key
+		commitAudits = append(commitAudits, commitAudit)
+	}
+
+	err = api.Database.DeleteAPIKeysByUserID(r.Context(), user.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting API keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "All API keys have been deleted!",
+	})
+}
+
 // Clear the user's session cookie.
 func (api *API) postLogout(rw http.ResponseWriter, r *http.Request) {
 	// Get a blank token cookie.
@@ -838,6 +1520,8 @@ func (api *API) createAPIKey(rw http.ResponseWriter, r *http.Request, params dat
 		OAuthRefreshToken: params.OAuthRefreshToken,
 		OAuthIDToken:      params.OAuthIDToken,
 		OAuthExpiry:       params.OAuthExpiry,
+		Scopes:            params.Scopes,
+		TokenName:         params.TokenName,
 	})
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
@@ -936,11 +1620,14 @@ func (api *API) createUser(ctx context.Context, req codersdk.CreateUserRequest)
 	})
 }
 
-func convertUser(user database.User, organizationIDs []uuid.UUID) codersdk.User {
+// convertUser converts a user for the API. roleDisplayNames are the display
+// names of site custom roles by name, see siteRoleDisplayNames.
+func convertUser(user database.User, organizationIDs []uuid.UUID, roleDisplayNames map[string]string) codersdk.User {
 	convertedUser := codersdk.User{
 		ID:              user.ID,
 		Email:           user.Email,
 		CreatedAt:       user.CreatedAt,
+		LastSeenAt:      user.LastSeenAt,
 		Username:        user.Username,
 		Status:          codersdk.UserStatus(user.Status),
 		OrganizationIDs: organizationIDs,
@@ -948,18 +1635,27 @@ func convertUser(user database.User, organizationIDs []uuid.UUID) codersdk.User
 	}
 
 	for _, roleName := range user.RBACRoles {
-		rbacRole, _ := rbac.RoleByName(roleName)
+		rbacRole, err := rbac.RoleByName(roleName)
+		if err != nil {
+			// Custom roles are shown by their display name, falling back to
+			// their name if the role couldn't be found.
+			displayName, ok := roleDisplayNames[roleName]
+			if !ok || displayName == "" {
+				displayName = roleName
+			}
+			rbacRole = rbac.Role{Name: roleName, DisplayName: displayName}
+		}
 		convertedUser.Roles = append(convertedUser.Roles, convertRole(rbacRole))
 	}
 
 	return convertedUser
 }
 
-func convertUsers(users []database.User, organizationIDsByUserID map[uuid.UUID][]uuid.UUID) []codersdk.User {
+func convertUsers(users []database.User, organizationIDsByUserID map[uuid.UUID][]uuid.UUID, roleDisplayNames map[string]string) []codersdk.User {
 	converted := make([]codersdk.User, 0, len(users))
 	for _, u := range users {
 		userOrganizationIDs := organizationIDsByUserID[u.ID]
-		converted = append(converted, convertUser(u, userOrganizationIDs))
+		converted = append(converted, convertUser(u, userOrganizationIDs, roleDisplayNames))
 	}
 	return converted
 }
@@ -1016,11 +1712,31 @@ func userSearchQuery(query string) (database.GetUsersParams, []codersdk.Validati
 		Search:   parser.String(searchParams, "", "search"),
 		Status:   httpapi.ParseCustom(parser, searchParams, []database.UserStatus{}, "status", parseUserStatus),
 		RbacRole: parser.Strings(searchParams, []string{}, "role"),
+
+		LastSeenBefore: httpapi.ParseCustom(parser, searchParams, time.Time{}, "last_seen_before", parseSearchTime),
+		LastSeenAfter:  httpapi.ParseCustom(parser, searchParams, time.Time{}, "last_seen_after", parseSearchTime),
 	}
 
 	return filter, parser.Errors
 }
 
+// parseSearchTime parses a date like "2022-08-30", or a time in RFC 3339 format.
+// Search queries are lowercased, so times are uppercased before being parsed.
+func parseSearchTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err == nil {
+		return t, nil
+	}
+	t, err = time.Parse(time.RFC3339, strings.ToUpper(v))
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("%q must be a date like \"2006-01-02\" or a time in RFC 3339 format", v)
+	}
+	return t, nil
+}
+
 // parseUserStatus ensures proper enums are used for user statuses
 func parseUserStatus(v string) ([]database.UserStatus, error) {
 	var statuses []database.UserStatus
@@ -1049,5 +1765,8 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		UpdatedAt:       k.UpdatedAt,
 		LoginType:       codersdk.LoginType(k.LoginType),
 		LifetimeSeconds: k.LifetimeSeconds,
+		Scopes:          k.Scopes,
+		TokenName:       k.TokenName,
+		IPAddress:       k.IPAddress.IPNet.IP.String(),
 	}
 }
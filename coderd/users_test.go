@@ -2,6 +2,7 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
@@ -12,8 +13,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/userpassword"
 	"github.com/coder/coder/codersdk"
 )
 
@@ -226,7 +230,7 @@ func TestPostUsers(t *testing.T) {
 			OrganizationID: uuid.New(),
 			Email:          "another@user.org",
 			Username:       "someone-else",
-			Password:       "testing",
+			Password:       "testpass",
 		})
 		var apiErr *codersdk.Error
 		require.ErrorAs(t, err, &apiErr)
@@ -247,7 +251,7 @@ func TestPostUsers(t *testing.T) {
 		_, err = notInOrg.CreateUser(context.Background(), codersdk.CreateUserRequest{
 			Email:          "some@domain.com",
 			Username:       "anotheruser",
-			Password:       "testing",
+			Password:       "testpass",
 			OrganizationID: org.ID,
 		})
 		var apiErr *codersdk.Error
@@ -255,6 +259,28 @@ func TestPostUsers(t *testing.T) {
 		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
 	})
 
+	t.Run("InvalidPassword", func(t *testing.T) {
+		t.Parallel()
+		breached, err := userpassword.ReadBreached(strings.NewReader("password123\n"))
+		require.NoError(t, err)
+		client := coderdtest.New(t, &coderdtest.Options{
+			PasswordPolicy: userpassword.Policy{
+				Breached: breached,
+			},
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		_, err = client.CreateUser(context.Background(), codersdk.CreateUserRequest{
+			OrganizationID: user.OrganizationID,
+			Email:          "another@user.org",
+			Username:       "someone-else",
+			Password:       "Password123",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Validations[0].Detail, "breached")
+	})
+
 	t.Run("Create", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
@@ -263,9 +289,38 @@ func TestPostUsers(t *testing.T) {
 			OrganizationID: user.OrganizationID,
 			Email:          "another@user.org",
 			Username:       "someone-else",
-			Password:       "testing",
+			Password:       "testpass",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("AuditLog", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		created, err := client.CreateUser(context.Background(), codersdk.CreateUserRequest{
+			OrganizationID: user.OrganizationID,
+			Email:          "another@user.org",
+			Username:       "someone-else",
+			Password:       "testpass",
 		})
 		require.NoError(t, err)
+
+		logs := auditor.AuditLogs()
+		require.NotEmpty(t, logs)
+		alog := logs[len(logs)-1]
+		require.Equal(t, database.AuditActionCreate, alog.Action)
+		require.Equal(t, database.ResourceTypeUser, alog.ResourceType)
+		require.Equal(t, created.ID, alog.ResourceID)
+		require.Equal(t, user.UserID, alog.UserID)
+		require.Equal(t, int32(http.StatusCreated), alog.StatusCode)
+
+		// The hashed password must never be leaked in the diff.
+		var diff audit.Map
+		require.NoError(t, json.Unmarshal(alog.Diff, &diff))
+		require.Equal(t, "someone-else", diff["username"])
+		require.Empty(t, diff["hashed_password"])
 	})
 }
 
@@ -383,6 +438,63 @@ func TestUpdateUserPassword(t *testing.T) {
 		})
 		require.NoError(t, err, "admin should be able to update own password without providing old password")
 	})
+	t.Run("PasswordPolicy", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			PasswordPolicy: userpassword.Policy{
+				MinLength:           10,
+				MinCharacterClasses: 3,
+			},
+		})
+		req := coderdtest.FirstUserParams
+		_, err := client.CreateFirstUser(context.Background(), req)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		req.Password = "FirstPassword1"
+		_, err = client.CreateFirstUser(context.Background(), req)
+		require.NoError(t, err)
+		login, err := client.LoginWithPassword(context.Background(), codersdk.LoginWithPasswordRequest{
+			Email:    req.Email,
+			Password: req.Password,
+		})
+		require.NoError(t, err)
+		client.SessionToken = login.SessionToken
+
+		err = client.UpdateUserPassword(context.Background(), "me", codersdk.UpdateUserPasswordRequest{
+			Password: "newpassword",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Equal(t, "password", apiErr.Validations[0].Field)
+
+		err = client.UpdateUserPassword(context.Background(), "me", codersdk.UpdateUserPasswordRequest{
+			Password: "NewPassword1",
+		})
+		require.NoError(t, err)
+	})
+	t.Run("PasswordHistory", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			PasswordPolicy: userpassword.Policy{
+				History: 2,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		update := func(password string) error {
+			return client.UpdateUserPassword(context.Background(), "me", codersdk.UpdateUserPasswordRequest{
+				Password: password,
+			})
+		}
+		// The current password can't be reused.
+		require.Error(t, update(coderdtest.FirstUserParams.Password))
+		require.NoError(t, update("password1"))
+		// Neither can the previous one.
+		require.Error(t, update(coderdtest.FirstUserParams.Password))
+		require.NoError(t, update("password2"))
+		// It's no longer in the history.
+		require.NoError(t, update(coderdtest.FirstUserParams.Password))
+	})
 }
 
 func TestGrantRoles(t *testing.T) {
@@ -555,6 +667,197 @@ func TestPutUserSuspend(t *testing.T) {
 	})
 }
 
+func TestDeleteUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		other := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		user, err := other.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = other.GitSSHKey(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		builds, err := client.DeleteUser(ctx, user.Username, codersdk.DeleteUserOptions{})
+		require.NoError(t, err)
+		require.Empty(t, builds)
+
+		_, err = client.User(ctx, user.ID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		// The session of the deleted user is revoked.
+		_, err = other.User(ctx, codersdk.Me)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		// Their username and email can be reused.
+		_, err = client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          user.Email,
+			Username:       user.Username,
+			Password:       "testpass",
+			OrganizationID: admin.OrganizationID,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		coderdtest.CreateFirstUser(t, client)
+		_, err := client.DeleteUser(context.Background(), codersdk.Me, codersdk.DeleteUserOptions{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		other := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		_, err := other.DeleteUser(context.Background(), admin.UserID.String(), codersdk.DeleteUserOptions{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("OwnsWorkspaces", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		other := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		workspace := coderdtest.CreateWorkspace(t, other, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		_, err := client.DeleteUser(ctx, workspace.OwnerID.String(), codersdk.DeleteUserOptions{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusPreconditionFailed, apiErr.StatusCode())
+		// Workspaces are never deleted without destroying their resources.
+		_, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		_, err = client.User(ctx, workspace.OwnerID.String())
+		require.NoError(t, err)
+	})
+
+	t.Run("DestroyWorkspaces", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true, Auditor: auditor})
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		other := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		workspace := coderdtest.CreateWorkspace(t, other, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		builds, err := client.DeleteUser(ctx, workspace.OwnerID.String(), codersdk.DeleteUserOptions{
+			DestroyWorkspaces: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, builds, 1)
+		require.Equal(t, workspace.ID, builds[0].WorkspaceID)
+		require.Equal(t, codersdk.WorkspaceTransitionDelete, builds[0].Transition)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, builds[0].ID)
+		// Only destroying the workspace is audited, since the user still
+		// exists.
+		logs := auditor.AuditLogs()
+		require.NotEmpty(t, logs)
+		alog := logs[len(logs)-1]
+		require.Equal(t, database.ResourceTypeWorkspace, alog.ResourceType)
+		require.Equal(t, database.AuditActionDelete, alog.Action)
+		require.Equal(t, workspace.ID, alog.ResourceID)
+		require.Equal(t, int32(http.StatusAccepted), alog.StatusCode)
+		_, err = client.User(ctx, workspace.OwnerID.String())
+		require.NoError(t, err)
+
+		builds, err = client.DeleteUser(ctx, workspace.OwnerID.String(), codersdk.DeleteUserOptions{})
+		require.NoError(t, err)
+		require.Empty(t, builds)
+		_, err = client.User(ctx, workspace.OwnerID.String())
+		require.Error(t, err)
+		logs = auditor.AuditLogs()
+		alog = logs[len(logs)-1]
+		require.Equal(t, database.ResourceTypeUser, alog.ResourceType)
+		require.Equal(t, database.AuditActionDelete, alog.Action)
+		require.Equal(t, workspace.OwnerID, alog.ResourceID)
+	})
+
+	t.Run("TemplateAuthor", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		admin := coderdtest.CreateFirstUser(t, client)
+		other := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID, rbac.RoleAdmin())
+		version := coderdtest.CreateTemplateVersion(t, other, admin.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, other, admin.OrganizationID, version.ID)
+
+		_, err := client.DeleteUser(ctx, template.CreatedByID.String(), codersdk.DeleteUserOptions{})
+		require.NoError(t, err)
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, admin.UserID, template.CreatedByID)
+	})
+
+	t.Run("RemovedFromACLs", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true, Auditor: auditor})
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		deleted, err := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID).User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		other, err := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID).User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{deleted.ID.String(): codersdk.TemplateRoleUse},
+		})
+		require.NoError(t, err)
+		_, err = client.ShareWorkspace(ctx, workspace.ID, deleted.ID.String(), codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleRead,
+		})
+		require.NoError(t, err)
+		_, err = client.DeleteUser(ctx, deleted.ID.String(), codersdk.DeleteUserOptions{})
+		require.NoError(t, err)
+
+		// The audited access lists show the users they grant access to.
+		userACL := func() map[string]interface{} {
+			logs := auditor.AuditLogs()
+			require.NotEmpty(t, logs)
+			var diff audit.Map
+			require.NoError(t, json.Unmarshal(logs[len(logs)-1].Diff, &diff))
+			acl, ok := diff["user_acl"].(map[string]interface{})
+			require.True(t, ok)
+			return acl
+		}
+		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{other.ID.String(): codersdk.TemplateRoleUse},
+		})
+		require.NoError(t, err)
+		require.NotContains(t, userACL(), deleted.ID.String())
+		_, err = client.ShareWorkspace(ctx, workspace.ID, other.ID.String(), codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleRead,
+		})
+		require.NoError(t, err)
+		require.NotContains(t, userACL(), deleted.ID.String())
+	})
+}
+
 func TestGetUser(t *testing.T) {
 	t.Parallel()
 
@@ -832,6 +1135,50 @@ func TestGetUsers(t *testing.T) {
 		require.NoError(t, err)
 		require.ElementsMatch(t, active, users)
 	})
+	t.Run("LastSeen", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		firstUser, err := client.User(ctx, first.UserID.String())
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now(), firstUser.LastSeenAt, time.Minute)
+
+		// Alice never logs in.
+		alice, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          "alice@email.com",
+			Username:       "alice",
+			Password:       "password",
+			OrganizationID: first.OrganizationID,
+		})
+		require.NoError(t, err)
+		require.True(t, alice.LastSeenAt.IsZero())
+
+		users, err := client.Users(ctx, codersdk.UsersRequest{
+			SearchQuery: "last_seen_before:" + time.Now().Add(-time.Hour).Format("2006-01-02T15:04:05Z07:00"),
+		})
+		require.Error(t, err, "times must be quoted, since they contain colons")
+		users, err = client.Users(ctx, codersdk.UsersRequest{
+			SearchQuery: fmt.Sprintf("last_seen_before:%q", time.Now().Add(-time.Hour).Format(time.RFC3339)),
+		})
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		require.Equal(t, alice.ID, users[0].ID)
+
+		users, err = client.Users(ctx, codersdk.UsersRequest{
+			SearchQuery: "last_seen_after:" + time.Now().Add(-48*time.Hour).Format("2006-01-02"),
+		})
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		require.Equal(t, first.UserID, users[0].ID)
+
+		_, err = client.Users(ctx, codersdk.UsersRequest{
+			SearchQuery: "last_seen_after:yesterday",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
 }
 
 func TestPostAPIKey(t *testing.T) {
@@ -859,6 +1206,215 @@ func TestPostAPIKey(t *testing.T) {
 	})
 }
 
+func TestPostToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Defaults", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		token, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+
+		apiKey, err := client.GetAPIKey(context.Background(), codersdk.Me, strings.Split(token.Key, "-")[0])
+		require.NoError(t, err)
+		require.Equal(t, codersdk.LoginTypeToken, apiKey.LoginType)
+		require.Equal(t, []string{rbac.ScopeAll}, apiKey.Scopes)
+		require.Equal(t, int64((30 * 24 * time.Hour).Seconds()), apiKey.LifetimeSeconds)
+		require.WithinDuration(t, time.Now().Add(30*24*time.Hour), apiKey.ExpiresAt, time.Minute)
+	})
+
+	t.Run("MaxLifetime", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			MaxTokenLifetime: 7 * 24 * time.Hour,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{
+			LifetimeSeconds: int64((8 * 24 * time.Hour).Seconds()),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// The default lifetime is shortened to the maximum.
+		token, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+		apiKey, err := client.GetAPIKey(context.Background(), codersdk.Me, strings.Split(token.Key, "-")[0])
+		require.NoError(t, err)
+		require.Equal(t, int64((7 * 24 * time.Hour).Seconds()), apiKey.LifetimeSeconds)
+	})
+
+	t.Run("Scoped", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		token, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{
+			LifetimeSeconds: int64(time.Hour.Seconds()),
+			Scopes:          []string{"user:read"},
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SessionToken = token.Key
+
+		_, err = scoped.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+
+		// The user is an admin, but the token can only read users.
+		_, err = scoped.CreateOrganization(context.Background(), codersdk.CreateOrganizationRequest{
+			Name: "scoped",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("ScopedCantCreateKeys", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		token, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{
+			Scopes: []string{"api_key:*", "user:read"},
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SessionToken = token.Key
+
+		_, err = scoped.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = scoped.CreateAPIKey(context.Background(), codersdk.Me)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidScope", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{
+			Scopes: []string{"workspace:destroy"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "ci",
+		})
+		require.NoError(t, err)
+		_, err = client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "ci",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+}
+
+func TestAPIKeys(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	token, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{
+		TokenName: "ci",
+	})
+	require.NoError(t, err)
+
+	keys, err := client.APIKeys(context.Background(), codersdk.Me)
+	require.NoError(t, err)
+	// The session of the first user and the token.
+	require.Len(t, keys, 2)
+	require.Equal(t, strings.Split(token.Key, "-")[0], keys[1].ID)
+	require.Equal(t, "ci", keys[1].TokenName)
+	require.NotEmpty(t, keys[1].IPAddress)
+}
+
+func TestDeleteAPIKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+		token, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+		keyID := strings.Split(token.Key, "-")[0]
+
+		err = client.DeleteAPIKey(context.Background(), codersdk.Me, keyID)
+		require.NoError(t, err)
+		_, err = client.GetAPIKey(context.Background(), codersdk.Me, keyID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		logs := auditor.AuditLogs()
+		require.NotEmpty(t, logs)
+		log := logs[len(logs)-1]
+		require.Equal(t, database.ResourceTypeAPIKey, log.ResourceType)
+		require.Equal(t, database.AuditActionDelete, log.Action)
+		require.Equal(t, keyID, log.ResourceTarget)
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		other := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		token, err := client.CreateToken(context.Background(), codersdk.Me, codersdk.CreateTokenRequest{})
+		require.NoError(t, err)
+
+		// The key exists, but it isn't owned by the other user.
+		err = other.DeleteAPIKey(context.Background(), codersdk.Me, strings.Split(token.Key, "-")[0])
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
+func TestDeleteAPIKeys(t *testing.T) {
+	t.Parallel()
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+	user := coderdtest.CreateFirstUser(t, client)
+	other := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	otherUser, err := other.User(context.Background(), codersdk.Me)
+	require.NoError(t, err)
+
+	_, err = client.UpdateUserStatus(context.Background(), otherUser.ID.String(), codersdk.UserStatusSuspended)
+	require.NoError(t, err)
+	revoked, err := client.APIKeys(context.Background(), otherUser.ID.String())
+	require.NoError(t, err)
+	require.NotEmpty(t, revoked)
+	err = client.DeleteAPIKeys(context.Background(), otherUser.ID.String())
+	require.NoError(t, err)
+
+	keys, err := client.APIKeys(context.Background(), otherUser.ID.String())
+	require.NoError(t, err)
+	require.Empty(t, keys)
+	// Each revoked key is audited.
+	audited := 0
+	for _, log := range auditor.AuditLogs() {
+		if log.ResourceType == database.ResourceTypeAPIKey && log.Action == database.AuditActionDelete {
+			require.Equal(t, otherUser.ID, log.ResourceID)
+			audited++
+		}
+	}
+	require.Equal(t, len(revoked), audited)
+	_, err = other.User(context.Background(), codersdk.Me)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+}
+
 func TestWorkspacesByUser(t *testing.T) {
 	t.Parallel()
 	t.Run("Empty", func(t *testing.T) {
@@ -0,0 +1,665 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/coderd/totp"
+	"github.com/coder/coder/codersdk"
+	"github.com/coder/coder/cryptorand"
+)
+
+const (
+	// totpIssuer is displayed alongside the account in authenticator apps.
+	totpIssuer = "Coder"
+	// totpLoginChallengeLifetime is how long users have to provide a code
+	// after entering their password.
+	totpLoginChallengeLifetime = 5 * time.Minute
+	// totpLoginChallengeAttempts is how many incorrect codes can be provided
+	// before the password has to be entered again.
+	totpLoginChallengeAttempts = 5
+	// totpRecoveryCodes is the number of recovery codes generated when
+	// enrolling.
+	totpRecoveryCodes = 10
+)
+
+// totpRequired returns whether the user must enroll in TOTP to log in with a
+// password.
+func (api *API) totpRequired(user database.User) bool {
+	return api.TOTPRequiredForAdmins && slices.Contains(user.RBACRoles, rbac.RoleAdmin())
+}
+
+// writeTOTPChallenge is called by postLogin instead of creating a session if
+// the user must provide a second factor. The challenge is exchanged for a
+// session by postLoginTOTP. Users that must enroll get a new secret, and
+// enrollment is completed by the code they log in with.
+//
+// Incorrect codes count against the challenge that replaces an unexpired
+// one, so logging in again doesn't allow guessing more codes.
+func (api *API) writeTOTPChallenge(rw http.ResponseWriter, r *http.Request, user database.User, userTOTP database.UserTOTP) {
+	ctx := r.Context()
+	var attempts int32
+	if api.Now().Before(userTOTP.LoginChallengeExpiresAt) {
+		attempts = userTOTP.LoginChallengeAttempts
+	}
+	if attempts >= totpLoginChallengeAttempts {
+		httpapi.Write(rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Too many incorrect codes. Try again later.",
+		})
+		return
+	}
+
+	var enrollment *codersdk.TOTPEnrollment
+	if !userTOTP.Enabled {
+		secret, err := totp.GenerateSecret()
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error generating TOTP secret.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		_, err = api.Database.UpsertUserTOTP(ctx, database.UpsertUserTOTPParams{
+			UserID:    user.ID,
+			Secret:    secret,
+			CreatedAt: database.Now(),
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error enrolling in TOTP.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		enrollment = &codersdk.TOTPEnrollment{
+			Secret: secret,
+			URL:    totp.URL(totpIssuer, user.Email, secret),
+		}
+	}
+
+	token, err := cryptorand.String(32)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating TOTP token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	hashed := sha256.Sum256([]byte(token))
+	err = api.Database.UpdateUserTOTPLoginChallenge(ctx, database.UpdateUserTOTPLoginChallengeParams{
+		UserID:                  user.ID,
+		LoginChallenge:          hashed[:],
+		LoginChallengeExpiresAt: api.Now().Add(totpLoginChallengeLifetime),
+		LoginChallengeAttempts:  attempts,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating TOTP token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusAccepted, codersdk.LoginWithPasswordResponse{
+		TOTPToken:      token,
+		TOTPEnrollment: enrollment,
+	})
+}
+
+// Completes a password login with a TOTP or recovery code.
+func (api *API) postLoginTOTP(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req codersdk.LoginWithTOTPRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	hashed := sha256.Sum256([]byte(req.TOTPToken))
+	userTOTP, err := api.Database.GetUserTOTPByLoginChallenge(ctx, hashed[:])
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && api.Now().After(userTOTP.LoginChallengeExpiresAt)) {
+		httpapi.Write(rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your login has expired. Log in with your password again.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	user, err := api.Database.GetUserByID(ctx, userTOTP.UserID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if user.Status != database.UserStatusActive {
+		httpapi.Write(rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your account is suspended. Contact an admin to reactivate your account.",
+		})
+		return
+	}
+	// Incorrect codes count as failed logins, so they lock the user out
+	// like incorrect passwords.
+	lockout, err := api.loginLockout(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching lockout.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if lockout.LockedUntil.After(api.Now()) {
+		httpapi.Write(rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your login has expired. Log in with your password again.",
+		})
+		return
+	}
+
+	step, valid, err := api.validateTOTPCode(userTOTP, req.Code)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error validating code.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Recovery codes can't be used to complete enrollment, since they're only
+	// generated by it.
+	if !valid && userTOTP.Enabled {
+		valid, err = api.useTOTPRecoveryCode(ctx, user.ID, req.Code)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error validating recovery code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		step = userTOTP.LastUsedStep
+	}
+	if !valid {
+		challenge := userTOTP.LoginChallenge
+		attempts := userTOTP.LoginChallengeAttempts + 1
+		if attempts >= totpLoginChallengeAttempts {
+			challenge = nil
+		}
+		err = api.Database.UpdateUserTOTPLoginChallenge(ctx, database.UpdateUserTOTPLoginChallengeParams{
+			UserID:                  user.ID,
+			LoginChallenge:          challenge,
+			LoginChallengeExpiresAt: userTOTP.LoginChallengeExpiresAt,
+			LoginChallengeAttempts:  attempts,
+		})
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error updating TOTP token.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		err = api.recordFailedLogin(ctx, user.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error recording failed login.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		httpapi.Write(rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid code.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "code",
+				Detail: "The code is incorrect or has already been used.",
+			}},
+		})
+		return
+	}
+
+	// The token can only be used once.
+	err = api.Database.UpdateUserTOTPLoginChallenge(ctx, database.UpdateUserTOTPLoginChallengeParams{
+		UserID: user.ID,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating TOTP token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// The password alone doesn't reset failed attempts for users with a
+	// second factor, so they're reset once the login completes.
+	if lockout.FailedAttempts > 0 {
+		err = api.Database.DeleteUserLockoutByUserID(ctx, user.ID)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error resetting failed attempts.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	var recoveryCodes []string
+	if userTOTP.Enabled {
+		err = api.Database.UpdateUserTOTP(ctx, database.UpdateUserTOTPParams{
+			UserID:       user.ID,
+			Enabled:      true,
+			LastUsedStep: step,
+			UpdatedAt:    database.Now(),
+		})
+	} else {
+		recoveryCodes, err = api.enableTOTP(ctx, user.ID, step)
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	sessionToken, created := api.createAPIKey(rw, r, database.InsertAPIKeyParams{
+		UserID:    user.ID,
+		LoginType: database.LoginTypePassword,
+	})
+	if !created {
+		return
+	}
+
+	httpapi.Write(rw, http.StatusCreated, codersdk.LoginWithTOTPResponse{
+		SessionToken:  sessionToken,
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+func (api *API) userTOTP(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceUserData.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	userTOTP, err := api.Database.GetUserTOTPByUserID(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	recoveryCodes, err := api.Database.GetUserTOTPRecoveryCodeCount(r.Context(), user.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.TOTPStatus{
+		Enabled:                userTOTP.Enabled,
+		RecoveryCodesRemaining: recoveryCodes,
+	})
+}
+
+// Starts enrolling the user in TOTP. It's completed by postUserTOTPVerify.
+func (api *API) postUserTOTP(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+	aReq, commitAudit := audit.InitRequest[database.UserTOTP](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if !api.requireSelfTOTP(rw, r, user) {
+		return
+	}
+
+	userTOTP, err := api.Database.GetUserTOTPByUserID(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = userTOTP
+	if userTOTP.Enabled {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: "TOTP is already enabled. Reset it to enroll again.",
+		})
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating TOTP secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	newTOTP, err := api.Database.UpsertUserTOTP(r.Context(), database.UpsertUserTOTPParams{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: database.Now(),
+		UpdatedAt: database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error enrolling in TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = newTOTP
+
+	httpapi.Write(rw, http.StatusCreated, codersdk.TOTPEnrollment{
+		Secret: secret,
+		URL:    totp.URL(totpIssuer, user.Email, secret),
+	})
+}
+
+// Completes enrolling the user in TOTP with a code generated from the secret.
+func (api *API) postUserTOTPVerify(rw http.ResponseWriter, r *http.Request) {
+	user := httpmw.UserParam(r)
+	aReq, commitAudit := audit.InitRequest[database.UserTOTP](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if !api.requireSelfTOTP(rw, r, user) {
+		return
+	}
+
+	var req codersdk.VerifyTOTPRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	userTOTP, err := api.Database.GetUserTOTPByUserID(r.Context(), user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "TOTP enrollment hasn't been started.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = userTOTP
+	if userTOTP.Enabled {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: "TOTP is already enabled.",
+		})
+		return
+	}
+
+	step, valid, err := api.validateTOTPCode(userTOTP, req.Code)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error validating code.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !valid {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid code.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "code",
+				Detail: "The code is incorrect or has expired.",
+			}},
+		})
+		return
+	}
+
+	recoveryCodes, err := api.enableTOTP(r.Context(), user.ID, step)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error enabling TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	enabled := userTOTP
+	enabled.Enabled = true
+	enabled.LastUsedStep = step
+	aReq.New = enabled
+
+	httpapi.Write(rw, http.StatusOK, codersdk.TOTPRecoveryCodes{
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// Removes the user's second factor. Users must provide a code to remove
+// their own, so a stolen session can't be used to remove it. Admins use this
+// to reset the second factor of users that have lost access to it.
+func (api *API) deleteUserTOTP(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+	aReq, commitAudit := audit.InitRequest[database.UserTOTP](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionDelete,
+	})
+	defer commitAudit()
+
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceUserData.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.ResetTOTPRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	userTOTP, err := api.Database.GetUserTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = userTOTP
+
+	if httpmw.APIKey(r).UserID == user.ID && userTOTP.Enabled {
+		valid, err := api.verifyTOTPReset(ctx, userTOTP, req.Code)
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error validating code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !valid {
+			httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+				Message: "A valid code is required to disable two-factor authentication.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "code",
+					Detail: "The code is incorrect or has already been used.",
+				}},
+			})
+			return
+		}
+	}
+
+	err = api.Database.InTx(func(db database.Store) error {
+		err := db.DeleteUserTOTPByUserID(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete totp: %w", err)
+		}
+		err = db.DeleteUserTOTPRecoveryCodesByUserID(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete recovery codes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resetting TOTP.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(rw, http.StatusOK, codersdk.Response{
+		Message: "TOTP has been reset!",
+	})
+}
+
+// verifyTOTPReset returns whether code is a valid TOTP or recovery code for
+// removing the user's own second factor. Incorrect codes count as failed
+// logins, so the lockout also limits guessing them.
+func (api *API) verifyTOTPReset(ctx context.Context, userTOTP database.UserTOTP, code string) (bool, error) {
+	lockout, err := api.loginLockout(ctx, userTOTP.UserID)
+	if err != nil {
+		return false, xerrors.Errorf("get lockout: %w", err)
+	}
+	if lockout.LockedUntil.After(api.Now()) {
+		return false, nil
+	}
+	_, valid, err := api.validateTOTPCode(userTOTP, code)
+	if err != nil {
+		return false, xerrors.Errorf("validate code: %w", err)
+	}
+	if !valid {
+		valid, err = api.useTOTPRecoveryCode(ctx, userTOTP.UserID, code)
+		if err != nil {
+			return false, xerrors.Errorf("use recovery code: %w", err)
+		}
+	}
+	if !valid {
+		return false, api.recordFailedLogin(ctx, userTOTP.UserID)
+	}
+	return true, nil
+}
+
+// requireSelfTOTP writes an error if the user isn't the authenticated user.
+// The secret has to be added to the user's own authenticator app, so users
+// can't be enrolled by others.
+func (*API) requireSelfTOTP(rw http.ResponseWriter, r *http.Request, user database.User) bool {
+	if httpmw.APIKey(r).UserID != user.ID {
+		httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+			Message: "Users can only enroll themselves in TOTP.",
+		})
+		return false
+	}
+	return true
+}
+
+// validateTOTPCode returns the time step the code is valid for. Codes for
+// steps that have already been used are rejected, so they can't be replayed.
+func (api *API) validateTOTPCode(userTOTP database.UserTOTP, code string) (int64, bool, error) {
+	step, valid, err := totp.Validate(userTOTP.Secret, code, api.Now())
+	if err != nil || !valid {
+		return 0, false, err
+	}
+	if step <= userTOTP.LastUsedStep {
+		return 0, false, nil
+	}
+	return step, true, nil
+}
+
+// enableTOTP completes enrollment and generates recovery codes.
+func (api *API) enableTOTP(ctx context.Context, userID uuid.UUID, step int64) ([]string, error) {
+	recoveryCodes := make([]string, 0, totpRecoveryCodes)
+	for i := 0; i < totpRecoveryCodes; i++ {
+		code, err := cryptorand.StringCharset(cryptorand.Human, 10)
+		if err != nil {
+			return nil, xerrors.Errorf("generate recovery code: %w", err)
+		}
+		recoveryCodes = append(recoveryCodes, code[:5]+"-"+code[5:])
+	}
+
+	return recoveryCodes, api.Database.InTx(func(db database.Store) error {
+		err := db.UpdateUserTOTP(ctx, database.UpdateUserTOTPParams{
+			UserID:       userID,
+			Enabled:      true,
+			LastUsedStep: step,
+			UpdatedAt:    database.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("update totp: %w", err)
+		}
+		err = db.DeleteUserTOTPRecoveryCodesByUserID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf("delete recovery codes: %w", err)
+		}
+		for _, code := range recoveryCodes {
+			err = db.InsertUserTOTPRecoveryCode(ctx, database.InsertUserTOTPRecoveryCodeParams{
+				UserID:     userID,
+				HashedCode: hashTOTPRecoveryCode(code),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert recovery code: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// useTOTPRecoveryCode deletes the recovery code if it's valid.
+func (api *API) useTOTPRecoveryCode(ctx context.Context, userID uuid.UUID, code string) (bool, error) {
+	_, err := api.Database.DeleteUserTOTPRecoveryCode(ctx, database.DeleteUserTOTPRecoveryCodeParams{
+		UserID:     userID,
+		HashedCode: hashTOTPRecoveryCode(code),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// hashTOTPRecoveryCode hashes recovery codes for storage. They're random, so
+// a fast hash is sufficient.
+func hashTOTPRecoveryCode(code string) []byte {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	hashed := sha256.Sum256([]byte(code))
+	return hashed[:]
+}
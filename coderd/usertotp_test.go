@@ -0,0 +1,438 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/totp"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestUserTOTP(t *testing.T) {
+	t.Parallel()
+	t.Run("Enroll", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{Now: clock.Now})
+		user := coderdtest.CreateFirstUser(t, client)
+
+		status, err := client.TOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+
+		codes := enrollTOTP(t, client, clock)
+		require.Len(t, codes, 10)
+
+		status, err = client.TOTP(ctx, user.UserID.String())
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+		require.EqualValues(t, 10, status.RecoveryCodesRemaining)
+
+		_, err = client.EnrollTOTP(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("EnrollOtherUser", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		_, err = client.EnrollTOTP(ctx, memberUser.ID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("VerifyInvalidCode", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{Code: "000000"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{Code: "not-a-code"})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		status, err := client.TOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{Now: clock.Now})
+		first := coderdtest.CreateFirstUser(t, client)
+		member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_ = enrollTOTP(t, member, clock)
+
+		// Members can't reset the second factor of others.
+		err = member.ResetTOTP(ctx, first.UserID.String(), codersdk.ResetTOTPRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// Admins don't need a code to reset the second factor of others.
+		err = client.ResetTOTP(ctx, memberUser.ID.String(), codersdk.ResetTOTPRequest{})
+		require.NoError(t, err)
+		status, err := client.TOTP(ctx, memberUser.ID.String())
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+		require.Zero(t, status.RecoveryCodesRemaining)
+
+		resp, err := member.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    memberUser.Email,
+			Password: "testpass",
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.SessionToken)
+		require.Empty(t, resp.TOTPToken)
+	})
+
+	t.Run("DisableRequiresCode", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{Now: clock.Now})
+		_ = coderdtest.CreateFirstUser(t, client)
+		enrollment, err := client.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+			Code: totpCode(t, enrollment.Secret, clock),
+		})
+		require.NoError(t, err)
+		clock.Add(totp.Period)
+
+		// Even owners need a code to remove their own second factor.
+		for _, code := range []string{"", "000000"} {
+			err = client.ResetTOTP(ctx, codersdk.Me, codersdk.ResetTOTPRequest{Code: code})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		}
+		status, err := client.TOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+
+		err = client.ResetTOTP(ctx, codersdk.Me, codersdk.ResetTOTPRequest{
+			Code: totpCode(t, enrollment.Secret, clock),
+		})
+		require.NoError(t, err)
+		status, err = client.TOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.False(t, status.Enabled)
+	})
+
+	t.Run("AuditLog", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Now: clock.Now, Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+		codes := enrollTOTP(t, client, clock)
+		err := client.ResetTOTP(ctx, codersdk.Me, codersdk.ResetTOTPRequest{Code: codes[0]})
+		require.NoError(t, err)
+
+		var actions []database.AuditAction
+		for _, log := range auditor.AuditLogs() {
+			if log.ResourceType == database.ResourceTypeUserTOTP {
+				actions = append(actions, log.Action)
+			}
+		}
+		require.Equal(t, []database.AuditAction{
+			database.AuditActionCreate,
+			database.AuditActionWrite,
+			database.AuditActionDelete,
+		}, actions)
+	})
+}
+
+func TestPostLoginTOTP(t *testing.T) {
+	t.Parallel()
+	t.Run("Code", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{Now: clock.Now})
+		_ = coderdtest.CreateFirstUser(t, client)
+		enrollment, err := client.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		code := totpCode(t, enrollment.Secret, clock)
+		_, err = client.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{Code: code})
+		require.NoError(t, err)
+
+		resp := loginWithPassword(t, client)
+		require.Empty(t, resp.SessionToken)
+		require.NotEmpty(t, resp.TOTPToken)
+		require.Nil(t, resp.TOTPEnrollment)
+
+		// The code used to enroll can't be replayed.
+		_, err = client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			TOTPToken: resp.TOTPToken,
+			Code:      code,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		clock.Add(totp.Period)
+		totpResp, err := client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			TOTPToken: resp.TOTPToken,
+			Code:      totpCode(t, enrollment.Secret, clock),
+		})
+		require.NoError(t, err)
+		require.Empty(t, totpResp.RecoveryCodes)
+
+		client.SessionToken = totpResp.SessionToken
+		_, err = client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		// The token can only be used once.
+		clock.Add(totp.Period)
+		_, err = client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			TOTPToken: resp.TOTPToken,
+			Code:      totpCode(t, enrollment.Secret, clock),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("RecoveryCode", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{Now: clock.Now})
+		_ = coderdtest.CreateFirstUser(t, client)
+		codes := enrollTOTP(t, client, clock)
+
+		resp := loginWithPassword(t, client)
+		totpResp, err := client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			TOTPToken: resp.TOTPToken,
+			Code:      codes[0],
+		})
+		require.NoError(t, err)
+		client.SessionToken = totpResp.SessionToken
+		status, err := client.TOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.EqualValues(t, 9, status.RecoveryCodesRemaining)
+
+		// Recovery codes can only be used once.
+		resp = loginWithPassword(t, client)
+		_, err = client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			TOTPToken: resp.TOTPToken,
+			Code:      codes[0],
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{Now: clock.Now})
+		_ = coderdtest.CreateFirstUser(t, client)
+		enrollment, err := client.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+			Code: totpCode(t, enrollment.Secret, clock),
+		})
+		require.NoError(t, err)
+
+		resp := loginWithPassword(t, client)
+		clock.Add(10 * time.Minute)
+		_, err = client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			TOTPToken: resp.TOTPToken,
+			Code:      totpCode(t, enrollment.Secret, clock),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("TooManyAttempts", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{Now: clock.Now})
+		_ = coderdtest.CreateFirstUser(t, client)
+		enrollment, err := client.EnrollTOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.VerifyTOTP(ctx, codersdk.Me, codersdk.VerifyTOTPRequest{
+			Code: totpCode(t, enrollment.Secret, clock),
+		})
+		require.NoError(t, err)
+		clock.Add(totp.Period)
+
+		resp := loginWithPassword(t, client)
+		for i := 0; i < 5; i++ {
+			_, err = client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+				TOTPToken: resp.TOTPToken,
+				Code:      "invalid",
+			})
+			require.Error(t, err)
+		}
+		_, err = client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			TOTPToken: resp.TOTPToken,
+			Code:      totpCode(t, enrollment.Secret, clock),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		// Logging in again doesn't allow guessing more codes until the
+		// challenge expires.
+		_, err = client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    coderdtest.FirstUserParams.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		clock.Add(10 * time.Minute)
+		resp = loginWithPassword(t, client)
+		_, err = client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			TOTPToken: resp.TOTPToken,
+			Code:      totpCode(t, enrollment.Secret, clock),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Lockout", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Now:              clock.Now,
+			LoginMaxAttempts: 3,
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		_ = enrollTOTP(t, client, clock)
+
+		// Incorrect codes count towards the lockout, and a correct password
+		// doesn't reset them.
+		for i := 0; i < 3; i++ {
+			resp := loginWithPassword(t, client)
+			_, err := client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+				TOTPToken: resp.TOTPToken,
+				Code:      "invalid",
+			})
+			require.Error(t, err)
+		}
+		lockout, err := client.UserLockout(ctx, first.UserID.String())
+		require.NoError(t, err)
+		require.NotNil(t, lockout.LockedUntil)
+	})
+
+	t.Run("RequiredForAdmins", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		clock := newTOTPClock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Now:                   clock.Now,
+			TOTPRequiredForAdmins: true,
+		})
+		first, err := client.CreateFirstUser(ctx, coderdtest.FirstUserParams)
+		require.NoError(t, err)
+
+		resp := loginWithPassword(t, client)
+		require.Empty(t, resp.SessionToken)
+		require.NotNil(t, resp.TOTPEnrollment)
+
+		totpResp, err := client.LoginWithTOTP(ctx, codersdk.LoginWithTOTPRequest{
+			TOTPToken: resp.TOTPToken,
+			Code:      totpCode(t, resp.TOTPEnrollment.Secret, clock),
+		})
+		require.NoError(t, err)
+		require.Len(t, totpResp.RecoveryCodes, 10)
+
+		client.SessionToken = totpResp.SessionToken
+		status, err := client.TOTP(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.True(t, status.Enabled)
+
+		// Members aren't required to enroll.
+		member := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		_, err = member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+	})
+}
+
+// totpClock is a deterministic clock for validating TOTP codes.
+type totpClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTOTPClock() *totpClock {
+	return &totpClock{now: time.Date(2022, time.June, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *totpClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *totpClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func totpCode(t *testing.T, secret string, clock *totpClock) string {
+	t.Helper()
+	code, err := totp.Code(secret, clock.Now())
+	require.NoError(t, err)
+	return code
+}
+
+// enrollTOTP enrolls the authenticated user and returns their recovery codes.
+func enrollTOTP(t *testing.T, client *codersdk.Client, clock *totpClock) []string {
+	t.Helper()
+	enrollment, err := client.EnrollTOTP(context.Background(), codersdk.Me)
+	require.NoError(t, err)
+	codes, err := client.VerifyTOTP(context.Background(), codersdk.Me, codersdk.VerifyTOTPRequest{
+		Code: totpCode(t, enrollment.Secret, clock),
+	})
+	require.NoError(t, err)
+	clock.Add(totp.Period)
+	return codes.RecoveryCodes
+}
+
+func loginWithPassword(t *testing.T, client *codersdk.Client) codersdk.LoginWithPasswordResponse {
+	t.Helper()
+	resp, err := client.LoginWithPassword(context.Background(), codersdk.LoginWithPasswordRequest{
+		Email:    coderdtest.FirstUserParams.Email,
+		Password: coderdtest.FirstUserParams.Password,
+	})
+	require.NoError(t, err)
+	return resp
+}
@@ -21,6 +21,7 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/agent"
+	"github.com/coder/coder/coderd/autobuild/executor"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/database/dbtypes"
 	"github.com/coder/coder/coderd/httpapi"
@@ -505,6 +506,107 @@ func (api *API) postWorkspaceAgentKeys(rw http.ResponseWriter, r *http.Request)
 	rw.WriteHeader(http.StatusNoContent)
 }
 
+// postWorkspaceAgentActivity postpones the autostop deadline of the
+// workspace while its agent has active sessions, so it isn't stopped while
+// it's used.
+func (api *API) postWorkspaceAgentActivity(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx            = r.Context()
+		workspaceAgent = httpmw.WorkspaceAgent(r)
+		activity       agent.Activity
+	)
+	if !httpapi.Read(rw, r, &activity) {
+		return
+	}
+	if activity.Sessions <= 0 {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resource.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(db database.Store) error {
+		latest, err := db.GetLatestWorkspaceBuildByWorkspaceID(ctx, build.WorkspaceID)
+		if err != nil {
+			return xerrors.Errorf("get latest workspace build: %w", err)
+		}
+		// Agents of older builds and workspaces without autostop don't
+		// affect the deadline.
+		if latest.ID != build.ID || latest.Transition != database.WorkspaceTransitionStart || latest.Deadline.IsZero() {
+			return nil
+		}
+		workspace, err := db.GetWorkspaceByID(ctx, latest.WorkspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+		// The workspace is kept running for its TTL after the last activity,
+		// like after it started.
+		if !workspace.Ttl.Valid || workspace.Ttl.Int64 <= 0 {
+			return nil
+		}
+		newDeadline := database.Now().Add(time.Duration(workspace.Ttl.Int64))
+		if !newDeadline.After(latest.Deadline) {
+			return nil
+		}
+
+		template, err := db.GetTemplateByID(ctx, workspace.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("get template: %w", err)
+		}
+		job, err := db.GetProvisionerJobByID(ctx, latest.JobID)
+		if err != nil {
+			return xerrors.Errorf("get provisioner job: %w", err)
+		}
+		// Activity doesn't keep the workspace running for longer than the
+		// template allows since it started, nor postpone the stop required by
+		// the template.
+		if template.MaxTtl > 0 {
+			maxDeadline := job.CompletedAt.Time.Add(time.Duration(template.MaxTtl))
+			if newDeadline.After(maxDeadline) {
+				newDeadline = maxDeadline
+			}
+		}
+		newDeadline = executor.Deadline(template, workspace, newDeadline, job.CompletedAt.Time)
+		if !newDeadline.After(latest.Deadline) {
+			return nil
+		}
+		err = db.UpdateWorkspaceBuildByID(ctx, database.UpdateWorkspaceBuildByIDParams{
+			ID:               latest.ID,
+			UpdatedAt:        latest.UpdatedAt,
+			ProvisionerState: latest.ProvisionerState,
+			Deadline:         newDeadline,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace build: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error postponing workspace deadline.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 func (api *API) postWorkspaceAgentWireguardPeer(rw http.ResponseWriter, r *http.Request) {
 	var (
 		req            peerwg.Handshake
@@ -17,6 +17,7 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/agent"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
 	"github.com/coder/coder/peer"
 	"github.com/coder/coder/provisioner/echo"
@@ -194,6 +195,95 @@ func TestWorkspaceAgentListen(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentActivity(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerD: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:           echo.ParseComplete,
+		ProvisionDryRun: echo.ProvisionComplete,
+		Provision: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(req *codersdk.CreateTemplateRequest) {
+		req.MaxTTLMillis = ptr.Ref((3 * time.Hour).Milliseconds())
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID, func(req *codersdk.CreateWorkspaceRequest) {
+		req.TTLMillis = ptr.Ref(time.Hour.Milliseconds())
+	})
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := codersdk.New(client.URL)
+	agentClient.SessionToken = authToken
+
+	// The deadline is further away than the TTL of the workspace, so it's
+	// kept.
+	err := client.PutExtendWorkspace(ctx, workspace.ID, codersdk.PutExtendWorkspaceRequest{
+		Deadline: time.Now().Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	deadline := workspace.LatestBuild.Deadline
+	err = agentClient.PostWorkspaceAgentActivity(ctx, agent.Activity{Sessions: 1})
+	require.NoError(t, err)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.Equal(t, deadline, workspace.LatestBuild.Deadline)
+
+	// Bring the deadline closer than the TTL.
+	err = client.PutExtendWorkspace(ctx, workspace.ID, codersdk.PutExtendWorkspaceRequest{
+		Deadline: time.Now().Add(35 * time.Minute),
+	})
+	require.NoError(t, err)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	deadline = workspace.LatestBuild.Deadline
+
+	// Reports without sessions don't postpone the deadline.
+	err = agentClient.PostWorkspaceAgentActivity(ctx, agent.Activity{})
+	require.NoError(t, err)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.Equal(t, deadline, workspace.LatestBuild.Deadline)
+
+	before := time.Now()
+	err = agentClient.PostWorkspaceAgentActivity(ctx, agent.Activity{Sessions: 2})
+	require.NoError(t, err)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.True(t, workspace.LatestBuild.Deadline.After(deadline))
+	require.WithinDuration(t, before.Add(time.Hour), workspace.LatestBuild.Deadline, time.Minute)
+
+	// Activity doesn't keep the workspace running for longer than the
+	// template allows since it started.
+	err = client.UpdateWorkspaceTTL(ctx, workspace.ID, codersdk.UpdateWorkspaceTTLRequest{
+		TTLMillis: ptr.Ref((3 * time.Hour).Milliseconds()),
+	})
+	require.NoError(t, err)
+	err = agentClient.PostWorkspaceAgentActivity(ctx, agent.Activity{Sessions: 1})
+	require.NoError(t, err)
+	workspace = coderdtest.MustWorkspace(t, client, workspace.ID)
+	require.NotNil(t, workspace.LatestBuild.Job.CompletedAt)
+	maxDeadline := workspace.LatestBuild.Job.CompletedAt.Add(3 * time.Hour)
+	require.False(t, workspace.LatestBuild.Deadline.After(maxDeadline))
+	require.WithinDuration(t, maxDeadline, workspace.LatestBuild.Deadline, time.Minute)
+}
+
 func TestWorkspaceAgentTURN(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{
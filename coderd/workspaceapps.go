@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -94,36 +95,8 @@ func (api *API) workspaceAppsProxyPath(rw http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	app, err := api.Database.GetWorkspaceAppByAgentIDAndName(r.Context(), database.GetWorkspaceAppByAgentIDAndNameParams{
-		AgentID: agent.ID,
-		Name:    chi.URLParam(r, "workspaceapp"),
-	})
-	if errors.Is(err, sql.ErrNoRows) {
-		httpapi.Write(rw, http.StatusNotFound, codersdk.Response{
-			Message: "Application not found.",
-		})
-		return
-	}
-	if err != nil {
-		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching workspace application.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-	if !app.Url.Valid {
-		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
-			Message: fmt.Sprintf("Application %s does not have a url.", app.Name),
-		})
-		return
-	}
-
-	appURL, err := url.Parse(app.Url.String)
-	if err != nil {
-		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: fmt.Sprintf("App url %q must be a valid url.", app.Url.String),
-			Detail:  err.Error(),
-		})
+	appURL, ok := api.workspaceAppURL(rw, r, agent.ID, chi.URLParam(r, "workspaceapp"))
+	if !ok {
 		return
 	}
 
@@ -172,3 +145,48 @@ func (api *API) workspaceAppsProxyPath(rw http.ResponseWriter, r *http.Request)
 	proxy.Transport = conn.HTTPTransport()
 	proxy.ServeHTTP(rw, r)
 }
+
+// workspaceAppURL returns the URL of the application of an agent. Ports
+// listening in the workspace are proxied like applications, e.g.
+// "/@<user>/<workspace>/apps/8080", unless an application has the same name.
+func (api *API) workspaceAppURL(rw http.ResponseWriter, r *http.Request, agentID uuid.UUID, name string) (*url.URL, bool) {
+	app, err := api.Database.GetWorkspaceAppByAgentIDAndName(r.Context(), database.GetWorkspaceAppByAgentIDAndNameParams{
+		AgentID: agentID,
+		Name:    name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		if port, err := strconv.ParseUint(name, 10, 16); err == nil && port != 0 {
+			return &url.URL{
+				Scheme: "http",
+				Host:   fmt.Sprintf("127.0.0.1:%d", port),
+			}, true
+		}
+		httpapi.Write(rw, http.StatusNotFound, codersdk.Response{
+			Message: "Application not found.",
+		})
+		return nil, false
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace application.",
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	if !app.Url.Valid {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Application %s does not have a url.", app.Name),
+		})
+		return nil, false
+	}
+
+	appURL, err := url.Parse(app.Url.String)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: fmt.Sprintf("App url %q must be a valid url.", app.Url.String),
+			Detail:  err.Error(),
+		})
+		return nil, false
+	}
+	return appURL, true
+}
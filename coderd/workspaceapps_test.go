@@ -61,6 +61,10 @@ func TestWorkspaceAppsProxyPath(t *testing.T) {
 							}, {
 								Name: "fake",
 								Url:  "http://127.0.0.2",
+							}, {
+								// Applications take precedence over ports.
+								Name: "1",
+								Url:  fmt.Sprintf("http://127.0.0.1:%d?query=true", tcpAddr.Port),
 							}},
 						}},
 					}},
@@ -138,4 +142,48 @@ func TestWorkspaceAppsProxyPath(t *testing.T) {
 		defer resp.Body.Close()
 		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
+	t.Run("ProxiesPort", func(t *testing.T) {
+		t.Parallel()
+		resp, err := client.Request(context.Background(), http.MethodGet, fmt.Sprintf("/@me/%s/apps/%d/", workspace.Name, tcpAddr.Port), nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("NumericAppName", func(t *testing.T) {
+		t.Parallel()
+		resp, err := client.Request(context.Background(), http.MethodGet, "/@me/"+workspace.Name+"/apps/1/", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		// Only the application has default query parameters to redirect to.
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		loc, err := resp.Location()
+		require.NoError(t, err)
+		require.Equal(t, "query=true", loc.RawQuery)
+	})
+
+	t.Run("Shared", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		memberUser, err := member.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		owner, err := client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		path := fmt.Sprintf("/@%s/%s/apps/example/?query=true", owner.Username, workspace.Name)
+
+		resp, err := member.Request(ctx, http.MethodGet, path, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		_, err = client.ShareWorkspace(ctx, workspace.ID, memberUser.Username, codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleRead,
+		})
+		require.NoError(t, err)
+		resp, err = member.Request(ctx, http.MethodGet, path, nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
 }
@@ -1,21 +1,25 @@
 package coderd
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/quota"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/codersdk"
 )
@@ -24,8 +28,7 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 	workspaceBuild := httpmw.WorkspaceBuildParam(r)
 	workspace := httpmw.WorkspaceParam(r)
 
-	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceWorkspace.
-		InOrg(workspace.OrganizationID).WithOwner(workspace.OwnerID.String()).WithID(workspace.ID.String())) {
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -239,8 +242,7 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 func (api *API) workspaceBuildByName(rw http.ResponseWriter, r *http.Request) {
 	workspace := httpmw.WorkspaceParam(r)
 	workspaceBuildName := chi.URLParam(r, "workspacebuildname")
-	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceWorkspace.
-		InOrg(workspace.OrganizationID).WithOwner(workspace.OwnerID.String()).WithID(workspace.ID.String())) {
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -291,23 +293,42 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	// Rbac action depends on the transition
-	var action rbac.Action
+	var (
+		action      rbac.Action
+		auditAction database.AuditAction
+	)
 	switch createBuild.Transition {
 	case codersdk.WorkspaceTransitionDelete:
 		action = rbac.ActionDelete
+		auditAction = database.AuditActionDelete
 	case codersdk.WorkspaceTransitionStart, codersdk.WorkspaceTransitionStop:
 		action = rbac.ActionUpdate
+		auditAction = database.AuditActionWrite
 	default:
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: fmt.Sprintf("Transition %q not supported.", createBuild.Transition),
 		})
 		return
 	}
-	if !api.Authorize(r, action, rbac.ResourceWorkspace.
-		InOrg(workspace.OrganizationID).WithOwner(workspace.OwnerID.String()).WithID(workspace.ID.String())) {
+
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  auditAction,
+	})
+	defer commitAudit()
+	aReq.Old = workspace
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
+	// Users the workspace is shared with can connect to it, but building it
+	// is left to its owner.
+	if !api.Authorize(r, action, workspaceOwnerObject(workspace)) {
+		httpapi.Forbidden(rw)
+		return
+	}
 
 	if createBuild.TemplateVersionID == uuid.Nil {
 		latestBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(r.Context(), workspace.ID)
@@ -376,6 +397,8 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 
 	// Store prior build number to compute new build number
 	var priorBuildNum int32
+	// Whether the workspace already counts as running toward quotas.
+	var priorRunning bool
 	priorHistory, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(r.Context(), workspace.ID)
 	if err == nil {
 		priorJob, err := api.Database.GetProvisionerJobByID(r.Context(), priorHistory.JobID)
@@ -387,6 +410,8 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		}
 
 		priorBuildNum = priorHistory.BuildNumber
+		priorRunning = err == nil && priorHistory.Transition == database.WorkspaceTransitionStart &&
+			!priorJob.Error.Valid && !priorJob.CanceledAt.Valid
 	} else if !errors.Is(err, sql.ErrNoRows) {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching prior workspace build.",
@@ -395,11 +420,43 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Dormant workspaces are likely abandoned, so starting one must be
+	// confirmed. Starting it clears its dormancy.
+	wakeDormant := createBuild.Transition == codersdk.WorkspaceTransitionStart && workspace.DormantAt.Valid
+	if wakeDormant && !createBuild.ConfirmDormant {
+		httpapi.Write(rw, http.StatusPreconditionFailed, codersdk.Response{
+			Message: "Workspace is dormant.",
+			Detail: fmt.Sprintf("The workspace has been dormant since %s because it was stopped for longer than its template allows. Confirm to start it.",
+				workspace.DormantAt.Time.Format(time.RFC3339)),
+		})
+		return
+	}
+
 	var workspaceBuild database.WorkspaceBuild
 	var provisionerJob database.ProvisionerJob
 	// This must happen in a transaction to ensure history can be inserted, and
 	// the prior history can update it's "after" column to point at the new.
 	err = api.Database.InTx(func(db database.Store) error {
+		err := quota.Check(r.Context(), db, quota.Workspace{
+			OwnerID:        workspace.OwnerID,
+			OrganizationID: workspace.OrganizationID,
+			Template:       template,
+			Starting:       createBuild.Transition == codersdk.WorkspaceTransitionStart && !priorRunning,
+		})
+		if err != nil {
+			return xerrors.Errorf("check quota: %w", err)
+		}
+
+		if wakeDormant {
+			err := db.UpdateWorkspaceDormantAt(r.Context(), database.UpdateWorkspaceDormantAtParams{
+				ID: workspace.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("clear workspace dormancy: %w", err)
+			}
+			workspace.DormantAt = sql.NullTime{}
+		}
+
 		existing, err := db.ParameterValues(r.Context(), database.ParameterValuesParams{
 			Scopes:   []database.ParameterScope{database.ParameterScopeWorkspace},
 			ScopeIds: []uuid.UUID{workspace.ID},
@@ -484,6 +541,9 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 
 		return nil
 	})
+	if writeWorkspaceQuotaError(rw, err) {
+		return
+	}
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error inserting workspace build.",
@@ -491,6 +551,11 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if createBuild.Transition != codersdk.WorkspaceTransitionDelete {
+		// The workspace row is only marked as deleted once the delete build
+		// completes, so the new value is only known for other transitions.
+		aReq.New = workspace
+	}
 
 	users, err := api.Database.GetUsersByIDs(r.Context(), []uuid.UUID{
 		workspace.OwnerID,
@@ -519,11 +584,14 @@ func (api *API) patchCancelWorkspaceBuild(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceWorkspace.
-		InOrg(workspace.OrganizationID).WithOwner(workspace.OwnerID.String()).WithID(workspace.ID.String())) {
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
+	if !api.Authorize(r, rbac.ActionUpdate, workspaceOwnerObject(workspace)) {
+		httpapi.Forbidden(rw)
+		return
+	}
 
 	job, err := api.Database.GetProvisionerJobByID(r.Context(), workspaceBuild.JobID)
 	if err != nil {
@@ -574,8 +642,7 @@ func (api *API) workspaceBuildResources(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceWorkspace.
-		InOrg(workspace.OrganizationID).WithOwner(workspace.OwnerID.String()).WithID(workspace.ID.String())) {
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -601,8 +668,7 @@ func (api *API) workspaceBuildLogs(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceWorkspace.
-		InOrg(workspace.OrganizationID).WithOwner(workspace.OwnerID.String()).WithID(workspace.ID.String())) {
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -638,6 +704,52 @@ func (api *API) workspaceBuildState(rw http.ResponseWriter, r *http.Request) {
 	_, _ = rw.Write(workspaceBuild.ProvisionerState)
 }
 
+// queueWorkspaceBuild inserts a build that moves a workspace to a transition,
+// reusing the template version and state of its prior build.
+func queueWorkspaceBuild(ctx context.Context, db database.Store, template database.Template, workspace database.Workspace, priorBuild database.WorkspaceBuild, priorJob database.ProvisionerJob, transition database.WorkspaceTransition, initiatorID uuid.UUID) (database.WorkspaceBuild, database.ProvisionerJob, error) {
+	workspaceBuildID := uuid.New()
+	input, err := json.Marshal(workspaceProvisionJob{
+		WorkspaceBuildID: workspaceBuildID,
+	})
+	if err != nil {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, xerrors.Errorf("marshal provision job: %w", err)
+	}
+	now := database.Now()
+	provisionerJob, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+		ID:             uuid.New(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		InitiatorID:    initiatorID,
+		OrganizationID: template.OrganizationID,
+		Provisioner:    template.Provisioner,
+		Type:           database.ProvisionerJobTypeWorkspaceBuild,
+		StorageMethod:  priorJob.StorageMethod,
+		StorageSource:  priorJob.StorageSource,
+		Input:          input,
+	})
+	if err != nil {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, xerrors.Errorf("insert provisioner job: %w", err)
+	}
+	workspaceBuild, err := db.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
+		ID:                workspaceBuildID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		WorkspaceID:       workspace.ID,
+		TemplateVersionID: priorBuild.TemplateVersionID,
+		BuildNumber:       priorBuild.BuildNumber + 1,
+		Name:              namesgenerator.GetRandomName(1),
+		ProvisionerState:  priorBuild.ProvisionerState,
+		InitiatorID:       initiatorID,
+		Transition:        transition,
+		JobID:             provisionerJob.ID,
+		Reason:            database.BuildReasonInitiator,
+	})
+	if err != nil {
+		return database.WorkspaceBuild{}, database.ProvisionerJob{}, xerrors.Errorf("insert workspace build: %w", err)
+	}
+	return workspaceBuild, provisionerJob, nil
+}
+
 func convertWorkspaceBuild(
 	workspaceOwner *database.User,
 	buildInitiator *database.User,
@@ -657,7 +769,7 @@ func convertWorkspaceBuild(
 	}
 
 	initiatorName := "unknown"
-	if workspaceOwner != nil {
+	if buildInitiator != nil {
 		initiatorName = buildInitiator.Username
 	}
 
@@ -22,10 +22,13 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/autobuild/executor"
 	"github.com/coder/coder/coderd/autobuild/schedule"
 	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/httpapi"
 	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/quota"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/telemetry"
 	"github.com/coder/coder/coderd/util/ptr"
@@ -34,6 +37,8 @@ import (
 
 const workspaceDefaultTTL = 2 * time.Hour
 
+const scheduleOverrideForbiddenMessage = "The template of the workspace doesn't allow changing its schedule."
+
 func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 	workspace := httpmw.WorkspaceParam(r)
 	if !api.Authorize(r, rbac.ActionRead, workspace) {
@@ -218,22 +223,32 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	initiator, err := api.Database.GetUserByID(r.Context(), build.InitiatorID)
-	if err != nil {
+	// The user who started the build may have been deleted since.
+	users, err := api.Database.GetUsersByIDs(r.Context(), []uuid.UUID{build.InitiatorID})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching template.",
+			Message: "Internal error fetching build initiator.",
 			Detail:  err.Error(),
 		})
 		return
 	}
+	initiator := findUser(build.InitiatorID, users)
 
-	httpapi.Write(rw, http.StatusOK, convertWorkspace(workspace, build, job, template, &owner, &initiator))
+	httpapi.Write(rw, http.StatusOK, convertWorkspace(workspace, build, job, template, &owner, initiator))
 }
 
 // Create a new workspace for the currently authenticated user.
 func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Request) {
 	organization := httpmw.OrganizationParam(r)
 	apiKey := httpmw.APIKey(r)
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionCreate,
+	})
+	defer commitAudit()
+
 	if !api.Authorize(r, rbac.ActionCreate,
 		rbac.ResourceWorkspace.InOrg(organization.ID).WithOwner(apiKey.UserID.String())) {
 		httpapi.ResourceNotFound(rw)
@@ -276,6 +291,19 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Templates may forbid users from choosing the schedule of their
+	// workspaces, in which case the template defaults are used.
+	if !template.AllowUserScheduleOverride {
+		createWorkspace.AutostartSchedule = nil
+		createWorkspace.TTLMillis = nil
+	}
+	if createWorkspace.AutostartSchedule == nil && template.DefaultAutostartSchedule != "" {
+		createWorkspace.AutostartSchedule = ptr.Ref(template.DefaultAutostartSchedule)
+	}
+	if createWorkspace.TTLMillis == nil && template.DefaultTtl > 0 {
+		createWorkspace.TTLMillis = ptr.Ref(time.Duration(template.DefaultTtl).Milliseconds())
+	}
+
 	dbAutostartSchedule, err := validWorkspaceSchedule(createWorkspace.AutostartSchedule, time.Duration(template.MinAutostartInterval))
 	if err != nil {
 		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
@@ -331,7 +359,16 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
-	templateVersion, err := api.Database.GetTemplateVersionByID(r.Context(), template.ActiveVersionID)
+	templateVersionID := template.ActiveVersionID
+	if createWorkspace.FromWorkspaceID != uuid.Nil {
+		var ok bool
+		templateVersionID, createWorkspace.ParameterValues, ok = api.cloneWorkspace(rw, r, template, createWorkspace)
+		if !ok {
+			return
+		}
+	}
+
+	templateVersion, err := api.Database.GetTemplateVersionByID(r.Context(), templateVersionID)
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching template version.",
@@ -369,6 +406,17 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 	var provisionerJob database.ProvisionerJob
 	var workspaceBuild database.WorkspaceBuild
 	err = api.Database.InTx(func(db database.Store) error {
+		err := quota.Check(r.Context(), db, quota.Workspace{
+			OwnerID:        apiKey.UserID,
+			OrganizationID: organization.ID,
+			Template:       template,
+			New:            true,
+			Starting:       true,
+		})
+		if err != nil {
+			return xerrors.Errorf("check quota: %w", err)
+		}
+
 		now := database.Now()
 		workspaceBuildID := uuid.New()
 		// Workspaces are created without any versions.
@@ -443,6 +491,9 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		}
 		return nil
 	})
+	if writeWorkspaceQuotaError(rw, err) {
+		return
+	}
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error creating workspace.",
@@ -450,6 +501,8 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		})
 		return
 	}
+	aReq.New = workspace
+
 	users, err := api.Database.GetUsersByIDs(r.Context(), []uuid.UUID{apiKey.UserID, workspaceBuild.InitiatorID})
 	if err != nil {
 		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
@@ -470,10 +523,24 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 
 func (api *API) putWorkspaceAutostart(rw http.ResponseWriter, r *http.Request) {
 	workspace := httpmw.WorkspaceParam(r)
-	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = workspace
+
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
+	// Users the workspace is shared with can't change its schedule.
+	if !api.Authorize(r, rbac.ActionUpdate, workspaceOwnerObject(workspace)) {
+		httpapi.Forbidden(rw)
+		return
+	}
 
 	var req codersdk.UpdateWorkspaceAutostartRequest
 	if !httpapi.Read(rw, r, &req) {
@@ -488,6 +555,12 @@ func (api *API) putWorkspaceAutostart(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if !template.AllowUserScheduleOverride {
+		httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+			Message: scheduleOverrideForbiddenMessage,
+		})
+		return
+	}
 
 	dbSched, err := validWorkspaceSchedule(req.Schedule, time.Duration(template.MinAutostartInterval))
 	if err != nil {
@@ -509,14 +582,32 @@ func (api *API) putWorkspaceAutostart(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+
+	newWorkspace := workspace
+	newWorkspace.AutostartSchedule = dbSched
+	aReq.New = newWorkspace
 }
 
 func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 	workspace := httpmw.WorkspaceParam(r)
-	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = workspace
+
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
+	// Users the workspace is shared with can't change its schedule.
+	if !api.Authorize(r, rbac.ActionUpdate, workspaceOwnerObject(workspace)) {
+		httpapi.Forbidden(rw)
+		return
+	}
 
 	var req codersdk.UpdateWorkspaceTTLRequest
 	if !httpapi.Read(rw, r, &req) {
@@ -524,6 +615,7 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	var validErrs []codersdk.ValidationError
+	var forbidden bool
 
 	err := api.Database.InTx(func(s database.Store) error {
 		template, err := s.GetTemplateByID(r.Context(), workspace.TemplateID)
@@ -533,6 +625,10 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 			})
 			return xerrors.Errorf("fetch workspace template: %w", err)
 		}
+		if !template.AllowUserScheduleOverride {
+			forbidden = true
+			return xerrors.New("template forbids schedule override")
+		}
 
 		dbTTL, err := validWorkspaceTTLMillis(req.TTLMillis, time.Duration(template.MaxTtl))
 		if err != nil {
@@ -546,9 +642,19 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 			return xerrors.Errorf("update workspace TTL: %w", err)
 		}
 
+		newWorkspace := workspace
+		newWorkspace.Ttl = dbTTL
+		aReq.New = newWorkspace
+
 		return nil
 	})
 
+	if forbidden {
+		httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+			Message: scheduleOverrideForbiddenMessage,
+		})
+		return
+	}
 	if err != nil {
 		code := http.StatusInternalServerError
 		if len(validErrs) > 0 {
@@ -567,11 +673,24 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 
 func (api *API) putExtendWorkspace(rw http.ResponseWriter, r *http.Request) {
 	workspace := httpmw.WorkspaceParam(r)
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = workspace
 
-	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
+	// Users the workspace is shared with can't change its schedule.
+	if !api.Authorize(r, rbac.ActionUpdate, workspaceOwnerObject(workspace)) {
+		httpapi.Forbidden(rw)
+		return
+	}
 
 	var req codersdk.PutExtendWorkspaceRequest
 	if !httpapi.Read(rw, r, &req) {
@@ -603,6 +722,12 @@ func (api *API) putExtendWorkspace(rw http.ResponseWriter, r *http.Request) {
 			return xerrors.Errorf("get provisioner job: %w", err)
 		}
 
+		if !template.AllowUserScheduleOverride {
+			code = http.StatusForbidden
+			resp.Message = scheduleOverrideForbiddenMessage
+			return xerrors.New("template forbids schedule override")
+		}
+
 		if build.Transition != database.WorkspaceTransitionStart {
 			code = http.StatusConflict
 			resp.Message = "Workspace must be started, current status: " + string(build.Transition)
@@ -628,6 +753,12 @@ func (api *API) putExtendWorkspace(rw http.ResponseWriter, r *http.Request) {
 			resp.Validations = append(resp.Validations, codersdk.ValidationError{Field: "deadline", Detail: err.Error()})
 			return err
 		}
+		if requiredStop := executor.Deadline(template, workspace, newDeadline, job.CompletedAt.Time); requiredStop.Before(newDeadline) {
+			code = http.StatusBadRequest
+			resp.Message = "Bad extend workspace request."
+			resp.Validations = append(resp.Validations, codersdk.ValidationError{Field: "deadline", Detail: "new deadline must be before the stop required by the template at " + requiredStop.Format(time.RFC3339)})
+			return xerrors.New("new deadline is after the required stop")
+		}
 
 		if err := s.UpdateWorkspaceBuildByID(r.Context(), database.UpdateWorkspaceBuildByIDParams{
 			ID:               build.ID,
@@ -640,6 +771,7 @@ func (api *API) putExtendWorkspace(rw http.ResponseWriter, r *http.Request) {
 			return xerrors.Errorf("update workspace build: %w", err)
 		}
 		resp.Message = "Deadline updated to " + newDeadline.Format(time.RFC3339) + "."
+		aReq.New = workspace
 
 		return nil
 	})
@@ -650,6 +782,413 @@ func (api *API) putExtendWorkspace(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(rw, code, resp)
 }
 
+// putWorkspaceOwner transfers a workspace to another member of its
+// organization. The agent tokens are regenerated and the latest build is run
+// again, so the workspace picks up the metadata of its new owner.
+func (api *API) putWorkspaceOwner(rw http.ResponseWriter, r *http.Request) {
+	var (
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+	)
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = workspace
+
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	// Only admins of the organization can update the workspaces of others.
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceWorkspace.InOrg(workspace.OrganizationID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.TransferWorkspaceRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if req.OwnerID == workspace.OwnerID {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The workspace is already owned by this user.",
+		})
+		return
+	}
+	owner, err := api.Database.GetUserByID(r.Context(), req.OwnerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q does not exist.", req.OwnerID),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	_, err = api.Database.GetOrganizationMemberByUserID(r.Context(), database.GetOrganizationMemberByUserIDParams{
+		OrganizationID: workspace.OrganizationID,
+		UserID:         owner.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("User %q is not a member of the organization of the workspace.", owner.Username),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization member.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	_, err = api.Database.GetWorkspaceByOwnerIDAndName(r.Context(), database.GetWorkspaceByOwnerIDAndNameParams{
+		OwnerID: owner.ID,
+		Name:    workspace.Name,
+	})
+	if err == nil {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("User %q already has a workspace named %q.", owner.Username, workspace.Name),
+		})
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	priorBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(r.Context(), workspace.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching the latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	priorJob, err := api.Database.GetProvisionerJobByID(r.Context(), priorBuild.JobID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if convertProvisionerJob(priorJob).Status.Active() {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: "A workspace build is already active.",
+		})
+		return
+	}
+	template, err := api.Database.GetTemplateByID(r.Context(), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(r.Context(), priorBuild.JobID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(r.Context(), resourceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var (
+		workspaceBuild database.WorkspaceBuild
+		provisionerJob database.ProvisionerJob
+	)
+	err = api.Database.InTx(func(db database.Store) error {
+		// The workspace already counts toward the quota of its organization,
+		// so only the quota of the new owner is checked.
+		err := quota.Check(r.Context(), db, quota.Workspace{
+			OwnerID:  owner.ID,
+			Template: template,
+			New:      true,
+			Starting: priorBuild.Transition == database.WorkspaceTransitionStart && !priorJob.Error.Valid && !priorJob.CanceledAt.Valid,
+		})
+		if err != nil {
+			return xerrors.Errorf("check quota: %w", err)
+		}
+
+		now := database.Now()
+		err = db.UpdateWorkspaceOwner(r.Context(), database.UpdateWorkspaceOwnerParams{
+			ID:        workspace.ID,
+			OwnerID:   owner.ID,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace owner: %w", err)
+		}
+		// The previous owner chose who the workspace was shared with.
+		err = db.UpdateWorkspaceUserACL(r.Context(), database.UpdateWorkspaceUserACLParams{
+			ID:      workspace.ID,
+			UserACL: database.WorkspaceACL{},
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace shares: %w", err)
+		}
+		// The previous owner may know the tokens of the running agents, so
+		// they're revoked right away. The new build issues new ones.
+		for _, agent := range agents {
+			err = db.UpdateWorkspaceAgentAuthTokenByID(r.Context(), database.UpdateWorkspaceAgentAuthTokenByIDParams{
+				ID:        agent.ID,
+				AuthToken: uuid.New(),
+				UpdatedAt: now,
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace agent %q auth token: %w", agent.ID, err)
+			}
+		}
+
+		workspaceBuild, provisionerJob, err = queueWorkspaceBuild(r.Context(), db, template, workspace, priorBuild, priorJob, priorBuild.Transition, apiKey.UserID)
+		return err
+	})
+	if writeWorkspaceQuotaError(rw, err) {
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error transferring workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	workspace.OwnerID = owner.ID
+	workspace.UserACL = database.WorkspaceACL{}
+	aReq.New = workspace
+
+	initiator, err := api.Database.GetUserByID(r.Context(), apiKey.UserID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(rw, http.StatusOK, convertWorkspaceBuild(&owner, &initiator, workspace, workspaceBuild, provisionerJob))
+}
+
+func (api *API) putWorkspaceName(rw http.ResponseWriter, r *http.Request) {
+	workspace := httpmw.WorkspaceParam(r)
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = workspace
+
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if !api.Authorize(r, rbac.ActionUpdate, workspaceOwnerObject(workspace)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.RenameWorkspaceRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if req.Name == workspace.Name {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The workspace is already named %q.", req.Name),
+		})
+		return
+	}
+	existing, err := api.Database.GetWorkspaceByOwnerIDAndName(r.Context(), database.GetWorkspaceByOwnerIDAndNameParams{
+		OwnerID: workspace.OwnerID,
+		Name:    req.Name,
+	})
+	// Names are unique regardless of case, so a workspace can still be
+	// renamed to change the case of its name.
+	if err == nil && existing.ID != workspace.ID {
+		httpapi.Write(rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "this value is already in use and should be unique",
+			}},
+		})
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: fmt.Sprintf("Internal error fetching workspace by name %q.", req.Name),
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Database.UpdateWorkspaceName(r.Context(), database.UpdateWorkspaceNameParams{
+		ID:        workspace.ID,
+		Name:      req.Name,
+		UpdatedAt: database.Now(),
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error renaming workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	workspace.Name = req.Name
+	aReq.New = workspace
+
+	httpapi.Write(rw, http.StatusOK, nil)
+}
+
+func (api *API) putWorkspaceDormancy(rw http.ResponseWriter, r *http.Request) {
+	workspace := httpmw.WorkspaceParam(r)
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = workspace
+
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if !api.Authorize(r, rbac.ActionUpdate, workspaceOwnerObject(workspace)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateWorkspaceDormancyRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+
+	err := api.Database.UpdateWorkspaceDormancyOptOut(r.Context(), database.UpdateWorkspaceDormancyOptOutParams{
+		ID:             workspace.ID,
+		DormancyOptOut: req.OptOut,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace dormancy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	workspace.DormancyOptOut = req.OptOut
+	workspace.DormantAt = sql.NullTime{}
+	aReq.New = workspace
+
+	httpapi.Write(rw, http.StatusOK, nil)
+}
+
+// cloneWorkspace returns the template version and parameter values to
+// create a clone of a workspace with. Parameter values of the request
+// override those of the cloned workspace.
+func (api *API) cloneWorkspace(rw http.ResponseWriter, r *http.Request, template database.Template, createWorkspace codersdk.CreateWorkspaceRequest) (uuid.UUID, []codersdk.CreateParameterRequest, bool) {
+	source, err := api.Database.GetWorkspaceByID(r.Context(), createWorkspace.FromWorkspaceID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (source.Deleted || !api.Authorize(r, rbac.ActionRead, source))) {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Workspace %q to clone doesn't exist.", createWorkspace.FromWorkspaceID.String()),
+			Validations: []codersdk.ValidationError{{
+				Field:  "from_workspace_id",
+				Detail: "workspace not found",
+			}},
+		})
+		return uuid.Nil, nil, false
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace to clone.",
+			Detail:  err.Error(),
+		})
+		return uuid.Nil, nil, false
+	}
+	// Clones copy the parameter values of the workspace, which may be secret,
+	// so sharing a workspace doesn't allow cloning it.
+	if !api.Authorize(r, rbac.ActionUpdate, workspaceOwnerObject(source)) {
+		httpapi.Write(rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Only the owner of the workspace %q and admins can clone it.", source.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "from_workspace_id",
+				Detail: "insufficient permissions to clone the workspace",
+			}},
+		})
+		return uuid.Nil, nil, false
+	}
+	if source.TemplateID != template.ID {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The workspace %q to clone uses another template than %q.", source.Name, template.Name),
+		})
+		return uuid.Nil, nil, false
+	}
+
+	build, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(r.Context(), source.ID)
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching the latest build of the workspace to clone.",
+			Detail:  err.Error(),
+		})
+		return uuid.Nil, nil, false
+	}
+	values, err := api.Database.ParameterValues(r.Context(), database.ParameterValuesParams{
+		Scopes:   []database.ParameterScope{database.ParameterScopeWorkspace},
+		ScopeIds: []uuid.UUID{source.ID},
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching parameters of the workspace to clone.",
+			Detail:  err.Error(),
+		})
+		return uuid.Nil, nil, false
+	}
+
+	parameterValues := createWorkspace.ParameterValues
+	overridden := make(map[string]struct{}, len(parameterValues))
+	for _, parameterValue := range parameterValues {
+		overridden[parameterValue.Name] = struct{}{}
+	}
+	for _, value := range values {
+		if _, ok := overridden[value.Name]; ok {
+			continue
+		}
+		parameterValues = append(parameterValues, codersdk.CreateParameterRequest{
+			Name:              value.Name,
+			SourceValue:       value.SourceValue,
+			SourceScheme:      codersdk.ParameterSourceScheme(value.SourceScheme),
+			DestinationScheme: codersdk.ParameterDestinationScheme(value.DestinationScheme),
+		})
+	}
+	return build.TemplateVersionID, parameterValues, true
+}
+
 func (api *API) watchWorkspace(rw http.ResponseWriter, r *http.Request) {
 	workspace := httpmw.WorkspaceParam(r)
 	if !api.Authorize(r, rbac.ActionRead, workspace) {
@@ -840,11 +1379,12 @@ func convertWorkspaces(ctx context.Context, db database.Store, workspaces []data
 		if !exists {
 			return nil, xerrors.Errorf("owner not found for workspace: %q", workspace.Name)
 		}
-		initiator, exists := userByID[build.InitiatorID]
-		if !exists {
-			return nil, xerrors.Errorf("build initiator not found for workspace: %q", workspace.Name)
+		// The user who started the build may have been deleted since.
+		var initiator *database.User
+		if user, exists := userByID[build.InitiatorID]; exists {
+			initiator = &user
 		}
-		apiWorkspaces = append(apiWorkspaces, convertWorkspace(workspace, build, job, template, &owner, &initiator))
+		apiWorkspaces = append(apiWorkspaces, convertWorkspace(workspace, build, job, template, &owner, initiator))
 	}
 	return apiWorkspaces, nil
 }
@@ -862,6 +1402,20 @@ func convertWorkspace(
 	}
 
 	ttlMillis := convertWorkspaceTTLMillis(workspace.Ttl)
+	var dormantAt, dormantAfter, deletingAt *time.Time
+	if workspace.DormantAt.Valid {
+		dormantAt = &workspace.DormantAt.Time
+		if template.DormantDeletionTtl > 0 {
+			t := workspace.DormantAt.Time.Add(time.Duration(template.DormantDeletionTtl))
+			deletingAt = &t
+		}
+	} else if !workspace.DormancyOptOut && template.InactivityTtl > 0 &&
+		workspaceBuild.Transition == database.WorkspaceTransitionStop && job.CompletedAt.Valid && !job.Error.Valid {
+		// Warns the owner ahead of the autobuild executor marking the
+		// workspace dormant.
+		t := job.CompletedAt.Time.Add(time.Duration(template.InactivityTtl))
+		dormantAfter = &t
+	}
 	return codersdk.Workspace{
 		ID:                workspace.ID,
 		CreatedAt:         workspace.CreatedAt,
@@ -875,6 +1429,10 @@ func convertWorkspace(
 		Name:              workspace.Name,
 		AutostartSchedule: autostartSchedule,
 		TTLMillis:         ttlMillis,
+		DormantAt:         dormantAt,
+		DormantAfter:      dormantAfter,
+		DeletingAt:        deletingAt,
+		DormancyOptOut:    workspace.DormancyOptOut,
 	}
 }
 
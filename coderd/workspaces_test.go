@@ -11,8 +11,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/coderd/audit"
 	"github.com/coder/coder/coderd/autobuild/schedule"
 	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/coderd/database"
 	"github.com/coder/coder/coderd/rbac"
 	"github.com/coder/coder/coderd/util/ptr"
 	"github.com/coder/coder/codersdk"
@@ -168,6 +170,68 @@ func TestPostWorkspacesByOrganization(t *testing.T) {
 		_ = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
 	})
 
+	t.Run("Clone", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID, func(req *codersdk.CreateWorkspaceRequest) {
+			req.ParameterValues = []codersdk.CreateParameterRequest{{
+				Name:              "region",
+				SourceValue:       "us-east",
+				SourceScheme:      codersdk.ParameterSourceSchemeData,
+				DestinationScheme: codersdk.ParameterDestinationSchemeProvisionerVariable,
+			}, {
+				Name:              "size",
+				SourceValue:       "small",
+				SourceScheme:      codersdk.ParameterSourceSchemeData,
+				DestinationScheme: codersdk.ParameterDestinationSchemeProvisionerVariable,
+			}}
+		})
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		// Clones use the version of the workspace, not the active one.
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{ID: newVersion.ID})
+		require.NoError(t, err)
+
+		clone := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID, func(req *codersdk.CreateWorkspaceRequest) {
+			req.FromWorkspaceID = workspace.ID
+			req.ParameterValues = []codersdk.CreateParameterRequest{{
+				Name:              "size",
+				SourceValue:       "large",
+				SourceScheme:      codersdk.ParameterSourceSchemeData,
+				DestinationScheme: codersdk.ParameterDestinationSchemeProvisionerVariable,
+			}}
+		})
+		require.NotEqual(t, workspace.ID, clone.ID)
+		require.Equal(t, version.ID, clone.LatestBuild.TemplateVersionID)
+		parameters, err := client.Parameters(ctx, codersdk.ParameterWorkspace, clone.ID)
+		require.NoError(t, err)
+		names := make([]string, 0, len(parameters))
+		for _, parameter := range parameters {
+			names = append(names, parameter.Name)
+		}
+		require.ElementsMatch(t, []string{"region", "size"}, names)
+
+		// Clones must use the template of the workspace.
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, otherVersion.ID)
+		otherTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, otherVersion.ID)
+		_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.CreateWorkspaceRequest{
+			TemplateID:      otherTemplate.ID,
+			Name:            "other",
+			FromWorkspaceID: workspace.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
 	t.Run("TemplateCustomTTL", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
@@ -186,6 +250,64 @@ func TestPostWorkspacesByOrganization(t *testing.T) {
 		require.Equal(t, template.MaxTTLMillis, template.MaxTTLMillis, workspace.TTLMillis)
 	})
 
+	t.Run("TemplateDefaultSchedule", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.DefaultAutostartSchedule = ptr.Ref("CRON_TZ=Europe/Dublin 30 9 * * 1-5")
+			ctr.DefaultTTLMillis = ptr.Ref((4 * time.Hour).Milliseconds())
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = nil
+			cwr.TTLMillis = nil
+		})
+		require.Equal(t, ptr.Ref("CRON_TZ=Europe/Dublin 30 9 * * 1-5"), workspace.AutostartSchedule)
+		require.Equal(t, ptr.Ref((4 * time.Hour).Milliseconds()), workspace.TTLMillis)
+
+		// Users can still choose their own schedule.
+		workspace = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		require.Equal(t, ptr.Ref("CRON_TZ=US/Central 30 9 * * 1-5"), workspace.AutostartSchedule)
+		require.Equal(t, ptr.Ref((8 * time.Hour).Milliseconds()), workspace.TTLMillis)
+	})
+
+	t.Run("TemplateForbidsScheduleOverride", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID, func(ctr *codersdk.CreateTemplateRequest) {
+			ctr.DefaultTTLMillis = ptr.Ref((4 * time.Hour).Milliseconds())
+			ctr.AllowUserScheduleOverride = ptr.Ref(false)
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		// The schedule of the request is ignored.
+		require.Nil(t, workspace.AutostartSchedule)
+		require.Equal(t, ptr.Ref((4 * time.Hour).Milliseconds()), workspace.TTLMillis)
+
+		var apiErr *codersdk.Error
+		err := client.UpdateWorkspaceAutostart(ctx, workspace.ID, codersdk.UpdateWorkspaceAutostartRequest{
+			Schedule: ptr.Ref("CRON_TZ=Europe/Dublin 30 9 * * 1-5"),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = client.UpdateWorkspaceTTL(ctx, workspace.ID, codersdk.UpdateWorkspaceTTLRequest{
+			TTLMillis: ptr.Ref(time.Hour.Milliseconds()),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = client.PutExtendWorkspace(ctx, workspace.ID, codersdk.PutExtendWorkspaceRequest{
+			Deadline: time.Now().Add(6 * time.Hour),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
 	t.Run("InvalidTTL", func(t *testing.T) {
 		t.Parallel()
 		t.Run("BelowMin", func(t *testing.T) {
@@ -1091,6 +1213,241 @@ func TestWorkspaceExtend(t *testing.T) {
 	require.WithinDuration(t, oldDeadline.Add(-time.Hour), updated.LatestBuild.Deadline, time.Minute)
 }
 
+func TestWorkspaceExtendAuditLog(t *testing.T) {
+	t.Parallel()
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true, Auditor: auditor})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	err := client.PutExtendWorkspace(context.Background(), workspace.ID, codersdk.PutExtendWorkspaceRequest{
+		Deadline: time.Now().Add(9 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	logs := auditor.AuditLogs()
+	require.NotEmpty(t, logs)
+	alog := logs[len(logs)-1]
+	require.Equal(t, database.AuditActionWrite, alog.Action)
+	require.Equal(t, database.ResourceTypeWorkspace, alog.ResourceType)
+	require.Equal(t, workspace.ID, alog.ResourceID)
+	require.Equal(t, int32(http.StatusOK), alog.StatusCode)
+}
+
+func TestWorkspaceTransfer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Transfer", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client, closer := coderdtest.NewWithProvisionerCloser(t, nil)
+		defer closer.Close()
+		admin := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, &echo.Responses{
+			Parse:           echo.ParseComplete,
+			ProvisionDryRun: echo.ProvisionComplete,
+			Provision: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Resources: []*proto.Resource{{
+							Name: "example",
+							Type: "aws_instance",
+							Agents: []*proto.Agent{{
+								Id:   uuid.NewString(),
+								Auth: &proto.Agent_Token{Token: authToken},
+							}},
+						}},
+					},
+				},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		previous := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		workspace := coderdtest.CreateWorkspace(t, previous, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, previous, workspace.LatestBuild.ID)
+		_, err := previous.ShareWorkspace(ctx, workspace.ID, admin.UserID.String(), codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleRead,
+		})
+		require.NoError(t, err)
+		owner := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		ownerUser, err := owner.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		agentClient := codersdk.New(client.URL)
+		agentClient.SessionToken = authToken
+		_, err = agentClient.AgentGitSSHKey(ctx)
+		require.NoError(t, err)
+
+		// Stop the provisioner, so the tokens of the previous build can be
+		// checked before the new build replaces them.
+		closer.Close()
+		build, err := client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			OwnerID: ownerUser.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, ownerUser.ID, build.WorkspaceOwnerID)
+		require.Equal(t, workspace.LatestBuild.BuildNumber+1, build.BuildNumber)
+		require.Equal(t, workspace.LatestBuild.Transition, build.Transition)
+
+		_, err = agentClient.AgentGitSSHKey(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		transferred, err := owner.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, ownerUser.ID, transferred.OwnerID)
+		builds, err := owner.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{WorkspaceID: workspace.ID})
+		require.NoError(t, err)
+		require.Len(t, builds, 2)
+		// The previous owner chose who the workspace was shared with.
+		shares, err := owner.WorkspaceShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares)
+		_, err = previous.Workspace(ctx, workspace.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		workspace := coderdtest.CreateWorkspace(t, member, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+
+		_, err := member.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			OwnerID: admin.UserID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("NameConflict", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		workspace := coderdtest.CreateWorkspace(t, member, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+		existing := coderdtest.CreateWorkspace(t, client, admin.OrganizationID, template.ID, func(req *codersdk.CreateWorkspaceRequest) {
+			req.Name = workspace.Name
+		})
+		coderdtest.AwaitWorkspaceBuildJob(t, client, existing.LatestBuild.ID)
+
+		_, err := client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			OwnerID: admin.UserID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("NotOrganizationMember", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		other, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "other"})
+		require.NoError(t, err)
+		outsider, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          "outsider@coder.com",
+			Username:       "outsider",
+			Password:       "testpass",
+			OrganizationID: other.ID,
+		})
+		require.NoError(t, err)
+
+		_, err = client.TransferWorkspace(ctx, workspace.ID, codersdk.TransferWorkspaceRequest{
+			OwnerID: outsider.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestWorkspaceRename(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Rename", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		err := client.RenameWorkspace(ctx, workspace.ID, codersdk.RenameWorkspaceRequest{Name: "renamed"})
+		require.NoError(t, err)
+		renamed, err := client.WorkspaceByOwnerAndName(ctx, codersdk.Me, "renamed", codersdk.WorkspaceOptions{})
+		require.NoError(t, err)
+		require.Equal(t, workspace.ID, renamed.ID)
+
+		// The case of the name can be changed.
+		err = client.RenameWorkspace(ctx, workspace.ID, codersdk.RenameWorkspaceRequest{Name: "Renamed"})
+		require.NoError(t, err)
+	})
+
+	t.Run("NameConflict", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		existing := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+
+		err := client.RenameWorkspace(ctx, workspace.ID, codersdk.RenameWorkspaceRequest{Name: existing.Name})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		err := member.RenameWorkspace(ctx, workspace.ID, codersdk.RenameWorkspaceRequest{Name: "renamed"})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
 func TestWorkspaceWatcher(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
@@ -0,0 +1,208 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/coderd/audit"
+	"github.com/coder/coder/coderd/database"
+	"github.com/coder/coder/coderd/httpapi"
+	"github.com/coder/coder/coderd/httpmw"
+	"github.com/coder/coder/coderd/rbac"
+	"github.com/coder/coder/codersdk"
+)
+
+// workspaceShares lists the users a workspace is shared with.
+func (api *API) workspaceShares(rw http.ResponseWriter, r *http.Request) {
+	workspace := httpmw.WorkspaceParam(r)
+	if !api.Authorize(r, rbac.ActionRead, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	shares := make([]codersdk.WorkspaceShare, 0, len(workspace.UserACL))
+	for userID, actions := range workspace.UserACL {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			continue
+		}
+		user, err := api.Database.GetUserByID(r.Context(), id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching user.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		shares = append(shares, codersdk.WorkspaceShare{
+			User: convertUser(user, []uuid.UUID{workspace.OrganizationID}, api.siteRoleDisplayNames(r.Context())),
+			Role: convertWorkspaceShareRole(actions),
+		})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].User.Username < shares[j].User.Username
+	})
+	httpapi.Write(rw, http.StatusOK, shares)
+}
+
+// putWorkspaceShare shares a workspace with a user, or changes the access
+// they were given.
+func (api *API) putWorkspaceShare(rw http.ResponseWriter, r *http.Request) {
+	var (
+		workspace = httpmw.WorkspaceParam(r)
+		user      = httpmw.UserParam(r)
+	)
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = workspace
+
+	if !api.Authorize(r, rbac.ActionUpdate, workspaceOwnerObject(workspace)) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.ShareWorkspaceRequest
+	if !httpapi.Read(rw, r, &req) {
+		return
+	}
+	if user.ID == workspace.OwnerID {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A workspace can't be shared with its owner.",
+		})
+		return
+	}
+	// Access lists are only checked for members of the organization of the
+	// workspace.
+	_, err := api.Database.GetOrganizationMemberByUserID(r.Context(), database.GetOrganizationMemberByUserIDParams{
+		OrganizationID: workspace.OrganizationID,
+		UserID:         user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Workspaces can only be shared with members of their organization.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization member.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	acl := copyWorkspaceACL(workspace.UserACL)
+	acl[user.ID.String()] = workspaceShareRoleActions(req.Role)
+	if !api.updateWorkspaceUserACL(rw, r, workspace.ID, acl) {
+		return
+	}
+	updated := workspace
+	updated.UserACL = acl
+	aReq.New = updated
+
+	httpapi.Write(rw, http.StatusOK, codersdk.WorkspaceShare{
+		User: convertUser(user, []uuid.UUID{workspace.OrganizationID}, api.siteRoleDisplayNames(r.Context())),
+		Role: req.Role,
+	})
+}
+
+// deleteWorkspaceShare revokes the access a user was given to a workspace.
+func (api *API) deleteWorkspaceShare(rw http.ResponseWriter, r *http.Request) {
+	var (
+		workspace = httpmw.WorkspaceParam(r)
+		user      = httpmw.UserParam(r)
+	)
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Auditor: api.Auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	aReq.Old = workspace
+
+	if !api.Authorize(r, rbac.ActionUpdate, workspaceOwnerObject(workspace)) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if _, ok := workspace.UserACL[user.ID.String()]; !ok {
+		httpapi.Write(rw, http.StatusNotFound, codersdk.Response{
+			Message: "The workspace isn't shared with this user.",
+		})
+		return
+	}
+
+	acl := copyWorkspaceACL(workspace.UserACL)
+	delete(acl, user.ID.String())
+	if !api.updateWorkspaceUserACL(rw, r, workspace.ID, acl) {
+		return
+	}
+	updated := workspace
+	updated.UserACL = acl
+	aReq.New = updated
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (api *API) updateWorkspaceUserACL(rw http.ResponseWriter, r *http.Request, workspaceID uuid.UUID, acl database.WorkspaceACL) bool {
+	err := api.Database.UpdateWorkspaceUserACL(r.Context(), database.UpdateWorkspaceUserACLParams{
+		ID:      workspaceID,
+		UserACL: acl,
+	})
+	if err != nil {
+		httpapi.Write(rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace shares.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// workspaceOwnerObject authorizes changes only the owner of a workspace or an
+// admin can make, like changing the users it's shared with. Its access list is
+// left out, so those users can't share it further, rename it, build it, change
+// its schedule or opt it out of dormancy.
+func workspaceOwnerObject(workspace database.Workspace) rbac.Object {
+	workspace.UserACL = nil
+	return workspace.RBACObject()
+}
+
+// workspaceShareRoleActions returns the actions a share role allows. Reading
+// a workspace allows using its applications, and updating it allows
+// connecting to its agents.
+func workspaceShareRoleActions(role codersdk.WorkspaceShareRole) []rbac.Action {
+	if role == codersdk.WorkspaceShareRoleFull {
+		return []rbac.Action{rbac.ActionRead, rbac.ActionUpdate}
+	}
+	return []rbac.Action{rbac.ActionRead}
+}
+
+func convertWorkspaceShareRole(actions []rbac.Action) codersdk.WorkspaceShareRole {
+	for _, action := range actions {
+		if action == rbac.ActionUpdate {
+			return codersdk.WorkspaceShareRoleFull
+		}
+	}
+	return codersdk.WorkspaceShareRoleRead
+}
+
+func copyWorkspaceACL(acl database.WorkspaceACL) database.WorkspaceACL {
+	copied := make(database.WorkspaceACL, len(acl))
+	for id, actions := range acl {
+		copied[id] = actions
+	}
+	return copied
+}
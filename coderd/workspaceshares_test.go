@@ -0,0 +1,200 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/coderd/coderdtest"
+	"github.com/coder/coder/codersdk"
+)
+
+func TestWorkspaceShares(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.Workspace, *codersdk.Client, codersdk.User) {
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerD: true})
+		admin := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, admin.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, admin.OrganizationID, version.ID)
+		owner := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		workspace := coderdtest.CreateWorkspace(t, owner, admin.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, owner, workspace.LatestBuild.ID)
+		member := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		memberUser, err := member.User(context.Background(), codersdk.Me)
+		require.NoError(t, err)
+		return owner, workspace, member, memberUser
+	}
+
+	t.Run("Read", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		owner, workspace, member, memberUser := setup(t)
+
+		_, err := member.Workspace(ctx, workspace.ID)
+		require.Error(t, err)
+
+		share, err := owner.ShareWorkspace(ctx, workspace.ID, memberUser.Username, codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleRead,
+		})
+		require.NoError(t, err)
+		require.Equal(t, memberUser.ID, share.User.ID)
+		require.Equal(t, codersdk.WorkspaceShareRoleRead, share.Role)
+
+		shares, err := owner.WorkspaceShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, shares, 1)
+		require.Equal(t, memberUser.ID, shares[0].User.ID)
+
+		_, err = member.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		// Reading doesn't allow starting or stopping the workspace.
+		_, err = member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.Error(t, err)
+		// Nor cloning it, which would reveal its parameter values.
+		_, err = member.CreateWorkspace(ctx, memberUser.OrganizationIDs[0], codersdk.CreateWorkspaceRequest{
+			TemplateID:      workspace.TemplateID,
+			Name:            "clone",
+			FromWorkspaceID: workspace.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Full", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		owner, workspace, member, memberUser := setup(t)
+
+		_, err := owner.ShareWorkspace(ctx, workspace.ID, memberUser.ID.String(), codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleFull,
+		})
+		require.NoError(t, err)
+		_, err = member.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		// Building the workspace is left to its owner.
+		_, err = member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		// So are changing its schedule, renaming it and opting it out of
+		// dormancy.
+		ttl := int64(time.Hour / time.Millisecond)
+		err = member.UpdateWorkspaceTTL(ctx, workspace.ID, codersdk.UpdateWorkspaceTTLRequest{
+			TTLMillis: &ttl,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = member.UpdateWorkspaceAutostart(ctx, workspace.ID, codersdk.UpdateWorkspaceAutostartRequest{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = member.PutExtendWorkspace(ctx, workspace.ID, codersdk.PutExtendWorkspaceRequest{
+			Deadline: time.Now().Add(time.Hour),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = member.RenameWorkspace(ctx, workspace.ID, codersdk.RenameWorkspaceRequest{
+			Name: "renamed",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = member.UpdateWorkspaceDormancy(ctx, workspace.ID, codersdk.UpdateWorkspaceDormancyRequest{
+			OptOut: true,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = member.CreateWorkspace(ctx, memberUser.OrganizationIDs[0], codersdk.CreateWorkspaceRequest{
+			TemplateID:      workspace.TemplateID,
+			Name:            "clone",
+			FromWorkspaceID: workspace.ID,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = owner.RenameWorkspace(ctx, workspace.ID, codersdk.RenameWorkspaceRequest{
+			Name: "renamed",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		owner, workspace, member, memberUser := setup(t)
+
+		_, err := owner.ShareWorkspace(ctx, workspace.ID, memberUser.Username, codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleFull,
+		})
+		require.NoError(t, err)
+		err = owner.DeleteWorkspaceShare(ctx, workspace.ID, memberUser.Username)
+		require.NoError(t, err)
+
+		shares, err := owner.WorkspaceShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares)
+		_, err = member.Workspace(ctx, workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		err = owner.DeleteWorkspaceShare(ctx, workspace.ID, memberUser.Username)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("NoReshare", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		owner, workspace, member, memberUser := setup(t)
+
+		_, err := owner.ShareWorkspace(ctx, workspace.ID, memberUser.Username, codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleFull,
+		})
+		require.NoError(t, err)
+		_, err = member.ShareWorkspace(ctx, workspace.ID, memberUser.Username, codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleRead,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		owner, workspace, _, _ := setup(t)
+
+		_, err := owner.ShareWorkspace(ctx, workspace.ID, codersdk.Me, codersdk.ShareWorkspaceRequest{
+			Role: codersdk.WorkspaceShareRoleRead,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidRole", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		owner, workspace, _, memberUser := setup(t)
+
+		_, err := owner.ShareWorkspace(ctx, workspace.ID, memberUser.Username, codersdk.ShareWorkspaceRequest{
+			Role: "admin",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,91 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ResourceType string
+
+const (
+	ResourceTypeOrganization       ResourceType = "organization"
+	ResourceTypeTemplate           ResourceType = "template"
+	ResourceTypeTemplateVersion    ResourceType = "template_version"
+	ResourceTypeUser               ResourceType = "user"
+	ResourceTypeWorkspace          ResourceType = "workspace"
+	ResourceTypeOrganizationMember ResourceType = "organization_member"
+	ResourceTypeGitSSHKey          ResourceType = "git_ssh_key"
+	ResourceTypeAPIKey             ResourceType = "api_key"
+	ResourceTypeUserTOTP           ResourceType = "user_totp"
+	ResourceTypeCustomRole         ResourceType = "custom_role"
+	ResourceTypeGroup              ResourceType = "group"
+	ResourceTypeUserLockout        ResourceType = "user_lockout"
+	ResourceTypeUserQuota          ResourceType = "user_quota"
+	ResourceTypeOrganizationQuota  ResourceType = "organization_quota"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionWrite  AuditAction = "write"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditLog is a record of a mutating action taken against a resource.
+type AuditLog struct {
+	ID             uuid.UUID    `json:"id"`
+	Time           time.Time    `json:"time"`
+	OrganizationID uuid.UUID    `json:"organization_id"`
+	IP             string       `json:"ip"`
+	UserAgent      string       `json:"user_agent"`
+	ResourceType   ResourceType `json:"resource_type"`
+	ResourceID     uuid.UUID    `json:"resource_id"`
+	ResourceTarget string       `json:"resource_target"`
+	Action         AuditAction  `json:"action"`
+	// Diff maps the fields of the resource that changed to their new value.
+	Diff       json.RawMessage `json:"diff" typescript:"Record<string, unknown>"`
+	StatusCode int32           `json:"status_code"`
+
+	UserID uuid.UUID `json:"user_id"`
+	// User is nil if the action was unauthenticated or the user no longer
+	// exists.
+	User *User `json:"user"`
+}
+
+type AuditLogsRequest struct {
+	// SearchQuery filters audit logs. Supported keys are "user",
+	// "resource_type", "resource_id", "action", "status_code", "date_from"
+	// and "date_to", e.g. "action:delete resource_type:workspace".
+	SearchQuery string `json:"q,omitempty"`
+	Pagination
+}
+
+// AuditLogs returns audit logs matching the request, newest first.
+func (c *Client) AuditLogs(ctx context.Context, req AuditLogsRequest) ([]AuditLog, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit", nil,
+		req.Pagination.asRequestOption(),
+		func(r *http.Request) {
+			q := r.URL.Query()
+			if req.SearchQuery != "" {
+				q.Set("q", req.SearchQuery)
+			}
+			r.URL.RawQuery = q.Encode()
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+
+	var logs []AuditLog
+	return logs, json.NewDecoder(res.Body).Decode(&logs)
+}
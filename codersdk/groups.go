@@ -0,0 +1,157 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// Group is a set of organization members. Members inherit the organization
+// roles granted to the group, and any access the group has to templates.
+type Group struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Roles          []string  `json:"roles"`
+	Members        []User    `json:"members"`
+}
+
+type CreateGroupRequest struct {
+	Name string `json:"name" validate:"required,username"`
+	// Roles are organization roles granted to members of the group.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// PatchGroupRequest renames a group and adds or removes members by user ID.
+type PatchGroupRequest struct {
+	Name        string   `json:"name,omitempty" validate:"omitempty,username"`
+	AddUsers    []string `json:"add_users,omitempty"`
+	RemoveUsers []string `json:"remove_users,omitempty"`
+}
+
+// CreateGroup creates a group inside an organization.
+func (c *Client) CreateGroup(ctx context.Context, organizationID uuid.UUID, req CreateGroupRequest) (Group, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/groups", organizationID.String()),
+		req,
+	)
+	if err != nil {
+		return Group{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return Group{}, readBodyAsError(res)
+	}
+	var group Group
+	return group, json.NewDecoder(res.Body).Decode(&group)
+}
+
+// GroupsByOrganization lists the groups inside an organization.
+func (c *Client) GroupsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]Group, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/groups", organizationID.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+	var groups []Group
+	return groups, json.NewDecoder(res.Body).Decode(&groups)
+}
+
+// GroupByOrgAndName finds a group inside an organization by name.
+func (c *Client) GroupByOrgAndName(ctx context.Context, organizationID uuid.UUID, name string) (Group, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/groups/%s", organizationID.String(), name),
+		nil,
+	)
+	if err != nil {
+		return Group{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Group{}, readBodyAsError(res)
+	}
+	var group Group
+	return group, json.NewDecoder(res.Body).Decode(&group)
+}
+
+func (c *Client) Group(ctx context.Context, group uuid.UUID) (Group, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/groups/%s", group.String()),
+		nil,
+	)
+	if err != nil {
+		return Group{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Group{}, readBodyAsError(res)
+	}
+	var resp Group
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) PatchGroup(ctx context.Context, group uuid.UUID, req PatchGroupRequest) (Group, error) {
+	res, err := c.Request(ctx, http.MethodPatch,
+		fmt.Sprintf("/api/v2/groups/%s", group.String()),
+		req,
+	)
+	if err != nil {
+		return Group{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Group{}, readBodyAsError(res)
+	}
+	var resp Group
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateGroupRoles replaces the organization roles granted to the members of
+// a group.
+func (c *Client) UpdateGroupRoles(ctx context.Context, group uuid.UUID, req UpdateRoles) (Group, error) {
+	res, err := c.Request(ctx, http.MethodPut,
+		fmt.Sprintf("/api/v2/groups/%s/roles", group.String()),
+		req,
+	)
+	if err != nil {
+		return Group{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Group{}, readBodyAsError(res)
+	}
+	var resp Group
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) DeleteGroup(ctx context.Context, group uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/groups/%s", group.String()),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
@@ -32,6 +32,10 @@ type Organization struct {
 	UpdatedAt time.Time `json:"updated_at" validate:"required"`
 }
 
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,username"`
+}
+
 // CreateTemplateVersionRequest enables callers to create a new Template Version.
 type CreateTemplateVersionRequest struct {
 	// TemplateID optionally associates a version with a template.
@@ -70,6 +74,38 @@ type CreateTemplateRequest struct {
 	// allowable duration between autostarts for all workspaces created from
 	// this template.
 	MinAutostartIntervalMillis *int64 `json:"min_autostart_interval_ms,omitempty"`
+
+	// CostUnits is how much a running workspace of the template counts
+	// toward quotas on cost units.
+	CostUnits int32 `json:"cost_units,omitempty" validate:"min=0"`
+
+	// InactivityTTLMillis allows optionally specifying how long workspaces
+	// created from this template can stay stopped before they're marked
+	// dormant.
+	InactivityTTLMillis *int64 `json:"inactivity_ttl_ms,omitempty"`
+
+	// DormantDeletionTTLMillis allows optionally specifying how long
+	// workspaces created from this template can stay dormant before they're
+	// deleted.
+	DormantDeletionTTLMillis *int64 `json:"dormant_deletion_ttl_ms,omitempty"`
+
+	// DefaultAutostartSchedule allows optionally specifying the autostart
+	// schedule of workspaces created from this template.
+	DefaultAutostartSchedule *string `json:"default_autostart_schedule,omitempty"`
+
+	// DefaultTTLMillis allows optionally specifying the TTL of workspaces
+	// created from this template.
+	DefaultTTLMillis *int64 `json:"default_ttl_ms,omitempty"`
+
+	// RequiredStopSchedule allows optionally specifying a weekly schedule,
+	// without a timezone, at which running workspaces created from this
+	// template are stopped in the timezone of their owner.
+	RequiredStopSchedule *string `json:"required_stop_schedule,omitempty"`
+
+	// AllowUserScheduleOverride allows optionally forbidding workspace
+	// owners from changing the autostart schedule and TTL of their
+	// workspaces. Defaults to true.
+	AllowUserScheduleOverride *bool `json:"allow_user_schedule_override,omitempty"`
 }
 
 // CreateWorkspaceRequest provides options for creating a new workspace.
@@ -81,6 +117,11 @@ type CreateWorkspaceRequest struct {
 	// ParameterValues allows for additional parameters to be provided
 	// during the initial provision.
 	ParameterValues []CreateParameterRequest `json:"parameter_values,omitempty"`
+	// FromWorkspaceID clones a workspace of the same template. The new
+	// workspace is built with the template version and parameter values of
+	// the latest build of the workspace. ParameterValues override the copied
+	// values.
+	FromWorkspaceID uuid.UUID `json:"from_workspace_id,omitempty"`
 }
 
 func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization, error) {
@@ -98,6 +139,53 @@ func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization,
 	return organization, json.NewDecoder(res.Body).Decode(&organization)
 }
 
+// Organizations returns the organizations the user can read.
+func (c *Client) Organizations(ctx context.Context) ([]Organization, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/organizations", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+
+	var organizations []Organization
+	return organizations, json.NewDecoder(res.Body).Decode(&organizations)
+}
+
+// UpdateOrganization renames an organization.
+func (c *Client) UpdateOrganization(ctx context.Context, id uuid.UUID, req UpdateOrganizationRequest) (Organization, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s", id.String()), req)
+	if err != nil {
+		return Organization{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Organization{}, readBodyAsError(res)
+	}
+
+	var organization Organization
+	return organization, json.NewDecoder(res.Body).Decode(&organization)
+}
+
+// DeleteOrganization deletes an organization. Organizations can only be
+// deleted once their templates and workspaces are.
+func (c *Client) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s", id.String()), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
 // ProvisionerDaemonsByOrganization returns provisioner daemons available for an organization.
 func (c *Client) ProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
 	res, err := c.Request(ctx, http.MethodGet,
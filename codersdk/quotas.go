@@ -0,0 +1,95 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceQuotaExceeded is the message of errors returned when creating or
+// starting a workspace would exceed a quota.
+const WorkspaceQuotaExceeded = "Workspace quota exceeded."
+
+// QuotaLimits limits the workspaces of a user or organization. Zero means
+// unlimited.
+type QuotaLimits struct {
+	MaxWorkspaces int32 `json:"max_workspaces" validate:"min=0"`
+	// MaxRunningWorkspaces limits how many workspaces can run at once.
+	MaxRunningWorkspaces int32 `json:"max_running_workspaces" validate:"min=0"`
+	// MaxCostUnits limits the sum of the cost units of the templates of
+	// running workspaces.
+	MaxCostUnits int32 `json:"max_cost_units" validate:"min=0"`
+}
+
+// QuotaUsage is how much of a quota is used.
+type QuotaUsage struct {
+	Workspaces        int64 `json:"workspaces"`
+	RunningWorkspaces int64 `json:"running_workspaces"`
+	CostUnits         int64 `json:"cost_units"`
+}
+
+type Quota struct {
+	Limits QuotaLimits `json:"limits"`
+	Usage  QuotaUsage  `json:"usage"`
+}
+
+// UserQuota returns the quota of a user, and the usage of their workspaces
+// across organizations.
+func (c *Client) UserQuota(ctx context.Context, user string) (Quota, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/quota", user), nil)
+	if err != nil {
+		return Quota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Quota{}, readBodyAsError(res)
+	}
+	var quota Quota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
+
+// UpdateUserQuota sets the quota of a user.
+func (c *Client) UpdateUserQuota(ctx context.Context, user string, req QuotaLimits) (Quota, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/quota", user), req)
+	if err != nil {
+		return Quota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Quota{}, readBodyAsError(res)
+	}
+	var quota Quota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
+
+// OrganizationQuota returns the quota of an organization, and the usage of
+// the workspaces in it.
+func (c *Client) OrganizationQuota(ctx context.Context, organizationID uuid.UUID) (Quota, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/quota", organizationID.String()), nil)
+	if err != nil {
+		return Quota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Quota{}, readBodyAsError(res)
+	}
+	var quota Quota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
+
+// UpdateOrganizationQuota sets the quota of an organization.
+func (c *Client) UpdateOrganizationQuota(ctx context.Context, organizationID uuid.UUID, req QuotaLimits) (Quota, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/organizations/%s/quota", organizationID.String()), req)
+	if err != nil {
+		return Quota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Quota{}, readBodyAsError(res)
+	}
+	var quota Quota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
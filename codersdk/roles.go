@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,6 +15,47 @@ type Role struct {
 	DisplayName string `json:"display_name"`
 }
 
+// Permission allows an action on every resource of a type, or denies it if
+// Negate is set. "*" matches any resource type or action.
+type Permission struct {
+	Negate       bool   `json:"negate"`
+	ResourceType string `json:"resource_type" validate:"required"`
+	Action       string `json:"action" validate:"required"`
+}
+
+// CustomRole is a role created in addition to the built-in roles. Site roles
+// have site and user permissions. Organization roles only have organization
+// permissions, and are assigned to members as "<name>:<organization id>".
+type CustomRole struct {
+	ID                      uuid.UUID    `json:"id"`
+	Name                    string       `json:"name"`
+	DisplayName             string       `json:"display_name"`
+	OrganizationID          *uuid.UUID   `json:"organization_id,omitempty"`
+	SitePermissions         []Permission `json:"site_permissions"`
+	OrganizationPermissions []Permission `json:"organization_permissions"`
+	// UserPermissions apply to the resources owned by the user.
+	UserPermissions []Permission `json:"user_permissions"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+type CreateRoleRequest struct {
+	Name                    string       `json:"name" validate:"required,username"`
+	DisplayName             string       `json:"display_name" validate:"required"`
+	SitePermissions         []Permission `json:"site_permissions,omitempty" validate:"dive"`
+	OrganizationPermissions []Permission `json:"organization_permissions,omitempty" validate:"dive"`
+	UserPermissions         []Permission `json:"user_permissions,omitempty" validate:"dive"`
+}
+
+// UpdateRoleRequest replaces the display name and permissions of a custom
+// role.
+type UpdateRoleRequest struct {
+	DisplayName             string       `json:"display_name" validate:"required"`
+	SitePermissions         []Permission `json:"site_permissions,omitempty" validate:"dive"`
+	OrganizationPermissions []Permission `json:"organization_permissions,omitempty" validate:"dive"`
+	UserPermissions         []Permission `json:"user_permissions,omitempty" validate:"dive"`
+}
+
 // ListSiteRoles lists all available site wide roles.
 // This is not user specific.
 func (c *Client) ListSiteRoles(ctx context.Context) ([]Role, error) {
@@ -56,3 +98,99 @@ func (c *Client) CheckPermissions(ctx context.Context, checks UserAuthorizationR
 	var roles UserAuthorizationResponse
 	return roles, json.NewDecoder(res.Body).Decode(&roles)
 }
+
+// CustomSiteRoles lists the custom site roles.
+func (c *Client) CustomSiteRoles(ctx context.Context) ([]CustomRole, error) {
+	return c.customRoles(ctx, "/api/v2/roles")
+}
+
+// CreateCustomSiteRole creates a site role with the given permissions.
+func (c *Client) CreateCustomSiteRole(ctx context.Context, req CreateRoleRequest) (CustomRole, error) {
+	return c.createCustomRole(ctx, "/api/v2/roles", req)
+}
+
+// UpdateCustomSiteRole replaces the display name and permissions of a site
+// role.
+func (c *Client) UpdateCustomSiteRole(ctx context.Context, name string, req UpdateRoleRequest) (CustomRole, error) {
+	return c.updateCustomRole(ctx, fmt.Sprintf("/api/v2/roles/%s", name), req)
+}
+
+// DeleteCustomSiteRole deletes a site role and removes it from the users
+// it's assigned to.
+func (c *Client) DeleteCustomSiteRole(ctx context.Context, name string) error {
+	return c.deleteCustomRole(ctx, fmt.Sprintf("/api/v2/roles/%s", name))
+}
+
+// CustomOrganizationRoles lists the custom roles of an organization.
+func (c *Client) CustomOrganizationRoles(ctx context.Context, org uuid.UUID) ([]CustomRole, error) {
+	return c.customRoles(ctx, fmt.Sprintf("/api/v2/organizations/%s/roles", org.String()))
+}
+
+// CreateCustomOrganizationRole creates an organization role with the given
+// permissions.
+func (c *Client) CreateCustomOrganizationRole(ctx context.Context, org uuid.UUID, req CreateRoleRequest) (CustomRole, error) {
+	return c.createCustomRole(ctx, fmt.Sprintf("/api/v2/organizations/%s/roles", org.String()), req)
+}
+
+// UpdateCustomOrganizationRole replaces the display name and permissions of
+// an organization role.
+func (c *Client) UpdateCustomOrganizationRole(ctx context.Context, org uuid.UUID, name string, req UpdateRoleRequest) (CustomRole, error) {
+	return c.updateCustomRole(ctx, fmt.Sprintf("/api/v2/organizations/%s/roles/%s", org.String(), name), req)
+}
+
+// DeleteCustomOrganizationRole deletes an organization role and removes it
+// from the members it's assigned to.
+func (c *Client) DeleteCustomOrganizationRole(ctx context.Context, org uuid.UUID, name string) error {
+	return c.deleteCustomRole(ctx, fmt.Sprintf("/api/v2/organizations/%s/roles/%s", org.String(), name))
+}
+
+func (c *Client) customRoles(ctx context.Context, path string) ([]CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+	var roles []CustomRole
+	return roles, json.NewDecoder(res.Body).Decode(&roles)
+}
+
+func (c *Client) createCustomRole(ctx context.Context, path string, req CreateRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return CustomRole{}, readBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+func (c *Client) updateCustomRole(ctx context.Context, path string, req UpdateRoleRequest) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, readBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+func (c *Client) deleteCustomRole(ctx context.Context, path string) error {
+	res, err := c.Request(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
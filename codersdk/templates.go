@@ -27,6 +27,25 @@ type Template struct {
 	MinAutostartIntervalMillis int64           `json:"min_autostart_interval_ms"`
 	CreatedByID                uuid.UUID       `json:"created_by_id"`
 	CreatedByName              string          `json:"created_by_name"`
+	// CostUnits is how much a running workspace of the template counts
+	// toward quotas on cost units.
+	CostUnits int32 `json:"cost_units"`
+	// InactivityTTLMillis is how long a workspace can stay stopped before
+	// it's marked dormant. Zero disables dormancy.
+	InactivityTTLMillis int64 `json:"inactivity_ttl_ms"`
+	// DormantDeletionTTLMillis is how long a workspace can stay dormant
+	// before it's deleted. Zero disables automatic deletion.
+	DormantDeletionTTLMillis int64 `json:"dormant_deletion_ttl_ms"`
+	// DefaultAutostartSchedule and DefaultTTLMillis are applied to new
+	// workspaces of the template.
+	DefaultAutostartSchedule string `json:"default_autostart_schedule"`
+	DefaultTTLMillis         int64  `json:"default_ttl_ms"`
+	// RequiredStopSchedule is a weekly cron schedule without a timezone at
+	// which running workspaces are stopped, in the timezone of their owner.
+	RequiredStopSchedule string `json:"required_stop_schedule"`
+	// AllowUserScheduleOverride is whether workspace owners can change the
+	// autostart schedule and TTL of their workspaces.
+	AllowUserScheduleOverride bool `json:"allow_user_schedule_override"`
 }
 
 type UpdateActiveTemplateVersion struct {
@@ -37,6 +56,56 @@ type UpdateTemplateMeta struct {
 	Description                string `json:"description,omitempty"`
 	MaxTTLMillis               int64  `json:"max_ttl_ms,omitempty"`
 	MinAutostartIntervalMillis int64  `json:"min_autostart_interval_ms,omitempty"`
+	// CostUnits is kept unchanged when nil.
+	CostUnits *int32 `json:"cost_units,omitempty"`
+	// InactivityTTLMillis is kept unchanged when nil, and zero disables
+	// dormancy.
+	InactivityTTLMillis *int64 `json:"inactivity_ttl_ms,omitempty"`
+	// DormantDeletionTTLMillis is kept unchanged when nil, and zero disables
+	// automatic deletion.
+	DormantDeletionTTLMillis *int64 `json:"dormant_deletion_ttl_ms,omitempty"`
+	// DefaultAutostartSchedule, DefaultTTLMillis, RequiredStopSchedule and
+	// AllowUserScheduleOverride are kept unchanged when nil. Empty
+	// schedules and zero TTLs disable them.
+	DefaultAutostartSchedule  *string `json:"default_autostart_schedule,omitempty"`
+	DefaultTTLMillis          *int64  `json:"default_ttl_ms,omitempty"`
+	RequiredStopSchedule      *string `json:"required_stop_schedule,omitempty"`
+	AllowUserScheduleOverride *bool   `json:"allow_user_schedule_override,omitempty"`
+}
+
+// TemplateRole is the access a user or group is granted to a template. "use"
+// allows creating workspaces from it, "admin" allows managing it.
+type TemplateRole string
+
+const (
+	TemplateRoleAdmin TemplateRole = "admin"
+	TemplateRoleUse   TemplateRole = "use"
+	// TemplateRoleDeleted removes a grant.
+	TemplateRoleDeleted TemplateRole = ""
+)
+
+type TemplateUser struct {
+	User User         `json:"user"`
+	Role TemplateRole `json:"role"`
+}
+
+type TemplateGroup struct {
+	Group Group        `json:"group"`
+	Role  TemplateRole `json:"role"`
+}
+
+// TemplateACL lists the users and groups with access to a template. Only
+// organization admins can access templates that aren't listed for them.
+type TemplateACL struct {
+	Users  []TemplateUser  `json:"users"`
+	Groups []TemplateGroup `json:"groups"`
+}
+
+// UpdateTemplateACL maps user and group IDs to the role they're granted. The
+// ID of the organization is the group of all its members.
+type UpdateTemplateACL struct {
+	UserPerms  map[string]TemplateRole `json:"user_perms,omitempty"`
+	GroupPerms map[string]TemplateRole `json:"group_perms,omitempty"`
 }
 
 // Template returns a single template.
@@ -81,6 +150,34 @@ func (c *Client) UpdateTemplateMeta(ctx context.Context, templateID uuid.UUID, r
 	return updated, json.NewDecoder(res.Body).Decode(&updated)
 }
 
+// TemplateACL returns the users and groups granted access to a template.
+func (c *Client) TemplateACL(ctx context.Context, templateID uuid.UUID) (TemplateACL, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/acl", templateID), nil)
+	if err != nil {
+		return TemplateACL{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateACL{}, readBodyAsError(res)
+	}
+	var acl TemplateACL
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
+// UpdateTemplateACL grants or revokes the access of users and groups to a
+// template.
+func (c *Client) UpdateTemplateACL(ctx context.Context, templateID uuid.UUID, req UpdateTemplateACL) error {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s/acl", templateID), req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
 // UpdateActiveTemplateVersion updates the active template version to the ID provided.
 // The template version must be attached to the template.
 func (c *Client) UpdateActiveTemplateVersion(ctx context.Context, template uuid.UUID, req UpdateActiveTemplateVersion) error {
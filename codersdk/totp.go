@@ -0,0 +1,128 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// TOTPStatus describes a user's TOTP second factor.
+type TOTPStatus struct {
+	Enabled                bool  `json:"enabled"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// TOTPEnrollment contains the secret to add to an authenticator app. The
+// enrollment is completed by verifying a code generated from it.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// URL is an otpauth:// URL that can be rendered as a QR code.
+	URL string `json:"url"`
+}
+
+type VerifyTOTPRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// ResetTOTPRequest confirms removing a second factor.
+type ResetTOTPRequest struct {
+	// Code is a TOTP or recovery code. It's required to reset your own second
+	// factor, so a stolen session can't remove it. Admins reset the second
+	// factor of other users without one.
+	Code string `json:"code,omitempty"`
+}
+
+// TOTPRecoveryCodes can each be used once in place of a TOTP code. They're
+// only displayed when enrollment is completed.
+type TOTPRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginWithTOTPRequest completes a password login that requires a second
+// factor.
+type LoginWithTOTPRequest struct {
+	// TOTPToken is returned by LoginWithPassword.
+	TOTPToken string `json:"totp_token" validate:"required"`
+	// Code is generated by an authenticator app, or is a recovery code.
+	Code string `json:"code" validate:"required"`
+}
+
+// LoginWithTOTPResponse contains a session token for the authenticated user.
+type LoginWithTOTPResponse struct {
+	SessionToken string `json:"session_token"`
+	// RecoveryCodes are set when logging in completed enrollment.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// LoginWithTOTP exchanges the TOTP token returned by LoginWithPassword and a
+// code for a session token.
+// Call `SetSessionToken()` to apply the newly acquired token to the client.
+func (c *Client) LoginWithTOTP(ctx context.Context, req LoginWithTOTPRequest) (LoginWithTOTPResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/totp", req)
+	if err != nil {
+		return LoginWithTOTPResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithTOTPResponse{}, readBodyAsError(res)
+	}
+	var resp LoginWithTOTPResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// TOTP returns the status of the user's TOTP second factor.
+func (c *Client) TOTP(ctx context.Context, user string) (TOTPStatus, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/totp", user), nil)
+	if err != nil {
+		return TOTPStatus{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TOTPStatus{}, readBodyAsError(res)
+	}
+	var status TOTPStatus
+	return status, json.NewDecoder(res.Body).Decode(&status)
+}
+
+// EnrollTOTP starts enrolling the user in TOTP. Call VerifyTOTP with a code
+// generated from the secret to complete it.
+func (c *Client) EnrollTOTP(ctx context.Context, user string) (TOTPEnrollment, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/totp", user), nil)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TOTPEnrollment{}, readBodyAsError(res)
+	}
+	var enrollment TOTPEnrollment
+	return enrollment, json.NewDecoder(res.Body).Decode(&enrollment)
+}
+
+// VerifyTOTP completes enrollment, returning recovery codes.
+func (c *Client) VerifyTOTP(ctx context.Context, user string, req VerifyTOTPRequest) (TOTPRecoveryCodes, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/totp/verify", user), req)
+	if err != nil {
+		return TOTPRecoveryCodes{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TOTPRecoveryCodes{}, readBodyAsError(res)
+	}
+	var codes TOTPRecoveryCodes
+	return codes, json.NewDecoder(res.Body).Decode(&codes)
+}
+
+// ResetTOTP removes the user's TOTP second factor and recovery codes.
+func (c *Client) ResetTOTP(ctx context.Context, user string, req ResetTOTPRequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/totp", user), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
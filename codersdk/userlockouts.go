@@ -0,0 +1,46 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// UserLockout describes the failed password logins of a user. Users are
+// locked out for a while after too many failed logins.
+type UserLockout struct {
+	// FailedAttempts is the number of failed logins since the last
+	// successful login or lockout.
+	FailedAttempts int32 `json:"failed_attempts"`
+	// LockedUntil is set while the user is locked out.
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+// UserLockout returns the lockout of a user.
+func (c *Client) UserLockout(ctx context.Context, user string) (UserLockout, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/lockout", user), nil)
+	if err != nil {
+		return UserLockout{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserLockout{}, readBodyAsError(res)
+	}
+	var lockout UserLockout
+	return lockout, json.NewDecoder(res.Body).Decode(&lockout)
+}
+
+// UnlockUser clears the lockout and failed logins of a user.
+func (c *Client) UnlockUser(ctx context.Context, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/lockout", user), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
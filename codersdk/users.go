@@ -27,6 +27,8 @@ type LoginType string
 const (
 	LoginTypePassword LoginType = "password"
 	LoginTypeGithub   LoginType = "github"
+	LoginTypeOIDC     LoginType = "oidc"
+	LoginTypeToken    LoginType = "token"
 )
 
 type UsersRequest struct {
@@ -45,6 +47,7 @@ type User struct {
 	ID              uuid.UUID   `json:"id" validate:"required"`
 	Email           string      `json:"email" validate:"required"`
 	CreatedAt       time.Time   `json:"created_at" validate:"required"`
+	LastSeenAt      time.Time   `json:"last_seen_at"`
 	Username        string      `json:"username" validate:"required"`
 	Status          UserStatus  `json:"status"`
 	OrganizationIDs []uuid.UUID `json:"organization_ids"`
@@ -60,6 +63,11 @@ type APIKey struct {
 	UpdatedAt       time.Time `json:"updated_at" validate:"required"`
 	LoginType       LoginType `json:"login_type" validate:"required"`
 	LifetimeSeconds int64     `json:"lifetime_seconds" validate:"required"`
+	Scopes          []string  `json:"scopes" validate:"required"`
+	// TokenName is only set for keys created as named tokens.
+	TokenName string `json:"token_name"`
+	// IPAddress is the address the key was last used from.
+	IPAddress string `json:"ip_address"`
 }
 
 type CreateFirstUserRequest struct {
@@ -159,6 +167,26 @@ type LoginWithPasswordRequest struct {
 // LoginWithPasswordResponse contains a session token for the newly authenticated user.
 type LoginWithPasswordResponse struct {
 	SessionToken string `json:"session_token" validate:"required"`
+	// TOTPToken is set instead of SessionToken when the user must provide a
+	// TOTP code to log in. Exchange it for a session with LoginWithTOTP.
+	TOTPToken string `json:"totp_token,omitempty"`
+	// TOTPEnrollment is set when the user must enroll in TOTP to log in. The
+	// code passed to LoginWithTOTP completes the enrollment.
+	TOTPEnrollment *TOTPEnrollment `json:"totp_enrollment,omitempty"`
+}
+
+// CreateTokenRequest creates an API key that expires at a fixed time and may
+// be restricted to a subset of the user's permissions.
+type CreateTokenRequest struct {
+	// TokenName identifies the token when listing or removing tokens. It must
+	// be unique for the user.
+	TokenName string `json:"token_name,omitempty" validate:"omitempty,username"`
+	// LifetimeSeconds is how long the token is valid for. Defaults to 30 days,
+	// or the server's maximum token lifetime if that's shorter.
+	LifetimeSeconds int64 `json:"lifetime_seconds,omitempty"`
+	// Scopes restrict the token's permissions, e.g. "workspace:read" or
+	// "template:push". Defaults to "all", which doesn't restrict the token.
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
@@ -174,6 +202,13 @@ type CreateOrganizationRequest struct {
 type AuthMethods struct {
 	Password bool `json:"password"`
 	Github   bool `json:"github"`
+	OIDC     bool `json:"oidc"`
+}
+
+// PasswordPolicy is the requirements passwords must meet when they're set.
+type PasswordPolicy struct {
+	MinLength           int `json:"min_length"`
+	MinCharacterClasses int `json:"min_character_classes"`
 }
 
 // HasFirstUser returns whether the first user has been created.
@@ -260,6 +295,49 @@ func (c *Client) UpdateUserStatus(ctx context.Context, user string, status UserS
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// DeleteUserOptions control what happens to the workspaces of a user being
+// deleted. Users who own workspaces can't be deleted without it.
+type DeleteUserOptions struct {
+	// DestroyWorkspaces queues builds that destroy the workspaces of the user
+	// instead of deleting the user. The server responds with 202 Accepted and
+	// the builds, and the user isn't deleted: call DeleteUser again once the
+	// builds complete. Users without workspaces are deleted right away.
+	DestroyWorkspaces bool `json:"destroy_workspaces,omitempty"`
+}
+
+// asRequestOption returns a function that can be used in (*Client).Request.
+// It modifies the request query parameters.
+func (o DeleteUserOptions) asRequestOption() requestOption {
+	return func(r *http.Request) {
+		q := r.URL.Query()
+		if o.DestroyWorkspaces {
+			q.Set("destroy_workspaces", "true")
+		}
+		r.URL.RawQuery = q.Encode()
+	}
+}
+
+// DeleteUser deletes a user, freeing their username and email for reuse.
+// When their workspaces are being destroyed, the server responds with 202
+// Accepted, the builds doing so are returned and the user isn't deleted yet.
+// A nil slice means the user was deleted.
+func (c *Client) DeleteUser(ctx context.Context, user string, opts DeleteUserOptions) ([]WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s", user), nil, opts.asRequestOption())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil, nil
+	case http.StatusAccepted:
+		var builds []WorkspaceBuild
+		return builds, json.NewDecoder(res.Body).Decode(&builds)
+	default:
+		return nil, readBodyAsError(res)
+	}
+}
+
 // UpdateUserPassword updates a user password.
 // It calls PUT /users/{user}/password
 func (c *Client) UpdateUserPassword(ctx context.Context, user string, req UpdateUserPasswordRequest) error {
@@ -332,6 +410,20 @@ func (c *Client) CreateAPIKey(ctx context.Context, user string) (*GenerateAPIKey
 	return apiKey, json.NewDecoder(res.Body).Decode(apiKey)
 }
 
+// CreateToken generates a long-lived API key for the user provided.
+func (c *Client) CreateToken(ctx context.Context, user string, req CreateTokenRequest) (*GenerateAPIKeyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/keys/tokens", user), req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return nil, readBodyAsError(res)
+	}
+	apiKey := &GenerateAPIKeyResponse{}
+	return apiKey, json.NewDecoder(res.Body).Decode(apiKey)
+}
+
 func (c *Client) GetAPIKey(ctx context.Context, user string, id string) (*APIKey, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/keys/%s", user, id), nil)
 	if err != nil {
@@ -345,7 +437,50 @@ func (c *Client) GetAPIKey(ctx context.Context, user string, id string) (*APIKey
 	return apiKey, json.NewDecoder(res.Body).Decode(apiKey)
 }
 
+// APIKeys returns the API keys of the user provided, including sessions
+// created by logging in.
+func (c *Client) APIKeys(ctx context.Context, user string) ([]APIKey, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/keys", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+	var apiKeys []APIKey
+	return apiKeys, json.NewDecoder(res.Body).Decode(&apiKeys)
+}
+
+// DeleteAPIKey revokes an API key of the user provided.
+func (c *Client) DeleteAPIKey(ctx context.Context, user string, id string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/keys/%s", user, id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
+// DeleteAPIKeys revokes every API key of the user provided, signing them out
+// of all sessions.
+func (c *Client) DeleteAPIKeys(ctx context.Context, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/keys", user), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
 // LoginWithPassword creates a session token authenticating with an email and password.
+// If the user must provide a second factor, TOTPToken is set instead.
 // Call `SetSessionToken()` to apply the newly acquired token to the client.
 func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordRequest) (LoginWithPasswordResponse, error) {
 	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login", req)
@@ -353,7 +488,9 @@ func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordReq
 		return LoginWithPasswordResponse{}, err
 	}
 	defer res.Body.Close()
-	if res.StatusCode != http.StatusCreated {
+	// Accepted means the password was correct, but a second factor is
+	// required.
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusAccepted {
 		return LoginWithPasswordResponse{}, readBodyAsError(res)
 	}
 	var resp LoginWithPasswordResponse
@@ -471,6 +608,22 @@ func (c *Client) CreateOrganization(ctx context.Context, req CreateOrganizationR
 	return org, json.NewDecoder(res.Body).Decode(&org)
 }
 
+// PasswordPolicy returns the requirements passwords must meet.
+func (c *Client) PasswordPolicy(ctx context.Context) (PasswordPolicy, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/password-policy", nil)
+	if err != nil {
+		return PasswordPolicy{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return PasswordPolicy{}, readBodyAsError(res)
+	}
+
+	var policy PasswordPolicy
+	return policy, json.NewDecoder(res.Body).Decode(&policy)
+}
+
 // AuthMethods returns types of authentication available to the user.
 func (c *Client) AuthMethods(ctx context.Context) (AuthMethods, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/authmethods", nil)
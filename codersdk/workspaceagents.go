@@ -343,6 +343,20 @@ func (c *Client) UploadWorkspaceAgentKeys(ctx context.Context, keys agent.Wiregu
 	return nil
 }
 
+// PostWorkspaceAgentActivity reports the active sessions of the agent. The
+// autostop deadline of the workspace is postponed while it has sessions.
+func (c *Client) PostWorkspaceAgentActivity(ctx context.Context, activity agent.Activity) error {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/activity", activity)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
 // DialWorkspaceAgent creates a connection to the specified resource.
 func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, options *peer.ConnOptions) (*agent.Conn, error) {
 	serverURL, err := c.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/dial", agentID.String()))
@@ -31,6 +31,10 @@ const (
 	// "autostop" is used when a build to stop a workspace is triggered by Autostop.
 	// The initiator id/username in this case is the workspace owner and can be ignored.
 	BuildReasonAutostop BuildReason = "autostop"
+	// "autodelete" is used when a build to delete a workspace is triggered
+	// because it was dormant for longer than its template allows.
+	// The initiator id/username in this case is the workspace owner and can be ignored.
+	BuildReasonAutodelete BuildReason = "autodelete"
 )
 
 // WorkspaceBuild is an at-point representation of a workspace state.
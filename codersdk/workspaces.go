@@ -29,6 +29,18 @@ type Workspace struct {
 	Name              string         `json:"name"`
 	AutostartSchedule *string        `json:"autostart_schedule,omitempty"`
 	TTLMillis         *int64         `json:"ttl_ms,omitempty"`
+	// DormantAt is when the workspace was marked dormant for staying
+	// stopped longer than its template allows. Starting a dormant workspace
+	// requires confirmation.
+	DormantAt *time.Time `json:"dormant_at,omitempty"`
+	// DormantAfter is when a stopped workspace will be marked dormant unless
+	// it's started before.
+	DormantAfter *time.Time `json:"dormant_after,omitempty"`
+	// DeletingAt is when a dormant workspace will be deleted.
+	DeletingAt *time.Time `json:"deleting_at,omitempty"`
+	// DormancyOptOut excludes the workspace from dormancy and automatic
+	// deletion.
+	DormancyOptOut bool `json:"dormancy_opt_out"`
 }
 
 // CreateWorkspaceBuildRequest provides options to update the latest workspace build.
@@ -41,6 +53,8 @@ type CreateWorkspaceBuildRequest struct {
 	// This will overwrite any existing parameters with the same name.
 	// This will not delete old params not included in this list.
 	ParameterValues []CreateParameterRequest `json:"parameter_values,omitempty"`
+	// ConfirmDormant must be set to start a dormant workspace.
+	ConfirmDormant bool `json:"confirm_dormant,omitempty"`
 }
 
 type WorkspaceOptions struct {
@@ -221,6 +235,70 @@ func (c *Client) PutExtendWorkspace(ctx context.Context, id uuid.UUID, req PutEx
 	return nil
 }
 
+// TransferWorkspaceRequest is a request to transfer a workspace to another
+// member of its organization.
+type TransferWorkspaceRequest struct {
+	OwnerID uuid.UUID `json:"owner_id" validate:"required"`
+}
+
+// TransferWorkspace changes the owner of a workspace. It returns the build
+// that applies the metadata of the new owner.
+func (c *Client) TransferWorkspace(ctx context.Context, id uuid.UUID, req TransferWorkspaceRequest) (WorkspaceBuild, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/owner", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return WorkspaceBuild{}, xerrors.Errorf("transfer workspace: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuild{}, readBodyAsError(res)
+	}
+	var build WorkspaceBuild
+	return build, json.NewDecoder(res.Body).Decode(&build)
+}
+
+// RenameWorkspaceRequest is a request to change the name of a workspace.
+type RenameWorkspaceRequest struct {
+	Name string `json:"name" validate:"username,required"`
+}
+
+// RenameWorkspace changes the name of a workspace. Agents see the new name
+// from the next build.
+func (c *Client) RenameWorkspace(ctx context.Context, id uuid.UUID, req RenameWorkspaceRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/name", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("rename workspace: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
+// UpdateWorkspaceDormancyRequest is a request to opt a workspace out of
+// dormancy and automatic deletion, or back in.
+type UpdateWorkspaceDormancyRequest struct {
+	OptOut bool `json:"opt_out"`
+}
+
+// UpdateWorkspaceDormancy opts a workspace out of dormancy and automatic
+// deletion, or back in. Opting out also clears the dormancy of the
+// workspace.
+func (c *Client) UpdateWorkspaceDormancy(ctx context.Context, id uuid.UUID, req UpdateWorkspaceDormancyRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/dormancy", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace dormancy: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readBodyAsError(res)
+	}
+	return nil
+}
+
 type WorkspaceFilter struct {
 	// Owner can be "me" or a username
 	Owner string `json:"owner,omitempty" typescript:"-"`
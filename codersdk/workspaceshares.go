@@ -0,0 +1,78 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WorkspaceShareRole is the access a workspace is shared with.
+type WorkspaceShareRole string
+
+const (
+	// WorkspaceShareRoleRead allows viewing the workspace and using its
+	// applications and ports.
+	WorkspaceShareRoleRead WorkspaceShareRole = "read"
+	// WorkspaceShareRoleFull also allows connecting to the workspace agent
+	// over SSH or a terminal.
+	WorkspaceShareRoleFull WorkspaceShareRole = "full"
+)
+
+// WorkspaceShare is a user a workspace is shared with.
+type WorkspaceShare struct {
+	User User               `json:"user"`
+	Role WorkspaceShareRole `json:"role"`
+}
+
+type ShareWorkspaceRequest struct {
+	Role WorkspaceShareRole `json:"role" validate:"oneof=read full,required"`
+}
+
+// WorkspaceShares lists the users a workspace is shared with.
+func (c *Client) WorkspaceShares(ctx context.Context, workspace uuid.UUID) ([]WorkspaceShare, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/shares", workspace), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readBodyAsError(res)
+	}
+	var shares []WorkspaceShare
+	return shares, json.NewDecoder(res.Body).Decode(&shares)
+}
+
+// ShareWorkspace shares a workspace with a user, or changes the access they
+// were given. The user can be a username or ID.
+func (c *Client) ShareWorkspace(ctx context.Context, workspace uuid.UUID, user string, req ShareWorkspaceRequest) (WorkspaceShare, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspaces/%s/shares/%s", workspace, user), req)
+	if err != nil {
+		return WorkspaceShare{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceShare{}, readBodyAsError(res)
+	}
+	var share WorkspaceShare
+	return share, json.NewDecoder(res.Body).Decode(&share)
+}
+
+// DeleteWorkspaceShare revokes the access a user was given to a workspace.
+func (c *Client) DeleteWorkspaceShare(ctx context.Context, workspace uuid.UUID, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/shares/%s", workspace, user), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return readBodyAsError(res)
+	}
+	return nil
+}
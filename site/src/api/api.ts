@@ -273,6 +273,10 @@ export const suspendUser = async (userId: TypesGen.User["id"]): Promise<TypesGen
   return response.data
 }
 
+export const revokeUserSessions = async (userId: TypesGen.User["id"]): Promise<void> => {
+  await axios.delete(`/api/v2/users/${userId}/keys`)
+}
+
 export const postFirstUser = async (
   req: TypesGen.CreateFirstUserRequest,
 ): Promise<TypesGen.CreateFirstUserResponse> => {
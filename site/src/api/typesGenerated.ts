@@ -1,6 +1,6 @@
 // Code generated by 'make coder/scripts/apitypings/main.go'. DO NOT EDIT.
 
-// From codersdk/users.go:54:6
+// From codersdk/users.go:57:6
 export interface APIKey {
   readonly id: string
   readonly user_id: string
@@ -10,6 +10,9 @@ export interface APIKey {
   readonly updated_at: string
   readonly login_type: LoginType
   readonly lifetime_seconds: number
+  readonly scopes: string[]
+  readonly token_name: string
+  readonly ip_address: string
 }
 
 // From codersdk/workspaceagents.go:35:6
@@ -24,10 +27,33 @@ export interface AgentGitSSHKey {
   readonly private_key: string
 }
 
-// From codersdk/users.go:174:6
+// From codersdk/audit.go:40:6
+export interface AuditLog {
+  readonly id: string
+  readonly time: string
+  readonly organization_id: string
+  readonly ip: string
+  readonly user_agent: string
+  readonly resource_type: ResourceType
+  readonly resource_id: string
+  readonly resource_target: string
+  readonly action: AuditAction
+  readonly diff: Record<string, unknown>
+  readonly status_code: number
+  readonly user_id: string
+  readonly user?: User
+}
+
+// From codersdk/audit.go:60:6
+export interface AuditLogsRequest extends Pagination {
+  readonly q?: string
+}
+
+// From codersdk/users.go:202:6
 export interface AuthMethods {
   readonly password: boolean
   readonly github: boolean
+  readonly oidc: boolean
 }
 
 // From codersdk/workspaceagents.go:40:6
@@ -49,7 +75,7 @@ export interface ComputedParameter extends Parameter {
   readonly default_source_value: boolean
 }
 
-// From codersdk/users.go:65:6
+// From codersdk/users.go:73:6
 export interface CreateFirstUserRequest {
   readonly email: string
   readonly username: string
@@ -57,13 +83,19 @@ export interface CreateFirstUserRequest {
   readonly organization: string
 }
 
-// From codersdk/users.go:73:6
+// From codersdk/users.go:81:6
 export interface CreateFirstUserResponse {
   readonly user_id: string
   readonly organization_id: string
 }
 
-// From codersdk/users.go:169:6
+// From codersdk/groups.go:23:6
+export interface CreateGroupRequest {
+  readonly name: string
+  readonly roles?: string[]
+}
+
+// From codersdk/users.go:197:6
 export interface CreateOrganizationRequest {
   readonly name: string
 }
@@ -77,7 +109,16 @@ export interface CreateParameterRequest {
   readonly destination_scheme: ParameterDestinationScheme
 }
 
-// From codersdk/organizations.go:49:6
+// From codersdk/roles.go:42:6
+export interface CreateRoleRequest {
+  readonly name: string
+  readonly display_name: string
+  readonly site_permissions?: Permission[]
+  readonly organization_permissions?: Permission[]
+  readonly user_permissions?: Permission[]
+}
+
+// From codersdk/organizations.go:53:6
 export interface CreateTemplateRequest {
   readonly name: string
   readonly description?: string
@@ -85,6 +126,13 @@ export interface CreateTemplateRequest {
   readonly parameter_values?: CreateParameterRequest[]
   readonly max_ttl_ms?: number
   readonly min_autostart_interval_ms?: number
+  readonly cost_units?: number
+  readonly inactivity_ttl_ms?: number
+  readonly dormant_deletion_ttl_ms?: number
+  readonly default_autostart_schedule?: string
+  readonly default_ttl_ms?: number
+  readonly required_stop_schedule?: string
+  readonly allow_user_schedule_override?: boolean
 }
 
 // From codersdk/templateversions.go:106:6
@@ -93,7 +141,7 @@ export interface CreateTemplateVersionDryRunRequest {
   readonly ParameterValues: CreateParameterRequest[]
 }
 
-// From codersdk/organizations.go:36:6
+// From codersdk/organizations.go:40:6
 export interface CreateTemplateVersionRequest {
   readonly template_id?: string
   readonly storage_method: ProvisionerStorageMethod
@@ -102,7 +150,14 @@ export interface CreateTemplateVersionRequest {
   readonly parameter_values?: CreateParameterRequest[]
 }
 
-// From codersdk/users.go:78:6
+// From codersdk/users.go:180:6
+export interface CreateTokenRequest {
+  readonly token_name?: string
+  readonly lifetime_seconds?: number
+  readonly scopes?: string[]
+}
+
+// From codersdk/users.go:86:6
 export interface CreateUserRequest {
   readonly email: string
   readonly username: string
@@ -110,25 +165,45 @@ export interface CreateUserRequest {
   readonly organization_id: string
 }
 
-// From codersdk/workspaces.go:35:6
+// From codersdk/workspaces.go:47:6
 export interface CreateWorkspaceBuildRequest {
   readonly template_version_id?: string
   readonly transition: WorkspaceTransition
   readonly dry_run?: boolean
   readonly state?: string
   readonly parameter_values?: CreateParameterRequest[]
+  readonly confirm_dormant?: boolean
 }
 
-// From codersdk/organizations.go:76:6
+// From codersdk/organizations.go:112:6
 export interface CreateWorkspaceRequest {
   readonly template_id: string
   readonly name: string
   readonly autostart_schedule?: string
   readonly ttl_ms?: number
   readonly parameter_values?: CreateParameterRequest[]
+  readonly from_workspace_id?: string
+}
+
+// From codersdk/roles.go:29:6
+export interface CustomRole {
+  readonly id: string
+  readonly name: string
+  readonly display_name: string
+  readonly organization_id?: string
+  readonly site_permissions: Permission[]
+  readonly organization_permissions: Permission[]
+  readonly user_permissions: Permission[]
+  readonly created_at: string
+  readonly updated_at: string
 }
 
-// From codersdk/users.go:165:6
+// From codersdk/users.go:300:6
+export interface DeleteUserOptions {
+  readonly destroy_workspaces?: boolean
+}
+
+// From codersdk/users.go:193:6
 export interface GenerateAPIKeyResponse {
   readonly key: string
 }
@@ -146,15 +221,38 @@ export interface GoogleInstanceIdentityToken {
   readonly json_web_token: string
 }
 
-// From codersdk/users.go:154:6
+// From codersdk/groups.go:15:6
+export interface Group {
+  readonly id: string
+  readonly name: string
+  readonly organization_id: string
+  readonly roles: string[]
+  readonly members: User[]
+}
+
+// From codersdk/users.go:162:6
 export interface LoginWithPasswordRequest {
   readonly email: string
   readonly password: string
 }
 
-// From codersdk/users.go:160:6
+// From codersdk/users.go:168:6
 export interface LoginWithPasswordResponse {
   readonly session_token: string
+  readonly totp_token?: string
+  readonly totp_enrollment?: TOTPEnrollment
+}
+
+// From codersdk/totp.go:44:6
+export interface LoginWithTOTPRequest {
+  readonly totp_token: string
+  readonly code: string
+}
+
+// From codersdk/totp.go:52:6
+export interface LoginWithTOTPResponse {
+  readonly session_token: string
+  readonly recovery_codes?: string[]
 }
 
 // From codersdk/organizations.go:28:6
@@ -165,7 +263,7 @@ export interface Organization {
   readonly updated_at: string
 }
 
-// From codersdk/organizationmember.go:9:6
+// From codersdk/organizationmember.go:14:6
 export interface OrganizationMember {
   readonly user_id: string
   readonly organization_id: string
@@ -174,6 +272,13 @@ export interface OrganizationMember {
   readonly roles: string[]
 }
 
+// From codersdk/organizationmember.go:24:6
+export interface OrganizationMemberWithUser {
+  readonly user: User
+  readonly roles: Role[]
+  readonly created_at: string
+}
+
 // From codersdk/pagination.go:11:6
 export interface Pagination {
   readonly after_id?: string
@@ -214,6 +319,26 @@ export interface ParameterSchema {
   readonly validation_contains?: string[]
 }
 
+// From codersdk/users.go:209:6
+export interface PasswordPolicy {
+  readonly min_length: number
+  readonly min_character_classes: number
+}
+
+// From codersdk/groups.go:30:6
+export interface PatchGroupRequest {
+  readonly name?: string
+  readonly add_users?: string[]
+  readonly remove_users?: string[]
+}
+
+// From codersdk/roles.go:20:6
+export interface Permission {
+  readonly negate: boolean
+  readonly resource_type: string
+  readonly action: string
+}
+
 // From codersdk/provisionerdaemons.go:36:6
 export interface ProvisionerDaemon {
   readonly id: string
@@ -245,11 +370,41 @@ export interface ProvisionerJobLog {
   readonly output: string
 }
 
-// From codersdk/workspaces.go:206:6
+// From codersdk/workspaces.go:220:6
 export interface PutExtendWorkspaceRequest {
   readonly deadline: string
 }
 
+// From codersdk/quotas.go:34:6
+export interface Quota {
+  readonly limits: QuotaLimits
+  readonly usage: QuotaUsage
+}
+
+// From codersdk/quotas.go:18:6
+export interface QuotaLimits {
+  readonly max_workspaces: number
+  readonly max_running_workspaces: number
+  readonly max_cost_units: number
+}
+
+// From codersdk/quotas.go:28:6
+export interface QuotaUsage {
+  readonly workspaces: number
+  readonly running_workspaces: number
+  readonly cost_units: number
+}
+
+// From codersdk/workspaces.go:261:6
+export interface RenameWorkspaceRequest {
+  readonly name: string
+}
+
+// From codersdk/totp.go:29:6
+export interface ResetTOTPRequest {
+  readonly code?: string
+}
+
 // From codersdk/error.go:4:6
 export interface Response {
   readonly message: string
@@ -257,12 +412,34 @@ export interface Response {
   readonly validations?: ValidationError[]
 }
 
-// From codersdk/roles.go:12:6
+// From codersdk/roles.go:13:6
 export interface Role {
   readonly name: string
   readonly display_name: string
 }
 
+// From codersdk/workspaceshares.go:31:6
+export interface ShareWorkspaceRequest {
+  readonly role: WorkspaceShareRole
+}
+
+// From codersdk/totp.go:18:6
+export interface TOTPEnrollment {
+  readonly secret: string
+  readonly url: string
+}
+
+// From codersdk/totp.go:38:6
+export interface TOTPRecoveryCodes {
+  readonly recovery_codes: string[]
+}
+
+// From codersdk/totp.go:11:6
+export interface TOTPStatus {
+  readonly enabled: boolean
+  readonly recovery_codes_remaining: number
+}
+
 // From codersdk/templates.go:16:6
 export interface Template {
   readonly id: string
@@ -278,6 +455,31 @@ export interface Template {
   readonly min_autostart_interval_ms: number
   readonly created_by_id: string
   readonly created_by_name: string
+  readonly cost_units: number
+  readonly inactivity_ttl_ms: number
+  readonly dormant_deletion_ttl_ms: number
+  readonly default_autostart_schedule: string
+  readonly default_ttl_ms: number
+  readonly required_stop_schedule: string
+  readonly allow_user_schedule_override: boolean
+}
+
+// From codersdk/templates.go:99:6
+export interface TemplateACL {
+  readonly users: TemplateUser[]
+  readonly groups: TemplateGroup[]
+}
+
+// From codersdk/templates.go:92:6
+export interface TemplateGroup {
+  readonly group: Group
+  readonly role: TemplateRole
+}
+
+// From codersdk/templates.go:87:6
+export interface TemplateUser {
+  readonly user: User
+  readonly role: TemplateRole
 }
 
 // From codersdk/templateversions.go:14:6
@@ -292,45 +494,81 @@ export interface TemplateVersion {
   readonly readme: string
 }
 
-// From codersdk/templates.go:100:6
+// From codersdk/templates.go:197:6
 export interface TemplateVersionsByTemplateRequest extends Pagination {
   readonly template_id: string
 }
 
-// From codersdk/templates.go:32:6
+// From codersdk/workspaces.go:240:6
+export interface TransferWorkspaceRequest {
+  readonly owner_id: string
+}
+
+// From codersdk/templates.go:51:6
 export interface UpdateActiveTemplateVersion {
   readonly id: string
 }
 
-// From codersdk/users.go:94:6
+// From codersdk/organizations.go:35:6
+export interface UpdateOrganizationRequest {
+  readonly name: string
+}
+
+// From codersdk/roles.go:52:6
+export interface UpdateRoleRequest {
+  readonly display_name: string
+  readonly site_permissions?: Permission[]
+  readonly organization_permissions?: Permission[]
+  readonly user_permissions?: Permission[]
+}
+
+// From codersdk/users.go:102:6
 export interface UpdateRoles {
   readonly roles: string[]
 }
 
-// From codersdk/templates.go:36:6
+// From codersdk/templates.go:106:6
+export interface UpdateTemplateACL {
+  readonly user_perms?: Record<string, TemplateRole>
+  readonly group_perms?: Record<string, TemplateRole>
+}
+
+// From codersdk/templates.go:55:6
 export interface UpdateTemplateMeta {
   readonly description?: string
   readonly max_ttl_ms?: number
   readonly min_autostart_interval_ms?: number
+  readonly cost_units?: number
+  readonly inactivity_ttl_ms?: number
+  readonly dormant_deletion_ttl_ms?: number
+  readonly default_autostart_schedule?: string
+  readonly default_ttl_ms?: number
+  readonly required_stop_schedule?: string
+  readonly allow_user_schedule_override?: boolean
 }
 
-// From codersdk/users.go:89:6
+// From codersdk/users.go:97:6
 export interface UpdateUserPasswordRequest {
   readonly old_password: string
   readonly password: string
 }
 
-// From codersdk/users.go:85:6
+// From codersdk/users.go:93:6
 export interface UpdateUserProfileRequest {
   readonly username: string
 }
 
-// From codersdk/workspaces.go:165:6
+// From codersdk/workspaces.go:179:6
 export interface UpdateWorkspaceAutostartRequest {
   readonly schedule?: string
 }
 
-// From codersdk/workspaces.go:185:6
+// From codersdk/workspaces.go:282:6
+export interface UpdateWorkspaceDormancyRequest {
+  readonly opt_out: boolean
+}
+
+// From codersdk/workspaces.go:199:6
 export interface UpdateWorkspaceTTLRequest {
   readonly ttl_ms?: number
 }
@@ -340,24 +578,25 @@ export interface UploadResponse {
   readonly hash: string
 }
 
-// From codersdk/users.go:44:6
+// From codersdk/users.go:46:6
 export interface User {
   readonly id: string
   readonly email: string
   readonly created_at: string
+  readonly last_seen_at: string
   readonly username: string
   readonly status: UserStatus
   readonly organization_ids: string[]
   readonly roles: Role[]
 }
 
-// From codersdk/users.go:119:6
+// From codersdk/users.go:127:6
 export interface UserAuthorization {
   readonly object: UserAuthorizationObject
   readonly action: string
 }
 
-// From codersdk/users.go:135:6
+// From codersdk/users.go:143:6
 export interface UserAuthorizationObject {
   readonly resource_type: string
   readonly owner_id?: string
@@ -365,21 +604,27 @@ export interface UserAuthorizationObject {
   readonly resource_id?: string
 }
 
-// From codersdk/users.go:108:6
+// From codersdk/users.go:116:6
 export interface UserAuthorizationRequest {
   readonly checks: Record<string, UserAuthorization>
 }
 
-// From codersdk/users.go:103:6
+// From codersdk/users.go:111:6
 export type UserAuthorizationResponse = Record<string, boolean>
 
-// From codersdk/users.go:98:6
+// From codersdk/userlockouts.go:13:6
+export interface UserLockout {
+  readonly failed_attempts: number
+  readonly locked_until?: string
+}
+
+// From codersdk/users.go:106:6
 export interface UserRoles {
   readonly roles: string[]
   readonly organization_roles: Record<string, string[]>
 }
 
-// From codersdk/users.go:32:6
+// From codersdk/users.go:34:6
 export interface UsersRequest extends Pagination {
   readonly q?: string
 }
@@ -390,6 +635,11 @@ export interface ValidationError {
   readonly detail: string
 }
 
+// From codersdk/totp.go:24:6
+export interface VerifyTOTPRequest {
+  readonly code: string
+}
+
 // From codersdk/workspaces.go:19:6
 export interface Workspace {
   readonly id: string
@@ -404,6 +654,10 @@ export interface Workspace {
   readonly name: string
   readonly autostart_schedule?: string
   readonly ttl_ms?: number
+  readonly dormant_at?: string
+  readonly dormant_after?: string
+  readonly deleting_at?: string
+  readonly dormancy_opt_out: boolean
 }
 
 // From codersdk/workspaceresources.go:33:6
@@ -470,7 +724,7 @@ export interface WorkspaceApp {
   readonly icon?: string
 }
 
-// From codersdk/workspacebuilds.go:38:6
+// From codersdk/workspacebuilds.go:42:6
 export interface WorkspaceBuild {
   readonly id: string
   readonly created_at: string
@@ -490,17 +744,17 @@ export interface WorkspaceBuild {
   readonly reason: BuildReason
 }
 
-// From codersdk/workspaces.go:88:6
+// From codersdk/workspaces.go:102:6
 export interface WorkspaceBuildsRequest extends Pagination {
   readonly WorkspaceID: string
 }
 
-// From codersdk/workspaces.go:224:6
+// From codersdk/workspaces.go:302:6
 export interface WorkspaceFilter {
   readonly q?: string
 }
 
-// From codersdk/workspaces.go:46:6
+// From codersdk/workspaces.go:60:6
 export interface WorkspaceOptions {
   readonly include_deleted?: boolean
 }
@@ -516,8 +770,17 @@ export interface WorkspaceResource {
   readonly agents?: WorkspaceAgent[]
 }
 
+// From codersdk/workspaceshares.go:26:6
+export interface WorkspaceShare {
+  readonly user: User
+  readonly role: WorkspaceShareRole
+}
+
+// From codersdk/audit.go:31:6
+export type AuditAction = "create" | "delete" | "write"
+
 // From codersdk/workspacebuilds.go:22:6
-export type BuildReason = "autostart" | "autostop" | "initiator"
+export type BuildReason = "autodelete" | "autostart" | "autostop" | "initiator"
 
 // From codersdk/provisionerdaemons.go:26:6
 export type LogLevel = "debug" | "error" | "info" | "trace" | "warn"
@@ -526,7 +789,7 @@ export type LogLevel = "debug" | "error" | "info" | "trace" | "warn"
 export type LogSource = "provisioner" | "provisioner_daemon"
 
 // From codersdk/users.go:25:6
-export type LoginType = "github" | "password"
+export type LoginType = "github" | "oidc" | "password" | "token"
 
 // From codersdk/parameters.go:29:6
 export type ParameterDestinationScheme = "environment_variable" | "none" | "provisioner_variable"
@@ -555,11 +818,34 @@ export type ProvisionerStorageMethod = "file"
 // From codersdk/organizations.go:20:6
 export type ProvisionerType = "echo" | "terraform"
 
+// From codersdk/audit.go:12:6
+export type ResourceType =
+  | "api_key"
+  | "custom_role"
+  | "git_ssh_key"
+  | "group"
+  | "organization"
+  | "organization_member"
+  | "organization_quota"
+  | "template"
+  | "template_version"
+  | "user"
+  | "user_lockout"
+  | "user_quota"
+  | "user_totp"
+  | "workspace"
+
+// From codersdk/templates.go:78:6
+export type TemplateRole = "" | "admin" | "use"
+
 // From codersdk/users.go:18:6
 export type UserStatus = "active" | "suspended"
 
 // From codersdk/workspaceresources.go:15:6
 export type WorkspaceAgentStatus = "connected" | "connecting" | "disconnected"
 
+// From codersdk/workspaceshares.go:14:6
+export type WorkspaceShareRole = "full" | "read"
+
 // From codersdk/workspacebuilds.go:14:6
 export type WorkspaceTransition = "delete" | "start" | "stop"
@@ -28,6 +28,7 @@ Loading.args = {
   isLoading: true,
   authMethods: {
     github: true,
+    oidc: false,
     password: true,
   },
 }
@@ -47,5 +48,16 @@ WithGithub.args = {
   authMethods: {
     password: true,
     github: true,
+    oidc: false,
+  },
+}
+
+export const WithOIDC = Template.bind({})
+WithOIDC.args = {
+  ...SignedOut.args,
+  authMethods: {
+    password: true,
+    github: false,
+    oidc: true,
   },
 }
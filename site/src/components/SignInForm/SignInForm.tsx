@@ -4,6 +4,7 @@ import Link from "@material-ui/core/Link"
 import { makeStyles } from "@material-ui/core/styles"
 import TextField from "@material-ui/core/TextField"
 import GitHubIcon from "@material-ui/icons/GitHub"
+import KeyIcon from "@material-ui/icons/VpnKey"
 import { FormikContextType, useFormik } from "formik"
 import { FC } from "react"
 import * as Yup from "yup"
@@ -31,6 +32,7 @@ export const Language = {
   methodsErrorMessage: "Unable to fetch auth methods.",
   passwordSignIn: "Sign In",
   githubSignIn: "GitHub",
+  oidcSignIn: "OpenID Connect",
 }
 
 const validationSchema = Yup.object({
@@ -144,33 +146,48 @@ export const SignInForm: FC<SignInFormProps> = ({
           </LoadingButton>
         </div>
       </form>
+      {(authMethods?.github || authMethods?.oidc) && (
+        <div className={styles.divider}>
+          <div className={styles.dividerLine} />
+          <div className={styles.dividerLabel}>Or</div>
+          <div className={styles.dividerLine} />
+        </div>
+      )}
       {authMethods?.github && (
-        <>
-          <div className={styles.divider}>
-            <div className={styles.dividerLine} />
-            <div className={styles.dividerLabel}>Or</div>
-            <div className={styles.dividerLine} />
-          </div>
-
-          <div>
-            <Link
-              underline="none"
-              href={`/api/v2/users/oauth2/github/callback?redirect=${encodeURIComponent(
-                redirectTo,
-              )}`}
+        <div>
+          <Link
+            underline="none"
+            href={`/api/v2/users/oauth2/github/callback?redirect=${encodeURIComponent(redirectTo)}`}
+          >
+            <Button
+              startIcon={<GitHubIcon className={styles.buttonIcon} />}
+              disabled={isLoading}
+              fullWidth
+              type="submit"
+              variant="contained"
             >
-              <Button
-                startIcon={<GitHubIcon className={styles.buttonIcon} />}
-                disabled={isLoading}
-                fullWidth
-                type="submit"
-                variant="contained"
-              >
-                {Language.githubSignIn}
-              </Button>
-            </Link>
-          </div>
-        </>
+              {Language.githubSignIn}
+            </Button>
+          </Link>
+        </div>
+      )}
+      {authMethods?.oidc && (
+        <div className={authMethods.github ? styles.submitBtn : undefined}>
+          <Link
+            underline="none"
+            href={`/api/v2/users/oidc/callback?redirect=${encodeURIComponent(redirectTo)}`}
+          >
+            <Button
+              startIcon={<KeyIcon className={styles.buttonIcon} />}
+              disabled={isLoading}
+              fullWidth
+              type="submit"
+              variant="contained"
+            >
+              {Language.oidcSignIn}
+            </Button>
+          </Link>
+        </div>
       )}
     </>
   )
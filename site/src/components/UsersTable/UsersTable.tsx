@@ -22,6 +22,7 @@ export interface UsersTableProps {
   onSuspendUser: (user: TypesGen.User) => void
   onActivateUser: (user: TypesGen.User) => void
   onResetUserPassword: (user: TypesGen.User) => void
+  onRevokeUserSessions: (user: TypesGen.User) => void
   onUpdateUserRoles: (user: TypesGen.User, roles: TypesGen.Role["name"][]) => void
 }
 
@@ -31,6 +32,7 @@ export const UsersTable: FC<UsersTableProps> = ({
   onSuspendUser,
   onActivateUser,
   onResetUserPassword,
+  onRevokeUserSessions,
   onUpdateUserRoles,
   isUpdatingUserRoles,
   canEditUsers,
@@ -56,6 +58,7 @@ export const UsersTable: FC<UsersTableProps> = ({
           isUpdatingUserRoles={isUpdatingUserRoles}
           onActivateUser={onActivateUser}
           onResetUserPassword={onResetUserPassword}
+          onRevokeUserSessions={onRevokeUserSessions}
           onSuspendUser={onSuspendUser}
           onUpdateUserRoles={onUpdateUserRoles}
         />
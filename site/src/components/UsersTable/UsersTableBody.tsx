@@ -16,6 +16,7 @@ export const Language = {
   suspendMenuItem: "Suspend",
   activateMenuItem: "Activate",
   resetPasswordMenuItem: "Reset password",
+  revokeSessionsMenuItem: "Revoke sessions",
 }
 
 interface UsersTableBodyProps {
@@ -27,6 +28,7 @@ interface UsersTableBodyProps {
   onSuspendUser: (user: TypesGen.User) => void
   onActivateUser: (user: TypesGen.User) => void
   onResetUserPassword: (user: TypesGen.User) => void
+  onRevokeUserSessions: (user: TypesGen.User) => void
   onUpdateUserRoles: (user: TypesGen.User, roles: TypesGen.Role["name"][]) => void
 }
 
@@ -36,6 +38,7 @@ export const UsersTableBody: FC<UsersTableBodyProps> = ({
   onSuspendUser,
   onActivateUser,
   onResetUserPassword,
+  onRevokeUserSessions,
   onUpdateUserRoles,
   isUpdatingUserRoles,
   canEditUsers,
@@ -117,10 +120,16 @@ export const UsersTableBody: FC<UsersTableBodyProps> = ({
                             onClick: onActivateUser,
                           },
                         ]
-                    ).concat({
-                      label: Language.resetPasswordMenuItem,
-                      onClick: onResetUserPassword,
-                    })
+                    ).concat(
+                      {
+                        label: Language.resetPasswordMenuItem,
+                        onClick: onResetUserPassword,
+                      },
+                      {
+                        label: Language.revokeSessionsMenuItem,
+                        onClick: onRevokeUserSessions,
+                      },
+                    )
                   }
                 />
               </TableCell>
@@ -76,6 +76,7 @@ describe("LoginPage", () => {
           ctx.json({
             password: true,
             github: true,
+            oidc: false,
           }),
         )
       }),
@@ -88,4 +89,27 @@ describe("LoginPage", () => {
     await screen.findByText(Language.passwordSignIn)
     await screen.findByText(Language.githubSignIn)
   })
+
+  it("shows oidc authentication when enabled", async () => {
+    // Given
+    server.use(
+      rest.get("/api/v2/users/authmethods", async (req, res, ctx) => {
+        return res(
+          ctx.status(200),
+          ctx.json({
+            password: true,
+            github: false,
+            oidc: true,
+          }),
+        )
+      }),
+    )
+
+    // When
+    render(<LoginPage />)
+
+    // Then
+    await screen.findByText(Language.passwordSignIn)
+    await screen.findByText(Language.oidcSignIn)
+  })
 })
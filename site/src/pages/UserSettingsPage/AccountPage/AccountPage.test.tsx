@@ -33,6 +33,7 @@ describe("AccountPage", () => {
           id: userId,
           email: "user@coder.com",
           created_at: new Date().toString(),
+          last_seen_at: new Date().toString(),
           status: "active",
           organization_ids: ["123"],
           roles: [],
@@ -108,6 +108,37 @@ const resetUserPassword = async (setupActionSpies: () => void) => {
   fireEvent.click(confirmButton)
 }
 
+const revokeUserSessions = async (setupActionSpies: () => void) => {
+  // Get the first user in the table
+  const users = await screen.findAllByText(/.*@coder.com/)
+  const firstUserRow = users[0].closest("tr")
+  if (!firstUserRow) {
+    throw new Error("Error on get the first user row")
+  }
+
+  // Click on the "more" button to display the "Revoke sessions" option
+  const moreButton = within(firstUserRow).getByLabelText("more")
+  fireEvent.click(moreButton)
+  const menu = screen.getByRole("menu")
+  const revokeButton = within(menu).getByText(UsersTableBodyLanguage.revokeSessionsMenuItem)
+  fireEvent.click(revokeButton)
+
+  // Check if the confirm message is displayed
+  const confirmDialog = screen.getByRole("dialog")
+  expect(confirmDialog).toHaveTextContent(
+    `${UsersPageLanguage.revokeSessionsDialogMessagePrefix} ${MockUser.username}?`,
+  )
+
+  // Setup spies to check the actions after
+  setupActionSpies()
+
+  // Click on the "Confirm" button
+  const confirmButton = within(confirmDialog).getByText(
+    UsersPageLanguage.revokeSessionsDialogAction,
+  )
+  fireEvent.click(confirmButton)
+}
+
 const updateUserRole = async (setupActionSpies: () => void, role: Role) => {
   // Get the first user in the table
   const users = await screen.findAllByText(/.*@coder.com/)
@@ -321,6 +352,52 @@ describe("Users Page", () => {
     })
   })
 
+  describe("revoke user sessions", () => {
+    describe("when it is success", () => {
+      it("shows a success message", async () => {
+        render(
+          <>
+            <UsersPage />
+            <GlobalSnackbar />
+          </>,
+        )
+
+        await revokeUserSessions(() => {
+          jest.spyOn(API, "revokeUserSessions").mockResolvedValueOnce(undefined)
+        })
+
+        // Check if the success message is displayed
+        await screen.findByText(usersXServiceLanguage.revokeUserSessionsSuccess)
+
+        // Check if the API was called correctly
+        expect(API.revokeUserSessions).toBeCalledTimes(1)
+        expect(API.revokeUserSessions).toBeCalledWith(MockUser.id)
+      })
+    })
+
+    describe("when it fails", () => {
+      it("shows an error message", async () => {
+        render(
+          <>
+            <UsersPage />
+            <GlobalSnackbar />
+          </>,
+        )
+
+        await revokeUserSessions(() => {
+          jest.spyOn(API, "revokeUserSessions").mockRejectedValueOnce({})
+        })
+
+        // Check if the error message is displayed
+        await screen.findByText(usersXServiceLanguage.revokeUserSessionsError)
+
+        // Check if the API was called correctly
+        expect(API.revokeUserSessions).toBeCalledTimes(1)
+        expect(API.revokeUserSessions).toBeCalledWith(MockUser.id)
+      })
+    })
+  })
+
   describe("Update user role", () => {
     describe("when it is success", () => {
       it("updates the roles", async () => {
@@ -17,6 +17,9 @@ export const Language = {
   activateDialogTitle: "Activate user",
   activateDialogAction: "Activate",
   activateDialogMessagePrefix: "Do you want to activate the user",
+  revokeSessionsDialogTitle: "Revoke sessions",
+  revokeSessionsDialogAction: "Revoke",
+  revokeSessionsDialogMessagePrefix: "Do you want to revoke all sessions and tokens of the user",
 }
 
 export const UsersPage: React.FC = () => {
@@ -29,12 +32,14 @@ export const UsersPage: React.FC = () => {
     userIdToActivate,
     userIdToResetPassword,
     newUserPassword,
+    userIdToRevokeSessions,
   } = usersState.context
   const navigate = useNavigate()
   const [searchParams, setSearchParams] = useSearchParams()
   const userToBeSuspended = users?.find((u) => u.id === userIdToSuspend)
   const userToBeActivated = users?.find((u) => u.id === userIdToActivate)
   const userToResetPassword = users?.find((u) => u.id === userIdToResetPassword)
+  const userToRevokeSessions = users?.find((u) => u.id === userIdToRevokeSessions)
 
   const [authState, _] = useActor(xServices.authXService)
   const { permissions } = authState.context
@@ -93,6 +98,9 @@ export const UsersPage: React.FC = () => {
         onResetUserPassword={(user) => {
           usersSend({ type: "RESET_USER_PASSWORD", userId: user.id })
         }}
+        onRevokeUserSessions={(user) => {
+          usersSend({ type: "REVOKE_USER_SESSIONS", userId: user.id })
+        }}
         onUpdateUserRoles={(user, roles) => {
           usersSend({
             type: "UPDATE_USER_ROLES",
@@ -152,6 +160,27 @@ export const UsersPage: React.FC = () => {
         }
       />
 
+      <ConfirmDialog
+        type="delete"
+        hideCancel={false}
+        open={usersState.matches("confirmUserSessionsRevocation")}
+        confirmLoading={usersState.matches("revokingUserSessions")}
+        title={Language.revokeSessionsDialogTitle}
+        confirmText={Language.revokeSessionsDialogAction}
+        onConfirm={() => {
+          usersSend("CONFIRM_USER_SESSIONS_REVOCATION")
+        }}
+        onClose={() => {
+          usersSend("CANCEL_USER_SESSIONS_REVOCATION")
+        }}
+        description={
+          <>
+            {Language.revokeSessionsDialogMessagePrefix}{" "}
+            <strong>{userToRevokeSessions?.username}</strong>?
+          </>
+        }
+      />
+
       <ResetPasswordDialog
         loading={usersState.matches("resettingUserPassword")}
         user={userToResetPassword}
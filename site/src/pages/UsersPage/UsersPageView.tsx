@@ -28,6 +28,7 @@ export interface UsersPageViewProps {
   onSuspendUser: (user: TypesGen.User) => void
   onActivateUser: (user: TypesGen.User) => void
   onResetUserPassword: (user: TypesGen.User) => void
+  onRevokeUserSessions: (user: TypesGen.User) => void
   onUpdateUserRoles: (user: TypesGen.User, roles: TypesGen.Role["name"][]) => void
   onFilter: (query: string) => void
 }
@@ -39,6 +40,7 @@ export const UsersPageView: FC<UsersPageViewProps> = ({
   onSuspendUser,
   onActivateUser,
   onResetUserPassword,
+  onRevokeUserSessions,
   onUpdateUserRoles,
   error,
   isUpdatingUserRoles,
@@ -80,6 +82,7 @@ export const UsersPageView: FC<UsersPageViewProps> = ({
         onSuspendUser={onSuspendUser}
         onActivateUser={onActivateUser}
         onResetUserPassword={onResetUserPassword}
+        onRevokeUserSessions={onRevokeUserSessions}
         onUpdateUserRoles={onUpdateUserRoles}
         isUpdatingUserRoles={isUpdatingUserRoles}
         canEditUsers={canEditUsers}
@@ -36,6 +36,7 @@ export const MockUser: TypesGen.User = {
   username: "TestUser",
   email: "test@coder.com",
   created_at: "",
+  last_seen_at: "",
   status: "active",
   organization_ids: ["fc0774ce-cc9e-48d4-80ae-88f7a4d4a8b0"],
   roles: [MockAdminRole],
@@ -46,6 +47,7 @@ export const MockUser2: TypesGen.User = {
   username: "TestUser2",
   email: "test2@coder.com",
   created_at: "",
+  last_seen_at: "",
   status: "active",
   organization_ids: ["fc0774ce-cc9e-48d4-80ae-88f7a4d4a8b0"],
   roles: [],
@@ -56,6 +58,7 @@ export const SuspendedMockUser: TypesGen.User = {
   username: "SuspendedMockUser",
   email: "iamsuspendedsad!@coder.com",
   created_at: "",
+  last_seen_at: "",
   status: "suspended",
   organization_ids: ["fc0774ce-cc9e-48d4-80ae-88f7a4d4a8b0"],
   roles: [],
@@ -128,6 +131,13 @@ export const MockTemplate: TypesGen.Template = {
   min_autostart_interval_ms: 3600000,
   created_by_id: "test-creator-id",
   created_by_name: "test_creator",
+  cost_units: 0,
+  inactivity_ttl_ms: 0,
+  dormant_deletion_ttl_ms: 0,
+  default_autostart_schedule: "",
+  default_ttl_ms: 0,
+  required_stop_schedule: "",
+  allow_user_schedule_override: true,
 }
 
 export const MockWorkspaceAutostartDisabled: TypesGen.UpdateWorkspaceAutostartRequest = {
@@ -186,6 +196,7 @@ export const MockWorkspace: TypesGen.Workspace = {
   autostart_schedule: MockWorkspaceAutostartEnabled.schedule,
   ttl_ms: 2 * 60 * 60 * 1000, // 2 hours as milliseconds
   latest_build: MockWorkspaceBuild,
+  dormancy_opt_out: false,
 }
 
 export const MockStoppedWorkspace: TypesGen.Workspace = {
@@ -288,6 +299,7 @@ export const MockUserAgent: Types.UserAgent = {
 export const MockAuthMethods: TypesGen.AuthMethods = {
   password: true,
   github: false,
+  oidc: false,
 }
 
 export const MockGitSSHKey: TypesGen.GitSSHKey = {
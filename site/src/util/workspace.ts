@@ -189,6 +189,7 @@ export const getDisplayWorkspaceBuildStatus = (
 export const DisplayWorkspaceBuildInitiatedByLanguage = {
   autostart: "system/autostart",
   autostop: "system/autostop",
+  autodelete: "system/autodelete",
 }
 
 export const getDisplayWorkspaceBuildInitiatedBy = (
@@ -214,6 +215,11 @@ export const getDisplayWorkspaceBuildInitiatedBy = (
         color: theme.palette.secondary.dark,
         initiatedBy: DisplayWorkspaceBuildInitiatedByLanguage.autostop,
       }
+    case "autodelete":
+      return {
+        color: theme.palette.secondary.dark,
+        initiatedBy: DisplayWorkspaceBuildInitiatedByLanguage.autodelete,
+      }
   }
 }
 
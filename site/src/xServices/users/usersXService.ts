@@ -23,6 +23,8 @@ export const Language = {
   activateUserError: "Error activating user.",
   resetUserPasswordSuccess: "Successfully updated the user password.",
   resetUserPasswordError: "Error on resetting the user password.",
+  revokeUserSessionsSuccess: "Successfully revoked the user sessions.",
+  revokeUserSessionsError: "Error revoking the user sessions.",
   updateUserRolesSuccess: "Successfully updated the user roles.",
   updateUserRolesError: "Error on updating the user roles.",
 }
@@ -44,6 +46,9 @@ export interface UsersContext {
   userIdToResetPassword?: TypesGen.User["id"]
   resetUserPasswordError?: Error | unknown
   newUserPassword?: string
+  // Revoke user sessions
+  userIdToRevokeSessions?: TypesGen.User["id"]
+  revokeUserSessionsError?: Error | unknown
   // Update user roles
   userIdToUpdateRoles?: TypesGen.User["id"]
   updateUserRolesError?: Error | unknown
@@ -65,6 +70,10 @@ export type UsersEvent =
   | { type: "RESET_USER_PASSWORD"; userId: TypesGen.User["id"] }
   | { type: "CONFIRM_USER_PASSWORD_RESET" }
   | { type: "CANCEL_USER_PASSWORD_RESET" }
+  // Revoke sessions events
+  | { type: "REVOKE_USER_SESSIONS"; userId: TypesGen.User["id"] }
+  | { type: "CONFIRM_USER_SESSIONS_REVOCATION" }
+  | { type: "CANCEL_USER_SESSIONS_REVOCATION" }
   // Update roles events
   | { type: "UPDATE_USER_ROLES"; userId: TypesGen.User["id"]; roles: TypesGen.Role["name"][] }
 
@@ -90,6 +99,9 @@ export const usersMachine = createMachine(
         updateUserPassword: {
           data: undefined
         }
+        revokeUserSessions: {
+          data: undefined
+        }
         updateUserRoles: {
           data: TypesGen.User
         }
@@ -118,6 +130,10 @@ export const usersMachine = createMachine(
             target: "confirmUserPasswordReset",
             actions: ["assignUserIdToResetPassword", "generateRandomPassword"],
           },
+          REVOKE_USER_SESSIONS: {
+            target: "confirmUserSessionsRevocation",
+            actions: ["assignUserIdToRevokeSessions"],
+          },
           UPDATE_USER_ROLES: {
             target: "updatingUserRoles",
             actions: ["assignUserIdToUpdateRoles"],
@@ -232,6 +248,27 @@ export const usersMachine = createMachine(
           },
         },
       },
+      confirmUserSessionsRevocation: {
+        on: {
+          CONFIRM_USER_SESSIONS_REVOCATION: "revokingUserSessions",
+          CANCEL_USER_SESSIONS_REVOCATION: "idle",
+        },
+      },
+      revokingUserSessions: {
+        entry: "clearRevokeUserSessionsError",
+        invoke: {
+          src: "revokeUserSessions",
+          id: "revokeUserSessions",
+          onDone: {
+            target: "idle",
+            actions: ["displayRevokeSessionsSuccess"],
+          },
+          onError: {
+            target: "idle",
+            actions: ["assignRevokeUserSessionsError", "displayRevokeSessionsErrorMessage"],
+          },
+        },
+      },
       updatingUserRoles: {
         entry: "clearUpdateUserRolesError",
         invoke: {
@@ -292,6 +329,13 @@ export const usersMachine = createMachine(
           old_password: "",
         })
       },
+      revokeUserSessions: (context) => {
+        if (!context.userIdToRevokeSessions) {
+          throw new Error("userIdToRevokeSessions is undefined")
+        }
+
+        return API.revokeUserSessions(context.userIdToRevokeSessions)
+      },
       updateUserRoles: (context, event) => {
         if (!context.userIdToUpdateRoles) {
           throw new Error("userIdToUpdateRoles is undefined")
@@ -322,6 +366,9 @@ export const usersMachine = createMachine(
       assignUserIdToResetPassword: assign({
         userIdToResetPassword: (_, event) => event.userId,
       }),
+      assignUserIdToRevokeSessions: assign({
+        userIdToRevokeSessions: (_, event) => event.userId,
+      }),
       assignUserIdToUpdateRoles: assign({
         userIdToUpdateRoles: (_, event) => event.userId,
       }),
@@ -346,6 +393,9 @@ export const usersMachine = createMachine(
       assignResetUserPasswordError: assign({
         resetUserPasswordError: (_, event) => event.data,
       }),
+      assignRevokeUserSessionsError: assign({
+        revokeUserSessionsError: (_, event) => event.data,
+      }),
       assignUpdateRolesError: assign({
         updateUserRolesError: (_, event) => event.data,
       }),
@@ -367,6 +417,9 @@ export const usersMachine = createMachine(
       clearResetUserPasswordError: assign({
         resetUserPasswordError: (_) => undefined,
       }),
+      clearRevokeUserSessionsError: assign({
+        revokeUserSessionsError: (_) => undefined,
+      }),
       clearUpdateUserRolesError: assign({
         updateUserRolesError: (_) => undefined,
       }),
@@ -401,6 +454,16 @@ export const usersMachine = createMachine(
         )
         displayError(message)
       },
+      displayRevokeSessionsSuccess: () => {
+        displaySuccess(Language.revokeUserSessionsSuccess)
+      },
+      displayRevokeSessionsErrorMessage: (context) => {
+        const message = getErrorMessage(
+          context.revokeUserSessionsError,
+          Language.revokeUserSessionsError,
+        )
+        displayError(message)
+      },
       displayUpdateRolesErrorMessage: (context) => {
         const message = getErrorMessage(context.updateUserRolesError, Language.updateUserRolesError)
         displayError(message)